@@ -1,26 +1,156 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"flag"
+	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/juanvallejo/streaming-server/pkg/logging"
+	"github.com/juanvallejo/streaming-server/pkg/metrics"
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/server"
+	"github.com/juanvallejo/streaming-server/pkg/server/path"
 	"github.com/juanvallejo/streaming-server/pkg/socket"
+	"github.com/juanvallejo/streaming-server/pkg/socket/auth"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/socket/cmd"
 	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/pubsub"
+	socketserver "github.com/juanvallejo/streaming-server/pkg/socket/server"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
+	"github.com/juanvallejo/streaming-server/pkg/validation"
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
 )
 
 func main() {
 	port := flag.String("port", "8080", "default port to listen on")
 	authz := flag.Bool("rbac", false, "enable role-based access control for request commands.")
+	stateFile := flag.String("state-file", "", "file used to save room state on shutdown and restore it on startup.")
+	chatHistory := flag.Int("chat-history", playback.DefaultChatHistorySize, "number of chat messages kept per room for clients joining mid-conversation.")
+	maxPinned := flag.Int("max-pinned-messages", playback.DefaultMaxPinnedMessages, "number of chat messages that may be pinned in a room at once.")
+	syncMin := flag.Int("sync-min", socket.StreamSyncMinRate, "seconds between streamsync events in small rooms.")
+	syncMax := flag.Int("sync-max", socket.StreamSyncMaxRate, "seconds between streamsync events in large rooms.")
+	probeImages := flag.Bool("probe-images", false, "probe chat message urls without a file extension for image content.")
+	previewLinks := flag.Bool("preview-links", false, "preview pages linked to by chat message urls from their Open Graph or oEmbed metadata.")
+	stripVideoUrls := flag.Bool("strip-video-urls", false, "remove YouTube and Vimeo urls embedded from chat messages from the message text.")
+	sanitizeChat := flag.String("sanitize-chat", socket.CHAT_SANITIZE_OFF, "how markup in chat messages is neutralized before broadcast (off, escape, or strip).")
+	preserveMediaUrls := flag.Bool("preserve-media-urls", socket.PreserveChatMediaUrls, "keep image urls extracted from chat messages when they are sanitized (requires -sanitize-chat).")
+	maxMessageLength := flag.Int("max-message-length", socket.DEFAULT_MAX_CHAT_MESSAGE_LENGTH, "maximum number of characters in a chat message.")
+	wordFilter := flag.String("word-filter", "", "comma-separated list of words filtered from chat messages in new rooms.")
+	wordFilterMode := flag.String("word-filter-mode", string(playback.DefaultWordFilterMode), "how new rooms treat chat messages containing filtered words (off, mask, or reject).")
+	chatBurst := flag.Int("chat-burst", client.DefaultChatBurst, "number of chat messages a client may send in a burst.")
+	connBurst := flag.Int("conn-burst", socketserver.DefaultConnectionBurst, "number of socket connections a single address may open in a burst.")
+	connRate := flag.Float64("conn-rate", socketserver.DefaultConnectionRefillRate, "number of socket connections per second a single address may open after a burst.")
+	maxConns := flag.Int("max-conns-per-address", socketserver.DefaultMaxConnectionsPerAddress, "number of socket connections a single address may hold open at once (0 for no limit).")
+	chatRate := flag.Float64("chat-rate", client.DefaultChatRefillRate, "number of chat messages per second a client may send after a burst.")
+	reservedNames := flag.String("reserved-names", "", "comma-separated list of additional usernames clients may not claim.")
+	awayAfter := flag.Duration("away-after", client.DefaultAwayThreshold, "idle time after which a user is shown as away.")
+	pingInterval := flag.Duration("ping-interval", socket.PingInterval, "time between client latency measurements.")
+	heartbeatInterval := flag.Duration("heartbeat-interval", socket.DefaultHeartbeatInterval, "time between heartbeat pings sent to each client (0 to disable).")
+	heartbeatMisses := flag.Int("heartbeat-misses", socket.DefaultHeartbeatMissLimit, "number of consecutive heartbeat pings a client may miss before it is disconnected.")
+	resumeGrace := flag.Duration("resume-grace", socket.DefaultResumeGracePeriod, "time a disconnected user may reconnect and resume their session.")
+	countdown := flag.Int("countdown", playback.DefaultStreamCountdown, "seconds new rooms count down before starting a newly-loaded stream.")
+	bufferPause := flag.Float64("buffer-pause", playback.BufferingPauseFraction, "fraction of a room's clients that must be buffering before playback is paused.")
+	bufferResume := flag.Float64("buffer-resume", playback.BufferingResumeFraction, "fraction of a room's clients that may still be buffering when paused playback resumes.")
+	queuePreview := flag.Int("queue-preview", playback.QueuePreviewSize, "number of upcoming queue items included in streamsync events (0 to disable).")
+	playHistory := flag.Int("play-history", playback.DefaultPlayHistorySize, "number of finished streams remembered per room.")
+	mediaRoot := flag.String("media-root", path.StreamDataRootPath, "directory from which local and file:// video streams are served.")
+	maxPlaylistItems := flag.Int("max-playlist-items", stream.DefaultMaxPlaylistExpansion, "number of streams a single playlist url expands into (also limited by the space left in the user's queue).")
+	metadataTTL := flag.Duration("metadata-ttl", stream.DefaultMetadataTTL, "time fetched stream metadata is reused before it is fetched again.")
+	auditLogSize := flag.Int("audit-log", playback.DefaultAuditLogSize, "number of privileged command executions recorded per room (requires -rbac).")
+	roomIdleTimeout := flag.Duration("room-idle-timeout", playback.RoomIdleTimeout, "time a room may go without connected clients before it is removed.")
+	reapInterval := flag.Duration("reap-interval", playback.RoomReapInterval, "time between checks for idle rooms to remove.")
+	logLevel := flag.String("log-level", logging.LevelInfo.String(), "lowest level of messages logged (debug, info, warn, or error).")
+	shutdownTimeout := flag.Duration("shutdown-timeout", server.DefaultShutdownTimeout, "time given to notify rooms, save state, and close connections once the server is asked to stop.")
+	authKeyFile := flag.String("auth-key-file", "", "file containing the HS256 key used to verify the token every connection must present (enables authentication).")
+	authIssuer := flag.String("auth-issuer", "", "issuer connection tokens must have been issued by (requires -auth-key-file).")
+	operatorTokenFile := flag.String("operator-token-file", "", "file containing the token operators must present to send announcements to every room (enables announcements).")
+	lockUsernames := flag.Bool("lock-usernames", false, "prevent authenticated users from changing the display name given by their token (requires -auth-key-file).")
+	webhookUrls := flag.String("webhook-urls", "", "comma-separated list of urls room events are POSTed to as JSON.")
+	webhookQueue := flag.Int("webhook-queue", webhook.DefaultQueueSize, "number of room events that may wait to be delivered to webhooks before new events are dropped.")
+	webhookAttempts := flag.Int("webhook-attempts", webhook.DefaultMaxAttempts, "number of times delivery of a room event to each webhook url is attempted.")
+	redisAddr := flag.String("redis-addr", "", "address of a redis server used to relay room broadcasts between server instances.")
+	bindingsFile := flag.String("role-bindings-file", "", "file used to remember users' role bindings in each room across reconnects and restarts (requires -rbac). Reloaded on SIGHUP.")
 	flag.Parse()
 
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		log.Fatalf("ERR %v\n", err)
+	}
+	logging.Default.SetLevel(level)
+
+	path.StreamDataRootPath = *mediaRoot
+	stream.MetadataTTL = *metadataTTL
+	if *maxPlaylistItems < 1 {
+		log.Fatalf("ERR -max-playlist-items must be at least 1\n")
+	}
+	stream.MaxPlaylistExpansion = *maxPlaylistItems
+	playback.ChatHistorySize = *chatHistory
+	playback.MaxPinnedMessages = *maxPinned
+	playback.PlayHistorySize = *playHistory
+	playback.AuditLogSize = *auditLogSize
+	playback.RoomIdleTimeout = *roomIdleTimeout
+	playback.RoomReapInterval = *reapInterval
+	playback.DefaultStreamCountdown = *countdown
+	playback.QueuePreviewSize = *queuePreview
+	playback.BufferingPauseFraction = *bufferPause
+	playback.BufferingResumeFraction = *bufferResume
+	switch mode := playback.WordFilterMode(*wordFilterMode); mode {
+	case playback.WORD_FILTER_OFF, playback.WORD_FILTER_MASK, playback.WORD_FILTER_REJECT:
+		playback.DefaultWordFilterMode = mode
+	default:
+		log.Fatalf("ERR unknown word filter mode %q (expected %s, %s, or %s)\n", mode, playback.WORD_FILTER_OFF, playback.WORD_FILTER_MASK, playback.WORD_FILTER_REJECT)
+	}
+	if len(*wordFilter) > 0 {
+		for _, word := range strings.Split(*wordFilter, ",") {
+			word = strings.TrimSpace(word)
+			if err := playback.ValidateFilteredWord(word); err != nil {
+				log.Fatalf("ERR %v\n", err)
+			}
+			playback.DefaultFilteredWords = append(playback.DefaultFilteredWords, word)
+		}
+	}
+	socket.StreamSyncMinRate = *syncMin
+	socket.StreamSyncMaxRate = *syncMax
+	socket.StripChatVideoUrls = *stripVideoUrls
+	socket.MaxChatMessageLength = *maxMessageLength
+	if err := socket.ValidateChatSanitizeMode(*sanitizeChat); err != nil {
+		log.Fatalf("ERR %v\n", err)
+	}
+	socket.ChatSanitizeMode = *sanitizeChat
+	socket.PreserveChatMediaUrls = *preserveMediaUrls
+	client.ChatBurst = *chatBurst
+	client.ChatRefillRate = *chatRate
+	socketserver.ConnectionBurst = *connBurst
+	socketserver.ConnectionRefillRate = *connRate
+	socketserver.MaxConnectionsPerAddress = *maxConns
+	client.AwayThreshold = *awayAfter
+	socket.PingInterval = *pingInterval
+	socket.HeartbeatInterval = *heartbeatInterval
+	socket.HeartbeatMissLimit = *heartbeatMisses
+	socket.ResumeGracePeriod = *resumeGrace
+	socket.LockAuthenticatedUsernames = *lockUsernames
+	validation.ReserveUsernames(strings.Split(*reservedNames, ",")...)
+
 	nsHandler := connection.NewNamespaceHandler()
+	if len(*redisAddr) > 0 {
+		relay, err := connection.NewRelayNamespaceHandler(nsHandler, pubsub.NewRedisBroker(*redisAddr))
+		if err != nil {
+			log.Fatalf("ERR PUBSUB %v\n", err)
+		}
+
+		log.Printf("INF PUBSUB relaying room broadcasts through redis at %q\n", *redisAddr)
+		nsHandler = relay
+	}
+
 	connHandler := connection.NewHandler(nsHandler)
 	cmdHandler := cmd.NewHandler()
 
@@ -35,15 +165,75 @@ func main() {
 
 	}
 
+	playbackHandler := playback.NewGarbageCollectedHandler(nsHandler)
+	if len(*webhookUrls) > 0 {
+		urls := strings.Split(*webhookUrls, ",")
+		log.Printf("INF WEBHOOK delivering room events to %v urls\n", len(urls))
+		playbackHandler.SetEventDispatcher(webhook.NewHTTPDispatcher(urls, *webhookQueue, *webhookAttempts))
+	}
+	if len(*stateFile) > 0 {
+		loadPlaybackState(playbackHandler, *stateFile)
+	}
+
 	socketHandler := socket.NewHandler(
 		nsHandler,
 		connHandler,
 		cmdHandler,
 		client.NewHandler(),
-		playback.NewGarbageCollectedHandler(nsHandler),
+		playbackHandler,
 		stream.NewGarbageCollectedHandler(),
 	)
 
+	if len(*authKeyFile) > 0 {
+		key, err := ioutil.ReadFile(*authKeyFile)
+		if err != nil {
+			log.Fatalf("ERR AUTHN unable to read authentication key %q: %v\n", *authKeyFile, err)
+		}
+
+		log.Printf("INF AUTHN connections must present a valid authentication token.\n")
+		socketHandler.SetAuthenticator(auth.NewJWTValidator(bytes.TrimSpace(key), *authIssuer))
+	}
+
+	if len(*operatorTokenFile) > 0 {
+		token, err := ioutil.ReadFile(*operatorTokenFile)
+		if err != nil {
+			log.Fatalf("ERR AUTHN unable to read operator token %q: %v\n", *operatorTokenFile, err)
+		}
+
+		token = bytes.TrimSpace(token)
+		if len(token) == 0 {
+			log.Fatalf("ERR AUTHN operator token %q is empty\n", *operatorTokenFile)
+		}
+
+		log.Printf("INF AUTHN operators may send announcements to every room.\n")
+		socketHandler.SetOperatorAuthenticator(auth.NewStaticValidator(token, "operator"))
+	}
+
+	if *probeImages {
+		socketHandler.SetImageProber(socket.NewDefaultImageProber())
+	}
+	if *previewLinks {
+		socketHandler.SetLinkPreviewer(socket.NewDefaultLinkPreviewer())
+	}
+
+	if *authz && len(*bindingsFile) > 0 {
+		bindings := rbac.NewBindingStore(*bindingsFile)
+		if err := bindings.Load(); err != nil {
+			log.Printf("ERR STATE unable to load saved role bindings %q: %v\n", *bindingsFile, err)
+		}
+		reloadRoleBindingsOnHangup(bindings, *bindingsFile)
+		socketHandler.SetBindingStore(bindings)
+	}
+
+	if err := playback.RegisterMetrics(metrics.Default, playbackHandler); err != nil {
+		log.Printf("ERR METRICS unable to register room metrics: %v\n", err)
+	}
+	if err := socketHandler.RegisterMetrics(metrics.Default); err != nil {
+		log.Printf("ERR METRICS unable to register client metrics: %v\n", err)
+	}
+
+	socketHandler.SetStateFile(*stateFile)
+
 	requestHandler := server.NewRequestHandler(socketHandler, connHandler)
 
 	// init http server with socket.io support
@@ -52,5 +242,65 @@ func main() {
 		Host: "0.0.0.0",
 		Out:  os.Stdout,
 	})
+
+	done := shutdownOnSignal(socketHandler, application, *shutdownTimeout)
 	application.Serve()
+	<-done
+}
+
+// loadPlaybackState restores room state saved to the given file, if it exists
+func loadPlaybackState(handler playback.PlaybackHandler, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("ERR STATE unable to open saved room state %q: %v\n", path, err)
+		}
+		return
+	}
+	defer f.Close()
+
+	if err := handler.LoadState(f); err != nil {
+		log.Printf("ERR STATE unable to load saved room state %q: %v\n", path, err)
+	}
+}
+
+// shutdownOnSignal shuts down the socket handler and then the http
+// server once the process receives an interrupt or termination signal.
+// The returned channel is closed once shutdown has completed.
+func shutdownOnSignal(socketHandler *socket.Handler, application *server.ServerOptions, timeout time.Duration) <-chan struct{} {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		<-sigChan
+		defer close(done)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := socketHandler.Shutdown(ctx); err != nil {
+			log.Printf("ERR SOCKET %v\n", err)
+		}
+		if err := application.Shutdown(ctx); err != nil {
+			log.Printf("ERR HTTP unable to shut down server cleanly: %v\n", err)
+		}
+	}()
+
+	return done
+}
+
+// reloadRoleBindingsOnHangup reloads the given role bindings from
+// their file once the process receives a hangup signal.
+func reloadRoleBindingsOnHangup(bindings *rbac.BindingStore, path string) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	go func() {
+		for range sigChan {
+			if err := bindings.Load(); err != nil {
+				log.Printf("ERR STATE unable to reload role bindings %q: %v\n", path, err)
+			}
+		}
+	}()
 }
@@ -1,5 +1,10 @@
 package query
 
 const (
-	CONN_ID_KEY = "id"
+	CONN_ID_KEY        = "id"
+	ROOM_PASSWORD_KEY  = "password"
+	ROOM_INVITE_KEY    = "invite"
+	SESSION_RESUME_KEY = "resume"
+	AUTH_TOKEN_KEY     = "token"
+	SESSION_TOKEN_KEY  = "session"
 )
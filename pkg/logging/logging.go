@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a log message
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// levelNames are the names a Level is parsed from, and
+// levelPrefixes the codes messages are prefixed with.
+var (
+	levelNames    = []string{"debug", "info", "warn", "error"}
+	levelPrefixes = []string{"DBG", "INF", "WRN", "ERR"}
+)
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level with the given name (debug, info, warn, or error).
+// Returns an error if the name does not correspond to a Level.
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q: must be one of %s", name, strings.Join(levelNames, ", "))
+}
+
+// Logger writes messages at a given severity level
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// LevelLogger is a Logger that discards messages below its level.
+// Written messages are prefixed with a code for their level, such as
+// "INF" or "ERR". It is safe for concurrent use.
+type LevelLogger struct {
+	mutex sync.RWMutex
+	level Level
+	out   *log.Logger
+}
+
+// SetLevel sets the lowest level of messages the logger writes
+func (l *LevelLogger) SetLevel(level Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.level = level
+}
+
+// Level returns the lowest level of messages the logger writes
+func (l *LevelLogger) Level() Level {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.level
+}
+
+func (l *LevelLogger) Debugf(format string, args ...interface{}) {
+	l.logf(LevelDebug, format, args...)
+}
+
+func (l *LevelLogger) Infof(format string, args ...interface{}) {
+	l.logf(LevelInfo, format, args...)
+}
+
+func (l *LevelLogger) Warnf(format string, args ...interface{}) {
+	l.logf(LevelWarn, format, args...)
+}
+
+func (l *LevelLogger) Errorf(format string, args ...interface{}) {
+	l.logf(LevelError, format, args...)
+}
+
+func (l *LevelLogger) logf(level Level, format string, args ...interface{}) {
+	if level < l.Level() {
+		return
+	}
+
+	l.out.Printf(levelPrefixes[level]+" "+format, args...)
+}
+
+// New returns a LevelLogger writing messages at
+// or above the given level to the given writer.
+func New(w io.Writer, level Level) *LevelLogger {
+	return &LevelLogger{
+		level: level,
+		out:   log.New(w, "", log.LstdFlags),
+	}
+}
+
+// Default is the logger used by components that have not been given
+// another. It writes info messages and above to standard error.
+var Default = New(os.Stderr, LevelInfo)
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevelLogger(t *testing.T) {
+	tests := []struct {
+		name         string
+		level        Level
+		expectLogged []string
+	}{
+		{
+			name:         "debug level",
+			level:        LevelDebug,
+			expectLogged: []string{"DBG debug message", "INF info message", "WRN warn message", "ERR error message"},
+		},
+		{
+			name:         "info level",
+			level:        LevelInfo,
+			expectLogged: []string{"INF info message", "WRN warn message", "ERR error message"},
+		},
+		{
+			name:         "warn level",
+			level:        LevelWarn,
+			expectLogged: []string{"WRN warn message", "ERR error message"},
+		},
+		{
+			name:         "error level",
+			level:        LevelError,
+			expectLogged: []string{"ERR error message"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := New(&out, tc.level)
+
+			logger.Debugf("%s message", "debug")
+			logger.Infof("%s message", "info")
+			logger.Warnf("%s message", "warn")
+			logger.Errorf("%s message", "error")
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != len(tc.expectLogged) {
+				t.Fatalf("expected %v messages to be logged, got %q", len(tc.expectLogged), out.String())
+			}
+			for i, expected := range tc.expectLogged {
+				if !strings.HasSuffix(lines[i], expected) {
+					t.Errorf("expected message %q to be logged, got %q", expected, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLevelLoggerSetLevel(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, LevelDebug)
+
+	logger.SetLevel(LevelError)
+	logger.Warnf("suppressed")
+	if out.Len() != 0 {
+		t.Errorf("expected messages below the new level to be suppressed, got %q", out.String())
+	}
+
+	logger.SetLevel(LevelWarn)
+	logger.Warnf("written")
+	if !strings.Contains(out.String(), "WRN written") {
+		t.Errorf("expected messages at the new level to be logged, got %q", out.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       string
+		expectLevel Level
+		expectErr   bool
+	}{
+		{
+			name:        "level name",
+			level:       "warn",
+			expectLevel: LevelWarn,
+		},
+		{
+			name:        "level name in a different case",
+			level:       "DEBUG",
+			expectLevel: LevelDebug,
+		},
+		{
+			name:        "unknown level",
+			level:       "verbose",
+			expectLevel: LevelInfo,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			level, err := ParseLevel(tc.level)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if level != tc.expectLevel {
+				t.Errorf("expected level %v, got %v", tc.expectLevel, level)
+			}
+		})
+	}
+}
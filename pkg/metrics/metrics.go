@@ -0,0 +1,298 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, used by histograms
+// measuring request latencies when no buckets are given.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector is a named metric that can write its
+// current samples in the Prometheus text format.
+type Collector interface {
+	// Name returns the metric's name
+	Name() string
+	// Write writes the metric's help text, type,
+	// and current samples to the given writer.
+	Write(w io.Writer) error
+}
+
+type metric struct {
+	name string
+	help string
+	kind string
+}
+
+func (m *metric) Name() string {
+	return m.name
+}
+
+func (m *metric) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, escapeHelp(m.help), m.name, m.kind)
+	return err
+}
+
+// Counter is a monotonically increasing value.
+// It is safe for concurrent use.
+type Counter struct {
+	metric
+
+	mutex sync.Mutex
+	value float64
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by the given amount.
+// Negative amounts are ignored.
+func (c *Counter) Add(n float64) {
+	if n < 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.value += n
+}
+
+// Value returns the counter's current value
+func (c *Counter) Value() float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.value
+}
+
+func (c *Counter) Write(w io.Writer) error {
+	if err := c.writeHeader(w); err != nil {
+		return err
+	}
+	return writeSample(w, c.name, "", c.Value())
+}
+
+// CounterVec is a set of counters sharing a name,
+// partitioned by the values of a single label.
+// It is safe for concurrent use.
+type CounterVec struct {
+	metric
+
+	label    string
+	mutex    sync.Mutex
+	counters map[string]*Counter
+}
+
+// WithLabel returns the counter for the given label
+// value, creating it if it does not exist yet.
+func (v *CounterVec) WithLabel(value string) *Counter {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	c, exists := v.counters[value]
+	if !exists {
+		c = &Counter{}
+		v.counters[value] = c
+	}
+	return c
+}
+
+func (v *CounterVec) Write(w io.Writer) error {
+	if err := v.writeHeader(w); err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	values := make(map[string]float64, len(v.counters))
+	for label, c := range v.counters {
+		values[label] = c.Value()
+	}
+	v.mutex.Unlock()
+
+	return writeLabelledSamples(w, v.name, v.label, values)
+}
+
+// GaugeFunc is a gauge whose value is computed
+// by calling a function each time it is collected.
+type GaugeFunc struct {
+	metric
+
+	fn func() float64
+}
+
+func (g *GaugeFunc) Write(w io.Writer) error {
+	if err := g.writeHeader(w); err != nil {
+		return err
+	}
+	return writeSample(w, g.name, "", g.fn())
+}
+
+// GaugeVecFunc is a set of gauges sharing a name, whose
+// values, keyed by the value of a single label, are computed
+// by calling a function each time they are collected.
+type GaugeVecFunc struct {
+	metric
+
+	label string
+	fn    func() map[string]float64
+}
+
+func (g *GaugeVecFunc) Write(w io.Writer) error {
+	if err := g.writeHeader(w); err != nil {
+		return err
+	}
+	return writeLabelledSamples(w, g.name, g.label, g.fn())
+}
+
+// Histogram counts observed values in
+// configurable buckets. It is safe for concurrent use.
+type Histogram struct {
+	metric
+
+	mutex   sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe records the given value
+func (h *Histogram) Observe(v float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// ObserveSince records the number of seconds elapsed since the given time
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Count returns the number of values observed
+func (h *Histogram) Count() uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.count
+}
+
+func (h *Histogram) Write(w io.Writer) error {
+	if err := h.writeHeader(w); err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i, upper := range h.buckets {
+		if err := writeSample(w, h.name+"_bucket", fmt.Sprintf("le=%q", formatFloat(upper)), float64(h.counts[i])); err != nil {
+			return err
+		}
+	}
+	if err := writeSample(w, h.name+"_bucket", `le="+Inf"`, float64(h.count)); err != nil {
+		return err
+	}
+	if err := writeSample(w, h.name+"_sum", "", h.sum); err != nil {
+		return err
+	}
+	return writeSample(w, h.name+"_count", "", float64(h.count))
+}
+
+// NewCounter returns a new counter with the given name and help text
+func NewCounter(name, help string) *Counter {
+	return &Counter{
+		metric: metric{name: name, help: help, kind: "counter"},
+	}
+}
+
+// NewCounterVec returns a new set of counters with the
+// given name and help text, partitioned by the given label.
+func NewCounterVec(name, help, label string) *CounterVec {
+	return &CounterVec{
+		metric:   metric{name: name, help: help, kind: "counter"},
+		label:    label,
+		counters: make(map[string]*Counter),
+	}
+}
+
+// NewGaugeFunc returns a new gauge with the given name and
+// help text, whose value is computed by the given function.
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return &GaugeFunc{
+		metric: metric{name: name, help: help, kind: "gauge"},
+		fn:     fn,
+	}
+}
+
+// NewGaugeVecFunc returns a new set of gauges with the given name
+// and help text, whose values for each value of the given label
+// are computed by the given function.
+func NewGaugeVecFunc(name, help, label string, fn func() map[string]float64) *GaugeVecFunc {
+	return &GaugeVecFunc{
+		metric: metric{name: name, help: help, kind: "gauge"},
+		label:  label,
+		fn:     fn,
+	}
+}
+
+// NewHistogram returns a new histogram with the given name and help
+// text, counting values in buckets with the given upper bounds.
+// DefaultBuckets are used if no buckets are given.
+func NewHistogram(name, help string, buckets ...float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &Histogram{
+		metric:  metric{name: name, help: help, kind: "histogram"},
+		buckets: sorted,
+		counts:  make([]uint64, len(sorted)),
+	}
+}
+
+func writeSample(w io.Writer, name, labels string, value float64) error {
+	if len(labels) > 0 {
+		name = name + "{" + labels + "}"
+	}
+	_, err := fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+	return err
+}
+
+func writeLabelledSamples(w io.Writer, name, label string, values map[string]float64) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := writeSample(w, name, label+`="`+escapeLabelValue(k)+`"`, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
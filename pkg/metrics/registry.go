@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ContentType is the media type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Default is the registry served on the server's metrics endpoint
+var Default = NewRegistry()
+
+// Registry is a set of uniquely-named collectors that can be
+// scraped by Prometheus. It is safe for concurrent use.
+// Implements http.Handler.
+type Registry struct {
+	mutex      sync.Mutex
+	collectors map[string]Collector
+}
+
+// Register adds the given collectors to the registry. An error
+// is returned if a collector with the same name is already registered.
+func (r *Registry) Register(collectors ...Collector) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, c := range collectors {
+		if _, exists := r.collectors[c.Name()]; exists {
+			return fmt.Errorf("a metric named %q is already registered", c.Name())
+		}
+		r.collectors[c.Name()] = c
+	}
+	return nil
+}
+
+// MustRegister adds the given collectors to
+// the registry, and panics if any cannot be added.
+func (r *Registry) MustRegister(collectors ...Collector) {
+	if err := r.Register(collectors...); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister removes the collector with the given name, returning
+// a boolean (false) if no such collector was registered.
+func (r *Registry) Unregister(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, exists := r.collectors[name]
+	delete(r.collectors, name)
+	return exists
+}
+
+// WriteTo writes every registered metric, sorted by
+// name, to the given writer in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	collectors := make([]Collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mutex.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].Name() < collectors[j].Name()
+	})
+
+	buf := &bytes.Buffer{}
+	for _, c := range collectors {
+		if err := c.Write(buf); err != nil {
+			return 0, err
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if _, err := r.WriteTo(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// MustRegister adds the given collectors to the Default registry
+func MustRegister(collectors ...Collector) {
+	Default.MustRegister(collectors...)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]Collector),
+	}
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
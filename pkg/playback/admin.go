@@ -2,9 +2,9 @@ package playback
 
 import (
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/juanvallejo/streaming-server/pkg/logging"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
@@ -85,14 +85,14 @@ func pickAdmin(picker AdminPicker, authorizer rbac.Authorizer, ns connection.Nam
 
 		select {
 		case <-stop:
-			log.Printf("INF PLAYBACK ADMIN-PICKER terminated for room %q.\n", ns.Name())
+			logging.Default.Infof("PLAYBACK ADMIN-PICKER terminated for room %q.\n", ns.Name())
 			return
 		default:
 		}
 
 		p, pExists := playbackHandler.PlaybackByNamespace(ns)
 		if !pExists {
-			log.Printf("INF PLAYBACK ADMIN-PICKER unable to find playback for namespace with id %v; terminating admin picker...\n", ns.UUID())
+			logging.Default.Infof("PLAYBACK ADMIN-PICKER unable to find playback for namespace with id %v; terminating admin picker...\n", ns.UUID())
 			return
 		}
 
@@ -142,17 +142,17 @@ func pickAdmin(picker AdminPicker, authorizer rbac.Authorizer, ns connection.Nam
 			after = loopPeriod
 		}
 
-		log.Printf("INF PLAYBACK ADMIN-PICKER elected connection with id (%q) as admin candidate after %v...\n", candidate.UUID(), after)
+		logging.Default.Infof("PLAYBACK ADMIN-PICKER elected connection with id (%q) as admin candidate after %v...\n", candidate.UUID(), after)
 
 		adminRole, exists := authorizer.Role(rbac.ADMIN_ROLE)
 		if !exists {
-			log.Printf("WRN PLAYBACK ADMIN-PICKER admin role did not exist - creating empty role\n")
+			logging.Default.Warnf("PLAYBACK ADMIN-PICKER admin role did not exist - creating empty role\n")
 			adminRole = rbac.NewRole(rbac.ADMIN_ROLE, []rbac.Rule{})
 			authorizer.AddRole(adminRole)
 		}
 
 		if authorizer.Bind(adminRole, candidate) {
-			log.Printf("INF PLAYBACK ADMIN-PICKER bound connection with id (%s) to rbac role %q\n", candidate.UUID(), "admin")
+			logging.Default.Infof("PLAYBACK ADMIN-PICKER bound connection with id (%s) to rbac role %q\n", candidate.UUID(), "admin")
 
 			// broadcast info to client
 			if c, err := clientHandler.GetClient(candidate.UUID()); err == nil {
@@ -162,7 +162,7 @@ func pickAdmin(picker AdminPicker, authorizer rbac.Authorizer, ns connection.Nam
 					Id: c.UUID(),
 				})
 			} else {
-				log.Printf("ERR PLAYBACK ADMIN-PICKER unable to broadcast admin-picker events to client - no client found wih id %q\n", candidate.UUID())
+				logging.Default.Errorf("PLAYBACK ADMIN-PICKER unable to broadcast admin-picker events to client - no client found wih id %q\n", candidate.UUID())
 			}
 		}
 	}
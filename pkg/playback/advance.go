@@ -0,0 +1,43 @@
+package playback
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// AdvanceQueue ends the current stream and loads the next one. A stream
+// interrupted by the current stream is resumed first; otherwise the next
+// item in the queue is loaded. If neither exists, playback is stopped.
+// The loaded stream starts after the room's countdown, if it has one.
+// Returns the loaded stream, or a boolean (false) if playback was stopped.
+func (p *Playback) AdvanceQueue() (stream.Stream, bool, error) {
+	if resumed, ok := p.ResumeInterrupted(); ok {
+		return resumed, true, p.startAdvancedStream()
+	}
+
+	queueItem, err := p.NextQueueItem()
+	if err != nil {
+		p.Stop()
+		return nil, false, nil
+	}
+
+	nextStream, ok := queueItem.(stream.Stream)
+	if !ok {
+		return nil, false, fmt.Errorf("expected next queue item to implement stream.Stream")
+	}
+
+	p.SetStream(nextStream)
+	p.Reset()
+	return nextStream, true, p.startAdvancedStream()
+}
+
+// startAdvancedStream counts down to the start of a stream loaded or
+// replayed in place of one that ended. Rooms without a countdown keep
+// playing as is.
+func (p *Playback) startAdvancedStream() error {
+	if p.Countdown() == 0 {
+		return nil
+	}
+	return p.StartPlayback()
+}
@@ -0,0 +1,38 @@
+package playback
+
+import "fmt"
+
+// SetAnnounceStreams enables or disables the system chat
+// message sent to the room whenever a new stream starts.
+func (p *Playback) SetAnnounceStreams(announce bool) {
+	p.quietStreams = !announce
+}
+
+// AnnounceStreams returns true if the room announces new streams in chat
+func (p *Playback) AnnounceStreams() bool {
+	return !p.quietStreams
+}
+
+// StreamAnnouncement returns a human-readable "now playing" message
+// for the room's current stream. Returns a boolean (false) if no stream
+// is loaded, or if the room has disabled stream announcements.
+func (p *Playback) StreamAnnouncement() (string, bool) {
+	if p.quietStreams {
+		return "", false
+	}
+
+	s, exists := p.GetStream()
+	if !exists {
+		return "", false
+	}
+
+	title := s.GetName()
+	if len(title) == 0 {
+		title = s.GetStreamURL()
+	}
+
+	if len(p.startedBy) == 0 {
+		return fmt.Sprintf("Now playing: %s", title), true
+	}
+	return fmt.Sprintf("Now playing: %s (queued by %s)", title, p.startedBy), true
+}
@@ -0,0 +1,111 @@
+package playback
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
+)
+
+const (
+	DefaultAuditLogSize = 200 // default number of privileged command executions recorded per room
+)
+
+// AuditLogSize is the number of privileged command
+// executions recorded by each newly-created room.
+var AuditLogSize = DefaultAuditLogSize
+
+// AuditEntry is a serializable schema describing a
+// single execution of a privileged command in a room.
+type AuditEntry struct {
+	Actor   string    `json:"actor"`
+	ActorId string    `json:"actorId"`
+	Target  string    `json:"target,omitempty"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// AuditLog is a serializable schema listing a room's
+// most recent privileged command executions, oldest first.
+// Implements api.ApiCodec.
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+func (l *AuditLog) Serialize() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// auditLog is a bounded list of a room's most recent
+// privileged command executions. It is safe for concurrent use.
+type auditLog struct {
+	mutex   sync.Mutex
+	size    int
+	entries []AuditEntry
+}
+
+// append adds an entry to the log, discarding
+// the oldest entry once the log is full.
+func (l *auditLog) append(entry AuditEntry) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.size <= 0 {
+		return
+	}
+
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > l.size {
+		l.entries = l.entries[len(l.entries)-l.size:]
+	}
+}
+
+func (l *auditLog) list() []AuditEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entries := make([]AuditEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+func (l *auditLog) clear() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries = []AuditEntry{}
+}
+
+// RecordAudit adds the given privileged command execution to the
+// room's audit log. The entry's time is set if it is not already.
+func (p *Playback) RecordAudit(entry AuditEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	p.audit.append(entry)
+
+	p.DispatchEvent(webhook.EVENT_COMMAND_PRIVILEGED, map[string]interface{}{
+		"actor":   entry.Actor,
+		"target":  entry.Target,
+		"command": entry.Command,
+		"args":    entry.Args,
+		"error":   entry.Error,
+	})
+}
+
+// AuditLog returns the room's most recent privileged command executions, oldest first
+func (p *Playback) AuditLog() *AuditLog {
+	return &AuditLog{
+		Entries: p.audit.list(),
+	}
+}
+
+func newAuditLog(size int) *auditLog {
+	return &auditLog{
+		size:    size,
+		entries: []AuditEntry{},
+	}
+}
@@ -0,0 +1,50 @@
+package playback
+
+import (
+	"sync"
+	"time"
+)
+
+// bans is a concurrency-safe set of banned client
+// addresses, along with the name each ban was placed on
+type bans struct {
+	mutex     sync.Mutex
+	byAddress map[string]string
+}
+
+// Ban prevents clients connecting from the given address from
+// joining the room. The given name is recorded for display.
+func (p *Playback) Ban(address, name string) {
+	p.bans.mutex.Lock()
+	p.bans.byAddress[address] = name
+	p.bans.mutex.Unlock()
+
+	p.SetLastUpdated(time.Now())
+}
+
+// Unban lifts a ban placed on the client with the given name.
+// Returns a boolean (false) if no such ban exists.
+func (p *Playback) Unban(name string) bool {
+	p.bans.mutex.Lock()
+	defer p.bans.mutex.Unlock()
+
+	for address, bannedName := range p.bans.byAddress {
+		if bannedName == name {
+			delete(p.bans.byAddress, address)
+			p.SetLastUpdated(time.Now())
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsBanned returns true if clients connecting from
+// the given address are banned from the room.
+func (p *Playback) IsBanned(address string) bool {
+	p.bans.mutex.Lock()
+	defer p.bans.mutex.Unlock()
+
+	_, banned := p.bans.byAddress[address]
+	return banned
+}
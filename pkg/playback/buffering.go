@@ -0,0 +1,57 @@
+package playback
+
+var (
+	BufferingPauseFraction  = 0.3 // fraction of a room's clients that must be buffering before playback is paused
+	BufferingResumeFraction = 0.1 // fraction of a room's clients that may still be buffering when playback is resumed
+)
+
+// BufferingAction describes the change, if any, made to a
+// room's playback in response to its clients' buffering
+type BufferingAction int
+
+const (
+	BUFFERING_NONE BufferingAction = iota
+	BUFFERING_PAUSED
+	BUFFERING_RESUMED
+)
+
+// UpdateBuffering receives the number of clients in the room that are
+// buffering, out of the room's total number of clients. Playing streams
+// are paused once more than BufferingPauseFraction of the clients are
+// buffering, and streams paused this way are resumed once no more than
+// BufferingResumeFraction of the clients are buffering. Streams paused or
+// played by a user in the meantime are left as they are.
+func (p *Playback) UpdateBuffering(buffering, clients int) (BufferingAction, error) {
+	fraction := 0.0
+	if clients > 0 {
+		fraction = float64(buffering) / float64(clients)
+	}
+
+	if p.bufferPaused {
+		if fraction > BufferingResumeFraction {
+			return BUFFERING_NONE, nil
+		}
+
+		if err := p.Play(); err != nil {
+			return BUFFERING_NONE, err
+		}
+		return BUFFERING_RESUMED, nil
+	}
+
+	if p.timer.State() != TIMER_PLAY || fraction <= BufferingPauseFraction {
+		return BUFFERING_NONE, nil
+	}
+
+	if err := p.Pause(); err != nil {
+		return BUFFERING_NONE, err
+	}
+
+	p.bufferPaused = true
+	return BUFFERING_PAUSED, nil
+}
+
+// IsBufferPaused returns true if the room's playback
+// is paused while its clients catch up on buffering.
+func (p *Playback) IsBufferPaused() bool {
+	return p.bufferPaused
+}
@@ -0,0 +1,35 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MaxRoomCapacity = 1000 // largest client limit that may be set for a room
+)
+
+// SetCapacity sets the maximum number of clients that may be connected
+// to the room at once. A capacity of zero removes the limit.
+// Returns an error if the given capacity is out of bounds.
+func (p *Playback) SetCapacity(capacity int) error {
+	if capacity < 0 || capacity > MaxRoomCapacity {
+		return fmt.Errorf("room capacity must be between 1 and %v, or 0 for no limit", MaxRoomCapacity)
+	}
+
+	p.capacity = capacity
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// Capacity returns the maximum number of clients that may be
+// connected to the room at once, or zero if there is no limit.
+func (p *Playback) Capacity() int {
+	return p.capacity
+}
+
+// IsFull returns true if the room has a capacity, and the given
+// number of connected clients has reached it.
+func (p *Playback) IsFull(clients int) bool {
+	return p.capacity > 0 && clients >= p.capacity
+}
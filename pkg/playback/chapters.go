@@ -0,0 +1,86 @@
+package playback
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Chapter is a titled marker within the
+// currently-playing stream.
+type Chapter struct {
+	Title string `json:"title"`
+	Start int    `json:"start"`
+}
+
+// ChapterList is a serializable schema representing
+// the chapters set on the current stream.
+// Implements api.ApiCodec.
+type ChapterList struct {
+	Chapters []Chapter `json:"chapters"`
+}
+
+func (l *ChapterList) Serialize() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// SetChapters receives a list of chapters and replaces the chapters
+// for the currently-playing stream, ordered by start time.
+// Returns an error if no stream is loaded, if a chapter is missing
+// a title, or if a chapter's start time is out of the stream's range.
+func (p *Playback) SetChapters(chapters []Chapter) error {
+	s, exists := p.GetStream()
+	if !exists {
+		return fmt.Errorf("error: no stream is currently loaded for your room")
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range chapters {
+		if len(c.Title) == 0 {
+			return fmt.Errorf("error: every chapter must have a title")
+		}
+		if _, exists := seen[c.Title]; exists {
+			return fmt.Errorf("error: duplicate chapter title %q", c.Title)
+		}
+		if c.Start < 0 {
+			return fmt.Errorf("error: chapter %q must have a positive start time", c.Title)
+		}
+		if s.GetDuration() > 0 && float64(c.Start) >= s.GetDuration() {
+			return fmt.Errorf("error: chapter %q starts after the end of the stream", c.Title)
+		}
+		seen[c.Title] = true
+	}
+
+	sorted := make([]Chapter, len(chapters))
+	copy(sorted, chapters)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	p.chapters = sorted
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// Chapters returns the chapters set on the current stream
+func (p *Playback) Chapters() []Chapter {
+	return p.chapters
+}
+
+// ChapterByTitle returns the chapter with the given title,
+// or a boolean (false) if no such chapter exists.
+func (p *Playback) ChapterByTitle(title string) (Chapter, bool) {
+	for _, c := range p.chapters {
+		if c.Title == title {
+			return c, true
+		}
+	}
+
+	return Chapter{}, false
+}
+
+// ClearChapters removes all chapters from the current stream
+func (p *Playback) ClearChapters() {
+	p.chapters = []Chapter{}
+}
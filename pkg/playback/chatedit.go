@@ -0,0 +1,150 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+const (
+	// ChatEditedKey is the key, in a chat message's extra
+	// data, set to true once the message has been edited
+	ChatEditedKey = "edited"
+	// ChatEditedTimestampKey is the key of the time, in milliseconds, a
+	// chat message was last edited, stored in the message's extra data
+	ChatEditedTimestampKey = "editedTimestamp"
+	// ChatDeletedKey is the key, in a chat message's extra
+	// data, set to true once the message has been deleted
+	ChatDeletedKey = "deleted"
+)
+
+// Message returns the message in the log with the given id,
+// or a boolean (false) if no such message exists
+func (l *ChatLog) Message(messageId string) (*client.Response, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	idx, exists := l.indexOf(messageId)
+	if !exists {
+		return nil, false
+	}
+	return l.messages[idx], true
+}
+
+// EditMessage replaces the text of the message in the log with the given
+// id, flagging it as edited. Any Markdown segments of the previous text are
+// discarded, and the given extra data, if any, is added to the message.
+// The message is replaced in the log by an edited copy, so that previously
+// returned messages are never modified.
+// Returns the edited message, or an error if no such message exists.
+func (l *ChatLog) EditMessage(messageId, text string, extra map[string]interface{}) (*client.Response, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	idx, exists := l.indexOf(messageId)
+	if !exists {
+		return nil, fmt.Errorf("error: unable to find that message in the chat history")
+	}
+
+	msg := copyChatMessage(l.messages[idx])
+	msg.Message = text
+	delete(msg.Extra, ChatMarkdownKey)
+	for k, v := range extra {
+		msg.Extra[k] = v
+	}
+	msg.Extra[ChatEditedKey] = true
+	msg.Extra[ChatEditedTimestampKey] = time.Now().UnixNano() / int64(time.Millisecond)
+	l.messages[idx] = msg
+
+	return msg, nil
+}
+
+// DeleteMessage removes the message with the given id from the log,
+// along with its reactions. Returns the removed message, or a
+// boolean (false) if no such message exists.
+func (l *ChatLog) DeleteMessage(messageId string) (*client.Response, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	idx, exists := l.indexOf(messageId)
+	if !exists {
+		return nil, false
+	}
+
+	deleted := l.messages[idx]
+	delete(l.reactions, messageId)
+
+	// rebuild the ring buffer, oldest message first, without the deleted message
+	remaining := make([]*client.Response, 0, len(l.messages))
+	for i := 0; i < len(l.messages); i++ {
+		slot := i
+		if l.full {
+			slot = (l.next + i) % len(l.messages)
+		} else if i >= l.next {
+			break
+		}
+		if slot == idx {
+			continue
+		}
+		remaining = append(remaining, l.messages[slot])
+	}
+
+	l.messages = make([]*client.Response, len(l.messages))
+	copy(l.messages, remaining)
+	l.next = len(remaining) % len(l.messages)
+	l.full = false
+
+	return deleted, true
+}
+
+// EditChatMessage replaces the text of a message in the room's chat
+// history, updating it if pinned. See ChatLog.EditMessage.
+func (p *Playback) EditChatMessage(messageId, text string, extra map[string]interface{}) (*client.Response, error) {
+	msg, err := p.chatLog.EditMessage(messageId, text, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	p.updatePinnedMessage(msg)
+	return msg, nil
+}
+
+// DeleteChatMessage removes a message from the room's chat
+// history, unpinning it if pinned. See ChatLog.DeleteMessage.
+func (p *Playback) DeleteChatMessage(messageId string) (*client.Response, bool) {
+	msg, exists := p.chatLog.DeleteMessage(messageId)
+	if exists {
+		p.UnpinChatMessage(messageId)
+	}
+	return msg, exists
+}
+
+// ChatMessage returns the message in the room's chat history with
+// the given id, or a boolean (false) if no such message exists
+func (p *Playback) ChatMessage(messageId string) (*client.Response, bool) {
+	return p.chatLog.Message(messageId)
+}
+
+// indexOf returns the index of the message in the log with the given
+// id, or a boolean (false) if no such message exists. The log's mutex
+// must be held by the caller.
+func (l *ChatLog) indexOf(messageId string) (int, bool) {
+	for i, msg := range l.messages {
+		if id, ok := chatMessageId(msg); ok && id == messageId {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// copyChatMessage returns a copy of the given
+// message, including a copy of its extra data
+func copyChatMessage(msg *client.Response) *client.Response {
+	c := *msg
+	c.Extra = make(map[string]interface{}, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		c.Extra[k] = v
+	}
+	return &c
+}
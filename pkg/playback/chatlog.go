@@ -0,0 +1,111 @@
+package playback
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+const (
+	DefaultChatHistorySize = 100 // default number of chat messages kept per room
+)
+
+// ChatHistorySize is the number of chat messages
+// retained by each newly-created room.
+var ChatHistorySize = DefaultChatHistorySize
+
+// ChatHistory is a serializable schema representing
+// a room's most recent chat messages.
+// Implements api.ApiCodec.
+type ChatHistory struct {
+	Messages []*client.Response `json:"messages"`
+}
+
+func (h *ChatHistory) Serialize() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+// ChatLog is a fixed-size ring buffer of a room's most recent
+// chat messages. It is safe for concurrent use.
+type ChatLog struct {
+	mutex    sync.Mutex
+	messages []*client.Response
+	next     int
+	full     bool
+	// reactions holds the ids of the clients who have reacted
+	// to each message, by message id and reaction
+	reactions map[string]map[string]map[string]bool
+}
+
+// Append adds a message to the log, overwriting
+// the oldest message once the log is full.
+func (l *ChatLog) Append(msg *client.Response) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.messages) == 0 {
+		return
+	}
+
+	if id, ok := chatMessageId(l.messages[l.next]); ok {
+		delete(l.reactions, id)
+	}
+
+	l.messages[l.next] = msg
+	l.next = (l.next + 1) % len(l.messages)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Messages returns every message in the log, oldest first
+func (l *ChatLog) Messages() []*client.Response {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.full {
+		messages := make([]*client.Response, l.next)
+		copy(messages, l.messages[:l.next])
+		return messages
+	}
+
+	messages := make([]*client.Response, 0, len(l.messages))
+	messages = append(messages, l.messages[l.next:]...)
+	return append(messages, l.messages[:l.next]...)
+}
+
+// Clear removes every message from the log
+func (l *ChatLog) Clear() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.messages = make([]*client.Response, len(l.messages))
+	l.reactions = make(map[string]map[string]map[string]bool)
+	l.next = 0
+	l.full = false
+}
+
+// RecordChatHistory adds a chat message to the room's chat history. The
+// message's IsSystem field must be set by the server, as it is exported
+// along with the message.
+func (p *Playback) RecordChatHistory(msg *client.Response) {
+	p.chatLog.Append(msg)
+}
+
+// ChatHistory returns the room's most recent chat messages, oldest first
+func (p *Playback) ChatHistory() []*client.Response {
+	return p.chatLog.Messages()
+}
+
+// NewChatLog returns a ChatLog retaining up to the given number of messages
+func NewChatLog(size int) *ChatLog {
+	if size < 0 {
+		size = 0
+	}
+
+	return &ChatLog{
+		messages:  make([]*client.Response, size),
+		reactions: make(map[string]map[string]map[string]bool),
+	}
+}
@@ -0,0 +1,72 @@
+package playback
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestConcurrentClientState(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(p *Playback, poll *Poll, id string)
+	}{
+		{
+			name: "slow mode",
+			action: func(p *Playback, poll *Poll, id string) {
+				p.RecordChatMessage(id)
+			},
+		},
+		{
+			name: "skip votes",
+			action: func(p *Playback, poll *Poll, id string) {
+				p.AddSkipVote(id)
+				p.SkipVotes()
+				p.RemoveSkipVote(id)
+			},
+		},
+		{
+			name: "poll votes",
+			action: func(p *Playback, poll *Poll, id string) {
+				poll.Vote(id, 1)
+				poll.State()
+				poll.RemoveVote(id)
+			},
+		},
+		{
+			name: "bans",
+			action: func(p *Playback, poll *Poll, id string) {
+				p.Ban(id, id)
+				p.IsBanned(id)
+				p.Unban(id)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+			p.SetSlowMode(time.Minute)
+
+			poll, err := NewPoll("question", []string{"yes", "no"}, "creator")
+			if err != nil {
+				t.Fatalf("unexpected error creating poll: %v", err)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+				go func(client int) {
+					defer wg.Done()
+					for j := 0; j < 1000; j++ {
+						tc.action(p, poll, fmt.Sprintf("client-%d-%d", client, j))
+					}
+				}(i)
+			}
+			wg.Wait()
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package playback
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	MaxStreamCountdown = 30 // maximum number of seconds a room may count down before a stream starts
+)
+
+var (
+	DefaultStreamCountdown = 0 // number of seconds new rooms count down before a stream starts
+)
+
+// CountdownCallback is called once a second while a room counts down
+// to the start of a stream, with the number of seconds remaining.
+// It is called a final time with 0 once the stream has started.
+type CountdownCallback func(int)
+
+// streamCountdown tracks a countdown in progress
+type streamCountdown struct {
+	remaining int
+	stopChan  chan bool
+}
+
+// countdownState guards the room's countdown in progress, if any
+type countdownState struct {
+	mutex    sync.Mutex
+	starting *streamCountdown
+}
+
+// SetCountdown sets the number of seconds the room counts
+// down before starting a newly-loaded stream. A value of
+// 0 starts streams immediately.
+func (p *Playback) SetCountdown(seconds int) error {
+	if seconds < 0 || seconds > MaxStreamCountdown {
+		return fmt.Errorf("countdowns must be between 0 and %v seconds", MaxStreamCountdown)
+	}
+
+	p.countdown = seconds
+	return nil
+}
+
+// Countdown returns the number of seconds the room counts
+// down before starting a newly-loaded stream.
+func (p *Playback) Countdown() int {
+	return p.countdown
+}
+
+// StartPlayback plays the current stream after counting down for the
+// room's configured countdown, or immediately if the room has none.
+// The room is "starting", and its timer paused, while the countdown
+// is in progress.
+func (p *Playback) StartPlayback() error {
+	p.cancelCountdown()
+	if p.countdown == 0 {
+		return p.Play()
+	}
+
+	if err := p.timer.Pause(); err != nil {
+		return err
+	}
+
+	c := &streamCountdown{
+		remaining: p.countdown,
+		stopChan:  make(chan bool, 1),
+	}
+
+	p.countdownState.mutex.Lock()
+	p.countdownState.starting = c
+	p.countdownState.mutex.Unlock()
+
+	go runCountdown(p, c)
+	return nil
+}
+
+// IsStarting returns the number of seconds remaining until the current
+// stream starts, or a boolean (false) if no countdown is in progress.
+func (p *Playback) IsStarting() (int, bool) {
+	p.countdownState.mutex.Lock()
+	defer p.countdownState.mutex.Unlock()
+
+	if p.countdownState.starting == nil {
+		return 0, false
+	}
+
+	return p.countdownState.starting.remaining, true
+}
+
+// SkipCountdown ends the countdown in progress and starts the current
+// stream immediately. Returns a boolean (false) if there is no countdown
+// in progress.
+func (p *Playback) SkipCountdown() (bool, error) {
+	if _, starting := p.IsStarting(); !starting {
+		return false, nil
+	}
+
+	p.cancelCountdown()
+	if err := p.Play(); err != nil {
+		return true, err
+	}
+
+	for _, cb := range p.countdownCallbacks {
+		cb(0)
+	}
+	return true, nil
+}
+
+// OnCountdownTick appends a callback to be called every second
+// while the room counts down, and once the stream starts.
+func (p *Playback) OnCountdownTick(callback CountdownCallback) {
+	p.countdownCallbacks = append(p.countdownCallbacks, callback)
+}
+
+// cancelCountdown stops the countdown in progress, if any,
+// without starting the current stream.
+func (p *Playback) cancelCountdown() {
+	p.countdownState.mutex.Lock()
+	defer p.countdownState.mutex.Unlock()
+
+	if p.countdownState.starting == nil {
+		return
+	}
+
+	p.countdownState.starting.stopChan <- true
+	p.countdownState.starting = nil
+}
+
+func runCountdown(p *Playback, c *streamCountdown) {
+	p.countdownState.mutex.Lock()
+	remaining := c.remaining
+	p.countdownState.mutex.Unlock()
+
+	for _, cb := range p.countdownCallbacks {
+		cb(remaining)
+	}
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		p.countdownState.mutex.Lock()
+		if p.countdownState.starting != c {
+			p.countdownState.mutex.Unlock()
+			return
+		}
+
+		c.remaining--
+		remaining := c.remaining
+		if remaining <= 0 {
+			p.countdownState.starting = nil
+		}
+		p.countdownState.mutex.Unlock()
+
+		if remaining <= 0 {
+			p.Play()
+		}
+
+		for _, cb := range p.countdownCallbacks {
+			cb(remaining)
+		}
+
+		if remaining <= 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package playback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestAdvanceQueueCountdown(t *testing.T) {
+	tests := []struct {
+		name           string
+		countdown      int
+		skip           bool
+		expectStarting bool
+	}{
+		{
+			name: "no countdown plays immediately",
+		},
+		{
+			name:           "countdown then play",
+			countdown:      1,
+			expectStarting: true,
+		},
+		{
+			name:           "skipped countdown plays immediately",
+			countdown:      1,
+			skip:           true,
+			expectStarting: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+
+			if err := p.SetCountdown(tc.countdown); err != nil {
+				t.Fatalf("unexpected error setting countdown: %v", err)
+			}
+
+			userQueue := queue.NewAggregatableQueue("user")
+			if err := p.GetQueue().Push(userQueue); err != nil {
+				t.Fatalf("unexpected error pushing user queue: %v", err)
+			}
+			for _, url := range []string{"http://example.com/a.mp4", "http://example.com/b.mp4"} {
+				if err := p.PushToQueue(userQueue, stream.NewRemoteVideoStream(url)); err != nil {
+					t.Fatalf("unexpected error queueing %q: %v", url, err)
+				}
+			}
+
+			// the first stream is playing when the second is advanced to
+			if _, _, err := p.AdvanceQueue(); err != nil {
+				t.Fatalf("unexpected error advancing queue: %v", err)
+			}
+			p.cancelCountdown()
+			if err := p.Play(); err != nil {
+				t.Fatalf("unexpected error playing first stream: %v", err)
+			}
+
+			if _, _, err := p.AdvanceQueue(); err != nil {
+				t.Fatalf("unexpected error advancing queue: %v", err)
+			}
+
+			if _, starting := p.IsStarting(); starting != tc.expectStarting {
+				t.Fatalf("expected room starting: %v, got %v", tc.expectStarting, starting)
+			}
+			if tc.expectStarting && p.timer.State() == TIMER_PLAY {
+				t.Errorf("expected the timer to be paused while counting down")
+			}
+
+			if tc.skip {
+				if skipped, err := p.SkipCountdown(); !skipped || err != nil {
+					t.Fatalf("expected countdown to be skipped, got %v: %v", skipped, err)
+				}
+			}
+
+			deadline := time.Now().Add(3 * time.Second)
+			for {
+				if _, starting := p.IsStarting(); !starting {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("expected countdown to finish")
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			if p.timer.State() != TIMER_PLAY {
+				t.Errorf("expected the timer to be playing once the countdown finished")
+			}
+		})
+	}
+}
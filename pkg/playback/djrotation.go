@@ -0,0 +1,126 @@
+package playback
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+)
+
+// djRotation is a concurrency-safe, ordered list of the clients
+// taking turns contributing the next stream in a room
+type djRotation struct {
+	mutex sync.Mutex
+	djs   []string
+	// turn is the index of the dj contributing the next stream
+	turn int
+}
+
+// JoinDJRotation adds the client with the given id to the end of the
+// room's dj rotation. While the rotation has at least one dj, streams
+// are played only from the djs' queues, one dj at a time, in turn.
+// Returns a boolean (false) if the client is already in the rotation.
+func (p *Playback) JoinDJRotation(id string) bool {
+	p.djs.mutex.Lock()
+	defer p.djs.mutex.Unlock()
+
+	for _, dj := range p.djs.djs {
+		if dj == id {
+			return false
+		}
+	}
+
+	p.djs.djs = append(p.djs.djs, id)
+	return true
+}
+
+// LeaveDJRotation removes the client with the given id from the room's
+// dj rotation. The turn passes to the next dj if it was the client's.
+// Returns a boolean (false) if the client is not in the rotation.
+func (p *Playback) LeaveDJRotation(id string) bool {
+	p.djs.mutex.Lock()
+	defer p.djs.mutex.Unlock()
+
+	for idx, dj := range p.djs.djs {
+		if dj != id {
+			continue
+		}
+
+		p.djs.djs = append(p.djs.djs[:idx], p.djs.djs[idx+1:]...)
+		if idx < p.djs.turn {
+			p.djs.turn--
+		}
+		if p.djs.turn >= len(p.djs.djs) {
+			p.djs.turn = 0
+		}
+		return true
+	}
+
+	return false
+}
+
+// DJRotation returns the ids of the clients in the room's dj
+// rotation, starting with the dj whose turn is next
+func (p *Playback) DJRotation() []string {
+	p.djs.mutex.Lock()
+	defer p.djs.mutex.Unlock()
+
+	djs := make([]string, 0, len(p.djs.djs))
+	djs = append(djs, p.djs.djs[p.djs.turn:]...)
+	return append(djs, p.djs.djs[:p.djs.turn]...)
+}
+
+// NextQueueItem removes and returns the next item to play from the room's
+// queue. If the room has a dj rotation, the item is taken from the queue of
+// the first dj, starting with the dj whose turn it is, with queued streams,
+// and the turn passes to the dj after them. Djs without queued streams are
+// skipped, and queues of clients outside of the rotation are never played.
+// Otherwise, the item is taken from the queue in the order of its queue mode.
+func (p *Playback) NextQueueItem() (queue.QueueItem, error) {
+	p.djs.mutex.Lock()
+	defer p.djs.mutex.Unlock()
+
+	if len(p.djs.djs) == 0 {
+		return p.GetQueue().Next()
+	}
+
+	for i := range p.djs.djs {
+		idx := (p.djs.turn + i) % len(p.djs.djs)
+		djQueue, exists := p.aggregatedQueue(p.djs.djs[idx])
+		if !exists {
+			continue
+		}
+
+		items := djQueue.List()
+		if len(items) == 0 {
+			continue
+		}
+
+		// the listed items share storage with the queue,
+		// which is shifted in place once the item is removed
+		next := items[0]
+		if err := p.GetQueue().DeleteFromQueue(djQueue, next); err != nil {
+			return nil, fmt.Errorf("unable to remove the next item from the queue of dj %q: %v", p.djs.djs[idx], err)
+		}
+
+		p.djs.turn = (idx + 1) % len(p.djs.djs)
+		return next, nil
+	}
+
+	return nil, queue.ErrNoItemsInQueue
+}
+
+// aggregatedQueue returns the aggregated queue in the
+// room's queue with the given id, or false if none exists
+func (p *Playback) aggregatedQueue(id string) (queue.AggregatableQueue, bool) {
+	for _, q := range p.GetQueue().List() {
+		if q.UUID() != id {
+			continue
+		}
+
+		aggQueue, ok := q.(queue.AggregatableQueue)
+		return aggQueue, ok
+	}
+
+	return nil, false
+}
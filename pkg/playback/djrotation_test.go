@@ -0,0 +1,111 @@
+package playback
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestDJRotation(t *testing.T) {
+	p := NewPlayback(connection.NewNamespace("room"))
+
+	// carol joins the rotation without queueing anything,
+	// and dave queues a stream without joining the rotation
+	queued := map[string][]string{
+		"alice": {"http://example.com/a1.mp4", "http://example.com/a2.mp4"},
+		"bob":   {"http://example.com/b1.mp4", "http://example.com/b2.mp4"},
+		"dave":  {"http://example.com/d1.mp4"},
+	}
+	for _, user := range []string{"alice", "bob", "dave"} {
+		userQueue := queue.NewAggregatableQueue(user)
+		if err := p.GetQueue().Push(userQueue); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, url := range queued[user] {
+			if err := p.PushToQueue(userQueue, stream.NewRemoteVideoStream(url)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	for _, dj := range []string{"alice", "bob", "carol"} {
+		if !p.JoinDJRotation(dj) {
+			t.Fatalf("expected %q to join the dj rotation", dj)
+		}
+	}
+	if p.JoinDJRotation("alice") {
+		t.Errorf("expected a dj already in the rotation not to join it again")
+	}
+	if p.LeaveDJRotation("dave") {
+		t.Errorf("expected a client outside of the rotation not to leave it")
+	}
+
+	tests := []struct {
+		name           string
+		leave          string
+		disconnect     string
+		expectStream   string
+		expectRotation []string
+	}{
+		{
+			name:           "the first dj to join plays first",
+			expectStream:   "http://example.com/a1.mp4",
+			expectRotation: []string{"bob", "carol", "alice"},
+		},
+		{
+			name:           "the turn passes to the next dj once a stream ends",
+			expectStream:   "http://example.com/b1.mp4",
+			expectRotation: []string{"carol", "alice", "bob"},
+		},
+		{
+			name:           "djs without queued streams are skipped",
+			expectStream:   "http://example.com/a2.mp4",
+			expectRotation: []string{"bob", "carol", "alice"},
+		},
+		{
+			name:           "a dj disconnecting on their turn passes it on",
+			disconnect:     "bob",
+			expectRotation: []string{"carol", "alice"},
+		},
+		{
+			name:           "queues outside of the rotation are not played",
+			leave:          "carol",
+			expectRotation: []string{"alice"},
+		},
+		{
+			name:         "queues are played once every dj has left",
+			leave:        "alice",
+			expectStream: "http://example.com/d1.mp4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.leave) > 0 && !p.LeaveDJRotation(tc.leave) {
+				t.Fatalf("expected %q to leave the dj rotation", tc.leave)
+			}
+			if len(tc.disconnect) > 0 {
+				p.HandleDisconnection(&idConnection{id: tc.disconnect}, nil, nil)
+			}
+
+			s, loaded, err := p.AdvanceQueue()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tc.expectStream) == 0 {
+				if loaded {
+					t.Fatalf("expected no stream to be loaded, got %q", s.GetStreamURL())
+				}
+			} else if !loaded || s.GetStreamURL() != tc.expectStream {
+				t.Fatalf("expected stream %q to be loaded, got %v", tc.expectStream, s.GetStreamURL())
+			}
+
+			if rotation := p.DJRotation(); strings.Join(rotation, ",") != strings.Join(tc.expectRotation, ",") {
+				t.Errorf("expected dj rotation %v, got %v", tc.expectRotation, rotation)
+			}
+		})
+	}
+}
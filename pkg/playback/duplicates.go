@@ -0,0 +1,83 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// DuplicatePolicy determines whether a stream may be
+// queued if its url has already been queued.
+type DuplicatePolicy string
+
+const (
+	// DUPLICATES_ALLOW allows any stream to be queued more than once
+	DUPLICATES_ALLOW DuplicatePolicy = "allow"
+	// DUPLICATES_CONSECUTIVE rejects a stream if it is the
+	// last item already stored in the same user's queue
+	DUPLICATES_CONSECUTIVE DuplicatePolicy = "consecutive"
+	// DUPLICATES_REJECT rejects a stream if it is already
+	// stored in any user's queue for the room
+	DUPLICATES_REJECT DuplicatePolicy = "reject"
+)
+
+// SetDuplicatePolicy sets the policy used to determine
+// whether a stream may be queued more than once.
+// Returns an error if the given policy is unknown.
+func (p *Playback) SetDuplicatePolicy(policy DuplicatePolicy) error {
+	switch policy {
+	case DUPLICATES_ALLOW, DUPLICATES_CONSECUTIVE, DUPLICATES_REJECT:
+	default:
+		return fmt.Errorf("unknown duplicate policy %q (expected %s, %s, or %s)", policy, DUPLICATES_ALLOW, DUPLICATES_CONSECUTIVE, DUPLICATES_REJECT)
+	}
+
+	p.duplicatePolicy = policy
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// DuplicatePolicy returns the policy used to determine
+// whether a stream may be queued more than once.
+func (p *Playback) DuplicatePolicy() DuplicatePolicy {
+	return p.duplicatePolicy
+}
+
+// checkDuplicate returns an error if the room's duplicate
+// policy forbids pushing the given stream to the given user queue.
+func (p *Playback) checkDuplicate(userQueue queue.AggregatableQueue, s stream.Stream) error {
+	switch p.duplicatePolicy {
+	case DUPLICATES_CONSECUTIVE:
+		items := userQueue.List()
+		if len(items) > 0 && isSameStream(items[len(items)-1], s) {
+			return fmt.Errorf("%q is already the last item in your queue.", s.GetStreamURL())
+		}
+	case DUPLICATES_REJECT:
+		for _, item := range p.GetQueue().List() {
+			aggQueue, ok := item.(queue.AggregatableQueue)
+			if !ok {
+				continue
+			}
+
+			for _, aggQueueItem := range aggQueue.List() {
+				if isSameStream(aggQueueItem, s) {
+					return fmt.Errorf("%q is already in the queue.", s.GetStreamURL())
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSameStream returns true if the given queue item
+// is a stream with the same url as the given stream.
+func isSameStream(item queue.QueueItem, s stream.Stream) bool {
+	queued, ok := item.(stream.Stream)
+	if !ok {
+		return false
+	}
+
+	return queued.GetStreamURL() == s.GetStreamURL()
+}
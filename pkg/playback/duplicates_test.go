@@ -0,0 +1,106 @@
+package playback
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestPushToQueueDuplicates(t *testing.T) {
+	// each push is made to the given user's queue
+	type push struct {
+		user      string
+		url       string
+		expectErr bool
+	}
+
+	tests := []struct {
+		name   string
+		policy DuplicatePolicy
+		pushes []push
+	}{
+		{
+			name: "consecutive duplicates are rejected by default",
+			pushes: []push{
+				{user: "a", url: "http://example.com/1.mp4"},
+				{user: "a", url: "http://example.com/1.mp4", expectErr: true},
+				{user: "a", url: "http://example.com/2.mp4"},
+				{user: "a", url: "http://example.com/1.mp4"},
+				{user: "b", url: "http://example.com/1.mp4"},
+			},
+		},
+		{
+			name:   "consecutive duplicates",
+			policy: DUPLICATES_CONSECUTIVE,
+			pushes: []push{
+				{user: "a", url: "http://example.com/1.mp4"},
+				{user: "b", url: "http://example.com/1.mp4"},
+				{user: "b", url: "http://example.com/1.mp4", expectErr: true},
+			},
+		},
+		{
+			name:   "duplicates allowed",
+			policy: DUPLICATES_ALLOW,
+			pushes: []push{
+				{user: "a", url: "http://example.com/1.mp4"},
+				{user: "a", url: "http://example.com/1.mp4"},
+				{user: "b", url: "http://example.com/1.mp4"},
+			},
+		},
+		{
+			name:   "duplicates rejected across every queue in the room",
+			policy: DUPLICATES_REJECT,
+			pushes: []push{
+				{user: "a", url: "http://example.com/1.mp4"},
+				{user: "a", url: "http://example.com/2.mp4"},
+				{user: "a", url: "http://example.com/1.mp4", expectErr: true},
+				{user: "b", url: "http://example.com/1.mp4", expectErr: true},
+				{user: "b", url: "http://example.com/3.mp4"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+			if len(tc.policy) > 0 {
+				if err := p.SetDuplicatePolicy(tc.policy); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			userQueues := map[string]queue.AggregatableQueue{}
+			for i, push := range tc.pushes {
+				userQueue, exists := userQueues[push.user]
+				if !exists {
+					userQueue = queue.NewAggregatableQueue(push.user)
+					if err := p.GetQueue().Push(userQueue); err != nil {
+						t.Fatalf("unexpected error pushing user queue: %v", err)
+					}
+					userQueues[push.user] = userQueue
+				}
+
+				size := userQueue.Size()
+				err := p.PushToQueue(userQueue, stream.NewRemoteVideoStream(push.url))
+				if push.expectErr != (err != nil) {
+					t.Fatalf("push %v: expected error queueing %q for %q: %v, got %v", i, push.url, push.user, push.expectErr, err)
+				}
+				if push.expectErr && userQueue.Size() != size {
+					t.Errorf("push %v: expected a rejected duplicate not to be queued", i)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown policy", func(t *testing.T) {
+		p := NewPlayback(connection.NewNamespace("room"))
+		if err := p.SetDuplicatePolicy("sometimes"); err == nil {
+			t.Errorf("expected an error setting an unknown duplicate policy")
+		}
+		if policy := p.DuplicatePolicy(); policy != DUPLICATES_CONSECUTIVE {
+			t.Errorf("expected the policy to remain %q, got %q", DUPLICATES_CONSECUTIVE, policy)
+		}
+	})
+}
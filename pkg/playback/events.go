@@ -0,0 +1,24 @@
+package playback
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
+)
+
+// SetEventDispatcher sets the dispatcher notified of the room's events
+func (p *Playback) SetEventDispatcher(d webhook.Dispatcher) {
+	p.events = d
+}
+
+// DispatchEvent notifies the room's event dispatcher, if
+// one is set, of an event of the given type and data.
+func (p *Playback) DispatchEvent(eventType string, data map[string]interface{}) {
+	if p.events == nil {
+		return
+	}
+
+	p.events.Dispatch(webhook.Event{
+		Type: eventType,
+		Room: p.UUID(),
+		Data: data,
+	})
+}
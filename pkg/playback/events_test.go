@@ -0,0 +1,71 @@
+package playback
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
+)
+
+func TestStreamChangedEvent(t *testing.T) {
+	delivered := make(chan webhook.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := webhook.Event{}
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("unexpected error decoding the delivered event: %v", err)
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("expected content type %q, got %q", "application/json", contentType)
+		}
+		delivered <- e
+	}))
+	defer server.Close()
+
+	p := NewPlayback(connection.NewNamespace("room"))
+	p.SetEventDispatcher(webhook.NewHTTPDispatcher([]string{server.URL}, webhook.DefaultQueueSize, 1))
+
+	dj := client.NewClient(&idConnection{id: "dj"})
+	if err := dj.UpdateUsername("dj"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := stream.NewRemoteVideoStream("http://example.com/a.mp4")
+	if err := s.SetInfo([]byte(`{"name": "A", "duration": 60}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Metadata().SetLabelledRef(p.UUID(), dj)
+	p.SetStream(s)
+
+	select {
+	case e := <-delivered:
+		if e.Type != webhook.EVENT_STREAM_CHANGED {
+			t.Fatalf("expected a %q event, got %q", webhook.EVENT_STREAM_CHANGED, e.Type)
+		}
+		if e.Room != "room" {
+			t.Errorf("expected the event to describe room %q, got %q", "room", e.Room)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("expected the event to be timestamped")
+		}
+
+		expected := map[string]interface{}{
+			"url":       "http://example.com/a.mp4",
+			"title":     "A",
+			"kind":      stream.STREAM_TYPE_REMOTE,
+			"startedBy": "dj",
+		}
+		for key, value := range expected {
+			if e.Data[key] != value {
+				t.Errorf("expected event data %q to be %v, got %v", key, value, e.Data[key])
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a %q event to be delivered", webhook.EVENT_STREAM_CHANGED)
+	}
+}
@@ -1,11 +1,15 @@
 package playback
 
 import (
+	"io"
 	"log"
 
+	"github.com/juanvallejo/streaming-server/pkg/logging"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
 )
 
 type PlaybackHandler interface {
@@ -24,6 +28,15 @@ type PlaybackHandler interface {
 	// IsReapable receives a Playback and determines if it is reapable
 	// based on whether or not its corresponding Namespace has any items left
 	IsReapable(*Playback) bool
+	// SaveState writes the state of all composed *Playback objects to the given writer
+	SaveState(io.Writer) error
+	// LoadState reads *Playback states written by SaveState from the given reader
+	LoadState(io.Reader) error
+	// RestorePlayback receives a newly-created *Playback and populates it from any
+	// loaded state for its room. Returns a boolean (false) if no state exists.
+	RestorePlayback(*Playback, *client.Client, stream.StreamHandler) bool
+	// SetEventDispatcher sets the dispatcher notified of every room's events
+	SetEventDispatcher(webhook.Dispatcher)
 }
 
 // Handler implements StreamPlaybackHandler
@@ -33,6 +46,10 @@ type Handler struct {
 	// map of stream ids to Playback objects
 	streamplaybacks  map[string]*Playback
 	namespaceHandler connection.NamespaceHandler
+	// map of room names to loaded Playback states
+	pendingRestores map[string]*PersistedPlayback
+	// events is notified of every room's events, if set
+	events webhook.Dispatcher
 }
 
 func (h *Handler) NewPlayback(ns connection.Namespace, authorizer rbac.Authorizer, clientHandler client.SocketClientHandler) *Playback {
@@ -44,11 +61,14 @@ func (h *Handler) NewPlayback(ns connection.Namespace, authorizer rbac.Authorize
 	}
 
 	h.streamplaybacks[ns.Name()] = s
+	s.SetEventDispatcher(h.events)
+	s.DispatchEvent(webhook.EVENT_ROOM_CREATED, nil)
 	return s
 }
 
 func (h *Handler) ReapPlayback(p *Playback) bool {
 	if sp, exists := h.streamplaybacks[p.name]; exists {
+		sp.DispatchEvent(webhook.EVENT_ROOM_REAPED, nil)
 		sp.Cleanup()
 		delete(h.streamplaybacks, sp.name)
 
@@ -60,6 +80,10 @@ func (h *Handler) ReapPlayback(p *Playback) bool {
 	return false
 }
 
+func (h *Handler) SetEventDispatcher(d webhook.Dispatcher) {
+	h.events = d
+}
+
 func (h *Handler) IsReapable(p *Playback) bool {
 	ns, exists := h.namespaceHandler.NamespaceByName(p.UUID())
 	if !exists {
@@ -100,13 +124,14 @@ func (h *Handler) initGarbageCollector() {
 
 	h.garbageCollector.Init(h)
 	h.isGarbageCollected = true
-	log.Printf("INF PlaybackHandler GarbageCollection started.\n")
+	logging.Default.Infof("PlaybackHandler GarbageCollection started.\n")
 }
 
 func NewHandler(nsHandler connection.NamespaceHandler) PlaybackHandler {
 	return &Handler{
 		namespaceHandler: nsHandler,
 		streamplaybacks:  make(map[string]*Playback),
+		pendingRestores:  make(map[string]*PersistedPlayback),
 	}
 }
 
@@ -115,6 +140,7 @@ func NewGarbageCollectedHandler(nsHandler connection.NamespaceHandler) PlaybackH
 		namespaceHandler: nsHandler,
 		garbageCollector: NewPlaybackReaper(),
 		streamplaybacks:  make(map[string]*Playback),
+		pendingRestores:  make(map[string]*PersistedPlayback),
 	}
 	h.initGarbageCollector()
 	return h
@@ -0,0 +1,102 @@
+package playback
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+const (
+	DefaultPlayHistorySize = 25 // default number of finished streams remembered per room
+)
+
+// PlayHistorySize is the number of finished streams
+// remembered by each newly-created room.
+var PlayHistorySize = DefaultPlayHistorySize
+
+// PlayedStream is a serializable schema describing
+// a stream that has finished playing in a room.
+type PlayedStream struct {
+	Url       string    `json:"url"`
+	Title     string    `json:"title"`
+	StartedBy string    `json:"startedBy"`
+	EndedAt   time.Time `json:"endedAt"`
+}
+
+// PlayHistory is a serializable schema listing a
+// room's most recently played streams, oldest first.
+// Implements api.ApiCodec.
+type PlayHistory struct {
+	Streams []PlayedStream `json:"streams"`
+}
+
+func (h *PlayHistory) Serialize() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+// playHistory is a bounded list of a room's most recently
+// played streams. It is safe for concurrent use.
+type playHistory struct {
+	mutex   sync.Mutex
+	size    int
+	streams []PlayedStream
+}
+
+// append adds a stream to the history, discarding
+// the oldest stream once the history is full.
+func (h *playHistory) append(played PlayedStream) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.size <= 0 {
+		return
+	}
+
+	h.streams = append(h.streams, played)
+	if len(h.streams) > h.size {
+		h.streams = h.streams[len(h.streams)-h.size:]
+	}
+}
+
+func (h *playHistory) list() []PlayedStream {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	streams := make([]PlayedStream, len(h.streams))
+	copy(streams, h.streams)
+	return streams
+}
+
+func (h *playHistory) clear() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.streams = []PlayedStream{}
+}
+
+// recordPlayed adds the given stream, started by the
+// user with the given name, to the room's play history.
+func (p *Playback) recordPlayed(s stream.Stream, startedBy string) {
+	p.history.append(PlayedStream{
+		Url:       s.GetStreamURL(),
+		Title:     s.GetName(),
+		StartedBy: startedBy,
+		EndedAt:   time.Now(),
+	})
+}
+
+// PlayHistory returns the room's most recently played streams, oldest first
+func (p *Playback) PlayHistory() *PlayHistory {
+	return &PlayHistory{
+		Streams: p.history.list(),
+	}
+}
+
+func newPlayHistory(size int) *playHistory {
+	return &playHistory{
+		size:    size,
+		streams: []PlayedStream{},
+	}
+}
@@ -0,0 +1,73 @@
+package playback
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestPlayHistory(t *testing.T) {
+	defer func(size int) { PlayHistorySize = size }(PlayHistorySize)
+
+	urls := []string{}
+	for i := 1; i <= 5; i++ {
+		urls = append(urls, fmt.Sprintf("http://example.com/%v.mp4", i))
+	}
+
+	tests := []struct {
+		name        string
+		historySize int
+		expectUrls  []string
+	}{
+		{
+			name:        "history under the cap",
+			historySize: 10,
+			expectUrls:  urls[:4],
+		},
+		{
+			name:        "history at the cap keeps the most recent streams",
+			historySize: 2,
+			expectUrls:  urls[2:4],
+		},
+		{
+			name:        "history disabled",
+			historySize: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			PlayHistorySize = tc.historySize
+			p := NewPlayback(connection.NewNamespace("room"))
+
+			userQueue := queue.NewAggregatableQueue("user")
+			if err := p.GetQueue().Push(userQueue); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, url := range urls {
+				if err := p.PushToQueue(userQueue, stream.NewRemoteVideoStream(url)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			// every stream but the last is replaced by the next
+			for range urls {
+				if _, _, err := p.AdvanceQueue(); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			played := []string{}
+			for _, s := range p.PlayHistory().Streams {
+				played = append(played, s.Url)
+			}
+			if strings.Join(played, ",") != strings.Join(tc.expectUrls, ",") {
+				t.Errorf("expected history %v, got %v", tc.expectUrls, played)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package playback
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	HypeWindow           = 5 * time.Second // period of time a tap contributes to the hype level
+	HypeDecayInterval    = 1 * time.Second // rate at which expired taps are pruned from the meter
+	MaxHypeTapsPerClient = 10              // max taps counted from a single client per HypeWindow
+	MaxHypeLevel         = 100
+)
+
+type HypeMeterCallback func(int)
+
+type hypeTap struct {
+	id   string
+	time time.Time
+}
+
+// HypeMeter aggregates reaction taps from a room's clients
+// over a sliding window into a single hype level. The level
+// decays as taps fall outside of the window.
+type HypeMeter struct {
+	mux       sync.Mutex
+	taps      []hypeTap
+	level     int
+	decaying  bool
+	callbacks []HypeMeterCallback
+	stopChan  chan bool
+}
+
+// Tap records a tap from the client with the given id.
+// Returns a boolean (false) if the client has exceeded
+// MaxHypeTapsPerClient for the current window.
+func (m *HypeMeter) Tap(id string) bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	now := time.Now()
+	m.prune(now)
+
+	count := 0
+	for _, t := range m.taps {
+		if t.id == id {
+			count++
+		}
+	}
+	if count >= MaxHypeTapsPerClient {
+		return false
+	}
+
+	m.taps = append(m.taps, hypeTap{
+		id:   id,
+		time: now,
+	})
+	m.update()
+
+	if !m.decaying {
+		m.decaying = true
+		m.stopChan = make(chan bool, 1)
+		go decayHype(m, m.stopChan)
+	}
+	return true
+}
+
+// Level returns the current hype level
+func (m *HypeMeter) Level() int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.level
+}
+
+// OnChange appends a callback to be called
+// with the new level whenever the level changes.
+func (m *HypeMeter) OnChange(callback HypeMeterCallback) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// Stop halts the meter's decay loop and discards all taps
+func (m *HypeMeter) Stop() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.decaying {
+		m.decaying = false
+		m.stopChan <- true
+	}
+
+	m.taps = []hypeTap{}
+	m.level = 0
+}
+
+// prune removes taps older than HypeWindow.
+// Must be called with the mutex held.
+func (m *HypeMeter) prune(now time.Time) {
+	idx := 0
+	for idx < len(m.taps) && now.Sub(m.taps[idx].time) > HypeWindow {
+		idx++
+	}
+
+	m.taps = m.taps[idx:]
+}
+
+// update recalculates the level from the aggregated taps and calls
+// the meter's callbacks if the level changed.
+// Must be called with the mutex held.
+func (m *HypeMeter) update() {
+	level := len(m.taps)
+	if level > MaxHypeLevel {
+		level = MaxHypeLevel
+	}
+
+	if level == m.level {
+		return
+	}
+
+	m.level = level
+	for _, cb := range m.callbacks {
+		cb(level)
+	}
+}
+
+func decayHype(m *HypeMeter, stop chan bool) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(HypeDecayInterval):
+		}
+
+		m.mux.Lock()
+		m.prune(time.Now())
+		m.update()
+
+		// nothing left to decay
+		if len(m.taps) == 0 {
+			m.decaying = false
+			m.mux.Unlock()
+			return
+		}
+		m.mux.Unlock()
+	}
+}
+
+func NewHypeMeter() *HypeMeter {
+	return &HypeMeter{
+		taps:      []hypeTap{},
+		callbacks: []HypeMeterCallback{},
+	}
+}
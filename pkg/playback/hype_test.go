@@ -0,0 +1,74 @@
+package playback
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHypeMeter(t *testing.T) {
+	window, interval := HypeWindow, HypeDecayInterval
+	HypeWindow, HypeDecayInterval = 200*time.Millisecond, 20*time.Millisecond
+	defer func() {
+		HypeWindow, HypeDecayInterval = window, interval
+	}()
+
+	tests := []struct {
+		name        string
+		taps        map[string]int
+		expectLevel int
+	}{
+		{
+			name:        "taps from every client are aggregated",
+			taps:        map[string]int{"a": 3, "b": 2, "c": 1},
+			expectLevel: 6,
+		},
+		{
+			name:        "taps beyond the per-client limit are not counted",
+			taps:        map[string]int{"a": MaxHypeTapsPerClient + 5, "b": 1},
+			expectLevel: MaxHypeTapsPerClient + 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewHypeMeter()
+			defer m.Stop()
+
+			mux := sync.Mutex{}
+			levels := []int{}
+			m.OnChange(func(level int) {
+				mux.Lock()
+				defer mux.Unlock()
+				levels = append(levels, level)
+			})
+
+			for id, taps := range tc.taps {
+				for i := 0; i < taps; i++ {
+					if counted := m.Tap(id); counted != (i < MaxHypeTapsPerClient) {
+						t.Fatalf("expected tap %v from client %q to be counted: %v, got %v", i+1, id, i < MaxHypeTapsPerClient, counted)
+					}
+				}
+			}
+
+			if level := m.Level(); level != tc.expectLevel {
+				t.Fatalf("expected hype level %v, got %v", tc.expectLevel, level)
+			}
+
+			// taps fall outside of the window and the level decays
+			deadline := time.Now().Add(2 * time.Second)
+			for m.Level() > 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if level := m.Level(); level != 0 {
+				t.Fatalf("expected the hype level to decay to 0, got %v", level)
+			}
+
+			mux.Lock()
+			defer mux.Unlock()
+			if len(levels) < 2 || levels[len(levels)-2] != tc.expectLevel || levels[len(levels)-1] != 0 {
+				t.Errorf("expected the level to rise to %v and then decay to 0, got changes %v", tc.expectLevel, levels)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package playback
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// interruptedStream is a stream whose playback was
+// interrupted, along with the position it was left at.
+type interruptedStream struct {
+	stream stream.Stream
+	time   int
+}
+
+// Interrupt saves the currently-playing stream and its position
+// and replaces it with the given stream. Once the interrupting
+// stream ends, ResumeInterrupted restores the saved stream.
+// Returns an error if no stream is currently loaded.
+func (p *Playback) Interrupt(s stream.Stream) error {
+	current, exists := p.GetStream()
+	if !exists {
+		return fmt.Errorf("error: no stream is currently loaded for your room - there is nothing to interrupt")
+	}
+
+	p.interrupted = append(p.interrupted, &interruptedStream{
+		stream: current,
+		time:   p.GetTime(),
+	})
+
+	p.SetStream(s)
+	p.Reset()
+
+	// mark the interrupted stream as unreapable until it is resumed
+	current.Metadata().AddParentRef(p)
+	return nil
+}
+
+// ResumeInterrupted restores the most recently interrupted
+// stream at the position it was left at.
+// Returns a boolean (false) if no stream has been interrupted.
+func (p *Playback) ResumeInterrupted() (stream.Stream, bool) {
+	if len(p.interrupted) == 0 {
+		return nil, false
+	}
+
+	last := p.interrupted[len(p.interrupted)-1]
+	p.interrupted = p.interrupted[:len(p.interrupted)-1]
+
+	p.SetStream(last.stream)
+	p.SetTime(last.time)
+	return last.stream, true
+}
+
+// ClearInterrupted discards all interrupted streams
+func (p *Playback) ClearInterrupted() {
+	for _, i := range p.interrupted {
+		i.stream.Metadata().RemoveParentRef(p)
+	}
+
+	p.interrupted = []*interruptedStream{}
+}
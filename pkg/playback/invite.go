@@ -0,0 +1,119 @@
+package playback
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultInviteTTL = 24 * time.Hour // default amount of time an invite token admits clients for
+	inviteTokenSize  = 16             // size in bytes of generated invite tokens
+)
+
+// invite admits clients to a room without its password until
+// it expires or its remaining uses have been consumed.
+type invite struct {
+	expires time.Time
+	// number of clients the invite may still admit; unlimited if zero
+	remaining int
+}
+
+func (i *invite) expired(now time.Time) bool {
+	return !i.expires.IsZero() && now.After(i.expires)
+}
+
+// invites aggregates a room's invite tokens.
+// It is safe for concurrent use.
+type invites struct {
+	mutex   sync.Mutex
+	byToken map[string]*invite
+}
+
+// prune discards expired invites. Callers must hold the mutex.
+func (i *invites) prune(now time.Time) {
+	for token, inv := range i.byToken {
+		if inv.expired(now) {
+			delete(i.byToken, token)
+		}
+	}
+}
+
+// CreateInvite returns a token admitting up to the given number of
+// clients to the room without its password, for the given amount of
+// time. Zero uses allows unlimited clients until the token expires,
+// and a zero ttl keeps the token until its uses are consumed.
+// Returns an error if neither a number of uses nor a ttl is given.
+func (p *Playback) CreateInvite(uses int, ttl time.Duration) (string, error) {
+	if uses < 0 || ttl < 0 {
+		return "", fmt.Errorf("invite uses and duration cannot be negative")
+	}
+	if uses == 0 && ttl == 0 {
+		return "", fmt.Errorf("an invite must be limited to a number of uses, a duration, or both")
+	}
+
+	b := make([]byte, inviteTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate invite token: %v", err)
+	}
+	token := hex.EncodeToString(b)
+
+	inv := &invite{
+		remaining: uses,
+	}
+	if ttl > 0 {
+		inv.expires = time.Now().Add(ttl)
+	}
+
+	p.invites.mutex.Lock()
+	defer p.invites.mutex.Unlock()
+
+	p.invites.prune(time.Now())
+	p.invites.byToken[token] = inv
+	return token, nil
+}
+
+// UseInvite returns true if the given token is a valid invite to the
+// room, consuming one of its uses. Spent and expired invites are discarded.
+func (p *Playback) UseInvite(token string) bool {
+	if len(token) == 0 {
+		return false
+	}
+
+	p.invites.mutex.Lock()
+	defer p.invites.mutex.Unlock()
+
+	p.invites.prune(time.Now())
+
+	inv, exists := p.invites.byToken[token]
+	if !exists {
+		return false
+	}
+
+	if inv.remaining > 0 {
+		inv.remaining--
+		if inv.remaining == 0 {
+			delete(p.invites.byToken, token)
+		}
+	}
+	return true
+}
+
+// InviteCount returns the number of the room's invites still valid
+func (p *Playback) InviteCount() int {
+	p.invites.mutex.Lock()
+	defer p.invites.mutex.Unlock()
+
+	p.invites.prune(time.Now())
+	return len(p.invites.byToken)
+}
+
+// RevokeInvites discards all of the room's invites
+func (p *Playback) RevokeInvites() {
+	p.invites.mutex.Lock()
+	defer p.invites.mutex.Unlock()
+
+	p.invites.byToken = make(map[string]*invite)
+}
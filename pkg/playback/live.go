@@ -0,0 +1,56 @@
+package playback
+
+import (
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// SetDurationOverride caps the current stream at the given number of
+// seconds, after which the room advances to the next stream. This is
+// mostly useful for live streams, which have no fixed duration. An
+// override of zero or less removes any existing override.
+func (p *Playback) SetDurationOverride(seconds float64) {
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	p.durationOverride = seconds
+	p.SetLastUpdated(time.Now())
+}
+
+// DurationOverride returns the number of seconds the current stream
+// has been capped at, or zero if no override has been set.
+func (p *Playback) DurationOverride() float64 {
+	return p.durationOverride
+}
+
+// EndTime returns the playback time (in seconds) at which the current
+// stream ends: its duration override if one has been set, otherwise its
+// duration. Returns a boolean (false) if no stream is loaded, or if the
+// stream's duration is unknown and no override has been set.
+func (p *Playback) EndTime() (float64, bool) {
+	s, exists := p.GetStream()
+	if !exists {
+		return 0, false
+	}
+
+	if p.durationOverride > 0 {
+		return p.durationOverride, true
+	}
+	if s.GetDuration() > 0 {
+		return s.GetDuration(), true
+	}
+
+	return 0, false
+}
+
+// IsLive returns true if the current stream is a live stream
+func (p *Playback) IsLive() bool {
+	s, exists := p.GetStream()
+	if !exists {
+		return false
+	}
+
+	return stream.IsLiveStream(s)
+}
@@ -0,0 +1,72 @@
+package playback
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NowPlaying is a serializable schema describing the
+// stream currently playing in a listed room.
+type NowPlaying struct {
+	Room      string  `json:"room"`
+	Title     string  `json:"title"`
+	Url       string  `json:"url"`
+	Kind      string  `json:"kind"`
+	Thumbnail string  `json:"thumb"`
+	Duration  float64 `json:"duration"`
+	Elapsed   int     `json:"elapsed"`
+	Viewers   int     `json:"viewers"`
+}
+
+// NowPlayingList is a serializable schema describing the
+// streams currently playing across all listed rooms.
+// Implements api.ApiCodec.
+type NowPlayingList struct {
+	Rooms []NowPlaying `json:"rooms"`
+}
+
+func (l *NowPlayingList) Serialize() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// SetListed sets whether the room is publicly listed
+func (p *Playback) SetListed(listed bool) {
+	p.unlisted = !listed
+	p.SetLastUpdated(time.Now())
+}
+
+// Listed returns true if the room is publicly listed
+func (p *Playback) Listed() bool {
+	return !p.unlisted
+}
+
+// ViewerCount returns the most recently sampled
+// number of viewers in the room.
+func (p *Playback) ViewerCount() int {
+	if len(p.viewerSamples) == 0 {
+		return 0
+	}
+
+	return p.viewerSamples[len(p.viewerSamples)-1].Count
+}
+
+// NowPlaying returns a summary of the room's currently-playing
+// stream, or a boolean (false) if the room is unlisted or no
+// stream is currently loaded.
+func (p *Playback) NowPlaying() (NowPlaying, bool) {
+	s, exists := p.GetStream()
+	if !exists || !p.Listed() {
+		return NowPlaying{}, false
+	}
+
+	return NowPlaying{
+		Room:      p.UUID(),
+		Title:     s.GetName(),
+		Url:       s.GetStreamURL(),
+		Kind:      s.GetKind(),
+		Thumbnail: s.GetThumbnail(),
+		Duration:  s.GetDuration(),
+		Elapsed:   p.GetTime(),
+		Viewers:   p.ViewerCount(),
+	}, true
+}
@@ -0,0 +1,49 @@
+package playback
+
+import "time"
+
+// Lock restricts control of the room's stream playback
+// to the connection with the given id.
+func (p *Playback) Lock(id, name string) {
+	p.lockedBy = id
+	p.lockedByName = name
+	p.SetLastUpdated(time.Now())
+}
+
+// Unlock lifts any restriction placed on the
+// room's stream playback controls.
+func (p *Playback) Unlock() {
+	p.lockedBy = ""
+	p.lockedByName = ""
+	p.SetLastUpdated(time.Now())
+}
+
+// LockedBy returns the id and name of the connection that
+// currently holds the playback lock, or a boolean (false)
+// if playback is not currently locked.
+func (p *Playback) LockedBy() (string, string, bool) {
+	return p.lockedBy, p.lockedByName, len(p.lockedBy) > 0
+}
+
+// LockQueue restricts changes to the room's queue
+// to the connection with the given id.
+func (p *Playback) LockQueue(id, name string) {
+	p.queueLockedBy = id
+	p.queueLockedByName = name
+	p.SetLastUpdated(time.Now())
+}
+
+// UnlockQueue lifts any restriction placed
+// on changes to the room's queue.
+func (p *Playback) UnlockQueue() {
+	p.queueLockedBy = ""
+	p.queueLockedByName = ""
+	p.SetLastUpdated(time.Now())
+}
+
+// QueueLockedBy returns the id and name of the connection that
+// currently holds the queue lock, or a boolean (false) if the
+// queue is not currently locked.
+func (p *Playback) QueueLockedBy() (string, string, bool) {
+	return p.queueLockedBy, p.queueLockedByName, len(p.queueLockedBy) > 0
+}
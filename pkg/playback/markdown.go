@@ -0,0 +1,21 @@
+package playback
+
+import "time"
+
+// ChatMarkdownKey is the key of the formatted segments of a chat
+// message's text, stored in the message's extra data when the
+// room renders Markdown and the message contains any.
+const ChatMarkdownKey = "markdown"
+
+// SetMarkdown enables or disables detection of
+// Markdown formatting in the room's chat messages.
+func (p *Playback) SetMarkdown(enabled bool) {
+	p.markdown = enabled
+	p.SetLastUpdated(time.Now())
+}
+
+// Markdown returns true if the room detects
+// Markdown formatting in its chat messages
+func (p *Playback) Markdown() bool {
+	return p.markdown
+}
@@ -0,0 +1,110 @@
+package playback
+
+import (
+	"encoding/json"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+var (
+	QueuePreviewSize = 3 // number of upcoming queue items included in a room's playback status
+)
+
+// QueueItemMetadata is a serializable schema
+// describing a single stream in the room's queue.
+type QueueItemMetadata struct {
+	Id          string  `json:"id"`
+	Url         string  `json:"url"`
+	Title       string  `json:"title"`
+	Kind        string  `json:"kind"`
+	Duration    float64 `json:"duration"`
+	Thumbnail   string  `json:"thumb"`
+	QueuedBy    string  `json:"queuedBy"`
+	FetchStatus string  `json:"fetchStatus"`
+}
+
+// QueueMetadata is a serializable schema describing
+// every stream in the room's queue.
+// Implements api.ApiCodec.
+type QueueMetadata struct {
+	Items []QueueItemMetadata `json:"items"`
+}
+
+func (m *QueueMetadata) Serialize() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// QueueMetadata returns metadata for every stream in the room's queue,
+// grouped by the queue of the client who added each stream.
+func (p *Playback) QueueMetadata(clientHandler client.SocketClientHandler) *QueueMetadata {
+	userQueues := p.GetQueue().List()
+	metadata := &QueueMetadata{
+		Items: make([]QueueItemMetadata, 0, len(userQueues)*queue.MaxAggregatableQueueItems),
+	}
+
+	for _, q := range userQueues {
+		userQueue, ok := q.(queue.AggregatableQueue)
+		if !ok {
+			continue
+		}
+
+		queuedBy := userQueue.UUID()
+		if c, err := clientHandler.GetClient(userQueue.UUID()); err == nil {
+			queuedBy = c.GetUsernameOrId()
+		}
+
+		for _, item := range userQueue.List() {
+			s, ok := item.(stream.Stream)
+			if !ok {
+				continue
+			}
+
+			metadata.Items = append(metadata.Items, QueueItemMetadata{
+				Id:          s.UUID(),
+				Url:         s.GetStreamURL(),
+				Title:       s.GetName(),
+				Kind:        s.GetKind(),
+				Duration:    s.GetDuration(),
+				Thumbnail:   s.GetThumbnail(),
+				QueuedBy:    queuedBy,
+				FetchStatus: s.Metadata().GetFetchStatus(),
+			})
+		}
+	}
+
+	return metadata
+}
+
+// UpNext returns metadata for the next QueuePreviewSize streams
+// that will be played from the room's queue, in order.
+func (p *Playback) UpNext() []QueueItemMetadata {
+	upNext := []QueueItemMetadata{}
+	for _, item := range p.GetQueue().Peek(QueuePreviewSize) {
+		s, ok := item.(stream.Stream)
+		if !ok {
+			continue
+		}
+
+		queuedBy := s.Metadata().GetCreationSource().GetSourceName()
+		if ref, exists := s.Metadata().GetLabelledRef(p.UUID()); exists {
+			if c, ok := ref.(*client.Client); ok {
+				queuedBy = c.GetUsernameOrId()
+			}
+		}
+
+		upNext = append(upNext, QueueItemMetadata{
+			Id:          s.UUID(),
+			Url:         s.GetStreamURL(),
+			Title:       s.GetName(),
+			Kind:        s.GetKind(),
+			Duration:    s.GetDuration(),
+			Thumbnail:   s.GetThumbnail(),
+			QueuedBy:    queuedBy,
+			FetchStatus: s.Metadata().GetFetchStatus(),
+		})
+	}
+
+	return upNext
+}
@@ -0,0 +1,41 @@
+package playback
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/metrics"
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+)
+
+var streamsStarted = metrics.NewCounter("streaming_streams_started_total", "Number of streams loaded into rooms.")
+
+func init() {
+	metrics.MustRegister(streamsStarted)
+}
+
+// RegisterMetrics adds gauges reporting the number of active
+// rooms and the length of each room's queue to the given registry.
+func RegisterMetrics(registry *metrics.Registry, handler PlaybackHandler) error {
+	return registry.Register(
+		metrics.NewGaugeFunc("streaming_rooms", "Number of active rooms.", func() float64 {
+			return float64(len(handler.Playbacks()))
+		}),
+		metrics.NewGaugeVecFunc("streaming_queue_length", "Number of items queued in each active room.", "room", func() map[string]float64 {
+			lengths := make(map[string]float64)
+			for _, p := range handler.Playbacks() {
+				lengths[p.UUID()] = float64(queuedItems(p))
+			}
+			return lengths
+		}),
+	)
+}
+
+// queuedItems returns the number of streams queued
+// by every client in the given room.
+func queuedItems(p *Playback) int {
+	count := 0
+	for _, q := range p.GetQueue().List() {
+		if userQueue, ok := q.(queue.AggregatableQueue); ok {
+			count += userQueue.Size()
+		}
+	}
+	return count
+}
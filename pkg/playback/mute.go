@@ -0,0 +1,98 @@
+package playback
+
+import (
+	"sync"
+	"time"
+)
+
+// mute describes a client whose chat messages are being suppressed
+type mute struct {
+	name  string
+	until time.Time
+	timer *time.Timer
+}
+
+// mutes is a concurrency-safe set of muted client addresses
+type mutes struct {
+	mutex     sync.Mutex
+	byAddress map[string]*mute
+}
+
+// Mute suppresses chat messages from clients connecting from the given
+// address for the given duration, or until unmuted if the duration is
+// zero. The given name is recorded for display.
+func (p *Playback) Mute(address, name string, duration time.Duration) {
+	p.mutes.mutex.Lock()
+	defer p.mutes.mutex.Unlock()
+
+	if existing, exists := p.mutes.byAddress[address]; exists && existing.timer != nil {
+		existing.timer.Stop()
+	}
+
+	m := &mute{
+		name: name,
+	}
+	if duration > 0 {
+		m.until = time.Now().Add(duration)
+		m.timer = time.AfterFunc(duration, func() {
+			p.mutes.mutex.Lock()
+			defer p.mutes.mutex.Unlock()
+
+			if current, exists := p.mutes.byAddress[address]; exists && current == m {
+				delete(p.mutes.byAddress, address)
+			}
+		})
+	}
+
+	p.mutes.byAddress[address] = m
+}
+
+// Unmute lifts a mute placed on the client with the given name.
+// Returns a boolean (false) if no such mute exists.
+func (p *Playback) Unmute(name string) bool {
+	p.mutes.mutex.Lock()
+	defer p.mutes.mutex.Unlock()
+
+	for address, m := range p.mutes.byAddress {
+		if m.name == name {
+			if m.timer != nil {
+				m.timer.Stop()
+			}
+			delete(p.mutes.byAddress, address)
+			return true
+		}
+	}
+
+	return false
+}
+
+// MutedFor returns the remaining duration of the mute placed on clients
+// connecting from the given address, or zero if the mute does not expire.
+// Returns a boolean (false) if no such mute exists.
+func (p *Playback) MutedFor(address string) (time.Duration, bool) {
+	p.mutes.mutex.Lock()
+	defer p.mutes.mutex.Unlock()
+
+	m, exists := p.mutes.byAddress[address]
+	if !exists {
+		return 0, false
+	}
+	if m.until.IsZero() {
+		return 0, true
+	}
+
+	return time.Until(m.until), true
+}
+
+// ClearMutes lifts every mute placed in the room
+func (p *Playback) ClearMutes() {
+	p.mutes.mutex.Lock()
+	defer p.mutes.mutex.Unlock()
+
+	for address, m := range p.mutes.byAddress {
+		if m.timer != nil {
+			m.timer.Stop()
+		}
+		delete(p.mutes.byAddress, address)
+	}
+}
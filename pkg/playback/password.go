@@ -0,0 +1,61 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHashCost is the bcrypt cost used to hash room passwords
+var PasswordHashCost = bcrypt.DefaultCost
+
+// roomPassword is a bcrypt hash of a room's password
+type roomPassword struct {
+	hash []byte
+}
+
+// SetPassword requires clients to supply the given password in order
+// to join the room. Only a bcrypt hash of the password is stored.
+// Returns an error if the password is empty or too long to be hashed.
+func (p *Playback) SetPassword(password string) error {
+	if len(password) == 0 {
+		return fmt.Errorf("a room password cannot be empty")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordHashCost)
+	if err == bcrypt.ErrPasswordTooLong {
+		return fmt.Errorf("a room password cannot be longer than 72 bytes")
+	}
+	if err != nil {
+		return fmt.Errorf("unable to hash room password: %v", err)
+	}
+
+	p.password = &roomPassword{
+		hash: hash,
+	}
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// ClearPassword allows any client to join the room
+func (p *Playback) ClearPassword() {
+	p.password = nil
+	p.SetLastUpdated(time.Now())
+}
+
+// HasPassword returns true if clients must
+// supply a password in order to join the room
+func (p *Playback) HasPassword() bool {
+	return p.password != nil
+}
+
+// CheckPassword returns true if the room has no password,
+// or if the given password matches the room's password.
+func (p *Playback) CheckPassword(password string) bool {
+	if p.password == nil {
+		return true
+	}
+
+	return bcrypt.CompareHashAndPassword(p.password.hash, []byte(password)) == nil
+}
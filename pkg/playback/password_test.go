@@ -0,0 +1,71 @@
+package playback
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCheckPassword(t *testing.T) {
+	cost := PasswordHashCost
+	PasswordHashCost = bcrypt.MinCost
+	defer func() { PasswordHashCost = cost }()
+
+	tests := []struct {
+		name      string
+		password  string
+		attempt   string
+		expectSet bool
+		expectOk  bool
+	}{
+		{
+			name:      "matching password is admitted",
+			password:  "hunter2",
+			attempt:   "hunter2",
+			expectSet: true,
+			expectOk:  true,
+		},
+		{
+			name:      "wrong password is rejected",
+			password:  "hunter2",
+			attempt:   "hunter3",
+			expectSet: true,
+		},
+		{
+			name:      "missing password is rejected",
+			password:  "hunter2",
+			expectSet: true,
+		},
+		{
+			name:     "room without password admits anyone",
+			attempt:  "anything",
+			expectOk: true,
+		},
+		{
+			name:     "overlong password is refused",
+			password: strings.Repeat("a", 73),
+			attempt:  strings.Repeat("a", 73),
+			expectOk: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+			if len(tc.password) > 0 {
+				err := p.SetPassword(tc.password)
+				if tc.expectSet != (err == nil) {
+					t.Fatalf("expected password set: %v, got error %v", tc.expectSet, err)
+				}
+			}
+			if p.HasPassword() != tc.expectSet {
+				t.Errorf("expected room to require a password: %v", tc.expectSet)
+			}
+			if ok := p.CheckPassword(tc.attempt); ok != tc.expectOk {
+				t.Errorf("expected password %q admitted: %v, got %v", tc.attempt, tc.expectOk, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package playback
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/juanvallejo/streaming-server/pkg/logging"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// PersistedPlayback is a serializable schema representing the
+// state of a Playback, used to restore rooms across server restarts.
+type PersistedPlayback struct {
+	Name      string       `json:"name"`
+	StartedBy string       `json:"startedBy"`
+	Timer     *TimerStatus `json:"timer"`
+	Snapshot  *Snapshot    `json:"snapshot"`
+	Topic     *RoomTopic   `json:"topic,omitempty"`
+}
+
+// Persist returns the room's current state
+func (p *Playback) Persist() *PersistedPlayback {
+	return &PersistedPlayback{
+		Name:      p.UUID(),
+		StartedBy: p.startedBy,
+		Timer:     p.timer.Snapshot(),
+		Snapshot:  p.Snapshot(),
+		Topic:     p.Topic(),
+	}
+}
+
+// Restore populates the room from a persisted state. Queued streams are
+// added to the given user's queue. Streams that can no longer be loaded
+// are skipped.
+func (p *Playback) Restore(state *PersistedPlayback, user *client.Client, streamHandler stream.StreamHandler) {
+	if state.Topic != nil {
+		p.topic = *state.Topic
+	}
+
+	if state.Snapshot != nil {
+		if err := p.LoadSnapshot(state.Snapshot, user, streamHandler); err != nil {
+			p.logger.Warnf("PLAYBACK RESTORE unable to restore stream for room %q, skipping: %v\n", p.UUID(), err)
+		}
+	}
+
+	if _, exists := p.GetStream(); !exists {
+		return
+	}
+
+	p.UpdateStartedBy(state.StartedBy)
+	if state.Timer != nil && state.Timer.IsPlaying {
+		p.Play()
+	}
+}
+
+// RestoreUserQueue pushes the given streams onto the given user's queue,
+// such as when a user resumes a previous session. Streams that can no
+// longer be queued are skipped.
+func (p *Playback) RestoreUserQueue(user *client.Client, streams []stream.Stream) {
+	for _, s := range streams {
+		if err := p.requeue(user, s); err != nil {
+			p.logger.Warnf("PLAYBACK RESTORE unable to restore queued stream %q for client %q, skipping: %v\n", s.GetStreamURL(), user.UUID(), err)
+		}
+	}
+}
+
+// SaveState writes the state of every Playback
+// aggregated by the handler to the given writer.
+func (h *Handler) SaveState(w io.Writer) error {
+	states := []*PersistedPlayback{}
+	for _, p := range h.streamplaybacks {
+		states = append(states, p.Persist())
+	}
+
+	// retain any states that have not yet been restored
+	for name, state := range h.pendingRestores {
+		if _, exists := h.streamplaybacks[name]; !exists {
+			states = append(states, state)
+		}
+	}
+
+	return json.NewEncoder(w).Encode(states)
+}
+
+// LoadState reads Playback states written by SaveState from the
+// given reader. Each state is restored once a Playback is next
+// created for the room it belongs to.
+func (h *Handler) LoadState(r io.Reader) error {
+	states := []*PersistedPlayback{}
+	if err := json.NewDecoder(r).Decode(&states); err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		if len(state.Name) == 0 {
+			continue
+		}
+		h.pendingRestores[state.Name] = state
+	}
+
+	logging.Default.Infof("PLAYBACK RESTORE loaded saved state for %v rooms\n", len(h.pendingRestores))
+	return nil
+}
+
+// RestorePlayback populates the given Playback from any
+// state loaded for its room, using the given user as the
+// owner of restored queue items.
+// Returns a boolean (false) if no state exists for the room.
+func (h *Handler) RestorePlayback(p *Playback, user *client.Client, streamHandler stream.StreamHandler) bool {
+	state, exists := h.pendingRestores[p.UUID()]
+	if !exists {
+		return false
+	}
+
+	delete(h.pendingRestores, p.UUID())
+	p.Restore(state, user, streamHandler)
+	return true
+}
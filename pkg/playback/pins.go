@@ -0,0 +1,116 @@
+package playback
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+const (
+	DefaultMaxPinnedMessages = 1 // default number of chat messages that may be pinned in a room at once
+
+	// ChatPinnedByKey is the key of the name of the user who
+	// pinned a chat message, stored in the message's extra data
+	ChatPinnedByKey = "pinnedBy"
+)
+
+// MaxPinnedMessages is the number of chat messages that may be pinned
+// in a room at once. Pinning a message once the limit has been reached
+// replaces the oldest pinned message.
+var MaxPinnedMessages = DefaultMaxPinnedMessages
+
+// PinnedMessages is a serializable schema representing
+// a room's pinned chat messages, oldest first.
+// Implements api.ApiCodec.
+type PinnedMessages struct {
+	Messages []*client.Response `json:"messages"`
+}
+
+func (m *PinnedMessages) Serialize() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// PinChatMessage pins the message in the room's chat history with the given
+// id on behalf of the user with the given name. If MaxPinnedMessages are
+// already pinned, the oldest pinned message is unpinned and returned.
+// Returns an error if no such message exists, or if it is already pinned.
+func (p *Playback) PinChatMessage(messageId, pinnedBy string) (*client.Response, error) {
+	if MaxPinnedMessages < 1 {
+		return nil, fmt.Errorf("error: pinning messages is disabled")
+	}
+	if p.IsPinned(messageId) {
+		return nil, fmt.Errorf("error: that message is already pinned")
+	}
+
+	msg, exists := p.ChatMessage(messageId)
+	if !exists {
+		return nil, fmt.Errorf("error: unable to find that message in the chat history")
+	}
+
+	pinned := copyChatMessage(msg)
+	pinned.Extra[ChatPinnedByKey] = pinnedBy
+
+	var replaced *client.Response
+	if len(p.pinned) >= MaxPinnedMessages {
+		replaced = p.pinned[0]
+		p.pinned = p.pinned[1:]
+	}
+
+	p.pinned = append(p.pinned, pinned)
+	return replaced, nil
+}
+
+// UnpinChatMessage unpins the message with the given id, or every
+// pinned message if no id is given. Returns a boolean (false) if
+// no matching message was pinned.
+func (p *Playback) UnpinChatMessage(messageId string) bool {
+	if len(messageId) == 0 {
+		unpinned := len(p.pinned) > 0
+		p.pinned = []*client.Response{}
+		return unpinned
+	}
+
+	for idx, msg := range p.pinned {
+		if id, _ := chatMessageId(msg); id == messageId {
+			p.pinned = append(p.pinned[:idx:idx], p.pinned[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsPinned returns true if the message with the given id is pinned
+func (p *Playback) IsPinned(messageId string) bool {
+	for _, msg := range p.pinned {
+		if id, _ := chatMessageId(msg); id == messageId {
+			return true
+		}
+	}
+	return false
+}
+
+// PinnedMessages returns the room's pinned chat messages, oldest first
+func (p *Playback) PinnedMessages() *PinnedMessages {
+	messages := make([]*client.Response, len(p.pinned))
+	copy(messages, p.pinned)
+	return &PinnedMessages{
+		Messages: messages,
+	}
+}
+
+// updatePinnedMessage replaces the pinned copy of the given
+// message, if pinned, with a copy of its current version
+func (p *Playback) updatePinnedMessage(msg *client.Response) {
+	messageId, _ := chatMessageId(msg)
+	for idx, pinned := range p.pinned {
+		if id, _ := chatMessageId(pinned); id != messageId {
+			continue
+		}
+
+		updated := copyChatMessage(msg)
+		updated.Extra[ChatPinnedByKey] = pinned.Extra[ChatPinnedByKey]
+		p.pinned[idx] = updated
+		return
+	}
+}
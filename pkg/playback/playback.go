@@ -3,16 +3,18 @@ package playback
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
 	api "github.com/juanvallejo/streaming-server/pkg/api/types"
+	"github.com/juanvallejo/streaming-server/pkg/logging"
 	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
 	"github.com/juanvallejo/streaming-server/pkg/playback/util"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
 )
 
 const (
@@ -52,13 +54,60 @@ type Playback struct {
 	timer              *Timer
 	lastUpdated        time.Time
 	lastAdminDeparture time.Time
+	chapters           []Chapter
+	lockedBy           string
+	lockedByName       string
+	queueLockedBy      string
+	queueLockedByName  string
+	presentation       bool
+	unlisted           bool
+	slowMode           *slowMode
+	skipVotes          *skipVotes
+	viewerSamples      []ViewerSample
+	interrupted        []*interruptedStream
+	hypeMeter          *HypeMeter
+	chatLog            *ChatLog
+	pinned             []*client.Response
+	history            *playHistory
+	audit              *auditLog
+	maxQueueItems      int
+	duplicatePolicy    DuplicatePolicy
+	durationOverride   float64
+	repeatMode         RepeatMode
+	volume             int
+	hasVolume          bool
+	subtitleTrack      string
+	bans               *bans
+	mutes              *mutes
+	djs                *djRotation
+	password           *roomPassword
+	invites            *invites
+	capacity           int
+	topic              RoomTopic
+	quietStreams       bool
+	markdown           bool
+	wordFilter         *wordFilter
+	poll               *Poll
+	pollMutex          sync.Mutex
+	scheduled          *scheduledStream
+	scheduledMutex     sync.Mutex
+	scheduleCallbacks  []ScheduleCallback
+	countdown          int
+	countdownState     countdownState
+	countdownCallbacks []CountdownCallback
+	bufferPaused       bool
 
 	// State indicates the current state of the
 	// room's Playback
 	state PlaybackState
+
+	logger logging.Logger
+	// events is notified of the room's events, if set
+	events webhook.Dispatcher
 }
 
-// Cleanup handles resource cleanup for room resources
+// Cleanup handles resource cleanup for room resources. The room's timer
+// is closed, so none of its tick callbacks fire once Cleanup returns.
 func (p *Playback) Cleanup() {
 	// remove room ref from the current stream
 	if p.stream != nil {
@@ -70,13 +119,30 @@ func (p *Playback) Cleanup() {
 		p.adminPicker.Stop()
 	}
 
-	p.timer.Stop()
-	p.timer.callbacks = []TimerCallback{}
-	p.timer = nil
+	p.hypeMeter.Stop()
+	p.UnscheduleStream()
+	p.scheduleCallbacks = []ScheduleCallback{}
+	p.cancelCountdown()
+	p.countdownCallbacks = []CountdownCallback{}
+
+	p.timer.Close()
 	p.ClearQueue()
+	p.ClearInterrupted()
+	p.ClearViewerHistory()
+	p.chatLog.Clear()
+	p.UnpinChatMessage("")
+	p.history.clear()
+	p.audit.clear()
+	p.ClearMutes()
+	p.RevokeInvites()
 	p.stream = nil
 }
 
+// SetLogger sets the logger used by the room
+func (p *Playback) SetLogger(logger logging.Logger) {
+	p.logger = logger
+}
+
 func (p *Playback) UUID() string {
 	return p.name
 }
@@ -108,6 +174,26 @@ func (p *Playback) HandleDisconnection(conn connection.Connection, authorizer rb
 		p.queueHandler.Queue().DeleteItem(queueItemToDelete)
 	}
 
+	// release any locks held by the departing connection
+	if conn != nil {
+		p.RemoveSkipVote(conn.UUID())
+		p.LeaveDJRotation(conn.UUID())
+		if poll, exists := p.Poll(); exists {
+			poll.RemoveVote(conn.UUID())
+		}
+		if lockedBy, _, locked := p.LockedBy(); locked && lockedBy == conn.UUID() {
+			p.Unlock()
+		}
+		if lockedBy, _, locked := p.QueueLockedBy(); locked && lockedBy == conn.UUID() {
+			p.UnlockQueue()
+		}
+		if p.presentation {
+			if _, _, locked := p.LockedBy(); !locked {
+				p.SetPresentationMode(false, "", "")
+			}
+		}
+	}
+
 	if authorizer == nil || conn == nil {
 		return
 	}
@@ -163,24 +249,43 @@ func (p *Playback) RefreshInfoFromClient(c *client.Client) bool {
 }
 
 func (p *Playback) Pause() error {
+	p.cancelCountdown()
+	p.bufferPaused = false
 	p.SetLastUpdated(time.Now())
 	return p.timer.Pause()
 }
 
 func (p *Playback) Play() error {
+	p.cancelCountdown()
+	p.bufferPaused = false
 	p.SetState(PLAYBACK_STATE_STARTED)
 	p.SetLastUpdated(time.Now())
 	return p.timer.Play()
 }
 
 func (p *Playback) Stop() error {
+	p.cancelCountdown()
+	p.bufferPaused = false
 	p.SetState(PLAYBACK_STATE_ENDED)
 	p.SetLastUpdated(time.Now())
 	return p.timer.Stop()
 }
 
+// StopTimer permanently stops the room's playback timer. The room's
+// state is kept, but its stream no longer advances.
+func (p *Playback) StopTimer() {
+	p.cancelCountdown()
+	p.timer.Close()
+}
+
 func (p *Playback) Reset() error {
 	p.SetLastUpdated(time.Now())
+
+	// trimmed streams begin playback at the start of their segment
+	if trimmed, ok := p.stream.(*stream.TrimmedStream); ok {
+		return p.timer.Set(trimmed.GetStart())
+	}
+
 	return p.timer.Set(0)
 }
 
@@ -206,6 +311,11 @@ func (p *Playback) SetLastUpdated(t time.Time) {
 	p.lastUpdated = t
 }
 
+// HypeMeter returns the room's reaction hype meter
+func (p *Playback) HypeMeter() *HypeMeter {
+	return p.hypeMeter
+}
+
 // OnTick calls the playback object's timer object and sets its
 // "tick" callback function; called every tick increment interval.
 func (p *Playback) OnTick(callback TimerCallback) {
@@ -254,12 +364,22 @@ func (p *Playback) GetQueue() queue.RoundRobinQueue {
 
 // PushUserQueue pushes a stream to the queue belonging to the given user
 // and adds the Playback object as the parentRef to the pushed stream.
+// Returns an error if the user's queue is at the room's queue limit, or
+// if the room's duplicate policy forbids queueing the stream.
 func (p *Playback) PushToQueue(userQueue queue.AggregatableQueue, s stream.Stream) error {
-	if err := p.queueHandler.PushToQueue(userQueue, s); err == nil {
-		// mark stream as unreapable while it is aggregated in the queue
-		if !s.Metadata().AddParentRef(p) {
-			log.Printf("INF SOCKET CLIENT duplicate attempt to set parent ref %q to stream %q\n", p.UUID(), s.UUID())
-		}
+	if err := p.checkQueueLimit(userQueue); err != nil {
+		return err
+	}
+	if err := p.checkDuplicate(userQueue, s); err != nil {
+		return err
+	}
+	if err := p.queueHandler.PushToQueue(userQueue, s); err != nil {
+		return err
+	}
+
+	// mark stream as unreapable while it is aggregated in the queue
+	if !s.Metadata().AddParentRef(p) {
+		p.logger.Infof("SOCKET CLIENT duplicate attempt to set parent ref %q to stream %q\n", p.UUID(), s.UUID())
 	}
 	return nil
 }
@@ -274,17 +394,48 @@ func (p *Playback) ClearQueueItem(userQueue queue.AggregatableQueue, qi queue.Qu
 
 	s, ok := qi.(stream.Stream)
 	if !ok {
-		log.Printf("INF SOCKET CLIENT unable to remove parent ref %q from QueueItem %q: does not implement stream.Stream", p.UUID(), s.UUID())
+		p.logger.Infof("SOCKET CLIENT unable to remove parent ref %q from QueueItem %q: does not implement stream.Stream", p.UUID(), s.UUID())
 		return nil
 	}
 
 	if !s.Metadata().RemoveParentRef(p) {
-		log.Printf("INF SOCKET CLIENT unable to remove parent ref %q from stream %q\n", p.UUID(), s.UUID())
+		p.logger.Infof("SOCKET CLIENT unable to remove parent ref %q from stream %q\n", p.UUID(), s.UUID())
 	}
 
 	return nil
 }
 
+// QueueItemById returns the pending queue item with the given id, along
+// with the user queue it was pushed to, or a bool (false) if no pending
+// item exists with that id.
+func (p *Playback) QueueItemById(itemId string) (queue.AggregatableQueue, queue.QueueItem, bool) {
+	for _, item := range p.GetQueue().List() {
+		userQueue, ok := item.(queue.AggregatableQueue)
+		if !ok {
+			continue
+		}
+
+		for _, userQueueItem := range userQueue.List() {
+			if userQueueItem.UUID() == itemId {
+				return userQueue, userQueueItem, true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+// RemoveQueueItemById removes a single pending item with the given id
+// from the queue. Returns an error if no pending item exists with that id.
+func (p *Playback) RemoveQueueItemById(itemId string) error {
+	userQueue, item, exists := p.QueueItemById(itemId)
+	if !exists {
+		return fmt.Errorf("no item with id %q was found in the queue", itemId)
+	}
+
+	return p.ClearQueueItem(userQueue, item)
+}
+
 // GetStream returns a stream.Stream object containing current stream data
 // tied to the current Playback object, or a bool (false) if there
 // is no stream information currently loaded for the current Playback
@@ -295,6 +446,8 @@ func (p *Playback) GetStream() (stream.Stream, bool) {
 // SetStream receives a stream.Stream and sets it as the currently-playing stream
 func (p *Playback) SetStream(s stream.Stream) {
 	if p.stream != nil {
+		p.recordPlayed(p.stream, p.startedBy)
+
 		// remove Playback object from list of current stream's refs
 		p.stream.Metadata().RemoveParentRef(p)
 		p.stream.Metadata().RemoveLabelledRef(p.UUID())
@@ -307,13 +460,27 @@ func (p *Playback) SetStream(s stream.Stream) {
 			p.UpdateStartedBy(u.GetUsernameOrId())
 		}
 	} else {
-		log.Printf("INF PLAYBACK unable to find labelled client reference for room with id %v\n", p.UUID())
+		p.logger.Infof("PLAYBACK unable to find labelled client reference for room with id %v\n", p.UUID())
 		p.UpdateStartedBy("<unknown>")
 	}
 
+	p.cancelCountdown()
+	p.bufferPaused = false
 	p.stream = s
 	p.stream.Metadata().SetLastUpdated(time.Now())
+	p.ClearChapters()
+	p.ClearSkipVotes()
+	p.ClearSubtitleTrack()
+	p.SetDurationOverride(0)
 	p.SetLastUpdated(time.Now())
+	streamsStarted.Inc()
+
+	p.DispatchEvent(webhook.EVENT_STREAM_CHANGED, map[string]interface{}{
+		"url":       s.GetStreamURL(),
+		"title":     s.GetName(),
+		"kind":      s.GetKind(),
+		"startedBy": p.startedBy,
+	})
 }
 
 // GetOrCreateStreamFromUrl receives a stream location (path, url, or unique identifier)
@@ -322,9 +489,20 @@ func (p *Playback) SetStream(s stream.Stream) {
 // newly-created stream.
 func (p *Playback) GetOrCreateStreamFromUrl(url string, user *client.Client, streamHandler stream.StreamHandler, callback PlaybackStreamMetadataCallback) (stream.Stream, error) {
 	if s, exists := streamHandler.GetStream(url); exists {
-		log.Printf("INF PLAYBACK found existing stream object with url %q, retrieving...", url)
+		if stream.IsUnplayable(s) {
+			return nil, fmt.Errorf("error: %v", stream.ErrStreamUnavailable)
+		}
+
+		p.logger.Infof("PLAYBACK found existing stream object with url %q, retrieving...", url)
 		callback([]byte{}, false, nil)
 
+		// refresh the existing stream's info in the background
+		// if its metadata has outlived the cache ttl.
+		if streamHandler.MetadataExpired(s) {
+			p.logger.Infof("PLAYBACK metadata for stream with url %q has expired; refetching...", url)
+			p.fetchStreamMetadata(s, streamHandler, func(data []byte, created bool, err error) {})
+		}
+
 		// determine if a labelled reference has already
 		// been set for the room - only return an error
 		// if the labelled ref still has the stream
@@ -371,24 +549,49 @@ func (p *Playback) GetOrCreateStreamFromUrl(url string, user *client.Client, str
 	s.Metadata().SetLabelledRef(p.UUID(), user)
 
 	// if created new stream, fetch its duration info
-	s.FetchMetadata(func(s stream.Stream, data []byte, err error) {
+	p.fetchStreamMetadata(s, streamHandler, callback)
+
+	p.logger.Infof("PLAYBACK no stream found with url %q; creating... There are now %v registered streams", url, streamHandler.GetSize())
+	return s, nil
+}
+
+// RefreshStreamMetadata discards any cached metadata for the given stream
+// and fetches it again, re-applying the stream's info. The given callback
+// receives the result once the fetch completes.
+func (p *Playback) RefreshStreamMetadata(s stream.Stream, streamHandler stream.StreamHandler, callback PlaybackStreamMetadataCallback) {
+	p.logger.Infof("PLAYBACK refreshing metadata for stream with url %q...", s.GetStreamURL())
+	streamHandler.InvalidateMetadata(s)
+	p.fetchStreamMetadata(s, streamHandler, callback)
+}
+
+// fetchStreamMetadata requests metadata for the given stream through the
+// stream handler, reusing cached metadata when available, and stores the
+// parsed info on the stream. The given callback receives the result.
+func (p *Playback) fetchStreamMetadata(s stream.Stream, streamHandler stream.StreamHandler, callback PlaybackStreamMetadataCallback) {
+	streamHandler.FetchMetadata(s, func(s stream.Stream, data []byte, err error) {
+		if err == stream.ErrStreamUnavailable {
+			p.logger.Infof("PLAYBACK FETCH-INFO-CALLBACK stream %q is unavailable and will be skipped: %v", s.GetStreamURL(), err)
+			s.Metadata().SetFetchStatus(stream.STREAM_FETCH_STATUS_UNAVAILABLE)
+			callback(data, true, err)
+			return
+		}
 		if err != nil {
-			log.Printf("ERR PLAYBACK FETCH-INFO-CALLBACK unable to calculate video metadata. Some information, such as media duration, will not be available: %v", err)
+			p.logger.Errorf("PLAYBACK FETCH-INFO-CALLBACK unable to calculate video metadata. Some information, such as media duration, will not be available: %v", err)
+			s.Metadata().SetFetchStatus(stream.STREAM_FETCH_STATUS_FAILED)
 			callback(data, true, err)
 			return
 		}
 
 		err = s.SetInfo(data)
 		if err != nil {
-			log.Printf("ERR PLAYBACK FETCH-INFO-CALLBACK unable to set parsed stream info: %v", err)
+			p.logger.Errorf("PLAYBACK FETCH-INFO-CALLBACK unable to set parsed stream info: %v", err)
+			s.Metadata().SetFetchStatus(stream.STREAM_FETCH_STATUS_FAILED)
 			callback(data, true, err)
 			return
 		}
+		s.Metadata().SetFetchStatus(stream.STREAM_FETCH_STATUS_FETCHED)
 		callback(data, true, nil)
 	})
-
-	log.Printf("INF PLAYBACK no stream found with url %q; creating... There are now %v registered streams", url, streamHandler.GetSize())
-	return s, nil
 }
 
 // PlaybackStatus is a serializable schema representing a summary of information
@@ -400,6 +603,26 @@ type PlaybackStatus struct {
 	CreatedBy   string       `json:"createdBy"`
 	Stream      api.ApiCodec `json:"stream"`
 	TimerStatus api.ApiCodec `json:"playback"`
+	Chapters    []Chapter    `json:"chapters"`
+	Settings    api.ApiCodec `json:"settings"`
+	Topic       *RoomTopic   `json:"topic"`
+	IsLive      bool         `json:"isLive"`
+	Repeat      RepeatMode   `json:"repeat"`
+	// Countdown is the number of seconds remaining
+	// until the current stream starts, if it is starting
+	Countdown int `json:"countdown,omitempty"`
+	// UpNext describes the streams that will
+	// be played next from the room's queue
+	UpNext []QueueItemMetadata `json:"upNext,omitempty"`
+	// DurationOverride is the number of seconds the
+	// current stream has been capped at, if any
+	DurationOverride float64 `json:"durationOverride,omitempty"`
+	// Volume is the volume level suggested
+	// to every client in the room, if any
+	Volume *int `json:"volume,omitempty"`
+	// SubtitleTrack is the subtitle track of the current stream
+	// every client in the room should enable, if any
+	SubtitleTrack *stream.SubtitleTrack `json:"subtitleTrack,omitempty"`
 }
 
 func (s *PlaybackStatus) Serialize() ([]byte, error) {
@@ -423,12 +646,35 @@ func (p *Playback) GetStatus() api.ApiCodec {
 		createdBy = s.Metadata().GetCreationSource().GetSourceName()
 	}
 
+	countdown, _ := p.IsStarting()
+
+	var volume *int
+	if level, hasVolume := p.Volume(); hasVolume {
+		volume = &level
+	}
+
+	var subtitleTrack *stream.SubtitleTrack
+	if track, selected := p.SubtitleTrack(); selected {
+		subtitleTrack = &track
+	}
+
 	return &PlaybackStatus{
 		QueueLength: p.GetQueue().Size(),
 		StartedBy:   p.startedBy,
 		CreatedBy:   createdBy,
 		TimerStatus: p.timer.Status(),
 		Stream:      streamCodec,
+		Chapters:    p.Chapters(),
+		Settings:    p.Settings(),
+		Topic:       p.Topic(),
+		IsLive:      p.IsLive(),
+		Repeat:      p.RepeatMode(),
+
+		DurationOverride: p.DurationOverride(),
+		Countdown:        countdown,
+		UpNext:           p.UpNext(),
+		Volume:           volume,
+		SubtitleTrack:    subtitleTrack,
 	}
 }
 
@@ -439,9 +685,9 @@ func NewPlaybackWithAdminPicker(ns connection.Namespace, authorizer rbac.Authori
 	p.adminPicker = picker
 
 	if err := picker.Init(ns, authorizer, clientHandler, playbackHandler); err != nil {
-		log.Printf("WRN PLAYBACK ADMIN-PICKER unable to initialize admin picker for room %q: %v\n", ns.Name(), err)
+		p.logger.Warnf("PLAYBACK ADMIN-PICKER unable to initialize admin picker for room %q: %v\n", ns.Name(), err)
 	} else {
-		log.Printf("INF PLAYBACK ADMIN-PICKER for room %q started\n", ns.Name())
+		p.logger.Infof("PLAYBACK ADMIN-PICKER for room %q started\n", ns.Name())
 	}
 
 	return p
@@ -458,6 +704,26 @@ func NewPlayback(ns connection.Namespace) *Playback {
 		queueHandler:       queue.NewQueueHandler(queue.NewRoundRobinQueue()),
 		lastUpdated:        time.Now(),
 		lastAdminDeparture: time.Time{},
+		chapters:           []Chapter{},
+		viewerSamples:      []ViewerSample{},
+		interrupted:        []*interruptedStream{},
+		hypeMeter:          NewHypeMeter(),
+		countdown:          DefaultStreamCountdown,
+		chatLog:            NewChatLog(ChatHistorySize),
+		pinned:             []*client.Response{},
+		history:            newPlayHistory(PlayHistorySize),
+		audit:              newAuditLog(AuditLogSize),
+		maxQueueItems:      queue.MaxAggregatableQueueItems,
+		duplicatePolicy:    DUPLICATES_CONSECUTIVE,
+		repeatMode:         REPEAT_OFF,
+		bans:               &bans{byAddress: make(map[string]string)},
+		mutes:              &mutes{byAddress: make(map[string]*mute)},
+		djs:                &djRotation{djs: []string{}},
+		wordFilter:         newWordFilter(),
+		invites:            &invites{byToken: make(map[string]*invite)},
+		slowMode:           &slowMode{lastMessages: make(map[string]time.Time)},
+		skipVotes:          &skipVotes{byId: make(map[string]bool)},
 		state:              PLAYBACK_STATE_NOT_STARTED,
+		logger:             logging.Default,
 	}
 }
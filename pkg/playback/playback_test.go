@@ -0,0 +1,36 @@
+package playback
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestGetStatusWithNoStream(t *testing.T) {
+	p := NewPlayback(connection.NewNamespace("room"))
+
+	status, ok := p.GetStatus().(*PlaybackStatus)
+	if !ok {
+		t.Fatalf("expected status of type *PlaybackStatus, got %T", p.GetStatus())
+	}
+	if status.Stream != nil {
+		t.Errorf("expected no stream status, got %v", status.Stream)
+	}
+	if len(status.CreatedBy) != 0 {
+		t.Errorf("expected no stream creator, got %q", status.CreatedBy)
+	}
+
+	b, err := status.Serialize()
+	if err != nil {
+		t.Fatalf("unexpected error serializing status: %v", err)
+	}
+
+	kv := map[string]interface{}{}
+	if err := json.Unmarshal(b, &kv); err != nil {
+		t.Fatalf("unexpected error decoding status: %v", err)
+	}
+	if stream, exists := kv["stream"]; !exists || stream != nil {
+		t.Errorf("expected a null stream in serialized status, got %v", stream)
+	}
+}
@@ -0,0 +1,177 @@
+package playback
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+const (
+	MinPollOptions = 2  // minimum number of options a poll may offer
+	MaxPollOptions = 10 // maximum number of options a poll may offer
+)
+
+// Poll is a question put to a room, offering a fixed
+// set of options. Each client may cast a single vote,
+// which they may change until the poll is closed.
+type Poll struct {
+	question  string
+	options   []string
+	createdBy string
+
+	mutex sync.Mutex
+	// map of client ids to the index of the option they voted for
+	votes map[string]int
+}
+
+// PollOption is a serializable schema describing
+// a single poll option and its current vote count.
+type PollOption struct {
+	Option string `json:"option"`
+	Votes  int    `json:"votes"`
+}
+
+// PollState is a serializable schema describing
+// a poll's question, options, and current tally.
+// Implements api.ApiCodec.
+type PollState struct {
+	Question  string       `json:"question"`
+	Options   []PollOption `json:"options"`
+	CreatedBy string       `json:"createdBy"`
+	Total     int          `json:"total"`
+	Closed    bool         `json:"closed"`
+}
+
+func (s *PollState) Serialize() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Vote records a vote from the client with the given id for the
+// option at the given (1-based) position, replacing any vote the
+// client had previously cast. Returns an error if no such option
+// exists.
+func (p *Poll) Vote(id string, option int) error {
+	if option < 1 || option > len(p.options) {
+		return fmt.Errorf("option must be a number between 1 and %v", len(p.options))
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.votes[id] = option - 1
+	return nil
+}
+
+// RemoveVote discards the vote from the client with the given id
+func (p *Poll) RemoveVote(id string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.votes, id)
+}
+
+// Tally returns the number of votes cast for each option,
+// in the order the options were given.
+func (p *Poll) Tally() []int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.tally()
+}
+
+func (p *Poll) tally() []int {
+	tally := make([]int, len(p.options))
+	for _, option := range p.votes {
+		tally[option]++
+	}
+	return tally
+}
+
+// Question returns the question the poll is asking
+func (p *Poll) Question() string {
+	return p.question
+}
+
+// Options returns the options the poll is offering
+func (p *Poll) Options() []string {
+	return p.options
+}
+
+// State returns a serializable snapshot of the poll
+func (p *Poll) State() *PollState {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	state := &PollState{
+		Question:  p.question,
+		Options:   []PollOption{},
+		CreatedBy: p.createdBy,
+		Total:     len(p.votes),
+	}
+
+	for i, votes := range p.tally() {
+		state.Options = append(state.Options, PollOption{
+			Option: p.options[i],
+			Votes:  votes,
+		})
+	}
+	return state
+}
+
+// NewPoll returns a poll asking the given question on behalf
+// of the user with the given name. Returns an error if the
+// number of options is outside of [MinPollOptions, MaxPollOptions].
+func NewPoll(question string, options []string, createdBy string) (*Poll, error) {
+	if len(question) == 0 {
+		return nil, fmt.Errorf("a poll must have a question")
+	}
+	if len(options) < MinPollOptions || len(options) > MaxPollOptions {
+		return nil, fmt.Errorf("a poll must have between %v and %v options", MinPollOptions, MaxPollOptions)
+	}
+
+	return &Poll{
+		question:  question,
+		options:   options,
+		createdBy: createdBy,
+		votes:     make(map[string]int),
+	}, nil
+}
+
+// StartPoll sets the room's active poll.
+// Returns an error if the room already has an active poll.
+func (p *Playback) StartPoll(poll *Poll) error {
+	p.pollMutex.Lock()
+	defer p.pollMutex.Unlock()
+
+	if p.poll != nil {
+		return fmt.Errorf("the room already has an active poll")
+	}
+
+	p.poll = poll
+	return nil
+}
+
+// Poll returns the room's active poll, and a boolean (false) if there is none
+func (p *Playback) Poll() (*Poll, bool) {
+	p.pollMutex.Lock()
+	defer p.pollMutex.Unlock()
+
+	return p.poll, p.poll != nil
+}
+
+// ClosePoll ends the room's active poll and returns its final state.
+// Returns an error if the room has no active poll.
+func (p *Playback) ClosePoll() (*PollState, error) {
+	p.pollMutex.Lock()
+	defer p.pollMutex.Unlock()
+
+	if p.poll == nil {
+		return nil, fmt.Errorf("the room has no active poll")
+	}
+
+	state := p.poll.State()
+	state.Closed = true
+
+	p.poll = nil
+	return state, nil
+}
@@ -0,0 +1,43 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	PresentationSlowModeInterval = 10 * time.Second // chat slow mode applied while a room is in presentation mode
+)
+
+// SetPresentationMode enables or disables presentation mode for the
+// room. Enabling presentation mode locks playback and the queue to
+// the connection with the given id, and enables chat slow mode.
+// Returns an error if playback or the queue is already locked by
+// another connection.
+func (p *Playback) SetPresentationMode(enabled bool, id, name string) error {
+	if !enabled {
+		p.presentation = false
+		p.Unlock()
+		p.UnlockQueue()
+		p.SetSlowMode(0)
+		return nil
+	}
+
+	if lockedBy, lockedByName, locked := p.LockedBy(); locked && lockedBy != id {
+		return fmt.Errorf("error: stream playback is already locked by %q", lockedByName)
+	}
+	if lockedBy, lockedByName, locked := p.QueueLockedBy(); locked && lockedBy != id {
+		return fmt.Errorf("error: the queue is already locked by %q", lockedByName)
+	}
+
+	p.presentation = true
+	p.Lock(id, name)
+	p.LockQueue(id, name)
+	p.SetSlowMode(PresentationSlowModeInterval)
+	return nil
+}
+
+// PresentationMode returns true if the room is in presentation mode
+func (p *Playback) PresentationMode() bool {
+	return p.presentation
+}
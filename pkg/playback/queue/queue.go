@@ -133,6 +133,19 @@ type SerializableQueue interface {
 	api.ApiCodec
 }
 
+// QueueMode determines the order in which a RoundRobinQueue
+// pops items from its aggregated Queues.
+type QueueMode string
+
+const (
+	// QUEUE_MODE_ROUND_ROBIN pops a single item from
+	// each aggregated Queue in turn
+	QUEUE_MODE_ROUND_ROBIN QueueMode = "roundrobin"
+	// QUEUE_MODE_FIFO pops every item from an aggregated
+	// Queue before moving on to the next one
+	QUEUE_MODE_FIFO QueueMode = "fifo"
+)
+
 // RoundRobinQueue aggregates a collection of Queues and steps through
 // them in fifo order.
 type RoundRobinQueue interface {
@@ -141,6 +154,13 @@ type RoundRobinQueue interface {
 
 	// CurrentIndex returns the current round-robin index
 	CurrentIndex() int
+	// Mode returns the order in which items are
+	// popped from the aggregated Queues
+	Mode() QueueMode
+	// SetMode sets the order in which items are popped
+	// from the aggregated Queues.
+	// Returns an error if the given mode is unknown.
+	SetMode(QueueMode) error
 	// DeleteFromQueue receives an aggregated queue within the round-robin
 	// queue and attempts to delete a QueueItem from it.
 	DeleteFromQueue(Queue, QueueItem) error
@@ -153,6 +173,10 @@ type RoundRobinQueue interface {
 	// PeekItems returns a slice containing the first item
 	// from each aggregated QueueItem in the queue.
 	PeekItems() []QueueItem
+	// Peek returns up to the given number of QueueItems, in
+	// the order they would be returned by successive calls
+	// to Next, without removing them from the queue.
+	Peek(int) []QueueItem
 }
 
 // AggregatableQueue is a queue that can be aggregated as a QueueItem
@@ -368,6 +392,7 @@ type RoundRobinQueueSchema struct {
 
 	// count used to round-robin the queue for each QueueItem
 	rrCount int
+	mode    QueueMode
 }
 
 func (q *RoundRobinQueueSchema) Clear() {
@@ -443,6 +468,21 @@ func (q *RoundRobinQueueSchema) CurrentIndex() int {
 	return q.rrCount
 }
 
+func (q *RoundRobinQueueSchema) Mode() QueueMode {
+	return q.mode
+}
+
+func (q *RoundRobinQueueSchema) SetMode(mode QueueMode) error {
+	switch mode {
+	case QUEUE_MODE_ROUND_ROBIN, QUEUE_MODE_FIFO:
+	default:
+		return fmt.Errorf("unknown queue mode %q (expected %s or %s)", mode, QUEUE_MODE_FIFO, QUEUE_MODE_ROUND_ROBIN)
+	}
+
+	q.mode = mode
+	return nil
+}
+
 func (q *RoundRobinQueueSchema) DeleteItem(queue QueueItem) error {
 	q.Lock()
 	defer q.Unlock()
@@ -510,7 +550,7 @@ func (q *RoundRobinQueueSchema) Next() (QueueItem, error) {
 	qItem := qItems[q.rrCount]
 	aggQueue, ok := qItem.(AggregatableQueue)
 	if !ok {
-		return nil, fmt.Errorf("expected QueueItem at round-robin count %q to implement AggregatableQueue", q.rrCount)
+		return nil, fmt.Errorf("expected QueueItem at round-robin count %v to implement AggregatableQueue", q.rrCount)
 	}
 
 	// get next queue - if empty,
@@ -535,7 +575,10 @@ func (q *RoundRobinQueueSchema) Next() (QueueItem, error) {
 		q.rrCount--
 	}
 
-	q.rrCount++
+	// in fifo mode, stay on the current Queue until it has been emptied
+	if q.mode != QUEUE_MODE_FIFO || aggQueue.Size() == 0 {
+		q.rrCount++
+	}
 	if q.rrCount >= q.Size() {
 		q.rrCount = 0
 	}
@@ -559,6 +602,58 @@ func (q *RoundRobinQueueSchema) PeekItems() []QueueItem {
 	return items
 }
 
+func (q *RoundRobinQueueSchema) Peek(n int) []QueueItem {
+	items := []QueueItem{}
+	if n <= 0 || q.Size() == 0 {
+		return items
+	}
+
+	// order aggregated queues starting at the round-robin index
+	queues := []AggregatableQueue{}
+	qItems := q.List()
+	for i := range qItems {
+		if aggQueue, ok := qItems[(q.rrCount+i)%len(qItems)].(AggregatableQueue); ok {
+			queues = append(queues, aggQueue)
+		}
+	}
+
+	if q.mode == QUEUE_MODE_FIFO {
+		for _, aggQueue := range queues {
+			for _, item := range aggQueue.List() {
+				if len(items) >= n {
+					return items
+				}
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+
+	// take the i-th item of each aggregated queue in
+	// turn, until every queue has been exhausted
+	for i := 0; len(items) < n; i++ {
+		found := false
+		for _, aggQueue := range queues {
+			aggQueueItems := aggQueue.List()
+			if i >= len(aggQueueItems) {
+				continue
+			}
+
+			found = true
+			items = append(items, aggQueueItems[i])
+			if len(items) >= n {
+				break
+			}
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return items
+}
+
 func (q *RoundRobinQueueSchema) Serialize() ([]byte, error) {
 	items := q.PeekItems()
 
@@ -578,5 +673,6 @@ func NewRoundRobinQueue() RoundRobinQueue {
 		ReorderableQueue: NewReorderableQueue(),
 
 		itemsById: make(map[string]AggregatableQueue),
+		mode:      QUEUE_MODE_ROUND_ROBIN,
 	}
 }
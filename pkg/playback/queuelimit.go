@@ -0,0 +1,37 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+)
+
+// SetMaxQueueItems sets the maximum number of items each client may
+// store in their queue for this room. Limits are bounded by
+// queue.MaxAggregatableQueueItems.
+// Returns an error if the given limit is out of bounds.
+func (p *Playback) SetMaxQueueItems(limit int) error {
+	if limit < 1 || limit > queue.MaxAggregatableQueueItems {
+		return fmt.Errorf("queue limit must be between 1 and %v", queue.MaxAggregatableQueueItems)
+	}
+
+	p.maxQueueItems = limit
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// MaxQueueItems returns the maximum number of items each
+// client may store in their queue for this room.
+func (p *Playback) MaxQueueItems() int {
+	return p.maxQueueItems
+}
+
+// checkQueueLimit returns an error if the given
+// user queue is at the room's queue limit.
+func (p *Playback) checkQueueLimit(userQueue queue.AggregatableQueue) error {
+	if userQueue.Size() >= p.maxQueueItems {
+		return fmt.Errorf("you cannot store more than %v items in your queue.", p.maxQueueItems)
+	}
+	return nil
+}
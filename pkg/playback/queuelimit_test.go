@@ -0,0 +1,90 @@
+package playback
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestPushToQueueLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		expectErr bool
+	}{
+		{
+			name:  "limit of a single item",
+			limit: 1,
+		},
+		{
+			name:  "limit of several items",
+			limit: 3,
+		},
+		{
+			name:  "largest limit",
+			limit: queue.MaxAggregatableQueueItems,
+		},
+		{
+			name:      "limit of no items",
+			limit:     0,
+			expectErr: true,
+		},
+		{
+			name:      "limit beyond the largest limit",
+			limit:     queue.MaxAggregatableQueueItems + 1,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+
+			err := p.SetMaxQueueItems(tc.limit)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if tc.expectErr {
+				if limit := p.MaxQueueItems(); limit != queue.MaxAggregatableQueueItems {
+					t.Errorf("expected the queue limit to remain %v, got %v", queue.MaxAggregatableQueueItems, limit)
+				}
+				return
+			}
+
+			userQueue := queue.NewAggregatableQueue("user")
+			otherQueue := queue.NewAggregatableQueue("other")
+			for _, q := range []queue.AggregatableQueue{userQueue, otherQueue} {
+				if err := p.GetQueue().Push(q); err != nil {
+					t.Fatalf("unexpected error pushing user queue: %v", err)
+				}
+			}
+
+			for i := 0; i < tc.limit; i++ {
+				url := fmt.Sprintf("http://example.com/%d.mp4", i)
+				if err := p.PushToQueue(userQueue, stream.NewRemoteVideoStream(url)); err != nil {
+					t.Fatalf("unexpected error queueing %q: %v", url, err)
+				}
+			}
+
+			err = p.PushToQueue(userQueue, stream.NewRemoteVideoStream("http://example.com/over.mp4"))
+			if err == nil {
+				t.Fatalf("expected an error queueing beyond the limit of %v items", tc.limit)
+			}
+			if expected := fmt.Sprintf("more than %v items", tc.limit); !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected the error to mention %q, got %q", expected, err)
+			}
+			if size := userQueue.Size(); size != tc.limit {
+				t.Errorf("expected %v items in the queue, got %v", tc.limit, size)
+			}
+
+			// the limit applies to each client's queue separately
+			if err := p.PushToQueue(otherQueue, stream.NewRemoteVideoStream("http://example.com/other.mp4")); err != nil {
+				t.Errorf("unexpected error queueing for another client: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package playback
+
+import (
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+)
+
+// SetQueueMode sets the order in which streams are played from
+// the users' queues: one from each user in turn (round-robin),
+// or every stream from a user before moving on (fifo).
+// Returns an error if the given mode is unknown.
+func (p *Playback) SetQueueMode(mode queue.QueueMode) error {
+	if err := p.GetQueue().SetMode(mode); err != nil {
+		return err
+	}
+
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// QueueMode returns the order in which streams are played from the users' queues
+func (p *Playback) QueueMode() queue.QueueMode {
+	return p.GetQueue().Mode()
+}
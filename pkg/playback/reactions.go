@@ -0,0 +1,110 @@
+package playback
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+const (
+	// ChatMessageIdKey is the key of the unique id
+	// stored in a chat message's extra data
+	ChatMessageIdKey = "messageId"
+	// ChatReactionsKey is the key of the reaction counts,
+	// by emoji, stored in a chat message's extra data
+	ChatReactionsKey = "reactions"
+
+	MaxReactionLength      = 8  // max number of characters in a single reaction
+	MaxReactionsPerMessage = 20 // max number of distinct reactions to a single message
+)
+
+// ValidateReaction returns an error if the given reaction is not
+// a short sequence of emoji or symbol characters
+func ValidateReaction(emoji string) error {
+	if len(emoji) == 0 {
+		return fmt.Errorf("error: a reaction is required")
+	}
+	if utf8.RuneCountInString(emoji) > MaxReactionLength {
+		return fmt.Errorf("error: reactions may be at most %v characters long", MaxReactionLength)
+	}
+
+	for _, r := range emoji {
+		if r == utf8.RuneError || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf("error: reactions must be emoji")
+		}
+	}
+	return nil
+}
+
+// ToggleReaction adds the given reaction from the client with the given
+// id to the message with the given id, or removes it if the client has
+// already reacted to the message with it. The message is replaced in the
+// log by a copy holding the updated reaction counts, so that previously
+// returned messages are never modified.
+// Returns the message's reaction counts, by emoji, and a boolean (true)
+// if the reaction was added, or an error if no such message exists.
+func (l *ChatLog) ToggleReaction(messageId, emoji, clientId string) (map[string]int, bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	idx, exists := l.indexOf(messageId)
+	if !exists {
+		return nil, false, fmt.Errorf("error: unable to find that message in the chat history")
+	}
+
+	byEmoji, exists := l.reactions[messageId]
+	if !exists {
+		byEmoji = make(map[string]map[string]bool)
+		l.reactions[messageId] = byEmoji
+	}
+
+	added := false
+	if clients, exists := byEmoji[emoji]; exists && clients[clientId] {
+		delete(clients, clientId)
+		if len(clients) == 0 {
+			delete(byEmoji, emoji)
+		}
+	} else {
+		if !exists && len(byEmoji) >= MaxReactionsPerMessage {
+			return nil, false, fmt.Errorf("error: messages may have at most %v different reactions", MaxReactionsPerMessage)
+		}
+		if !exists {
+			byEmoji[emoji] = make(map[string]bool)
+		}
+		byEmoji[emoji][clientId] = true
+		added = true
+	}
+
+	counts := make(map[string]int, len(byEmoji))
+	for e, clients := range byEmoji {
+		counts[e] = len(clients)
+	}
+	if len(byEmoji) == 0 {
+		delete(l.reactions, messageId)
+	}
+
+	msg := copyChatMessage(l.messages[idx])
+	msg.Extra[ChatReactionsKey] = counts
+	l.messages[idx] = msg
+
+	return counts, added, nil
+}
+
+// ToggleChatReaction adds or removes a reaction from the client
+// with the given id to a message in the room's chat history.
+// See ChatLog.ToggleReaction.
+func (p *Playback) ToggleChatReaction(messageId, emoji, clientId string) (map[string]int, bool, error) {
+	return p.chatLog.ToggleReaction(messageId, emoji, clientId)
+}
+
+// chatMessageId returns the unique id of the given chat message, if it has one
+func chatMessageId(msg *client.Response) (string, bool) {
+	if msg == nil || msg.Extra == nil {
+		return "", false
+	}
+
+	id, ok := msg.Extra[ChatMessageIdKey].(string)
+	return id, ok && len(id) > 0
+}
@@ -1,25 +1,42 @@
 package playback
 
 import (
-	"log"
 	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/logging"
 )
 
 const (
 	MaxStaleSPlaybackObjectDuration time.Duration = 5 * time.Minute // amount of time to wait before reaping a stale playback object
+	DefaultRoomReapInterval         time.Duration = 1 * time.Minute // amount of time between checks for rooms to reap
+)
+
+var (
+	// RoomIdleTimeout is the amount of time a room must go without
+	// any connected clients before it is reaped.
+	RoomIdleTimeout = MaxStaleSPlaybackObjectDuration
+	// RoomReapInterval is the amount of time between checks for rooms to reap
+	RoomReapInterval = DefaultRoomReapInterval
 )
 
 // PlaybackReaper is a PlaybackHandler's Garbage Collector.
-// Iterates through all playback objects stored in a handler every minute
-// reaping objects that are candidates for reaping and have exceeded their
-// maxStalePlaybackObjectLifetime. A playback object becomes a "candidate
-// for reaping"
+// Iterates through all playback objects stored in a handler every
+// RoomReapInterval, reaping objects that are candidates for reaping
+// and have been idle for longer than their maxStalePlaybackObjectLifetime.
+// A playback object becomes a "candidate for reaping" once its room
+// has no connected clients, regardless of whether a stream is playing
+// or items remain in its queue.
 type PlaybackReaper struct {
 	// max age of a stale playbackobject.
-	// a "stale" playbackobject is defined as a playback object that has not had its data
-	// updated in more than 1 second.
+	// a "stale" playbackobject is defined as a playback object
+	// whose room has had no connected clients for this long.
 	maxStalePlaybackObjectLifetime time.Duration
+	interval                       time.Duration
 	stopChan                       chan bool
+
+	// times at which rooms were found to be empty, keyed
+	// by room name. Only accessed by the reaping goroutine.
+	emptySince map[string]time.Time
 }
 
 func (r *PlaybackReaper) Stop() {
@@ -31,28 +48,76 @@ func (r *PlaybackReaper) Init(handler PlaybackHandler) {
 }
 
 func reap(reaper *PlaybackReaper, handler PlaybackHandler, stop chan bool) {
+	ticker := time.NewTicker(reaper.interval)
+	defer ticker.Stop()
+
 	for {
-		for _, s := range handler.Playbacks() {
-			if handler.IsReapable(s) && time.Now().Sub(s.GetLastUpdated()) > reaper.maxStalePlaybackObjectLifetime {
-				if handler.ReapPlayback(s) {
-					log.Printf("INF REAPER room with name %q has become a candidate for reaping after %v. Reaping...\n", s.name, time.Now().Sub(s.GetLastUpdated()))
-				}
-			}
-		}
+		reaper.sweep(handler, time.Now())
 
 		select {
 		case <-stop:
-			log.Printf("INF REAPER PlaybackReaper terminated.\n")
+			logging.Default.Infof("REAPER PlaybackReaper terminated.\n")
 			return
-		default:
+		case <-ticker.C:
 		}
-		time.Sleep(1 * time.Minute)
 	}
 }
 
+// sweep reaps every room that has had no connected
+// clients for longer than the reaper's idle timeout.
+func (r *PlaybackReaper) sweep(handler PlaybackHandler, now time.Time) {
+	seen := make(map[string]bool)
+
+	for _, s := range handler.Playbacks() {
+		seen[s.UUID()] = true
+
+		if !handler.IsReapable(s) {
+			delete(r.emptySince, s.UUID())
+			continue
+		}
+
+		// a room's last update is bumped as its last client leaves, so
+		// the room is considered idle from then, or from now if it has
+		// since been updated by its timer or queue.
+		since, exists := r.emptySince[s.UUID()]
+		if !exists {
+			since = s.GetLastUpdated()
+			if since.After(now) {
+				since = now
+			}
+			r.emptySince[s.UUID()] = since
+		}
+
+		if now.Sub(since) <= r.maxStalePlaybackObjectLifetime {
+			continue
+		}
+
+		if handler.ReapPlayback(s) {
+			logging.Default.Infof("REAPER room with name %q has become a candidate for reaping after %v. Reaping...\n", s.UUID(), now.Sub(since))
+			delete(r.emptySince, s.UUID())
+		}
+	}
+
+	// forget rooms removed since the last sweep
+	for name := range r.emptySince {
+		if !seen[name] {
+			delete(r.emptySince, name)
+		}
+	}
+}
+
+// NewPlaybackReaper returns a reaper using the current RoomIdleTimeout
+// and RoomReapInterval. A non-positive interval is replaced by the default.
 func NewPlaybackReaper() *PlaybackReaper {
+	interval := RoomReapInterval
+	if interval <= 0 {
+		interval = DefaultRoomReapInterval
+	}
+
 	return &PlaybackReaper{
-		maxStalePlaybackObjectLifetime: MaxStaleSPlaybackObjectDuration,
+		maxStalePlaybackObjectLifetime: RoomIdleTimeout,
+		interval:                       interval,
 		stopChan:                       make(chan bool, 1),
+		emptySince:                     make(map[string]time.Time),
 	}
 }
@@ -0,0 +1,82 @@
+package playback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// idConnection is a connection known only by its id
+type idConnection struct {
+	connection.Connection
+	id string
+}
+
+func (c *idConnection) UUID() string {
+	return c.id
+}
+
+func TestPlaybackReaperSweep(t *testing.T) {
+	nsHandler := connection.NewNamespaceHandler()
+	h := NewHandler(nsHandler)
+
+	rooms := map[string]connection.Namespace{}
+	for _, name := range []string{"empty", "repopulated", "occupied"} {
+		rooms[name] = nsHandler.NewNamespace(name)
+		h.NewPlayback(rooms[name], nil, nil)
+	}
+	rooms["occupied"].Add(&idConnection{id: "occupant"})
+
+	reaper := &PlaybackReaper{
+		maxStalePlaybackObjectLifetime: time.Minute,
+		emptySince:                     make(map[string]time.Time),
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		at           time.Duration
+		join         string
+		expectReaped []string
+		expectSpared []string
+	}{
+		{
+			name:         "empty rooms are spared until the timeout",
+			expectSpared: []string{"empty", "repopulated", "occupied"},
+		},
+		{
+			name:         "empty rooms are spared within the timeout",
+			at:           30 * time.Second,
+			expectSpared: []string{"empty", "repopulated", "occupied"},
+		},
+		{
+			name:         "empty rooms are reaped after the timeout",
+			at:           2 * time.Minute,
+			join:         "repopulated",
+			expectReaped: []string{"empty"},
+			expectSpared: []string{"repopulated", "occupied"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.join) > 0 {
+				rooms[tc.join].Add(&idConnection{id: tc.join + "-client"})
+			}
+
+			reaper.sweep(h, now.Add(tc.at))
+
+			for _, name := range tc.expectReaped {
+				if _, exists := h.PlaybackByNamespace(rooms[name]); exists {
+					t.Errorf("expected room %q to be reaped", name)
+				}
+			}
+			for _, name := range tc.expectSpared {
+				if _, exists := h.PlaybackByNamespace(rooms[name]); !exists {
+					t.Errorf("expected room %q to be spared", name)
+				}
+			}
+		})
+	}
+}
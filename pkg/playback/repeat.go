@@ -0,0 +1,110 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	playbackutil "github.com/juanvallejo/streaming-server/pkg/playback/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// RepeatMode determines what happens once the current stream ends
+type RepeatMode string
+
+const (
+	// REPEAT_OFF advances to the next stream in the queue
+	REPEAT_OFF RepeatMode = "off"
+	// REPEAT_ONE replays the current stream
+	REPEAT_ONE RepeatMode = "one"
+	// REPEAT_ALL pushes the current stream to the back of the
+	// queue of the user who queued it before advancing
+	REPEAT_ALL RepeatMode = "all"
+)
+
+// SetRepeatMode sets what happens once the current stream ends.
+// Returns an error if the given mode is unknown.
+func (p *Playback) SetRepeatMode(mode RepeatMode) error {
+	switch mode {
+	case REPEAT_OFF, REPEAT_ONE, REPEAT_ALL:
+	default:
+		return fmt.Errorf("unknown repeat mode %q (expected %s, %s, or %s)", mode, REPEAT_OFF, REPEAT_ONE, REPEAT_ALL)
+	}
+
+	p.repeatMode = mode
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// RepeatMode returns what happens once the current stream ends
+func (p *Playback) RepeatMode() RepeatMode {
+	return p.repeatMode
+}
+
+// EndStream is called once the current stream has played all the way
+// through, and replays or advances past it according to the room's
+// repeat mode. Returns the stream loaded in its place, or a boolean
+// (false) if the current stream was replayed or playback was stopped.
+func (p *Playback) EndStream() (stream.Stream, bool, error) {
+	current, exists := p.GetStream()
+	if !exists {
+		return p.AdvanceQueue()
+	}
+
+	switch p.repeatMode {
+	case REPEAT_ONE:
+		return nil, false, p.replayStream()
+	case REPEAT_ALL:
+		// with nothing else left to play, replay the current stream
+		if len(p.interrupted) == 0 && len(p.GetQueue().PeekItems()) == 0 {
+			return nil, false, p.replayStream()
+		}
+
+		owner, hasOwner := current.Metadata().GetLabelledRef(p.UUID())
+
+		next, loaded, err := p.AdvanceQueue()
+		if err != nil {
+			return next, loaded, err
+		}
+
+		if user, ok := owner.(*client.Client); hasOwner && ok {
+			if err := p.requeue(user, current); err != nil {
+				p.logger.Infof("PLAYBACK unable to re-queue stream %q in room %q: %v\n", current.GetStreamURL(), p.UUID(), err)
+			}
+		}
+		return next, loaded, nil
+	}
+
+	return p.AdvanceQueue()
+}
+
+// replayStream restarts the current stream from its beginning,
+// after the room's countdown, if it has one
+func (p *Playback) replayStream() error {
+	if err := p.Reset(); err != nil {
+		return err
+	}
+	return p.startAdvancedStream()
+}
+
+// requeue pushes a finished stream to the back of the given user's queue
+func (p *Playback) requeue(user *client.Client, s stream.Stream) error {
+	userQueue, exists, err := playbackutil.GetUserQueue(user, p.GetQueue())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		userQueue = queue.NewAggregatableQueue(user.UUID())
+		if err := p.GetQueue().Push(userQueue); err != nil {
+			return err
+		}
+	}
+
+	if err := p.PushToQueue(userQueue, s); err != nil {
+		return err
+	}
+
+	s.Metadata().SetLabelledRef(p.UUID(), user)
+	return nil
+}
@@ -0,0 +1,51 @@
+package playback
+
+import "encoding/json"
+
+// RoomSummary is a serializable schema
+// describing an active room at a glance.
+type RoomSummary struct {
+	Room        string `json:"room"`
+	Clients     int    `json:"clients"`
+	Url         string `json:"url,omitempty"`
+	Title       string `json:"title,omitempty"`
+	QueueLength int    `json:"queueLength"`
+	Topic       string `json:"topic,omitempty"`
+	Capacity    int    `json:"capacity,omitempty"`
+}
+
+// RoomList is a serializable schema
+// describing every active, public room.
+// Implements api.ApiCodec.
+type RoomList struct {
+	Rooms []RoomSummary `json:"rooms"`
+}
+
+func (l *RoomList) Serialize() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// IsPrivate returns true if the room is either
+// unlisted or requires a password to join.
+func (p *Playback) IsPrivate() bool {
+	return !p.Listed() || p.HasPassword()
+}
+
+// Summary returns a summary of the room, given
+// the number of clients currently connected to it.
+func (p *Playback) Summary(clients int) RoomSummary {
+	summary := RoomSummary{
+		Room:        p.UUID(),
+		Clients:     clients,
+		QueueLength: len(p.GetQueue().PeekItems()),
+		Topic:       p.topic.Topic,
+		Capacity:    p.Capacity(),
+	}
+
+	if s, exists := p.GetStream(); exists {
+		summary.Url = s.GetStreamURL()
+		summary.Title = s.GetName()
+	}
+
+	return summary
+}
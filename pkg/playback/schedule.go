@@ -0,0 +1,162 @@
+package playback
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// ScheduleCallback is called once a second while a stream is
+// scheduled, with the number of seconds remaining until it starts.
+// It is called a final time with 0 once the stream has started.
+type ScheduleCallback func(int)
+
+// scheduledStream is a stream armed to begin playback at a given time.
+// Scheduled streams are held in memory only, and are dropped if the
+// server restarts before they start.
+type scheduledStream struct {
+	stream      stream.Stream
+	at          time.Time
+	scheduledBy string
+	stopChan    chan bool
+}
+
+// ScheduleStatus is a serializable schema describing
+// a stream scheduled to start at a given time.
+// Implements api.ApiCodec.
+type ScheduleStatus struct {
+	Url         string `json:"url"`
+	Name        string `json:"name"`
+	At          int64  `json:"at"`
+	Remaining   int    `json:"remaining"`
+	ScheduledBy string `json:"scheduledBy"`
+}
+
+func (s *ScheduleStatus) Serialize() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// ScheduleStream arms the given stream to replace the currently-playing
+// stream and begin playback at the given time, on behalf of the user with
+// the given name. Returns an error if a stream is already scheduled, or if
+// the given time is not in the future.
+func (p *Playback) ScheduleStream(s stream.Stream, at time.Time, scheduledBy string) error {
+	p.scheduledMutex.Lock()
+	defer p.scheduledMutex.Unlock()
+
+	if p.scheduled != nil {
+		return fmt.Errorf("a stream is already scheduled for this room")
+	}
+	if !at.After(time.Now()) {
+		return fmt.Errorf("streams must be scheduled for a time in the future")
+	}
+
+	p.scheduled = &scheduledStream{
+		stream:      s,
+		at:          at,
+		scheduledBy: scheduledBy,
+		stopChan:    make(chan bool, 1),
+	}
+
+	// mark the stream as unreapable until it starts or is unscheduled
+	s.Metadata().AddParentRef(p)
+
+	go countdownScheduledStream(p, p.scheduled)
+	return nil
+}
+
+// UnscheduleStream disarms the room's scheduled stream.
+// Returns a boolean (false) if no stream was scheduled.
+func (p *Playback) UnscheduleStream() bool {
+	p.scheduledMutex.Lock()
+	defer p.scheduledMutex.Unlock()
+
+	if p.scheduled == nil {
+		return false
+	}
+
+	p.scheduled.stopChan <- true
+	p.scheduled.stream.Metadata().RemoveParentRef(p)
+	p.scheduled = nil
+	return true
+}
+
+// ScheduledStream returns the status of the room's scheduled
+// stream, or a boolean (false) if no stream is scheduled.
+func (p *Playback) ScheduledStream() (*ScheduleStatus, bool) {
+	p.scheduledMutex.Lock()
+	defer p.scheduledMutex.Unlock()
+
+	if p.scheduled == nil {
+		return nil, false
+	}
+
+	return &ScheduleStatus{
+		Url:         p.scheduled.stream.GetStreamURL(),
+		Name:        p.scheduled.stream.GetName(),
+		At:          p.scheduled.at.Unix(),
+		Remaining:   secondsUntil(p.scheduled.at),
+		ScheduledBy: p.scheduled.scheduledBy,
+	}, true
+}
+
+// OnScheduleTick appends a callback to be called every second
+// while a stream is scheduled, and once the stream starts.
+func (p *Playback) OnScheduleTick(callback ScheduleCallback) {
+	p.scheduleCallbacks = append(p.scheduleCallbacks, callback)
+}
+
+// startScheduledStream loads and plays the given scheduled stream
+// if it is still the room's scheduled stream.
+func (p *Playback) startScheduledStream(s *scheduledStream) {
+	p.scheduledMutex.Lock()
+	if p.scheduled != s {
+		p.scheduledMutex.Unlock()
+		return
+	}
+	p.scheduled = nil
+	p.scheduledMutex.Unlock()
+
+	p.SetStream(s.stream)
+	p.Reset()
+	p.StartPlayback()
+}
+
+func countdownScheduledStream(p *Playback, s *scheduledStream) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		remaining := secondsUntil(s.at)
+		if remaining <= 0 {
+			p.startScheduledStream(s)
+		}
+
+		for _, cb := range p.scheduleCallbacks {
+			cb(remaining)
+		}
+
+		if remaining <= 0 {
+			return
+		}
+	}
+}
+
+// secondsUntil returns the number of whole seconds, rounded
+// up, from now until the given time; 0 if it has passed.
+func secondsUntil(t time.Time) int {
+	d := time.Until(t)
+	if d <= 0 {
+		return 0
+	}
+
+	return int((d + time.Second - 1) / time.Second)
+}
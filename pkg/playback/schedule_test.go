@@ -0,0 +1,91 @@
+package playback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestScheduleStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		unschedule   bool
+		expectTicks  []int
+		expectStream bool
+	}{
+		{
+			name:         "scheduled stream starts on the final tick",
+			expectTicks:  []int{1, 0},
+			expectStream: true,
+		},
+		{
+			name:       "unscheduled stream never starts",
+			unschedule: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+
+			ticks := make(chan int, 10)
+			p.OnScheduleTick(func(remaining int) {
+				ticks <- remaining
+			})
+
+			url := "http://example.com/scheduled.mp4"
+			if err := p.ScheduleStream(stream.NewRemoteVideoStream(url), time.Now().Add(1500*time.Millisecond), "organizer"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status, exists := p.ScheduledStream(); !exists || status.Url != url || status.Remaining != 2 {
+				t.Fatalf("expected %q to be scheduled to start in 2 seconds, got %v", url, status)
+			}
+
+			if tc.unschedule {
+				if !p.UnscheduleStream() {
+					t.Fatalf("expected the scheduled stream to be cancelled")
+				}
+				if _, exists := p.ScheduledStream(); exists {
+					t.Errorf("expected no scheduled stream once cancelled")
+				}
+			}
+
+			got := []int{}
+			timeout := time.After(2500 * time.Millisecond)
+		collect:
+			for {
+				select {
+				case remaining := <-ticks:
+					got = append(got, remaining)
+					if remaining == 0 {
+						break collect
+					}
+				case <-timeout:
+					break collect
+				}
+			}
+
+			if len(got) != len(tc.expectTicks) {
+				t.Fatalf("expected ticks %v, got %v", tc.expectTicks, got)
+			}
+			for i := range got {
+				if got[i] != tc.expectTicks[i] {
+					t.Fatalf("expected ticks %v, got %v", tc.expectTicks, got)
+				}
+			}
+
+			s, exists := p.GetStream()
+			if exists != tc.expectStream {
+				t.Fatalf("expected a stream to be playing: %v, got %v", tc.expectStream, exists)
+			}
+			if tc.expectStream && s.GetStreamURL() != url {
+				t.Errorf("expected the scheduled stream %q to be playing, got %q", url, s.GetStreamURL())
+			}
+			if _, exists := p.ScheduledStream(); exists {
+				t.Errorf("expected no scheduled stream to remain")
+			}
+		})
+	}
+}
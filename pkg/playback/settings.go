@@ -0,0 +1,55 @@
+package playback
+
+import "encoding/json"
+
+// RoomSettings is a serializable schema representing
+// the room-wide settings currently in effect.
+// Implements api.ApiCodec.
+type RoomSettings struct {
+	Listed          bool   `json:"listed"`
+	Presentation    bool   `json:"presentation"`
+	PlaybackLocked  bool   `json:"playbackLocked"`
+	QueueLocked     bool   `json:"queueLocked"`
+	LockedBy        string `json:"lockedBy,omitempty"`
+	SlowMode        int    `json:"slowMode"`
+	MaxQueueItems   int    `json:"maxQueueItems"`
+	Duplicates      string `json:"duplicates"`
+	QueueMode       string `json:"queueMode"`
+	Protected       bool   `json:"protected"`
+	AnnounceStreams bool   `json:"announceStreams"`
+	Markdown        bool   `json:"markdown"`
+	WordFilter      string `json:"wordFilter"`
+	Countdown       int    `json:"countdown"`
+	Capacity        int    `json:"capacity"`
+}
+
+func (s *RoomSettings) Serialize() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Settings returns the room-wide settings currently in effect
+func (p *Playback) Settings() *RoomSettings {
+	_, lockedByName, playbackLocked := p.LockedBy()
+	_, queueLockedByName, queueLocked := p.QueueLockedBy()
+	if len(lockedByName) == 0 {
+		lockedByName = queueLockedByName
+	}
+
+	return &RoomSettings{
+		Listed:          p.Listed(),
+		Presentation:    p.PresentationMode(),
+		PlaybackLocked:  playbackLocked,
+		QueueLocked:     queueLocked,
+		LockedBy:        lockedByName,
+		SlowMode:        int(p.SlowMode().Seconds()),
+		MaxQueueItems:   p.MaxQueueItems(),
+		Duplicates:      string(p.DuplicatePolicy()),
+		QueueMode:       string(p.QueueMode()),
+		Protected:       p.HasPassword(),
+		AnnounceStreams: p.AnnounceStreams(),
+		Markdown:        p.Markdown(),
+		WordFilter:      string(p.WordFilterMode()),
+		Countdown:       p.Countdown(),
+		Capacity:        p.Capacity(),
+	}
+}
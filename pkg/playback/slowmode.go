@@ -0,0 +1,63 @@
+package playback
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// slowMode is a concurrency-safe record of the minimum interval
+// between chat messages and of when each client last sent one
+type slowMode struct {
+	mutex        sync.Mutex
+	interval     time.Duration
+	lastMessages map[string]time.Time
+}
+
+// SetSlowMode sets the minimum amount of time each client must
+// wait between chat messages. An interval of zero disables
+// slow mode.
+func (p *Playback) SetSlowMode(interval time.Duration) {
+	p.slowMode.mutex.Lock()
+	p.slowMode.interval = interval
+	p.slowMode.lastMessages = make(map[string]time.Time)
+	p.slowMode.mutex.Unlock()
+
+	p.SetLastUpdated(time.Now())
+}
+
+// SlowMode returns the minimum amount of time each
+// client must wait between chat messages.
+func (p *Playback) SlowMode() time.Duration {
+	p.slowMode.mutex.Lock()
+	defer p.slowMode.mutex.Unlock()
+
+	return p.slowMode.interval
+}
+
+// RecordChatMessage records a chat message sent by the client with
+// the given id. The holder of the playback lock is exempt from
+// slow mode.
+// Returns an error if slow mode is enabled and the client has
+// sent a message too recently.
+func (p *Playback) RecordChatMessage(id string) error {
+	if p.SlowMode() == 0 {
+		return nil
+	}
+	if lockedBy, _, locked := p.LockedBy(); locked && lockedBy == id {
+		return nil
+	}
+
+	p.slowMode.mutex.Lock()
+	defer p.slowMode.mutex.Unlock()
+
+	now := time.Now()
+	if last, exists := p.slowMode.lastMessages[id]; exists {
+		if wait := p.slowMode.interval - now.Sub(last); wait > 0 {
+			return fmt.Errorf("error: slow mode is enabled - you may send another message in %v", wait.Round(time.Second))
+		}
+	}
+
+	p.slowMode.lastMessages[id] = now
+	return nil
+}
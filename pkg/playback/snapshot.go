@@ -0,0 +1,152 @@
+package playback
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/playback/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// SnapshotItem is a stream saved as part of a room snapshot
+type SnapshotItem struct {
+	Url   string `json:"url"`
+	Start int    `json:"start,omitempty"`
+	End   int    `json:"end,omitempty"`
+}
+
+// Snapshot is a serializable schema representing a room's
+// queue, current stream, and current stream position.
+type Snapshot struct {
+	Stream *SnapshotItem   `json:"stream,omitempty"`
+	Time   int             `json:"time"`
+	Queue  []*SnapshotItem `json:"queue"`
+}
+
+// Token encodes the snapshot into a url-safe string
+// that can later be decoded with SnapshotFromToken.
+func (s *Snapshot) Token() (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SnapshotFromToken decodes a token created by Snapshot.Token
+func SnapshotFromToken(token string) (*Snapshot, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("error: invalid snapshot token")
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(b, snapshot); err != nil {
+		return nil, fmt.Errorf("error: invalid snapshot token")
+	}
+
+	return snapshot, nil
+}
+
+// Snapshot returns the current state of the room's queue and stream
+func (p *Playback) Snapshot() *Snapshot {
+	snapshot := &Snapshot{
+		Queue: []*SnapshotItem{},
+	}
+
+	if s, exists := p.GetStream(); exists {
+		snapshot.Stream = snapshotItemFromStream(s)
+		snapshot.Time = p.GetTime()
+	}
+
+	for _, q := range p.GetQueue().List() {
+		userQueue, ok := q.(queue.AggregatableQueue)
+		if !ok {
+			continue
+		}
+
+		for _, item := range userQueue.List() {
+			if s, ok := item.(stream.Stream); ok {
+				snapshot.Queue = append(snapshot.Queue, snapshotItemFromStream(s))
+			}
+		}
+	}
+
+	return snapshot
+}
+
+// LoadSnapshot populates the room from the given snapshot. Queued
+// streams are added to the given user's queue, and the snapshot's
+// stream is loaded at its saved position. Streams that cannot be
+// loaded are skipped.
+func (p *Playback) LoadSnapshot(snapshot *Snapshot, user *client.Client, streamHandler stream.StreamHandler) error {
+	if len(snapshot.Queue) > 0 {
+		userQueue, exists, err := util.GetUserQueue(user, p.GetQueue())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			userQueue = queue.NewAggregatableQueue(user.UUID())
+			if err := p.GetQueue().Push(userQueue); err != nil {
+				return err
+			}
+		}
+
+		for _, item := range snapshot.Queue {
+			if userQueue.Size() >= p.MaxQueueItems() {
+				break
+			}
+
+			s, err := p.streamFromSnapshotItem(item, user, streamHandler)
+			if err != nil {
+				p.logger.Infof("PLAYBACK SNAPSHOT skipping queued stream %q: %v", item.Url, err)
+				continue
+			}
+
+			p.PushToQueue(userQueue, s)
+		}
+	}
+
+	if snapshot.Stream != nil {
+		s, err := p.streamFromSnapshotItem(snapshot.Stream, user, streamHandler)
+		if err != nil {
+			return err
+		}
+
+		p.SetStream(s)
+		p.Reset()
+		p.SetTime(snapshot.Time)
+	}
+
+	return nil
+}
+
+func (p *Playback) streamFromSnapshotItem(item *SnapshotItem, user *client.Client, streamHandler stream.StreamHandler) (stream.Stream, error) {
+	s, err := p.GetOrCreateStreamFromUrl(item.Url, user, streamHandler, func(data []byte, created bool, err error) {})
+	if err != nil {
+		return nil, err
+	}
+
+	if item.End > 0 {
+		return stream.NewTrimmedStream(s, item.Start, item.End)
+	}
+
+	return s, nil
+}
+
+func snapshotItemFromStream(s stream.Stream) *SnapshotItem {
+	item := &SnapshotItem{
+		Url: s.GetStreamURL(),
+	}
+
+	if trimmed, ok := s.(*stream.TrimmedStream); ok {
+		item.Start = trimmed.GetStart()
+		item.End = trimmed.GetEnd()
+	}
+
+	return item
+}
@@ -0,0 +1,60 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// SetSubtitleTrack selects the subtitle track, with the given id, of the
+// current stream, which every client in the room should enable. Returns
+// an error if no stream is loaded, or the stream has no such track.
+func (p *Playback) SetSubtitleTrack(id string) error {
+	s, exists := p.GetStream()
+	if !exists {
+		return fmt.Errorf("no stream is currently loaded")
+	}
+
+	tracks := s.GetSubtitles()
+	if len(tracks) == 0 {
+		return fmt.Errorf("the current stream has no subtitle tracks")
+	}
+
+	for _, track := range tracks {
+		if track.Id == id {
+			p.subtitleTrack = id
+			p.SetLastUpdated(time.Now())
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the current stream has no subtitle track %q", id)
+}
+
+// ClearSubtitleTrack deselects the room's subtitle track,
+// so that every client in the room disables subtitles
+func (p *Playback) ClearSubtitleTrack() {
+	p.subtitleTrack = ""
+	p.SetLastUpdated(time.Now())
+}
+
+// SubtitleTrack returns the subtitle track selected for the room's
+// current stream, or a boolean (false) if none is selected
+func (p *Playback) SubtitleTrack() (stream.SubtitleTrack, bool) {
+	if len(p.subtitleTrack) == 0 {
+		return stream.SubtitleTrack{}, false
+	}
+
+	s, exists := p.GetStream()
+	if !exists {
+		return stream.SubtitleTrack{}, false
+	}
+
+	for _, track := range s.GetSubtitles() {
+		if track.Id == p.subtitleTrack {
+			return track, true
+		}
+	}
+	return stream.SubtitleTrack{}, false
+}
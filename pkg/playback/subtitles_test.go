@@ -0,0 +1,90 @@
+package playback
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestSubtitleTrackStatus(t *testing.T) {
+	captions := []byte(`{"items": [
+		{"id": "en1", "snippet": {"language": "en", "name": "English"}},
+		{"id": "fr1", "snippet": {"language": "fr", "name": "Français"}}
+	]}`)
+
+	tests := []struct {
+		name        string
+		captions    []byte
+		trackId     string
+		expectErr   bool
+		expectTrack string
+	}{
+		{
+			name:        "selected track is included in the status",
+			captions:    captions,
+			trackId:     "fr1",
+			expectTrack: "fr1",
+		},
+		{
+			name:      "unknown tracks cannot be selected",
+			captions:  captions,
+			trackId:   "de1",
+			expectErr: true,
+		},
+		{
+			name:      "streams without captions",
+			captions:  []byte(`{"items": []}`),
+			trackId:   "en1",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracks, err := stream.ParseYouTubeCaptionTracks(tc.captions)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := json.Marshal(map[string]interface{}{"subtitles": tracks})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			s := stream.NewLocalVideoStream("movie.mp4")
+			if err := s.SetInfo(data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			p := NewPlayback(connection.NewNamespace("room"))
+			p.SetStream(s)
+
+			err = p.SetSubtitleTrack(tc.trackId)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+
+			b, err := p.GetStatus().Serialize()
+			if err != nil {
+				t.Fatalf("unexpected error serializing status: %v", err)
+			}
+			status := struct {
+				SubtitleTrack *stream.SubtitleTrack `json:"subtitleTrack"`
+			}{}
+			if err := json.Unmarshal(b, &status); err != nil {
+				t.Fatalf("unexpected error decoding status: %v", err)
+			}
+
+			if len(tc.expectTrack) == 0 {
+				if status.SubtitleTrack != nil {
+					t.Errorf("expected no subtitle track in the status, got %v", status.SubtitleTrack)
+				}
+				return
+			}
+			if status.SubtitleTrack == nil || status.SubtitleTrack.Id != tc.expectTrack {
+				t.Errorf("expected subtitle track %q in the status, got %v", tc.expectTrack, status.SubtitleTrack)
+			}
+		})
+	}
+}
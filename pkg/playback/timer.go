@@ -3,10 +3,11 @@ package playback
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
 	api "github.com/juanvallejo/streaming-server/pkg/api/types"
+	"github.com/juanvallejo/streaming-server/pkg/logging"
 )
 
 const (
@@ -25,6 +26,12 @@ type Timer struct {
 	state     int
 	callbacks []TimerCallback
 	timeChan  chan int
+
+	// guards time, state and callbacks, which are used by
+	// the incrementing goroutine and the countdown goroutine
+	mutex sync.Mutex
+	// closed once the timer is torn down
+	closed chan struct{}
 }
 
 func (t *Timer) Play() error {
@@ -32,12 +39,19 @@ func (t *Timer) Play() error {
 		panic("attempt to start a nil timer channel")
 	}
 
+	if t.IsClosed() {
+		return fmt.Errorf("attempt to play a closed timer")
+	}
+
+	t.mutex.Lock()
 	if t.state == TIMER_PLAY {
-		log.Printf("STREAM PLAYBACK TIMER attempt to play an already playing timer, ignoring...")
+		t.mutex.Unlock()
+		logging.Default.Warnf("STREAM PLAYBACK TIMER attempt to play an already playing timer, ignoring...")
 		return nil
 	}
-
 	t.state = TIMER_PLAY
+	t.mutex.Unlock()
+
 	go Increment(t, t.timeChan)
 	return nil
 }
@@ -47,12 +61,17 @@ func (t *Timer) Stop() error {
 		panic("attempt to stop a nil timer channel")
 	}
 
+	t.mutex.Lock()
 	t.time = 0
 	if t.state != TIMER_PLAY {
+		t.mutex.Unlock()
 		return nil
 	}
-
 	t.state = TIMER_STOP
+	t.mutex.Unlock()
+
+	// the signal is sent without holding the mutex,
+	// which the incrementing goroutine needs to exit
 	t.timeChan <- TIMER_STOP
 	return nil
 }
@@ -62,11 +81,14 @@ func (t *Timer) Pause() error {
 		panic("attempt to pause a nil timer channel")
 	}
 
+	t.mutex.Lock()
 	if t.state != TIMER_PLAY {
+		t.mutex.Unlock()
 		return nil
 	}
-
 	t.state = TIMER_PAUSE
+	t.mutex.Unlock()
+
 	t.timeChan <- TIMER_PAUSE
 	return nil
 }
@@ -76,19 +98,74 @@ func (t *Timer) Set(time int) error {
 		return fmt.Errorf("time must be a positive integer")
 	}
 
-	t.time = time
+	t.setTime(time)
 	return nil
 }
 
+func (t *Timer) setTime(time int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.time = time
+}
+
 func (t *Timer) OnTick(callback TimerCallback) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.IsClosed() {
+		return
+	}
 	t.callbacks = append(t.callbacks, callback)
 }
 
+// Close stops the timer and removes its tick callbacks. Once closed,
+// the timer may not be played again, and no further ticks occur.
+func (t *Timer) Close() {
+	t.mutex.Lock()
+	if t.IsClosed() {
+		t.mutex.Unlock()
+		return
+	}
+
+	close(t.closed)
+	t.callbacks = []TimerCallback{}
+	t.mutex.Unlock()
+
+	t.Stop()
+}
+
+// IsClosed returns true if the timer has been closed
+func (t *Timer) IsClosed() bool {
+	select {
+	case <-t.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// tickCallbacks returns the callbacks to call on the current tick
+func (t *Timer) tickCallbacks() []TimerCallback {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	callbacks := make([]TimerCallback, len(t.callbacks))
+	copy(callbacks, t.callbacks)
+	return callbacks
+}
+
 func (t *Timer) GetTime() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	return t.time
 }
 
 func (t *Timer) State() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	return t.state
 }
 
@@ -111,6 +188,14 @@ func (s *TimerStatus) Serialize() ([]byte, error) {
 }
 
 func (t *Timer) Status() api.ApiCodec {
+	return t.Snapshot()
+}
+
+// Snapshot returns a summary of the current state of the Timer
+func (t *Timer) Snapshot() *TimerStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	return &TimerStatus{
 		IsPlaying: t.state == TIMER_PLAY,
 		IsStopped: t.state == TIMER_STOP,
@@ -122,7 +207,7 @@ func (t *Timer) Status() api.ApiCodec {
 // Increment is a convenience function for incrementing
 // a timer's time value every second. If a timer.callback
 // func exists, it is called every increment interval.
-// Warning: this is not a concurrency-safe function
+// The timer's time is only changed while holding its mutex.
 func Increment(timer *Timer, c chan int) {
 	if timer == nil {
 		panic("attempt to increment a nil timer")
@@ -130,22 +215,26 @@ func Increment(timer *Timer, c chan int) {
 
 	for {
 		time.Sleep(time.Duration(1 * time.Second))
+		if timer.IsClosed() {
+			return
+		}
+		timer.mutex.Lock()
 		timer.time++
+		now := timer.time
+		timer.mutex.Unlock()
 
-		if len(timer.callbacks) > 0 {
-			for _, c := range timer.callbacks {
-				c(timer.time)
-			}
+		for _, c := range timer.tickCallbacks() {
+			c(now)
 		}
 
 		select {
 		case sig := <-c:
 			if sig == TIMER_PAUSE || sig == TIMER_STOP {
-				log.Printf("STREAM PLAYBACK TIMER kill signal received: %v", sig)
+				logging.Default.Debugf("STREAM PLAYBACK TIMER kill signal received: %v", sig)
 				return
 			}
 
-			log.Printf("STREAM PLAYBACK TIMER invalid timer signal code: %v is not a recognized channel operation", sig)
+			logging.Default.Errorf("STREAM PLAYBACK TIMER invalid timer signal code: %v is not a recognized channel operation", sig)
 		default:
 			continue
 		}
@@ -157,5 +246,6 @@ func NewTimer() *Timer {
 		state:     TIMER_STOP,
 		timeChan:  make(chan int, MAX_TIMER_CHAN_BUFFER),
 		callbacks: []TimerCallback{},
+		closed:    make(chan struct{}),
 	}
 }
@@ -0,0 +1,82 @@
+package playback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestTimerTeardown(t *testing.T) {
+	tests := []struct {
+		name     string
+		teardown func(h PlaybackHandler, p *Playback)
+	}{
+		{
+			name: "closed timer",
+			teardown: func(h PlaybackHandler, p *Playback) {
+				p.timer.Close()
+			},
+		},
+		{
+			name: "cleaned up room",
+			teardown: func(h PlaybackHandler, p *Playback) {
+				p.Cleanup()
+			},
+		},
+		{
+			name: "reaped room",
+			teardown: func(h PlaybackHandler, p *Playback) {
+				if !h.ReapPlayback(p) {
+					t.Fatalf("expected the room to be reaped")
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nsHandler := connection.NewNamespaceHandler()
+			h := NewHandler(nsHandler)
+			p := h.NewPlayback(nsHandler.NewNamespace("room"), nil, nil)
+
+			ticks := make(chan int, 10)
+			p.timer.OnTick(func(now int) {
+				ticks <- now
+			})
+
+			if err := p.Play(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			select {
+			case <-ticks:
+			case <-time.After(2 * time.Second):
+				t.Fatalf("expected the tick callback to fire while playing")
+			}
+
+			tc.teardown(h, p)
+
+			// a tick already in progress may still complete
+			time.Sleep(100 * time.Millisecond)
+			for len(ticks) > 0 {
+				<-ticks
+			}
+
+			select {
+			case now := <-ticks:
+				t.Errorf("expected no ticks after teardown, got a tick at %v", now)
+			case <-time.After(1500 * time.Millisecond):
+			}
+
+			if err := p.timer.Play(); err == nil {
+				t.Errorf("expected the torn down timer not to be played again")
+			}
+			p.timer.OnTick(func(int) {
+				t.Errorf("expected no callbacks to be added once torn down")
+			})
+			if callbacks := p.timer.tickCallbacks(); len(callbacks) != 0 {
+				t.Errorf("expected no tick callbacks once torn down, got %v", len(callbacks))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package playback
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	MaxTopicLength       = 120 // maximum number of characters in a room's topic
+	MaxDescriptionLength = 500 // maximum number of characters in a room's description
+)
+
+// RoomTopic is a serializable schema describing
+// a room's human-facing topic and description.
+// Implements api.ApiCodec.
+type RoomTopic struct {
+	Topic       string `json:"topic"`
+	Description string `json:"description"`
+	SetBy       string `json:"setBy,omitempty"`
+}
+
+func (t *RoomTopic) Serialize() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// SetTopic updates the room's topic on behalf of the user with the given name.
+// Returns an error if the topic exceeds MaxTopicLength characters.
+func (p *Playback) SetTopic(topic, setBy string) error {
+	if len([]rune(topic)) > MaxTopicLength {
+		return fmt.Errorf("topics may be at most %v characters long", MaxTopicLength)
+	}
+
+	p.topic.Topic = topic
+	p.topic.SetBy = setBy
+	return nil
+}
+
+// SetDescription updates the room's description on behalf of the user with the
+// given name. Returns an error if the description exceeds MaxDescriptionLength
+// characters.
+func (p *Playback) SetDescription(description, setBy string) error {
+	if len([]rune(description)) > MaxDescriptionLength {
+		return fmt.Errorf("descriptions may be at most %v characters long", MaxDescriptionLength)
+	}
+
+	p.topic.Description = description
+	p.topic.SetBy = setBy
+	return nil
+}
+
+// ClearTopic removes the room's topic and description
+func (p *Playback) ClearTopic() {
+	p.topic = RoomTopic{}
+}
+
+// HasTopic returns true if the room has a topic or a description
+func (p *Playback) HasTopic() bool {
+	return len(p.topic.Topic) > 0 || len(p.topic.Description) > 0
+}
+
+// Topic returns a copy of the room's current topic and description
+func (p *Playback) Topic() *RoomTopic {
+	topic := p.topic
+	return &topic
+}
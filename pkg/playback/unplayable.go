@@ -0,0 +1,29 @@
+package playback
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// SkipUnplayable advances past the current stream if its provider has
+// reported it as unplayable, along with any unplayable streams loaded
+// after it. Returns the skipped streams, and a boolean (true) if a
+// playable stream is now loaded.
+func (p *Playback) SkipUnplayable() ([]stream.Stream, bool, error) {
+	skipped := []stream.Stream{}
+	for {
+		current, exists := p.GetStream()
+		if !exists || !stream.IsUnplayable(current) {
+			return skipped, exists, nil
+		}
+
+		skipped = append(skipped, current)
+
+		next, loaded, err := p.AdvanceQueue()
+		if err != nil {
+			return skipped, false, err
+		}
+		if !loaded || next == current {
+			return skipped, false, nil
+		}
+	}
+}
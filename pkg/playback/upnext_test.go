@@ -0,0 +1,77 @@
+package playback
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestUpNext(t *testing.T) {
+	defer func(size int) { QueuePreviewSize = size }(QueuePreviewSize)
+
+	tests := []struct {
+		name        string
+		previewSize int
+		expectLen   int
+	}{
+		{
+			name:        "preview truncated at the limit",
+			previewSize: 3,
+			expectLen:   3,
+		},
+		{
+			name:        "preview of the whole queue",
+			previewSize: 10,
+			expectLen:   5,
+		},
+		{
+			name:        "no preview",
+			previewSize: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			QueuePreviewSize = tc.previewSize
+			p := NewPlayback(connection.NewNamespace("room"))
+
+			queued := map[string][]string{
+				"alice": {"http://example.com/a1.mp4", "http://example.com/a2.mp4", "http://example.com/a3.mp4"},
+				"bob":   {"http://example.com/b1.mp4", "http://example.com/b2.mp4"},
+			}
+			for _, user := range []string{"alice", "bob"} {
+				userQueue := queue.NewAggregatableQueue(user)
+				if err := p.GetQueue().Push(userQueue); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, url := range queued[user] {
+					if err := p.PushToQueue(userQueue, stream.NewRemoteVideoStream(url)); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				}
+			}
+
+			status, ok := p.GetStatus().(*PlaybackStatus)
+			if !ok {
+				t.Fatalf("expected status of type *PlaybackStatus, got %T", p.GetStatus())
+			}
+			if len(status.UpNext) != tc.expectLen {
+				t.Fatalf("expected %v streams up next, got %v", tc.expectLen, status.UpNext)
+			}
+
+			// the preview matches the order the queue is played in
+			for i, item := range status.UpNext {
+				next, err := p.NextQueueItem()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				s := next.(stream.Stream)
+				if item.Url != s.GetStreamURL() || item.Id != s.UUID() {
+					t.Errorf("expected stream %v up next to be %q, got %q", i, s.GetStreamURL(), item.Url)
+				}
+			}
+		})
+	}
+}
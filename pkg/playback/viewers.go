@@ -0,0 +1,65 @@
+package playback
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	MaxViewerSamples = 500 // maximum number of viewer-count samples kept per room
+)
+
+// ViewerSample is the number of viewers
+// in a room at a given point in time.
+type ViewerSample struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+// ViewerHistory is a serializable schema representing
+// a room's recent viewer-count samples.
+// Implements api.ApiCodec.
+type ViewerHistory struct {
+	Samples []ViewerSample `json:"samples"`
+}
+
+func (h *ViewerHistory) Serialize() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+// RecordViewerCount appends a sample with the given viewer count
+// to the room's viewer history, discarding the oldest sample once
+// MaxViewerSamples is reached. Counts identical to the most recent
+// sample are not recorded.
+func (p *Playback) RecordViewerCount(count int) {
+	if len(p.viewerSamples) > 0 && p.viewerSamples[len(p.viewerSamples)-1].Count == count {
+		return
+	}
+
+	p.viewerSamples = append(p.viewerSamples, ViewerSample{
+		Time:  time.Now(),
+		Count: count,
+	})
+	if len(p.viewerSamples) > MaxViewerSamples {
+		p.viewerSamples = p.viewerSamples[len(p.viewerSamples)-MaxViewerSamples:]
+	}
+}
+
+// ViewerHistory returns up to the given number of the most
+// recent viewer-count samples, oldest first. A limit of zero
+// or less returns every stored sample.
+func (p *Playback) ViewerHistory(limit int) []ViewerSample {
+	samples := p.viewerSamples
+	if limit > 0 && len(samples) > limit {
+		samples = samples[len(samples)-limit:]
+	}
+
+	history := make([]ViewerSample, len(samples))
+	copy(history, samples)
+	return history
+}
+
+// ClearViewerHistory removes all viewer-count samples for the room
+func (p *Playback) ClearViewerHistory() {
+	p.viewerSamples = []ViewerSample{}
+}
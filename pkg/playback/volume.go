@@ -0,0 +1,37 @@
+package playback
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MinRoomVolume = 0   // lowest volume level a room may suggest
+	MaxRoomVolume = 100 // highest volume level a room may suggest
+)
+
+// SetVolume sets the volume level suggested to every client in the
+// room. Clients may still override it locally. Returns an error if
+// the level is outside of the range MinRoomVolume-MaxRoomVolume.
+func (p *Playback) SetVolume(level int) error {
+	if level < MinRoomVolume || level > MaxRoomVolume {
+		return fmt.Errorf("volume must be between %v and %v", MinRoomVolume, MaxRoomVolume)
+	}
+
+	p.volume = level
+	p.hasVolume = true
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// ClearVolume stops suggesting a volume level to the room's clients
+func (p *Playback) ClearVolume() {
+	p.hasVolume = false
+	p.SetLastUpdated(time.Now())
+}
+
+// Volume returns the volume level suggested to every client
+// in the room, or a boolean (false) if none is suggested
+func (p *Playback) Volume() (int, bool) {
+	return p.volume, p.hasVolume
+}
@@ -0,0 +1,64 @@
+package playback
+
+import (
+	"math"
+	"sync"
+)
+
+var (
+	SkipVoteFraction = 0.5 // fraction of a room's clients that must vote before a stream is skipped
+)
+
+// skipVotes is a concurrency-safe set of ids of
+// clients who voted to skip the current stream
+type skipVotes struct {
+	mutex sync.Mutex
+	byId  map[string]bool
+}
+
+// AddSkipVote records a vote to skip the current stream from
+// the client with the given id.
+// Returns a boolean (false) if the client had already voted.
+func (p *Playback) AddSkipVote(id string) bool {
+	p.skipVotes.mutex.Lock()
+	defer p.skipVotes.mutex.Unlock()
+
+	if _, exists := p.skipVotes.byId[id]; exists {
+		return false
+	}
+
+	p.skipVotes.byId[id] = true
+	return true
+}
+
+// RemoveSkipVote discards the vote from the client with the given id
+func (p *Playback) RemoveSkipVote(id string) {
+	p.skipVotes.mutex.Lock()
+	defer p.skipVotes.mutex.Unlock()
+
+	delete(p.skipVotes.byId, id)
+}
+
+// SkipVotes returns the number of distinct clients
+// who have voted to skip the current stream.
+func (p *Playback) SkipVotes() int {
+	p.skipVotes.mutex.Lock()
+	defer p.skipVotes.mutex.Unlock()
+
+	return len(p.skipVotes.byId)
+}
+
+// ClearSkipVotes discards all votes to skip the current stream
+func (p *Playback) ClearSkipVotes() {
+	p.skipVotes.mutex.Lock()
+	defer p.skipVotes.mutex.Unlock()
+
+	p.skipVotes.byId = make(map[string]bool)
+}
+
+// RequiredSkipVotes returns the number of votes needed to skip the
+// current stream in a room with the given number of clients - the
+// smallest number of votes exceeding SkipVoteFraction of the clients.
+func RequiredSkipVotes(clients int) int {
+	return int(math.Floor(float64(clients)*SkipVoteFraction)) + 1
+}
@@ -0,0 +1,206 @@
+package playback
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// WordFilterMode determines what happens to chat
+// messages containing words filtered by a room.
+type WordFilterMode string
+
+const (
+	// WORD_FILTER_OFF broadcasts chat messages as sent
+	WORD_FILTER_OFF WordFilterMode = "off"
+	// WORD_FILTER_MASK replaces each character of a
+	// filtered word with an asterisk before broadcast
+	WORD_FILTER_MASK WordFilterMode = "mask"
+	// WORD_FILTER_REJECT refuses to broadcast
+	// messages containing a filtered word
+	WORD_FILTER_REJECT WordFilterMode = "reject"
+
+	MaxFilteredWords = 500 // max number of words filtered by a single room
+)
+
+var (
+	// DefaultWordFilterMode is the word filter mode of newly created rooms
+	DefaultWordFilterMode = WORD_FILTER_OFF
+	// DefaultFilteredWords are the words filtered by newly created rooms
+	DefaultFilteredWords = []string{}
+)
+
+// wordFilter is a concurrency-safe set of lower-cased words
+type wordFilter struct {
+	mutex sync.Mutex
+	mode  WordFilterMode
+	words map[string]bool
+}
+
+// newWordFilter returns a word filter using the
+// default word filter mode and list of words
+func newWordFilter() *wordFilter {
+	f := &wordFilter{
+		mode:  DefaultWordFilterMode,
+		words: make(map[string]bool),
+	}
+	for _, word := range DefaultFilteredWords {
+		if err := ValidateFilteredWord(word); err == nil {
+			f.words[strings.ToLower(word)] = true
+		}
+	}
+	return f
+}
+
+// ValidateFilteredWord returns an error if the given word
+// is not a single word made of letters and digits
+func ValidateFilteredWord(word string) error {
+	if len(word) == 0 {
+		return fmt.Errorf("a word to filter is required")
+	}
+	for _, r := range word {
+		if !isWordRune(r) {
+			return fmt.Errorf("%q is not a single word - filtered words may only contain letters and digits", word)
+		}
+	}
+	return nil
+}
+
+// SetWordFilterMode sets what happens to chat messages containing
+// words filtered by the room. Returns an error if the mode is unknown.
+func (p *Playback) SetWordFilterMode(mode WordFilterMode) error {
+	switch mode {
+	case WORD_FILTER_OFF, WORD_FILTER_MASK, WORD_FILTER_REJECT:
+	default:
+		return fmt.Errorf("unknown word filter mode %q (expected %s, %s, or %s)", mode, WORD_FILTER_OFF, WORD_FILTER_MASK, WORD_FILTER_REJECT)
+	}
+
+	p.wordFilter.mutex.Lock()
+	p.wordFilter.mode = mode
+	p.wordFilter.mutex.Unlock()
+
+	p.SetLastUpdated(time.Now())
+	return nil
+}
+
+// WordFilterMode returns what happens to chat
+// messages containing words filtered by the room.
+func (p *Playback) WordFilterMode() WordFilterMode {
+	p.wordFilter.mutex.Lock()
+	defer p.wordFilter.mutex.Unlock()
+
+	return p.wordFilter.mode
+}
+
+// AddFilteredWords adds the given words to the room's word filter.
+// Returns the number of words added, or an error if any word is
+// invalid or the room would filter too many words.
+func (p *Playback) AddFilteredWords(words ...string) (int, error) {
+	for _, word := range words {
+		if err := ValidateFilteredWord(word); err != nil {
+			return 0, err
+		}
+	}
+
+	p.wordFilter.mutex.Lock()
+	defer p.wordFilter.mutex.Unlock()
+
+	added := []string{}
+	for _, word := range words {
+		word = strings.ToLower(word)
+		if !p.wordFilter.words[word] {
+			added = append(added, word)
+		}
+	}
+	if len(p.wordFilter.words)+len(added) > MaxFilteredWords {
+		return 0, fmt.Errorf("rooms may filter at most %v words", MaxFilteredWords)
+	}
+
+	for _, word := range added {
+		p.wordFilter.words[word] = true
+	}
+	return len(added), nil
+}
+
+// RemoveFilteredWords removes the given words from the room's
+// word filter. Returns the number of words removed.
+func (p *Playback) RemoveFilteredWords(words ...string) int {
+	p.wordFilter.mutex.Lock()
+	defer p.wordFilter.mutex.Unlock()
+
+	removed := 0
+	for _, word := range words {
+		word = strings.ToLower(word)
+		if p.wordFilter.words[word] {
+			delete(p.wordFilter.words, word)
+			removed++
+		}
+	}
+	return removed
+}
+
+// FilteredWords returns the words filtered by the room, sorted
+func (p *Playback) FilteredWords() []string {
+	p.wordFilter.mutex.Lock()
+	defer p.wordFilter.mutex.Unlock()
+
+	words := make([]string, 0, len(p.wordFilter.words))
+	for word := range p.wordFilter.words {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// FilterChatMessage applies the room's word filter to the given chat
+// message text. Words are matched case-insensitively, and only as whole
+// words, so that words containing a filtered word are left untouched.
+// Returns the text with filtered words masked if the room masks them,
+// or an error if the room rejects messages containing them.
+func (p *Playback) FilterChatMessage(text string) (string, error) {
+	p.wordFilter.mutex.Lock()
+	defer p.wordFilter.mutex.Unlock()
+
+	if p.wordFilter.mode == WORD_FILTER_OFF || len(p.wordFilter.words) == 0 {
+		return text, nil
+	}
+
+	runes := []rune(text)
+	filtered := false
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+
+		if p.wordFilter.words[strings.ToLower(string(runes[start:end]))] {
+			if p.wordFilter.mode == WORD_FILTER_REJECT {
+				return "", fmt.Errorf("error: your message contains a word that is not allowed in this room")
+			}
+
+			filtered = true
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+		}
+		start = end
+	}
+
+	if !filtered {
+		return text, nil
+	}
+	return string(runes), nil
+}
+
+// isWordRune returns true if the given rune may be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,95 @@
+package playback
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestFilterChatMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      WordFilterMode
+		words     []string
+		text      string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "filter off",
+			mode:     WORD_FILTER_OFF,
+			words:    []string{"darn"},
+			text:     "darn it",
+			expected: "darn it",
+		},
+		{
+			name:     "mask a filtered word",
+			mode:     WORD_FILTER_MASK,
+			words:    []string{"darn"},
+			text:     "darn it, darn it all",
+			expected: "**** it, **** it all",
+		},
+		{
+			name:      "reject a filtered word",
+			mode:      WORD_FILTER_REJECT,
+			words:     []string{"darn"},
+			text:      "oh darn",
+			expectErr: true,
+		},
+		{
+			name:     "words containing a filtered word are left untouched",
+			mode:     WORD_FILTER_MASK,
+			words:    []string{"ass"},
+			text:     "a classic assignment",
+			expected: "a classic assignment",
+		},
+		{
+			name:     "words are matched at the message's boundaries",
+			mode:     WORD_FILTER_MASK,
+			words:    []string{"darn"},
+			text:     "darn",
+			expected: "****",
+		},
+		{
+			name:     "punctuation separates words",
+			mode:     WORD_FILTER_MASK,
+			words:    []string{"darn"},
+			text:     "(darn)!",
+			expected: "(****)!",
+		},
+		{
+			name:     "words are matched regardless of case",
+			mode:     WORD_FILTER_MASK,
+			words:    []string{"DaRn"},
+			text:     "DARN and darn",
+			expected: "**** and ****",
+		},
+		{
+			name:     "non-ascii words are masked one character at a time",
+			mode:     WORD_FILTER_MASK,
+			words:    []string{"über"},
+			text:     "Über alles",
+			expected: "**** alles",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlayback(connection.NewNamespace("room"))
+			if err := p.SetWordFilterMode(tc.mode); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := p.AddFilteredWords(tc.words...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actual, err := p.FilterChatMessage(tc.text)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
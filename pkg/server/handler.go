@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/juanvallejo/streaming-server/pkg/api"
+	"github.com/juanvallejo/streaming-server/pkg/metrics"
 	"github.com/juanvallejo/streaming-server/pkg/server/path"
 	"github.com/juanvallejo/streaming-server/pkg/socket"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
@@ -29,9 +30,11 @@ type RequestHandler struct {
 //      location pattern ("/src/static/...") then it is served as a static file.
 //   3. If a url matches a room request regex pattern ("/v/..."), then the room index file
 //      is served back to the client.
-//   4. If a url begins with an api request prefix ("/api/..."), then the api handler
+//   4. If a url matches a room api request pattern ("/rooms/.../queue", "/rooms/.../chat"), then
+//      the socketRequestHandler is relayed the request as a rest request for the room's queue or chat.
+//   5. If a url begins with an api request prefix ("/api/..."), then the api handler
 //      is relayed the request entirely.
-//	 5. If a url does not match any of the above patterns, it is then treated as a generic
+//	 6. If a url does not match any of the above patterns, it is then treated as a generic
 // 		"path", which requires a path-handler for that specific url to have been registered.
 func (h *RequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	url := h.router.Route(r.URL.String())
@@ -60,6 +63,13 @@ func (h *RequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// handle rest requests for a room's queue or chat history
+	reg = regexp.MustCompile(path.RoomApiRegex)
+	if reg.MatchString(url) && h.sockReqHandler != nil {
+		h.sockReqHandler.ServeRoomAPI(w, r)
+		return
+	}
+
 	// handle wildcard urls for streams
 	reg = regexp.MustCompile(path.StreamRootRegex)
 	if reg.MatchString(url) {
@@ -139,6 +149,9 @@ func NewRequestHandler(socketRequestHandler *socket.Handler, connHandler connect
 		apiHandler:     api.NewHandler(connHandler),
 	}
 	addRequestHandlers(handler)
+	if socketRequestHandler != nil {
+		handler.RegisterPath(path.NewPathAnnouncements(socketRequestHandler.ServeAnnouncements))
+	}
 	return handler
 }
 
@@ -146,4 +159,5 @@ func addRequestHandlers(handler *RequestHandler) {
 	handler.RegisterPath(path.NewPathRoot())
 	handler.RegisterPath(path.NewPathRoom())
 	handler.RegisterPath(path.NewPathStream())
+	handler.RegisterPath(path.NewPathMetrics(metrics.Default))
 }
@@ -0,0 +1,31 @@
+package path
+
+import (
+	"net/http"
+)
+
+var (
+	AnnouncementsPathUrl = "/admin/announcements"
+)
+
+// AnnouncementsPathHandler implements Path and relays
+// operators' requests for server-wide announcements
+type AnnouncementsPathHandler struct {
+	*PathHandler
+
+	handler http.HandlerFunc
+}
+
+func (h *AnnouncementsPathHandler) Handle(url string, w http.ResponseWriter, r *http.Request) error {
+	h.handler(w, r)
+	return nil
+}
+
+func NewPathAnnouncements(handler http.HandlerFunc) Path {
+	return &AnnouncementsPathHandler{
+		PathHandler: &PathHandler{
+			pathUrl: AnnouncementsPathUrl,
+		},
+		handler: handler,
+	}
+}
@@ -0,0 +1,33 @@
+package path
+
+import (
+	"net/http"
+
+	"github.com/juanvallejo/streaming-server/pkg/metrics"
+)
+
+var (
+	MetricsPathUrl = "/metrics"
+)
+
+// MetricsPathHandler implements Path
+// and serves a metrics registry to Prometheus scrapers
+type MetricsPathHandler struct {
+	*PathHandler
+
+	registry *metrics.Registry
+}
+
+func (h *MetricsPathHandler) Handle(url string, w http.ResponseWriter, r *http.Request) error {
+	h.registry.ServeHTTP(w, r)
+	return nil
+}
+
+func NewPathMetrics(registry *metrics.Registry) Path {
+	return &MetricsPathHandler{
+		PathHandler: &PathHandler{
+			pathUrl: MetricsPathUrl,
+		},
+		registry: registry,
+	}
+}
@@ -18,6 +18,13 @@ var (
 	RoomRootRegex   = "^\\/v\\/.*"
 	StreamRootRegex = "^\\/s\\/.*"
 
+	// RoomApiRegex matches rest requests for a room's queue or chat history
+	RoomApiRegex = "^\\/rooms\\/[^\\/?]+\\/(queue|chat)(\\/|\\?|$)"
+
+	// StreamDataUrlPrefix is prepended to the name of a file in
+	// StreamDataRootPath to obtain the url it is served from
+	StreamDataUrlPrefix = "/s/"
+
 	StreamDataRootPath = "data"
 	FileRootPath       = "pkg/webclient"
 )
@@ -20,7 +20,12 @@ type StreamPathHandler struct {
 }
 
 func (h *StreamPathHandler) Handle(url string, w http.ResponseWriter, r *http.Request) error {
-	fpath := StreamDataFilePathFromUrl(r.URL.String())
+	fpath, err := StreamDataFilePath(strings.TrimPrefix(r.URL.Path, StreamDataUrlPrefix))
+	if err != nil {
+		log.Printf("ERR HTTP PATH rejected stream file request %q: %v", r.URL.Path, err)
+		HandleNotFound(url, w, r)
+		return nil
+	}
 
 	// determine if requested file exists
 	fileStat, err := os.Stat(fpath)
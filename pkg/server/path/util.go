@@ -4,9 +4,16 @@ import (
 	"fmt"
 	"mime"
 	"net/http"
+	"path/filepath"
 	"strings"
 )
 
+func init() {
+	// subtitle tracks stored alongside local streams are served
+	// as WebVTT, which is missing from some mime type tables
+	mime.AddExtensionType(".vtt", "text/vtt")
+}
+
 func FilePathFromRequest(r *http.Request) string {
 	return FileRootPath + r.URL.String()
 }
@@ -36,6 +43,32 @@ func StreamDataFilePathFromFilename(fname string) string {
 	return StreamDataRootPath + "/" + fname
 }
 
+// StreamDataFilePath receives the name of a file relative to
+// StreamDataRootPath and returns its path on disk. Returns an
+// error if the name is an absolute path, or resolves to a
+// location outside of StreamDataRootPath.
+func StreamDataFilePath(fname string) (string, error) {
+	if len(fname) == 0 || filepath.IsAbs(fname) {
+		return "", fmt.Errorf("invalid stream file name %q", fname)
+	}
+
+	root := filepath.Clean(StreamDataRootPath)
+	fpath := filepath.Join(root, fname)
+
+	rel, err := filepath.Rel(root, fpath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("stream file %q is outside of the media directory", fname)
+	}
+
+	return fpath, nil
+}
+
+// StreamDataUrlFromFilename receives the name of a file relative
+// to StreamDataRootPath and returns the url it is served from.
+func StreamDataUrlFromFilename(fname string) string {
+	return StreamDataUrlPrefix + filepath.ToSlash(filepath.Clean(fname))
+}
+
 func StreamDataFilePathFromUrl(url string) string {
 	return StreamDataRootPath + "/" + StreamDataFilenameFromUrl(url)
 }
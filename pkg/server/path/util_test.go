@@ -0,0 +1,79 @@
+package path
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamDataFilePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		fname      string
+		expectPath string
+		expectErr  bool
+	}{
+		{
+			name:       "file in the media root",
+			fname:      "movie.mp4",
+			expectPath: filepath.Join(StreamDataRootPath, "movie.mp4"),
+		},
+		{
+			name:       "file in a subdirectory of the media root",
+			fname:      "shows/episode.mkv",
+			expectPath: filepath.Join(StreamDataRootPath, "shows", "episode.mkv"),
+		},
+		{
+			name:       "relative segments resolving inside the media root",
+			fname:      "shows/../movie.mp4",
+			expectPath: filepath.Join(StreamDataRootPath, "movie.mp4"),
+		},
+		{
+			name:      "traversal outside of the media root",
+			fname:     "../../etc/passwd",
+			expectErr: true,
+		},
+		{
+			name:      "traversal through a subdirectory",
+			fname:     "shows/../../secret.mp4",
+			expectErr: true,
+		},
+		{
+			name:      "parent of the media root",
+			fname:     "..",
+			expectErr: true,
+		},
+		{
+			name:      "the media root itself",
+			fname:     ".",
+			expectErr: true,
+		},
+		{
+			name:      "absolute path",
+			fname:     "/etc/passwd",
+			expectErr: true,
+		},
+		{
+			name:      "empty name",
+			fname:     "",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fpath, err := StreamDataFilePath(tc.fname)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got path %q", fpath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fpath != tc.expectPath {
+				t.Errorf("expected path %q, got %q", tc.expectPath, fpath)
+			}
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package server
 
 import (
+	"context"
 	"io"
 	"log"
 	"net/http"
+	"time"
 )
 
 const (
 	defaultPort string = "8080"
 	defaultHost string = "0.0.0.0"
+
+	DefaultShutdownTimeout = 10 * time.Second // default amount of time given to shut down cleanly
 )
 
 type ServerOptions struct {
@@ -49,11 +53,19 @@ func (s *ServerOptions) Serve() {
 	log.Printf("INF HTTP Serving on %s\n", s.getAddr())
 
 	err := s.Server.ListenAndServe()
-	if err != nil {
+	if err != nil && err != http.ErrServerClosed {
 		panic(err.Error())
 	}
 }
 
+// Shutdown stops the http server from accepting new requests
+// and waits for active requests to complete, or for the
+// given context to be done.
+func (s *ServerOptions) Shutdown(ctx context.Context) error {
+	log.Printf("INF HTTP shutting down server on %s\n", s.getAddr())
+	return s.Server.Shutdown(ctx)
+}
+
 func (s *ServerOptions) getAddr() string {
 	return s.Host + ":" + s.Port
 }
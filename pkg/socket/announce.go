@@ -0,0 +1,110 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint"
+	"github.com/juanvallejo/streaming-server/pkg/socket/auth"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+const DEFAULT_MAX_ANNOUNCEMENT_LENGTH = 500
+
+// MaxAnnouncementLength is the maximum number
+// of characters in a server-wide announcement
+var MaxAnnouncementLength = DEFAULT_MAX_ANNOUNCEMENT_LENGTH
+
+// SetOperatorAuthenticator sets the validator used to verify the token
+// operators must present to make server-wide announcements. Without one,
+// announcements cannot be made.
+func (h *Handler) SetOperatorAuthenticator(v auth.Validator) {
+	h.operatorAuthenticator = v
+}
+
+// Announce sends the given message, as an "announcement" event, to every
+// room on the server. Returns the number of rooms the message was sent to.
+func (h *Handler) Announce(message string) int {
+	rooms := 0
+	for _, p := range h.PlaybackHandler.Playbacks() {
+		ns, exists := h.nsHandler.NamespaceByName(p.UUID())
+		if !exists {
+			continue
+		}
+
+		h.BroadcastToNamespace(ns, "announcement", &client.Response{
+			From:     client.USER_SYSTEM,
+			Message:  message,
+			IsSystem: true,
+		})
+		rooms++
+	}
+
+	return rooms
+}
+
+// ServeAnnouncements handles POST requests from operators to send an
+// announcement to every room on the server. Requests must carry the
+// operator token, and the announcement as a "message" form value or
+// as the "message" field of a JSON body.
+func (h *Handler) ServeAnnouncements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeRoomAPIError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed for %q", r.Method, r.URL.Path))
+		return
+	}
+
+	if h.operatorAuthenticator == nil {
+		writeRoomAPIError(w, http.StatusForbidden, fmt.Errorf("announcements are not enabled on this server"))
+		return
+	}
+
+	identity, err := auth.Authenticate(h.operatorAuthenticator, r)
+	if err != nil {
+		h.logger.Errorf("SOCKET ANNOUNCE refusing announcement request from %q: %v", r.RemoteAddr, err)
+		writeRoomAPIError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	message, err := announcementRequestMessage(r)
+	if err != nil {
+		writeRoomAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rooms := h.Announce(message)
+	h.logger.Infof("SOCKET ANNOUNCE %q sent an announcement to %v rooms: %q", identity.Subject, rooms, message)
+
+	writeRoomAPIResponse(w, &endpoint.ApiResponse{
+		Message:  fmt.Sprintf("announcement sent to %v rooms", rooms),
+		HTTPCode: http.StatusOK,
+	})
+}
+
+// announcementRequestMessage returns the announcement given with the request,
+// either as a form value or as the "message" field of a JSON body.
+func announcementRequestMessage(r *http.Request) (string, error) {
+	message := ""
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body := struct {
+			Message string `json:"message"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("malformed request body: %v", err)
+		}
+		message = body.Message
+	} else {
+		message = r.FormValue("message")
+	}
+
+	message = strings.TrimSpace(message)
+	if len(message) == 0 {
+		return "", fmt.Errorf("an announcement message is required")
+	}
+	if utf8.RuneCountInString(message) > MaxAnnouncementLength {
+		return "", fmt.Errorf("announcements may be at most %v characters long", MaxAnnouncementLength)
+	}
+	return message, nil
+}
@@ -0,0 +1,142 @@
+package socket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/auth"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+func TestServeAnnouncements(t *testing.T) {
+	h, ns := newTestHandler("a")
+	conns := []*fakeConnection{
+		connect(t, h, ns, nil, "first", "first", ""),
+		connect(t, h, h.nsHandler.NewNamespace("b"), nil, "second", "second", ""),
+		connect(t, h, h.nsHandler.NewNamespace("c"), nil, "third", "third", ""),
+	}
+
+	tests := []struct {
+		name               string
+		authenticator      auth.Validator
+		method             string
+		authorization      string
+		contentType        string
+		body               string
+		expectStatus       int
+		expectAnnouncement string
+	}{
+		{
+			name:          "announcements are not enabled",
+			method:        http.MethodPost,
+			authorization: "Bearer secret",
+			contentType:   "application/x-www-form-urlencoded",
+			body:          "message=hello",
+			expectStatus:  http.StatusForbidden,
+		},
+		{
+			name:          "request without a token",
+			authenticator: auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:        http.MethodPost,
+			contentType:   "application/x-www-form-urlencoded",
+			body:          "message=hello",
+			expectStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:          "request with the wrong token",
+			authenticator: auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:        http.MethodPost,
+			authorization: "Bearer guess",
+			contentType:   "application/x-www-form-urlencoded",
+			body:          "message=hello",
+			expectStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:               "announcement as a form value",
+			authenticator:      auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:             http.MethodPost,
+			authorization:      "Bearer secret",
+			contentType:        "application/x-www-form-urlencoded",
+			body:               "message=" + url.QueryEscape(" maintenance in 5 minutes "),
+			expectStatus:       http.StatusOK,
+			expectAnnouncement: "maintenance in 5 minutes",
+		},
+		{
+			name:               "announcement as a JSON body",
+			authenticator:      auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:             http.MethodPost,
+			authorization:      "Bearer secret",
+			contentType:        "application/json",
+			body:               `{"message": "back online"}`,
+			expectStatus:       http.StatusOK,
+			expectAnnouncement: "back online",
+		},
+		{
+			name:          "empty announcement",
+			authenticator: auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:        http.MethodPost,
+			authorization: "Bearer secret",
+			contentType:   "application/json",
+			body:          `{"message": "  "}`,
+			expectStatus:  http.StatusBadRequest,
+		},
+		{
+			name:          "announcement over the maximum length",
+			authenticator: auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:        http.MethodPost,
+			authorization: "Bearer secret",
+			contentType:   "application/x-www-form-urlencoded",
+			body:          "message=" + strings.Repeat("a", MaxAnnouncementLength+1),
+			expectStatus:  http.StatusBadRequest,
+		},
+		{
+			name:          "request with the wrong method",
+			authenticator: auth.NewStaticValidator([]byte("secret"), "operator"),
+			method:        http.MethodGet,
+			authorization: "Bearer secret",
+			expectStatus:  http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, conn := range conns {
+				conn.clearMessages()
+			}
+			h.SetOperatorAuthenticator(tc.authenticator)
+
+			req := httptest.NewRequest(tc.method, "/announcements", strings.NewReader(tc.body))
+			if len(tc.authorization) > 0 {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			if len(tc.contentType) > 0 {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeAnnouncements(w, req)
+			if w.Code != tc.expectStatus {
+				t.Fatalf("expected status %v, got %v: %s", tc.expectStatus, w.Code, w.Body.String())
+			}
+
+			for _, conn := range conns {
+				res := client.Response{}
+				announced := conn.lastMessage("announcement", &res)
+				if len(tc.expectAnnouncement) == 0 {
+					if announced {
+						t.Errorf("expected client %q not to be sent an announcement, got %q", conn.UUID(), res.Message)
+					}
+					continue
+				}
+				if !announced || res.Message != tc.expectAnnouncement || !res.IsSystem {
+					t.Errorf("expected client %q to be sent the announcement %q, got %q", conn.UUID(), tc.expectAnnouncement, conn.sent)
+				}
+			}
+			if len(tc.expectAnnouncement) > 0 && !strings.Contains(w.Body.String(), "sent to 3 rooms") {
+				t.Errorf("expected the announcement to be reported as sent to 3 rooms, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
+)
+
+var (
+	ErrMissingToken = errors.New("an authentication token is required")
+	ErrExpiredToken = errors.New("the authentication token has expired")
+)
+
+// Identity describes the authenticated user a token was issued to
+type Identity struct {
+	// Subject uniquely and permanently identifies the user
+	Subject string
+	// Name is the user's display name, if the token provides one
+	Name string
+	// ExpiresAt is the time the token expires, if it expires
+	ExpiresAt time.Time
+}
+
+// Validator verifies authentication tokens
+type Validator interface {
+	// Validate returns the identity described by the given token, or
+	// an error if the token is malformed, expired, or not trusted.
+	Validate(token string) (*Identity, error)
+}
+
+// TokenFromRequest returns the bearer token sent with the given
+// request's Authorization header, or with its token query parameter,
+// since browsers cannot set headers on websocket handshakes.
+// Returns an empty string if the request carries no token.
+func TokenFromRequest(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(header[len("Bearer "):])
+	}
+
+	return r.URL.Query().Get(query.AUTH_TOKEN_KEY)
+}
+
+// Authenticate validates the token sent with the given request
+// using the given validator. Returns ErrMissingToken if the
+// request carries no token.
+func Authenticate(v Validator, r *http.Request) (*Identity, error) {
+	token := TokenFromRequest(r)
+	if len(token) == 0 {
+		return nil, ErrMissingToken
+	}
+
+	return v.Validate(token)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jwtLeeway is the amount of clock skew tolerated
+// when checking a token's expiry and not-before times
+const jwtLeeway = 30 * time.Second
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Subject           string `json:"sub"`
+	Issuer            string `json:"iss"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	ExpiresAt         int64  `json:"exp"`
+	NotBefore         int64  `json:"nbf"`
+}
+
+// JWTValidator implements Validator, verifying JSON Web Tokens
+// signed with HMAC-SHA256 (HS256) using a shared key.
+type JWTValidator struct {
+	key    []byte
+	issuer string
+}
+
+func (v *JWTValidator) Validate(token string) (*Identity, error) {
+	segs := strings.Split(token, ".")
+	if len(segs) != 3 {
+		return nil, fmt.Errorf("malformed authentication token")
+	}
+
+	header := &jwtHeader{}
+	if err := decodeJWTSegment(segs[0], header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported authentication token algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(segs[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed authentication token signature")
+	}
+
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write([]byte(segs[0] + "." + segs[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("invalid authentication token signature")
+	}
+
+	claims := &jwtClaims{}
+	if err := decodeJWTSegment(segs[1], claims); err != nil {
+		return nil, err
+	}
+
+	if len(v.issuer) > 0 && claims.Issuer != v.issuer {
+		return nil, fmt.Errorf("authentication token was not issued by %q", v.issuer)
+	}
+	if len(claims.Subject) == 0 {
+		return nil, fmt.Errorf("authentication token does not identify a user")
+	}
+
+	now := time.Now()
+	identity := &Identity{
+		Subject: claims.Subject,
+		Name:    claims.PreferredUsername,
+	}
+	if len(identity.Name) == 0 {
+		identity.Name = claims.Name
+	}
+
+	if claims.ExpiresAt > 0 {
+		identity.ExpiresAt = time.Unix(claims.ExpiresAt, 0)
+		if now.After(identity.ExpiresAt.Add(jwtLeeway)) {
+			return nil, ErrExpiredToken
+		}
+	}
+	if claims.NotBefore > 0 && now.Add(jwtLeeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, fmt.Errorf("authentication token is not valid yet")
+	}
+
+	return identity, nil
+}
+
+// decodeJWTSegment decodes the given base64url-encoded JSON token segment into v
+func decodeJWTSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("malformed authentication token")
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("malformed authentication token: %v", err)
+	}
+	return nil
+}
+
+// NewJWTValidator returns a Validator accepting JSON Web Tokens signed
+// with the given HS256 key. If an issuer is given, tokens must also
+// have been issued by it.
+func NewJWTValidator(key []byte, issuer string) Validator {
+	return &JWTValidator{
+		key:    key,
+		issuer: issuer,
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+)
+
+// StaticValidator implements Validator, accepting a single
+// shared secret token as the identity of a fixed subject.
+type StaticValidator struct {
+	token   []byte
+	subject string
+}
+
+func (v *StaticValidator) Validate(token string) (*Identity, error) {
+	if len(v.token) == 0 || subtle.ConstantTimeCompare([]byte(token), v.token) != 1 {
+		return nil, fmt.Errorf("invalid authentication token")
+	}
+
+	return &Identity{
+		Subject: v.subject,
+		Name:    v.subject,
+	}, nil
+}
+
+// NewStaticValidator returns a Validator accepting only the given
+// token, which identifies the given subject. No token is accepted
+// if the given token is empty.
+func NewStaticValidator(token []byte, subject string) Validator {
+	return &StaticValidator{
+		token:   token,
+		subject: subject,
+	}
+}
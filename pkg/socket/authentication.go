@@ -0,0 +1,55 @@
+package socket
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/socket/auth"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/util"
+)
+
+// LockAuthenticatedUsernames determines whether authenticated clients
+// keep the display name given by their token, rather than being able
+// to choose their own username.
+var LockAuthenticatedUsernames = false
+
+// SetAuthenticator sets the validator used to verify the token every
+// connection must present with its handshake. Without one, connections
+// are not authenticated.
+func (h *Handler) SetAuthenticator(v auth.Validator) {
+	h.authenticator = v
+}
+
+// authenticate returns the identity described by the token sent with
+// the given connection's handshake, or an error if the token is missing
+// or invalid. No identity is returned if authentication is disabled.
+func (h *Handler) authenticate(conn connection.Connection) (*auth.Identity, error) {
+	if h.authenticator == nil {
+		return nil, nil
+	}
+
+	return auth.Authenticate(h.authenticator, conn.Request())
+}
+
+// applyIdentity assigns the given authenticated identity, and
+// the display name it provides, to the given connection's client.
+func (h *Handler) applyIdentity(conn connection.Connection, identity *auth.Identity) {
+	c, err := h.clientHandler.GetClient(conn.UUID())
+	if err != nil {
+		return
+	}
+
+	c.SetIdentity(identity.Subject)
+	h.logger.Infof("SOCKET AUTHN client with id %q authenticated as %q", conn.UUID(), identity.Subject)
+
+	if len(identity.Name) == 0 {
+		return
+	}
+
+	if err := util.UpdateClientUsername(c, identity.Name, h.clientHandler); err != nil {
+		h.logger.Errorf("SOCKET AUTHN unable to assign display name %q to client with id %q: %v", identity.Name, conn.UUID(), err)
+		return
+	}
+
+	if LockAuthenticatedUsernames {
+		c.LockUsername()
+	}
+}
@@ -0,0 +1,66 @@
+package socket
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/util"
+)
+
+// updateRoomBuffering counts the clients in a room whose players are
+// buffering, pausing or resuming the room's playback as needed, and
+// notifies the room of any change.
+func (h *Handler) updateRoomBuffering(ns connection.Namespace) {
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		return
+	}
+
+	clients := 0
+	buffering := 0
+	for _, c := range h.clientHandler.Clients() {
+		if cNs, exists := c.Namespace(); !exists || cNs.Name() != ns.Name() {
+			continue
+		}
+
+		clients++
+		if c.IsBuffering() {
+			buffering++
+		}
+	}
+
+	action, err := sPlayback.UpdateBuffering(buffering, clients)
+	if err != nil {
+		h.logger.Errorf("SOCKET CLIENT unable to update playback for buffering clients in room %q: %v", ns.Name(), err)
+		return
+	}
+
+	var message string
+	switch action {
+	case playback.BUFFERING_PAUSED:
+		message = "playback has been paused while viewers buffer"
+	case playback.BUFFERING_RESUMED:
+		message = "viewers have caught up - resuming playback"
+	default:
+		return
+	}
+
+	h.logger.Infof("SOCKET CLIENT %s in room %q (%v/%v clients buffering)", message, ns.Name(), buffering, clients)
+
+	res := &client.Response{
+		From: client.USER_SYSTEM,
+	}
+
+	err = util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		h.logger.Errorf("SOCKET CLIENT unable to serialize playback status: %v", err)
+		return
+	}
+
+	h.BroadcastToNamespace(ns, "streamsync", res)
+	h.BroadcastToNamespace(ns, "chatmessage", &client.Response{
+		From:     client.USER_SYSTEM,
+		Message:  message,
+		IsSystem: true,
+	})
+}
@@ -0,0 +1,73 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// decisionResponse is the data of a "canido" event
+type decisionResponse struct {
+	Extra cmd.Decision `json:"extra"`
+}
+
+func TestCanIDo(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	admin := connect(t, h, ns, authorizer, "admin", "admin", rbac.ADMIN_ROLE)
+	user := connect(t, h, ns, authorizer, "user", "user", rbac.USER_ROLE)
+
+	tests := []struct {
+		name          string
+		conn          *fakeConnection
+		action        string
+		expectAction  string
+		expectAllowed bool
+	}{
+		{
+			name:          "command name",
+			conn:          admin,
+			action:        "seek",
+			expectAction:  "seek/*",
+			expectAllowed: true,
+		},
+		{
+			name:         "command name denied",
+			conn:         user,
+			action:       "seek",
+			expectAction: "seek/*",
+		},
+		{
+			name:          "command name with arguments",
+			conn:          user,
+			action:        "queue/add/url",
+			expectAction:  "queue/add/url",
+			expectAllowed: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conn.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set("action", tc.action)
+			tc.conn.Emit("request_canido", data)
+
+			res := decisionResponse{}
+			if !tc.conn.lastMessage("canido", &res) {
+				t.Fatalf("expected a %q event to be sent, got %q", "canido", tc.conn.sent)
+			}
+			if res.Extra.Action != tc.expectAction {
+				t.Errorf("expected action %q, got %q", tc.expectAction, res.Extra.Action)
+			}
+			if res.Extra.Allowed != tc.expectAllowed {
+				t.Errorf("expected allowed: %v, got %v (%s)", tc.expectAllowed, res.Extra.Allowed, res.Extra.Reason)
+			}
+			if len(res.Extra.Reason) == 0 {
+				t.Errorf("expected the decision to include a reason")
+			}
+		})
+	}
+}
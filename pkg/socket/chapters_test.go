@@ -0,0 +1,106 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// chapterListResponse is the data of a "chapterlist" event
+type chapterListResponse struct {
+	Extra playback.ChapterList `json:"extra"`
+}
+
+func TestChapters(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	admin := connect(t, h, ns, authorizer, "admin", "admin", rbac.ADMIN_ROLE)
+	user := connect(t, h, ns, authorizer, "user", "user", rbac.USER_ROLE)
+	sPlayback := loadStream(t, h, ns, "movie.mp4", 600)
+
+	data := connection.NewMessageData()
+	data.Set("chapters", []interface{}{
+		map[string]interface{}{"title": "Credits", "start": float64(540)},
+		map[string]interface{}{"title": "Intro", "start": float64(0)},
+		map[string]interface{}{"title": "Act One", "start": float64(90)},
+	})
+	admin.Emit("request_setchapters", data)
+
+	expected := []playback.Chapter{
+		{Title: "Intro", Start: 0},
+		{Title: "Act One", Start: 90},
+		{Title: "Credits", Start: 540},
+	}
+	for _, conn := range []*fakeConnection{admin, user} {
+		res := chapterListResponse{}
+		if !conn.lastMessage("chapterlist", &res) {
+			t.Fatalf("expected client %q to be sent a %q event, got %q", conn.id, "chapterlist", conn.sent)
+		}
+		if len(res.Extra.Chapters) != len(expected) {
+			t.Fatalf("expected client %q to be sent chapters %v, got %v", conn.id, expected, res.Extra.Chapters)
+		}
+		for i := range expected {
+			if res.Extra.Chapters[i] != expected[i] {
+				t.Errorf("expected client %q to be sent chapters %v, got %v", conn.id, expected, res.Extra.Chapters)
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conn       *fakeConnection
+		title      string
+		expectTime int
+		expectSync bool
+	}{
+		{
+			name:       "jumping to a chapter seeks to its start",
+			conn:       admin,
+			title:      "Act One",
+			expectTime: 90,
+			expectSync: true,
+		},
+		{
+			name:       "jumping to another chapter",
+			conn:       admin,
+			title:      "Credits",
+			expectTime: 540,
+			expectSync: true,
+		},
+		{
+			name:       "unknown chapter",
+			conn:       admin,
+			title:      "Epilogue",
+			expectTime: 540,
+		},
+		{
+			name:       "unauthorized client",
+			conn:       user,
+			title:      "Intro",
+			expectTime: 540,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set("title", tc.title)
+			tc.conn.Emit("request_jumpchapter", data)
+
+			if time := sPlayback.GetTime(); time != tc.expectTime {
+				t.Errorf("expected playback time %v, got %v", tc.expectTime, time)
+			}
+
+			res := statusResponse{}
+			if synced := user.lastMessage("streamsync", &res); synced != tc.expectSync {
+				t.Fatalf("expected a %q event to be broadcast: %v, got %v", "streamsync", tc.expectSync, synced)
+			}
+			if tc.expectSync && res.Extra.Playback.Time != tc.expectTime {
+				t.Errorf("expected the broadcast playback time to be %v, got %v", tc.expectTime, res.Extra.Playback.Time)
+			}
+		})
+	}
+}
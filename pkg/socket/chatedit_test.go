@@ -0,0 +1,162 @@
+package socket
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestChatMessageEditing(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	moderator := connect(t, h, ns, authorizer, "moderator", "moderator", rbac.ADMIN_ROLE)
+	alice := connect(t, h, ns, authorizer, "alice", "alice", rbac.USER_ROLE)
+	bob := connect(t, h, ns, authorizer, "bob", "bob", rbac.USER_ROLE)
+	observer := connect(t, h, ns, authorizer, "observer", "observer", rbac.USER_ROLE)
+
+	// messageIds holds the id of the message sent by each client
+	messageIds := map[*fakeConnection]string{}
+	for _, conn := range []*fakeConnection{alice, bob} {
+		data := connection.NewMessageData()
+		data.Set("message", "sent by "+conn.UUID())
+		conn.Emit("request_chatmessage", data)
+
+		msg := chatMessageResponse{}
+		if !observer.lastMessage("chatmessage", &msg) {
+			t.Fatalf("expected a %q event to be broadcast, got %q", "chatmessage", observer.sent)
+		}
+		messageIds[conn], _ = msg.Extra[playback.ChatMessageIdKey].(string)
+	}
+
+	sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(ns)
+
+	tests := []struct {
+		name          string
+		conn          *fakeConnection
+		event         string
+		sentBy        *fakeConnection
+		message       string
+		expectErr     string
+		expectDeleted bool
+		expectHistory []string
+	}{
+		{
+			name:          "edit of your own message",
+			conn:          alice,
+			event:         "request_editmessage",
+			sentBy:        alice,
+			message:       "edited by alice",
+			expectHistory: []string{"edited by alice", "sent by bob"},
+		},
+		{
+			name:          "edit of another user's message",
+			conn:          bob,
+			event:         "request_editmessage",
+			sentBy:        alice,
+			message:       "edited by bob",
+			expectErr:     "you may only edit your own messages",
+			expectHistory: []string{"edited by alice", "sent by bob"},
+		},
+		{
+			name:          "moderator edit of another user's message",
+			conn:          moderator,
+			event:         "request_editmessage",
+			sentBy:        bob,
+			message:       "edited by moderator",
+			expectErr:     "you may only edit your own messages",
+			expectHistory: []string{"edited by alice", "sent by bob"},
+		},
+		{
+			name:          "empty edit",
+			conn:          alice,
+			event:         "request_editmessage",
+			sentBy:        alice,
+			message:       "  ",
+			expectErr:     "cannot be empty",
+			expectHistory: []string{"edited by alice", "sent by bob"},
+		},
+		{
+			name:          "deletion of another user's message",
+			conn:          bob,
+			event:         "request_deletemessage",
+			sentBy:        alice,
+			expectErr:     "not authorized",
+			expectHistory: []string{"edited by alice", "sent by bob"},
+		},
+		{
+			name:          "deletion by a moderator",
+			conn:          moderator,
+			event:         "request_deletemessage",
+			sentBy:        alice,
+			expectDeleted: true,
+			expectHistory: []string{"sent by bob"},
+		},
+		{
+			name:          "edit of a deleted message",
+			conn:          alice,
+			event:         "request_editmessage",
+			sentBy:        alice,
+			message:       "edited again",
+			expectErr:     "unable to find that message",
+			expectHistory: []string{"sent by bob"},
+		},
+		{
+			name:          "deletion of your own message",
+			conn:          bob,
+			event:         "request_deletemessage",
+			sentBy:        bob,
+			expectDeleted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conn.clearMessages()
+			observer.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set(playback.ChatMessageIdKey, messageIds[tc.sentBy])
+			if len(tc.message) > 0 {
+				data.Set("message", tc.message)
+			}
+			tc.conn.Emit(tc.event, data)
+
+			update := chatMessageResponse{}
+			updated := observer.lastMessage("chatmessageupdate", &update)
+			if len(tc.expectErr) > 0 {
+				if updated {
+					t.Errorf("expected no %q event to be broadcast, got %q", "chatmessageupdate", observer.sent)
+				}
+				notice := client.Response{}
+				if !tc.conn.lastMessage("info_clienterror", &notice) || !strings.Contains(notice.ErrMessage, tc.expectErr) {
+					t.Errorf("expected the client to be sent an error containing %q, got %q", tc.expectErr, tc.conn.sent)
+				}
+			} else {
+				if !updated {
+					t.Fatalf("expected a %q event to be broadcast, got %q", "chatmessageupdate", observer.sent)
+				}
+				if update.Extra[playback.ChatMessageIdKey] != messageIds[tc.sentBy] {
+					t.Errorf("expected message %q to be updated, got %v", messageIds[tc.sentBy], update.Extra[playback.ChatMessageIdKey])
+				}
+				if tc.expectDeleted {
+					if update.Extra[playback.ChatDeletedKey] != true || update.Extra["by"] != tc.conn.UUID() {
+						t.Errorf("expected the message to be marked as deleted by %q, got %v", tc.conn.UUID(), update.Extra)
+					}
+				} else if update.Message != tc.message || update.Extra[playback.ChatEditedKey] != true {
+					t.Errorf("expected the message to be marked as edited to %q, got %q (%v)", tc.message, update.Message, update.Extra)
+				}
+			}
+
+			history := []string{}
+			for _, msg := range sPlayback.ChatHistory() {
+				history = append(history, msg.Message)
+			}
+			if strings.Join(history, ",") != strings.Join(tc.expectHistory, ",") {
+				t.Errorf("expected chat history %q, got %q", tc.expectHistory, history)
+			}
+		})
+	}
+}
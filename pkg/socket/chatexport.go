@@ -0,0 +1,159 @@
+package socket
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd"
+)
+
+const (
+	CHAT_EXPORT_FORMAT_JSON = "json"
+	CHAT_EXPORT_FORMAT_CSV  = "csv"
+)
+
+// chatExportRecord is a serializable schema
+// describing a single exported chat message
+type chatExportRecord struct {
+	Timestamp string `json:"timestamp"`
+	Id        string `json:"id"`
+	Username  string `json:"username"`
+	Message   string `json:"message"`
+	System    bool   `json:"system"`
+}
+
+// serveChatExport writes the chat history of the given client's room as
+// JSON or CSV, as given by the request's format query parameter. Messages
+// are written one at a time, rather than serialized into a single buffer.
+func (h *Handler) serveChatExport(w http.ResponseWriter, r *http.Request, c *client.Client, segs []string) {
+	if r.Method != http.MethodGet || len(segs) != 3 {
+		writeRoomAPIError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed for %q", r.Method, r.URL.Path))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		format = CHAT_EXPORT_FORMAT_JSON
+	}
+	if format != CHAT_EXPORT_FORMAT_JSON && format != CHAT_EXPORT_FORMAT_CSV {
+		writeRoomAPIError(w, http.StatusBadRequest, fmt.Errorf("unsupported export format %q; expecting %q or %q", format, CHAT_EXPORT_FORMAT_JSON, CHAT_EXPORT_FORMAT_CSV))
+		return
+	}
+
+	if err := h.authorizeAction(c, cmd.CHAT_EXPORT_ACTION); err != nil {
+		writeRoomAPIError(w, http.StatusForbidden, err)
+		return
+	}
+
+	sPlayback, err := h.getPlaybackFromClient(c)
+	if err != nil {
+		writeRoomAPIError(w, http.StatusNotFound, err)
+		return
+	}
+
+	messages := sPlayback.ChatHistory()
+	h.logger.Infof("API client with id %q exporting %v chat messages from room %q as %s", c.UUID(), len(messages), sPlayback.UUID(), format)
+
+	filename := fmt.Sprintf("%s-chat-%s.%s", sPlayback.UUID(), time.Now().Format("20060102-150405"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == CHAT_EXPORT_FORMAT_CSV {
+		err = writeChatExportCSV(w, messages)
+	} else {
+		err = writeChatExportJSON(w, messages)
+	}
+	if err != nil {
+		h.logger.Errorf("SOCKET API unable to export chat history for room %q: %v", sPlayback.UUID(), err)
+	}
+}
+
+// writeChatExportJSON writes the given messages to w as a JSON array
+func writeChatExportJSON(w http.ResponseWriter, messages []*client.Response) error {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write([]byte("[")); err != nil {
+		return err
+	}
+
+	for i, msg := range messages {
+		if i > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+
+		b, err := json.Marshal(newChatExportRecord(msg))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write([]byte("]\n"))
+	return err
+}
+
+// writeChatExportCSV writes the given messages to w as CSV, with a header row
+func writeChatExportCSV(w http.ResponseWriter, messages []*client.Response) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"timestamp", "id", "username", "message", "system"})
+	for _, msg := range messages {
+		record := newChatExportRecord(msg)
+		if err := writer.Write([]string{
+			record.Timestamp,
+			record.Id,
+			escapeCSVFormula(record.Username),
+			escapeCSVFormula(record.Message),
+			strconv.FormatBool(record.System),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCSVFormula prefixes the given cell with a single quote if it
+// begins with a character that spreadsheet applications would otherwise
+// interpret as the start of a formula.
+func escapeCSVFormula(cell string) string {
+	if len(cell) > 0 && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
+
+func newChatExportRecord(msg *client.Response) *chatExportRecord {
+	record := &chatExportRecord{
+		Id:       msg.Id,
+		Username: msg.From,
+		Message:  msg.Message,
+		System:   msg.IsSystem,
+	}
+
+	// timestamps are stamped in milliseconds, but are
+	// de-serialized as floats if restored from a saved state
+	var millis int64
+	switch ts := msg.Extra["timestamp"].(type) {
+	case int64:
+		millis = ts
+	case float64:
+		millis = int64(ts)
+	}
+	if millis > 0 {
+		record.Timestamp = time.Unix(0, millis*int64(time.Millisecond)).UTC().Format(time.RFC3339)
+	}
+
+	return record
+}
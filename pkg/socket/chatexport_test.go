@@ -0,0 +1,135 @@
+package socket
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestEscapeCSVFormula(t *testing.T) {
+	tests := []struct {
+		name     string
+		cell     string
+		expected string
+	}{
+		{
+			name: "empty cell",
+		},
+		{
+			name:     "plain text",
+			cell:     "hello = world",
+			expected: "hello = world",
+		},
+		{
+			name:     "formula",
+			cell:     "=HYPERLINK(\"http://example.com\")",
+			expected: "'=HYPERLINK(\"http://example.com\")",
+		},
+		{
+			name:     "plus sign",
+			cell:     "+1",
+			expected: "'+1",
+		},
+		{
+			name:     "minus sign",
+			cell:     "-1+2",
+			expected: "'-1+2",
+		},
+		{
+			name:     "at sign",
+			cell:     "@SUM(A1)",
+			expected: "'@SUM(A1)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := escapeCSVFormula(tc.cell); actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestChatExport(t *testing.T) {
+	h, ns := newTestHandler("room")
+	conn := newFakeConnection("client", ns)
+	h.HandleClientConnection(conn)
+
+	for _, message := range []string{"hello", "=1+1", "@everyone"} {
+		data := connection.NewMessageData()
+		data.Set("message", message)
+		data.Set("system", true)
+		conn.Emit("request_chatmessage", data)
+	}
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		t.Fatalf("expected a playback to be created for the room")
+	}
+	history := sPlayback.ChatHistory()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 messages in the room's chat history, got %v", len(history))
+	}
+
+	t.Run("json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := writeChatExportJSON(w, history); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records := []chatExportRecord{}
+		if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(records) != len(history) {
+			t.Fatalf("expected %v records, got %v", len(history), len(records))
+		}
+		for i, record := range records {
+			if expected := newChatExportRecord(history[i]); record != *expected {
+				t.Errorf("expected record %v, got %v", *expected, record)
+			}
+			if record.System {
+				t.Errorf("expected message %q sent by a client not to be exported as a system message", record.Message)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := writeChatExportCSV(w, history); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != len(history)+1 {
+			t.Fatalf("expected a header and %v rows, got %v rows", len(history), len(rows))
+		}
+		for i, row := range rows[1:] {
+			record := newChatExportRecord(history[i])
+			expected := []string{
+				record.Timestamp,
+				record.Id,
+				escapeCSVFormula(record.Username),
+				escapeCSVFormula(record.Message),
+				strconv.FormatBool(false),
+			}
+			for j := range expected {
+				if row[j] != expected[j] {
+					t.Errorf("expected row %v, got %v", expected, row)
+					break
+				}
+			}
+		}
+		if message := rows[2][3]; message != "'=1+1" {
+			t.Errorf("expected formula to be escaped, got %q", message)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package socket
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestMaxChatMessageLength(t *testing.T) {
+	h, ns := newTestHandler("room")
+	sender := connect(t, h, ns, nil, "sender", "sender", "")
+	receiver := connect(t, h, ns, nil, "receiver", "receiver", "")
+
+	tests := []struct {
+		name            string
+		message         string
+		expectDelivered bool
+	}{
+		{
+			name:            "message at the limit",
+			message:         strings.Repeat("a", MaxChatMessageLength),
+			expectDelivered: true,
+		},
+		{
+			name:            "message at the limit in multi-byte characters",
+			message:         strings.Repeat("é", MaxChatMessageLength),
+			expectDelivered: true,
+		},
+		{
+			name:    "message over the limit",
+			message: strings.Repeat("a", MaxChatMessageLength+1),
+		},
+		{
+			name:    "message over the limit in multi-byte characters",
+			message: strings.Repeat("é", MaxChatMessageLength+1),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sender.clearMessages()
+			receiver.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set("message", tc.message)
+			sender.Emit("request_chatmessage", data)
+
+			res := client.Response{}
+			delivered := receiver.lastMessage("chatmessage", &res)
+			if delivered != tc.expectDelivered {
+				t.Fatalf("expected the message to be broadcast: %v, got %v", tc.expectDelivered, delivered)
+			}
+			if tc.expectDelivered {
+				if res.Message != tc.message {
+					t.Errorf("expected the message to be broadcast whole, got %v characters", len([]rune(res.Message)))
+				}
+				return
+			}
+
+			notice := client.Response{}
+			if !sender.lastMessage("chatmessage", &notice) || !notice.IsSystem || !strings.Contains(notice.Message, "at most") {
+				t.Errorf("expected the sender to be told the message is too long, got %q", sender.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package client
+
+// SetBuffering records whether the client's player is currently buffering.
+// Returns a boolean (true) if the client's buffering state changed.
+func (c *Client) SetBuffering(buffering bool) bool {
+	if c.buffering == buffering {
+		return false
+	}
+
+	c.buffering = buffering
+	return true
+}
+
+// IsBuffering returns true if the client last reported that its player is buffering
+func (c *Client) IsBuffering() bool {
+	return c.buffering
+}
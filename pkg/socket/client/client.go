@@ -3,10 +3,12 @@ package client
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/validation"
 )
 
 const (
@@ -14,13 +16,30 @@ const (
 	USER_SYSTEM       = "system"
 )
 
-var RESERVED_USERNAMES = map[string]bool{
-	"system": true,
-}
-
 type Client struct {
 	connection connection.Connection
 	usernames  []string // stores MAX_USERNAME_HIST usernames; tail represents current username
+	quality    Quality
+	// color overrides the client's assigned display color, if set
+	color string
+	// away is true if the client has explicitly marked itself as away
+	away bool
+	// buffering is true if the client's player last reported that it is buffering
+	buffering bool
+	// lastPresence is the presence last reported to the client's room
+	lastPresence string
+	// latency is the client's most recent ping round-trip time
+	latencyMutex sync.Mutex
+	latency      time.Duration
+	// chatLimiter limits the rate at which the
+	// client may send chat messages
+	chatLimiter *TokenBucket
+	// identity is the stable identity of an authenticated client
+	identity string
+	// usernameLocked prevents the client from changing its username
+	usernameLocked bool
+	// spectator prevents the client from chatting or changing the queue
+	spectator bool
 }
 
 type SerializableClientList struct {
@@ -37,10 +56,17 @@ func (s *SerializableClientList) Serialize() ([]byte, error) {
 }
 
 type SerializableClient struct {
-	Username string   `json:"username"`
-	Id       string   `json:"id"`
-	Room     string   `json:"room"`
-	Roles    []string `json:"roles"`
+	Username  string   `json:"username"`
+	Id        string   `json:"id"`
+	Room      string   `json:"room"`
+	Roles     []string `json:"roles"`
+	Quality   Quality  `json:"quality"`
+	Color     string   `json:"color"`
+	Presence  string   `json:"presence"`
+	Buffering bool     `json:"buffering"`
+	Spectator bool     `json:"spectator"`
+	// Latency is the client's round-trip time in milliseconds
+	Latency int64 `json:"latency"`
 }
 
 func (s *SerializableClient) Serialize() ([]byte, error) {
@@ -79,6 +105,12 @@ func NewClient(conn connection.Connection) *Client {
 	return &Client{
 		connection: conn,
 		usernames:  make([]string, 0, MAX_USERNAME_HIST),
+		quality: Quality{
+			Preferred: QUALITY_AUTO,
+			Actual:    QUALITY_AUTO,
+		},
+		chatLimiter:  NewTokenBucket(ChatBurst, ChatRefillRate),
+		lastPresence: PRESENCE_ACTIVE,
 	}
 }
 
@@ -92,9 +124,15 @@ func (c *Client) Serialize() ([]byte, error) {
 	}
 
 	sc := &SerializableClient{
-		Username: username,
-		Id:       c.UUID(),
-		Room:     roomName,
+		Username:  username,
+		Id:        c.UUID(),
+		Room:      roomName,
+		Quality:   c.Quality(),
+		Color:     c.Color(),
+		Presence:  c.Presence(),
+		Buffering: c.IsBuffering(),
+		Spectator: c.IsSpectator(),
+		Latency:   int64(c.Latency() / time.Millisecond),
 	}
 
 	return sc.Serialize()
@@ -116,7 +154,10 @@ func (c *Client) GetSourceName() string {
 }
 
 func (c *Client) UpdateUsername(username string) error {
-	if _, ok := RESERVED_USERNAMES[strings.ToLower(username)]; ok {
+	if c.usernameLocked {
+		return fmt.Errorf("error: your username is set by your account and may not be changed")
+	}
+	if validation.IsReservedUsername(username) {
 		return fmt.Errorf("you may not use that username")
 	}
 
@@ -166,6 +207,16 @@ func (c *Client) GetPreviousUsername() (string, bool) {
 	return c.usernames[len(c.usernames)-2], true
 }
 
+// Address returns the host the client has connected from
+func (c *Client) Address() string {
+	return connection.RemoteHost(c.connection)
+}
+
+// Disconnect closes the client's socket connection
+func (c *Client) Disconnect() error {
+	return c.connection.Close()
+}
+
 // BroadcastErrorTo broadcasts an error message event to the current client
 func (c *Client) BroadcastErrorTo(err error) {
 	c.BroadcastTo("info_clienterror", &Response{
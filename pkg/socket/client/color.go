@@ -0,0 +1,64 @@
+package client
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+// COLOR_PALETTE is the set of display colors
+// clients are assigned from by default.
+var COLOR_PALETTE = []string{
+	"#e6194b",
+	"#3cb44b",
+	"#ffe119",
+	"#4363d8",
+	"#f58231",
+	"#911eb4",
+	"#46f0f0",
+	"#f032e6",
+	"#bcf60c",
+	"#fabebe",
+	"#008080",
+	"#e6beff",
+	"#9a6324",
+	"#800000",
+	"#aaffc3",
+	"#808000",
+}
+
+var hexColor = regexp.MustCompile("^#[0-9a-f]{6}$")
+
+// SetColor overrides the client's assigned display color with
+// the given "#rrggbb" hex color. An empty value removes the
+// override. Returns an error if the color is not a valid hex color.
+func (c *Client) SetColor(color string) error {
+	color = strings.ToLower(color)
+	if len(color) > 0 && !hexColor.MatchString(color) {
+		return fmt.Errorf("error: %q is not a valid color - expecting a hex color such as #ff0000", color)
+	}
+
+	c.color = color
+	return nil
+}
+
+// Color returns the client's display color. Unless overridden, the color
+// is derived from the client's username (or id if it has no username),
+// so that a user is assigned the same color every time they reclaim
+// their username.
+func (c *Client) Color() string {
+	if len(c.color) > 0 {
+		return c.color
+	}
+
+	return ColorFromName(c.GetUsernameOrId())
+}
+
+// ColorFromName deterministically picks a color from
+// COLOR_PALETTE for the given username or id.
+func ColorFromName(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return COLOR_PALETTE[h.Sum32()%uint32(len(COLOR_PALETTE))]
+}
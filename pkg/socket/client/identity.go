@@ -0,0 +1,18 @@
+package client
+
+// SetIdentity records the stable identity the
+// client has authenticated as
+func (c *Client) SetIdentity(identity string) {
+	c.identity = identity
+}
+
+// Identity returns the stable identity the client has authenticated
+// as, or a boolean (false) if the client has not authenticated.
+func (c *Client) Identity() (string, bool) {
+	return c.identity, len(c.identity) > 0
+}
+
+// LockUsername prevents the client's current username from being changed
+func (c *Client) LockUsername() {
+	c.usernameLocked = true
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"time"
+)
+
+// SendPing sends a "ping" event to the client, stamped with the
+// current time in milliseconds. Clients are expected to reply
+// with a "pong" event carrying the same timestamp.
+func (c *Client) SendPing() {
+	c.BroadcastTo("ping", &Response{
+		From:     USER_SYSTEM,
+		IsSystem: true,
+		Extra: map[string]interface{}{
+			"timestamp": time.Now().UnixNano() / int64(time.Millisecond),
+		},
+	})
+}
+
+// RecordPong receives the timestamp, in milliseconds, of a ping
+// echoed back by the client, and stores the resulting round-trip
+// time as the client's latency.
+// Returns an error if the timestamp is in the future.
+func (c *Client) RecordPong(timestamp int64) (time.Duration, error) {
+	sentAt := time.Unix(0, timestamp*int64(time.Millisecond))
+	rtt := time.Since(sentAt)
+	if rtt < 0 {
+		return 0, fmt.Errorf("error: pong timestamp is in the future")
+	}
+
+	c.latencyMutex.Lock()
+	defer c.latencyMutex.Unlock()
+	c.latency = rtt
+	return rtt, nil
+}
+
+// Latency returns the client's most recently measured round-trip
+// time, or zero if it has not yet been measured.
+func (c *Client) Latency() time.Duration {
+	c.latencyMutex.Lock()
+	defer c.latencyMutex.Unlock()
+	return c.latency
+}
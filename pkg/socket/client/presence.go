@@ -0,0 +1,62 @@
+package client
+
+import (
+	"time"
+)
+
+const (
+	PRESENCE_ACTIVE = "active"
+	PRESENCE_AWAY   = "away"
+
+	DefaultAwayThreshold = 5 * time.Minute // idle time after which a client is considered away
+)
+
+// AwayThreshold is the amount of time a client may go
+// without sending any events before it is considered away
+var AwayThreshold = DefaultAwayThreshold
+
+// SetAway explicitly marks the client as away, regardless of its
+// activity, or clears an explicit away status.
+func (c *Client) SetAway(away bool) {
+	c.away = away
+}
+
+// LastActivity returns the last time the client sent an event
+func (c *Client) LastActivity() time.Time {
+	return c.connection.Metadata().LastActivity()
+}
+
+// Presence returns PRESENCE_AWAY if the client has marked itself as
+// away, or has been idle for longer than AwayThreshold. Returns
+// PRESENCE_ACTIVE otherwise.
+func (c *Client) Presence() string {
+	if c.away || time.Since(c.LastActivity()) > AwayThreshold {
+		return PRESENCE_AWAY
+	}
+	return PRESENCE_ACTIVE
+}
+
+// UpdatePresence returns the client's current presence, and a
+// boolean (true) if it has changed since UpdatePresence was last
+// called.
+func (c *Client) UpdatePresence() (string, bool) {
+	presence := c.Presence()
+	if presence == c.lastPresence {
+		return presence, false
+	}
+
+	c.lastPresence = presence
+	return presence, true
+}
+
+// BroadcastPresence notifies every client in the
+// client's room of the client's given presence
+func (c *Client) BroadcastPresence(presence string) {
+	c.BroadcastAll("info_presence", &Response{
+		Id:   c.UUID(),
+		From: c.GetUsernameOrId(),
+		Extra: map[string]interface{}{
+			"presence": presence,
+		},
+	})
+}
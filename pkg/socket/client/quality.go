@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	QUALITY_AUTO = "auto"
+)
+
+// QUALITY_LEVELS are the playback quality values
+// a client may report, lowest to highest.
+var QUALITY_LEVELS = []string{
+	QUALITY_AUTO,
+	"144p",
+	"240p",
+	"360p",
+	"480p",
+	"720p",
+	"1080p",
+	"1440p",
+	"2160p",
+}
+
+// Quality is a serializable schema describing the playback quality
+// a client prefers, and the quality it is actually receiving.
+type Quality struct {
+	Preferred string `json:"preferred"`
+	Actual    string `json:"actual"`
+}
+
+// SetQuality receives a client's preferred and actual playback
+// quality and stores them. An empty value leaves the stored
+// value unchanged.
+// Returns an error if either value is not a known quality level.
+func (c *Client) SetQuality(preferred, actual string) error {
+	preferred = strings.ToLower(preferred)
+	actual = strings.ToLower(actual)
+
+	for _, q := range []string{preferred, actual} {
+		if len(q) > 0 && !isQualityLevel(q) {
+			return fmt.Errorf("error: unknown quality %q - must be one of (%s)", q, strings.Join(QUALITY_LEVELS, "|"))
+		}
+	}
+
+	if len(preferred) > 0 {
+		c.quality.Preferred = preferred
+	}
+	if len(actual) > 0 {
+		c.quality.Actual = actual
+	}
+	return nil
+}
+
+// Quality returns the client's stored playback quality
+func (c *Client) Quality() Quality {
+	return c.quality
+}
+
+func isQualityLevel(q string) bool {
+	for _, level := range QUALITY_LEVELS {
+		if level == q {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultChatBurst      = 5   // number of chat messages a client may send at once
+	DefaultChatRefillRate = 1.0 // number of chat messages a client regains per second
+)
+
+var (
+	ChatBurst      = DefaultChatBurst
+	ChatRefillRate = DefaultChatRefillRate
+)
+
+// TokenBucket is a rate limiter allowing bursts of up to capacity
+// events, refilled continuously at refillRate events per second.
+// It is safe for concurrent use.
+type TokenBucket struct {
+	mutex      sync.Mutex
+	capacity   float64
+	refillRate float64
+	tokens     float64
+	last       time.Time
+}
+
+// Allow consumes a token and returns true if one is available
+func (b *TokenBucket) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.refillRate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// AllowChatMessage returns true if the client has not
+// exceeded its chat message rate limit, and records
+// the message against that limit.
+func (c *Client) AllowChatMessage() bool {
+	return c.chatLimiter.Allow()
+}
+
+// NewTokenBucket returns a full TokenBucket with the given
+// capacity, refilled at the given number of tokens per second.
+func NewTokenBucket(capacity int, refillRate float64) *TokenBucket {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &TokenBucket{
+		capacity:   float64(capacity),
+		refillRate: refillRate,
+		tokens:     float64(capacity),
+		last:       time.Now(),
+	}
+}
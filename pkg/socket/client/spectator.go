@@ -0,0 +1,12 @@
+package client
+
+// SetSpectator sets whether the client is a spectator. Spectators
+// receive room updates, but may not chat or change the queue.
+func (c *Client) SetSpectator(spectator bool) {
+	c.spectator = spectator
+}
+
+// IsSpectator returns true if the client has been made a spectator
+func (c *Client) IsSpectator() bool {
+	return c.spectator
+}
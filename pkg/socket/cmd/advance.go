@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+)
+
+// AdvancePlayback ends the room's current stream, loads the next one
+// (or stops playback if there is none) and broadcasts the updated
+// playback state to the user's room.
+func AdvancePlayback(user *client.Client, sPlayback *playback.Playback) error {
+	_, loaded, err := sPlayback.AdvanceQueue()
+	if err != nil {
+		return err
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: "system",
+	}
+
+	err = sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		return err
+	}
+
+	if loaded {
+		user.BroadcastAll("streamload", res)
+		announceStream(user, sPlayback)
+	}
+	user.BroadcastAll("streamsync", res)
+	return nil
+}
+
+// announceStream sends a "now playing" system message
+// to the user's room, if the room announces new streams.
+func announceStream(user *client.Client, sPlayback *playback.Playback) {
+	msg, ok := sPlayback.StreamAnnouncement()
+	if !ok {
+		return
+	}
+
+	user.BroadcastAll("chatmessage", &client.Response{
+		From:     client.USER_SYSTEM,
+		Message:  msg,
+		IsSystem: true,
+	})
+}
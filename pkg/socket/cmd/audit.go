@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+// REDACTED_ARG replaces every argument of a command
+// with secret arguments when the command is logged.
+const REDACTED_ARG = "[redacted]"
+
+// loggableArgs returns the given arguments to the given command,
+// or a placeholder for each of them if they are secret.
+func loggableArgs(command SocketCommand, args []string) []string {
+	if !command.HasSecretArgs() {
+		return args
+	}
+
+	redacted := make([]string, 0, len(args))
+	for range args {
+		redacted = append(redacted, REDACTED_ARG)
+	}
+	return redacted
+}
+
+// LoggableCommand returns the command line made up of the given command
+// root and arguments, safe to be logged. The arguments are redacted if
+// the command they are given to takes secret arguments.
+func LoggableCommand(cmdHandler SocketCommandHandler, cmdRoot string, args []string) string {
+	if command, exists := resolveCommandAlias(cmdRoot, cmdHandler.Commands(), cmdHandler.Aliases()); exists {
+		args = loggableArgs(command, args)
+	}
+
+	return strings.Join(append([]string{cmdRoot}, args...), " ")
+}
+
+// isPrivilegedAction returns true if the given action is not
+// allowed by the default role given to users joining a room.
+func isPrivilegedAction(authorizer rbac.Authorizer, action string) bool {
+	if authorizer == nil {
+		return false
+	}
+
+	rule, exists := rbac.RuleByActionInRoles(authorizer.Roles(), action)
+	if !exists {
+		return false
+	}
+
+	userRole, exists := authorizer.Role(rbac.USER_ROLE)
+	if !exists {
+		return true
+	}
+
+	for _, r := range rbac.InheritedRules(userRole) {
+		if r.Name() == rule.Name() {
+			return false
+		}
+	}
+	return true
+}
+
+// recordAudit adds an execution of the given command by the given
+// user to the audit log of the user's room. The command's target is
+// the first of its arguments naming another user in the room. Secret
+// arguments are redacted before the command is logged or recorded.
+func recordAudit(command SocketCommand, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, cmdErr error) {
+	ns, exists := user.Namespace()
+	if !exists {
+		return
+	}
+
+	sPlayback, exists := playbackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		return
+	}
+
+	usernames := make(map[string]bool)
+	for _, conn := range ns.Connections() {
+		if c, err := clientHandler.GetClient(conn.UUID()); err == nil {
+			if name, hasName := c.GetUsername(); hasName {
+				usernames[name] = true
+			}
+		}
+	}
+
+	args = loggableArgs(command, args)
+	entry := playback.AuditEntry{
+		Actor:   user.GetUsernameOrId(),
+		ActorId: user.UUID(),
+		Command: command.Name(),
+		Args:    args,
+	}
+	for _, arg := range args {
+		if usernames[arg] {
+			entry.Target = arg
+			break
+		}
+	}
+	if cmdErr != nil {
+		entry.Error = cmdErr.Error()
+	}
+
+	log.Printf("INF SOCKET CMD AUDIT client %q (%s) in room %q executed %q %v (target %q, error %q)", entry.Actor, entry.ActorId, ns.Name(), entry.Command, entry.Args, entry.Target, entry.Error)
+	sPlayback.RecordAudit(entry)
+}
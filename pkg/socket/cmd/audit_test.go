@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestSecretArgsAreRedacted(t *testing.T) {
+	rooms := newTestRooms(t)
+	owner := rooms.join("a", "owner", rbac.ADMIN_ROLE)
+
+	tests := []struct {
+		name     string
+		cmdRoot  string
+		args     []string
+		secret   string
+		expected string
+	}{
+		{
+			name:     "room password",
+			cmdRoot:  LOCK_NAME,
+			args:     []string{"hunter2", "hunter3"},
+			secret:   "hunter2",
+			expected: "lock [redacted] [redacted]",
+		},
+		{
+			name:     "invite settings",
+			cmdRoot:  INVITE_NAME,
+			args:     []string{"3", "1h"},
+			secret:   "1h",
+			expected: "invite [redacted] [redacted]",
+		},
+		{
+			name:     "commands without secrets",
+			cmdRoot:  ROOM_NAME,
+			args:     []string{"countdown", "5"},
+			expected: "room countdown 5",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := LoggableCommand(rooms.cmdHandler, tc.cmdRoot, tc.args); actual != tc.expected {
+				t.Errorf("expected loggable command %q, got %q", tc.expected, actual)
+			}
+			if len(tc.secret) == 0 {
+				return
+			}
+
+			var logged bytes.Buffer
+			log.SetOutput(&logged)
+			defer log.SetOutput(os.Stderr)
+
+			if _, err := rooms.execute(owner, tc.cmdRoot, tc.args...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Contains(logged.String(), tc.secret) {
+				t.Errorf("expected %q not to be logged, got %q", tc.secret, logged.String())
+			}
+
+			ns, _ := owner.Namespace()
+			sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+			entries := sPlayback.AuditLog().Entries
+			if len(entries) == 0 {
+				t.Fatalf("expected the command to be audited")
+			}
+			if args := strings.Join(entries[len(entries)-1].Args, " "); strings.Contains(args, tc.secret) {
+				t.Errorf("expected %q not to be recorded in the audit log, got %q", tc.secret, args)
+			}
+		})
+	}
+}
+
+func TestPrivilegedCommandsAreAudited(t *testing.T) {
+	rooms := newTestRooms(t)
+	owner := rooms.join("a", "owner", rbac.ADMIN_ROLE)
+	rooms.join("a", "target", rbac.USER_ROLE)
+	rooms.join("a", "member", rbac.USER_ROLE)
+	rooms.join("b", "bystander", rbac.ADMIN_ROLE)
+
+	if _, err := rooms.execute(owner, ROLE_NAME, "create", "mod", "kick/target"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	auditLog := func(room string) []playback.AuditEntry {
+		ns, _ := rooms.nsHandler.NamespaceByName(room)
+		sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+		return sPlayback.AuditLog().Entries
+	}
+
+	tests := []struct {
+		name          string
+		cmdRoot       string
+		args          []string
+		expectCommand string
+		expectTarget  string
+	}{
+		{
+			name:          "kick",
+			cmdRoot:       KICK_NAME,
+			args:          []string{"target"},
+			expectCommand: KICK_NAME,
+			expectTarget:  "target",
+		},
+		{
+			name:          "role bind",
+			cmdRoot:       ROLE_NAME,
+			args:          []string{"bind", "member", "mod"},
+			expectCommand: ROLE_NAME,
+			expectTarget:  "member",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before := len(auditLog("a"))
+			otherBefore := len(auditLog("b"))
+
+			if _, err := rooms.execute(owner, tc.cmdRoot, tc.args...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			entries := auditLog("a")
+			if len(entries) != before+1 {
+				t.Fatalf("expected a single audit entry to be recorded in the actor's room, got %v", entries[before:])
+			}
+			if others := auditLog("b"); len(others) != otherBefore {
+				t.Errorf("expected no audit entries to be recorded in another room, got %v", others[otherBefore:])
+			}
+
+			entry := entries[before]
+			if entry.Actor != "owner" || entry.ActorId != owner.UUID() {
+				t.Errorf("expected actor %q (%s), got %q (%s)", "owner", owner.UUID(), entry.Actor, entry.ActorId)
+			}
+			if entry.Command != tc.expectCommand {
+				t.Errorf("expected command %q, got %q", tc.expectCommand, entry.Command)
+			}
+			if strings.Join(entry.Args, " ") != strings.Join(tc.args, " ") {
+				t.Errorf("expected args %q, got %q", tc.args, entry.Args)
+			}
+			if entry.Target != tc.expectTarget {
+				t.Errorf("expected target %q, got %q", tc.expectTarget, entry.Target)
+			}
+			if len(entry.Error) > 0 {
+				t.Errorf("expected no error to be recorded, got %q", entry.Error)
+			}
+		})
+	}
+}
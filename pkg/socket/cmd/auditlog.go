@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type AuditLogCmd struct {
+	Command
+}
+
+const (
+	AUDITLOG_NAME        = "auditlog"
+	AUDITLOG_DESCRIPTION = "lists the most recent privileged commands executed in the room (requires rbac to be enabled)"
+	AUDITLOG_USAGE       = "Usage: /" + AUDITLOG_NAME + " [&lt;count&gt;]"
+
+	auditLogDefaultCount = 20
+)
+
+var (
+	auditlog_aliases = []string{"audit"}
+)
+
+func (h *AuditLogCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to view the audit log with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to view its audit log.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	count := auditLogDefaultCount
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("error: %q is not a valid number of entries\n%s", args[0], h.usage)
+		}
+		count = n
+	}
+
+	entries := sPlayback.AuditLog().Entries
+	if len(entries) == 0 {
+		return "no privileged commands have been executed in this room.", nil
+	}
+	if len(entries) > count {
+		entries = entries[len(entries)-count:]
+	}
+
+	lines := []string{fmt.Sprintf("Last %v privileged commands:", len(entries))}
+	for _, e := range entries {
+		line := fmt.Sprintf("%s %s: /%s", e.Time.Format("2006-01-02 15:04:05"), e.Actor, e.Command)
+		if len(e.Args) > 0 {
+			line += " " + strings.Join(e.Args, " ")
+		}
+		if len(e.Target) > 0 {
+			line += fmt.Sprintf(" (target: %s)", e.Target)
+		}
+		if len(e.Error) > 0 {
+			line += fmt.Sprintf(" [failed: %s]", e.Error)
+		}
+		lines = append(lines, html.EscapeString(line))
+	}
+
+	return strings.Join(lines, "<br />"), nil
+}
+
+func NewCmdAuditLog() SocketCommand {
+	return &AuditLogCmd{
+		Command{
+			name:        AUDITLOG_NAME,
+			description: AUDITLOG_DESCRIPTION,
+			usage:       AUDITLOG_USAGE,
+
+			aliases: auditlog_aliases,
+		},
+	}
+}
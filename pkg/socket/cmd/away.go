@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type AwayCmd struct {
+	Command
+}
+
+const (
+	AWAY_NAME        = "away"
+	AWAY_DESCRIPTION = "marks you as away until you use /back"
+	AWAY_USAGE       = "Usage: /" + AWAY_NAME
+)
+
+var (
+	away_aliases = []string{"afk"}
+)
+
+func (h *AwayCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	user.SetAway(true)
+	if presence, changed := user.UpdatePresence(); changed {
+		user.BroadcastPresence(presence)
+	}
+
+	return "you are now marked as away. Use /back to return.", nil
+}
+
+func NewCmdAway() SocketCommand {
+	return &AwayCmd{
+		Command{
+			name:        AWAY_NAME,
+			description: AWAY_DESCRIPTION,
+			usage:       AWAY_USAGE,
+
+			aliases: away_aliases,
+		},
+	}
+}
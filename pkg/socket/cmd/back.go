@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type BackCmd struct {
+	Command
+}
+
+const (
+	BACK_NAME        = "back"
+	BACK_DESCRIPTION = "clears your away status"
+	BACK_USAGE       = "Usage: /" + BACK_NAME
+)
+
+var (
+	back_aliases = []string{}
+)
+
+func (h *BackCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	user.SetAway(false)
+	if presence, changed := user.UpdatePresence(); changed {
+		user.BroadcastPresence(presence)
+	}
+
+	return "welcome back - you are no longer marked as away.", nil
+}
+
+func NewCmdBack() SocketCommand {
+	return &BackCmd{
+		Command{
+			name:        BACK_NAME,
+			description: BACK_DESCRIPTION,
+			usage:       BACK_USAGE,
+
+			aliases: back_aliases,
+		},
+	}
+}
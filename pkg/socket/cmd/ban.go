@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type BanCmd struct {
+	Command
+}
+
+const (
+	BAN_NAME        = "ban"
+	BAN_DESCRIPTION = "disconnects a user from the room and prevents them from rejoining"
+	BAN_USAGE       = "Usage: /" + BAN_NAME + " &lt;username&gt; | /" + BAN_NAME + " remove &lt;username&gt;"
+)
+
+var (
+	ban_aliases = []string{}
+)
+
+func (h *BanCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to ban a user with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to ban users from it.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if args[0] == "remove" {
+		if len(args) < 2 {
+			return "", fmt.Errorf("%v", h.usage)
+		}
+		if !sPlayback.Unban(args[1]) {
+			return "", fmt.Errorf("error: %q is not banned from this room", args[1])
+		}
+		return fmt.Sprintf("%q may now rejoin the room.", args[1]), nil
+	}
+
+	target, err := findRoomClient(user, clientHandler, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	sPlayback.Ban(target.Address(), args[0])
+	removeClient(user, target, "banned")
+	return fmt.Sprintf("%q has been banned from the room.", args[0]), nil
+}
+
+func NewCmdBan() SocketCommand {
+	return &BanCmd{
+		Command{
+			name:        BAN_NAME,
+			description: BAN_DESCRIPTION,
+			usage:       BAN_USAGE,
+
+			aliases: ban_aliases,
+		},
+	}
+}
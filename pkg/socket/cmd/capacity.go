@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type CapacityCmd struct {
+	Command
+}
+
+const (
+	CAPACITY_NAME        = "capacity"
+	CAPACITY_DESCRIPTION = "sets the maximum number of users that may be in the room at once"
+	CAPACITY_USAGE       = "Usage: /" + CAPACITY_NAME + " [&lt;users|off&gt;]"
+)
+
+var (
+	capacity_aliases = []string{}
+)
+
+func (h *CapacityCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to set a room capacity with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to set its capacity.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		if sPlayback.Capacity() == 0 {
+			return "this room has no capacity limit. " + h.usage, nil
+		}
+		return fmt.Sprintf("this room admits up to %v users at once.", sPlayback.Capacity()), nil
+	}
+
+	capacity := 0
+	if args[0] != "off" {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("error: room capacity must be a positive number of users. %s", h.usage)
+		}
+		capacity = n
+	}
+
+	if err := sPlayback.SetCapacity(capacity); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	var output string
+	if capacity == 0 {
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has removed the room's capacity limit", username))
+		output = "this room no longer has a capacity limit."
+	} else {
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has limited the room to %v users", username, capacity))
+		output = fmt.Sprintf("this room now admits up to %v users at once.", capacity)
+		if connected := len(userRoom.Connections()); connected > capacity {
+			output += fmt.Sprintf(" %v users are already connected and will not be removed, but no one else may join until some have left.", connected)
+		}
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.Settings(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("roomsettings", res)
+	return output, nil
+}
+
+func NewCmdCapacity() SocketCommand {
+	return &CapacityCmd{
+		Command{
+			name:        CAPACITY_NAME,
+			description: CAPACITY_DESCRIPTION,
+			usage:       CAPACITY_USAGE,
+
+			aliases: capacity_aliases,
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestExecuteCommandCase(t *testing.T) {
+	rooms := newTestRooms(t)
+	admin := rooms.join("a", "admin", rbac.ADMIN_ROLE)
+
+	recorder := &recordingCmd{
+		Command: Command{
+			name:       "play",
+			permission: "queue",
+		},
+	}
+	rooms.cmdHandler.AddCommand(recorder)
+
+	// the url's casing is significant and must be left as given
+	args := []string{"add", "https://www.YouTube.com/watch?v=AbC_123-xYz"}
+
+	tests := []struct {
+		name    string
+		cmdRoot string
+	}{
+		{
+			name:    "upper case",
+			cmdRoot: "PLAY",
+		},
+		{
+			name:    "mixed case",
+			cmdRoot: "Play",
+		},
+		{
+			name:    "lower case",
+			cmdRoot: "play",
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := rooms.execute(admin, tc.cmdRoot, args...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if recorder.executions != i+1 {
+				t.Fatalf("expected %q to resolve to %q, got %v executions", tc.cmdRoot, recorder.Name(), recorder.executions)
+			}
+			if strings.Join(recorder.args, " ") != strings.Join(args, " ") {
+				t.Errorf("expected arguments %q, got %q", args, recorder.args)
+			}
+		})
+	}
+}
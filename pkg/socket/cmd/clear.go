@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
@@ -12,8 +14,8 @@ type ClearCmd struct {
 
 const (
 	CLEAR_NAME        = "clear"
-	CLEAR_DESCRIPTION = "clears all messages from the chat window"
-	CLEAR_USAGE       = "Usage: /" + CLEAR_NAME
+	CLEAR_DESCRIPTION = "clears all messages from the chat window, or all pending items from the room's queue"
+	CLEAR_USAGE       = "Usage: /" + CLEAR_NAME + " [queue]"
 )
 
 var (
@@ -21,6 +23,16 @@ var (
 )
 
 func (h *ClearCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) > 0 {
+		if args[0] != "queue" {
+			return "", fmt.Errorf("%v", h.usage)
+		}
+
+		// delegate to the queue command so that the queue
+		// lock and room-queue rules are enforced as usual
+		return cmdHandler.ExecuteCommand(QUEUE_NAME, []string{"clear", "room"}, user, clientHandler, playbackHandler, streamHandler)
+	}
+
 	user.BroadcastChatActionTo("clearView", nil)
 	return "Clearing chat window messages...", nil
 }
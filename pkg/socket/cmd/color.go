@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type ColorCmd struct {
+	Command
+}
+
+const (
+	COLOR_NAME        = "color"
+	COLOR_DESCRIPTION = "displays or sets your display color"
+	COLOR_USAGE       = "Usage: /" + COLOR_NAME + " [&lt;#rrggbb&gt;|reset]"
+)
+
+var (
+	color_aliases = []string{"colour"}
+)
+
+func (h *ColorCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return fmt.Sprintf("your display color is %s. %s", user.Color(), h.usage), nil
+	}
+
+	color := args[0]
+	if color == "reset" {
+		color = ""
+	}
+
+	if err := user.SetColor(color); err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("info_updatecolor", &client.Response{
+		Id:   user.UUID(),
+		From: user.GetUsernameOrId(),
+		Extra: map[string]interface{}{
+			"color": user.Color(),
+		},
+	})
+
+	return fmt.Sprintf("your display color is now %s.", user.Color()), nil
+}
+
+func NewCmdColor() SocketCommand {
+	return &ColorCmd{
+		Command{
+			name:        COLOR_NAME,
+			description: COLOR_DESCRIPTION,
+			usage:       COLOR_USAGE,
+
+			aliases: color_aliases,
+		},
+	}
+}
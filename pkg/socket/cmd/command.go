@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
@@ -22,6 +23,15 @@ type SocketCommand interface {
 	GetUsage() string
 	// GetAliases returns the command's known aliases
 	GetAliases() []string
+	// GetCooldown returns the amount of time a user must
+	// wait between uses of the command. Zero if none.
+	GetCooldown() time.Duration
+	// GetPermission returns the root of the rbac actions a user
+	// must be allowed to perform in order to run the command.
+	GetPermission() string
+	// HasSecretArgs returns true if the command's arguments
+	// must never be logged or recorded in the audit log.
+	HasSecretArgs() bool
 }
 
 // Command implements SocketCommand
@@ -34,6 +44,14 @@ type Command struct {
 	description string
 	// slice of alternative command root names
 	aliases []string
+	// minimum amount of time between uses of the command by a single user
+	cooldown time.Duration
+	// root of the rbac actions checked before the command
+	// is run; the command's name is used if empty
+	permission string
+	// true if the command's arguments are secret (e.g. passwords)
+	// and are replaced before being logged or audited
+	secretArgs bool
 }
 
 func (c *Command) Execute(cmdHandler SocketCommandHandler, args []string, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
@@ -59,3 +77,19 @@ func (c *Command) GetAliases() []string {
 
 	return c.aliases
 }
+
+func (c *Command) GetCooldown() time.Duration {
+	return c.cooldown
+}
+
+func (c *Command) GetPermission() string {
+	if len(c.permission) == 0 {
+		return c.name
+	}
+
+	return c.permission
+}
+
+func (c *Command) HasSecretArgs() bool {
+	return c.secretArgs
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// cooldowns tracks the last time each client ran each
+// command declaring a cooldown. It is safe for concurrent use.
+type cooldowns struct {
+	mutex sync.Mutex
+	// map of client ids to [commandName]lastRun
+	lastRun map[string]map[string]time.Time
+}
+
+// check returns an error if the client with the given id ran the
+// given command less than the command's cooldown ago.
+func (c *cooldowns) check(command SocketCommand, clientId string) error {
+	cooldown := command.GetCooldown()
+	if cooldown <= 0 {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	last, exists := c.lastRun[clientId][command.Name()]
+	if !exists {
+		return nil
+	}
+
+	if wait := cooldown - time.Since(last); wait > 0 {
+		return fmt.Errorf("error: you must wait %v before using /%s again", wait.Round(time.Second), command.Name())
+	}
+	return nil
+}
+
+// record marks the given command as having just been
+// run by the client with the given id.
+func (c *cooldowns) record(command SocketCommand, clientId string) {
+	if command.GetCooldown() <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.lastRun[clientId]; !exists {
+		c.lastRun[clientId] = make(map[string]time.Time)
+	}
+	c.lastRun[clientId][command.Name()] = time.Now()
+}
+
+// clear removes all cooldown state for the client with the given id
+func (c *cooldowns) clear(clientId string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.lastRun, clientId)
+}
+
+func newCooldowns() *cooldowns {
+	return &cooldowns{
+		lastRun: make(map[string]map[string]time.Time),
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestExecuteWithCooldown(t *testing.T) {
+	rooms := newTestRooms(t)
+	alice := rooms.join("a", "alice", rbac.ADMIN_ROLE)
+	bob := rooms.join("a", "bob", rbac.ADMIN_ROLE)
+
+	cooldown := 500 * time.Millisecond
+	recorder := &recordingCmd{
+		Command: Command{
+			name:       "recorder",
+			permission: "kick",
+			cooldown:   cooldown,
+		},
+	}
+	rooms.cmdHandler.AddCommand(recorder)
+
+	tests := []struct {
+		name             string
+		user             *client.Client
+		wait             time.Duration
+		expectCooldown   bool
+		expectExecutions int
+	}{
+		{
+			name:             "first use",
+			user:             alice,
+			expectExecutions: 1,
+		},
+		{
+			name:             "second use within the cooldown",
+			user:             alice,
+			expectCooldown:   true,
+			expectExecutions: 1,
+		},
+		{
+			name:             "other users are not affected",
+			user:             bob,
+			expectExecutions: 2,
+		},
+		{
+			name:             "use once the cooldown has passed",
+			user:             alice,
+			wait:             cooldown + 100*time.Millisecond,
+			expectExecutions: 3,
+		},
+		{
+			name:             "use right after the cooldown has restarted",
+			user:             alice,
+			expectCooldown:   true,
+			expectExecutions: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			time.Sleep(tc.wait)
+
+			_, err := rooms.execute(tc.user, "recorder", tc.user.GetUsernameOrId())
+			if tc.expectCooldown {
+				if err == nil || !strings.Contains(err.Error(), "you must wait") {
+					t.Errorf("expected a cooldown error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if recorder.executions != tc.expectExecutions {
+				t.Errorf("expected %v executions of the command, got %v", tc.expectExecutions, recorder.executions)
+			}
+		})
+	}
+}
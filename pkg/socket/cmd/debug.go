@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
@@ -35,6 +37,9 @@ func NewCmdDebug() SocketCommand {
 			name:        DEBUG_NAME,
 			description: DEBUG_DESCRIPTION,
 			usage:       DEBUG_USAGE,
+
+			// reloading every client is disruptive
+			cooldown: 10 * time.Second,
 		},
 	}
 }
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/util"
+)
+
+// ErrNotAuthorized is wrapped by the error returned for
+// every action a client is not allowed to perform.
+var ErrNotAuthorized = errors.New("error: you are not authorized to perform that action")
+
+// Decision describes the outcome of authorizing
+// a client to perform a given action.
+// Implements api.ApiCodec.
+type Decision struct {
+	Action  string `json:"action"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+
+	// hasRule is true if the action is
+	// defined by a known rbac rule
+	hasRule bool
+}
+
+func (d *Decision) Serialize() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// Err returns an error wrapping ErrNotAuthorized, along with the
+// reason the decision's action was denied, or nil if it is allowed.
+func (d *Decision) Err() error {
+	if d.Allowed {
+		return nil
+	}
+
+	return fmt.Errorf("%w - %s", ErrNotAuthorized, d.Reason)
+}
+
+// ActionByName receives the name of a command, or one of its aliases,
+// optionally followed by "/"-separated arguments (e.g. "seek" or
+// "queue/add/url"), and returns the rbac action running that command
+// with those arguments is authorized by. A command name given without
+// arguments stands for running the command with any arguments. Names
+// that do not start with a known command are assumed to already be
+// fully-qualified actions.
+func ActionByName(cmdHandler SocketCommandHandler, name string) string {
+	segs := strings.Split(name, "/")
+	command, exists := resolveCommandAlias(segs[0], cmdHandler.Commands(), cmdHandler.Aliases())
+	if !exists {
+		return name
+	}
+
+	args := segs[1:]
+	if len(args) == 0 {
+		args = []string{"*"}
+	}
+	return util.CommandAction(command.GetPermission(), args)
+}
+
+// Authorize computes whether the given client is permitted to
+// perform the given action, and why. Stream and queue actions are
+// denied to everyone but the lock holder while a room's playback or
+// queue is locked. Any other action is verified against the roles
+// the client is bound to. If no authorizer is given, any other action
+// is allowed.
+func Authorize(authorizer rbac.Authorizer, c *client.Client, action string, playbackHandler playback.PlaybackHandler) *Decision {
+	decision := &Decision{
+		Action: action,
+	}
+
+	if ns, exists := c.Namespace(); exists {
+		if sPlayback, exists := playbackHandler.PlaybackByNamespace(ns); exists {
+			if lockedBy, lockedByName, locked := sPlayback.LockedBy(); locked && lockedBy != c.UUID() && isLockableAction(action) {
+				decision.hasRule = true
+				decision.Reason = fmt.Sprintf("playback is locked by %q", lockedByName)
+				return decision
+			}
+			if lockedBy, lockedByName, locked := sPlayback.QueueLockedBy(); locked && lockedBy != c.UUID() && isLockableQueueAction(action) {
+				decision.hasRule = true
+				decision.Reason = fmt.Sprintf("the queue is locked by %q", lockedByName)
+				return decision
+			}
+			if c.IsSpectator() && isSpectatorRestrictedAction(action) {
+				decision.hasRule = true
+				decision.Reason = "spectators may not change the queue"
+				return decision
+			}
+		}
+	}
+
+	if authorizer == nil {
+		decision.hasRule = true
+		decision.Allowed = true
+		decision.Reason = "access control is not enabled"
+		return decision
+	}
+
+	rule, exists := rbac.RuleByAction(authorizer.Bindings(), action)
+	if !exists {
+		decision.Reason = fmt.Sprintf("no rule defines the action %q", action)
+		return decision
+	}
+	decision.hasRule = true
+
+	roles := []string{}
+	for _, r := range rbac.SubjectRoles(authorizer, c) {
+		roles = append(roles, r.Name())
+	}
+	sort.Strings(roles)
+
+	if len(roles) == 0 {
+		decision.Reason = fmt.Sprintf("you are not bound to any role allowing %q", rule.Name())
+		return decision
+	}
+
+	if authorizer.Verify(c, rule) {
+		decision.Allowed = true
+		decision.Reason = fmt.Sprintf("your roles (%s) allow %q", strings.Join(roles, ", "), rule.Name())
+		return decision
+	}
+
+	decision.Reason = fmt.Sprintf("none of your roles (%s) allow %q", strings.Join(roles, ", "), rule.Name())
+	return decision
+}
+
+// AuthorizeCommand is the gate every command passes through before it is
+// executed. It authorizes the given client to run the given command with
+// the given arguments, and returns the decision along with the decision's
+// error if the client is not allowed to run it. The command's usage is
+// appended to the error if no rule defines the requested action.
+func AuthorizeCommand(authorizer rbac.Authorizer, c *client.Client, command SocketCommand, args []string, playbackHandler playback.PlaybackHandler) (*Decision, error) {
+	decision := Authorize(authorizer, c, util.CommandAction(command.GetPermission(), args), playbackHandler)
+	if decision.Allowed {
+		return decision, nil
+	}
+
+	if !decision.hasRule {
+		return decision, fmt.Errorf("%w\n%s", decision.Err(), command.GetUsage())
+	}
+	return decision, decision.Err()
+}
+
+// CanInvoke returns true if the given client is bound to a role allowing
+// at least one of the actions under the given command's permission, such
+// that some form of the command may be run. Every command may be invoked
+// if no authorizer is given.
+func CanInvoke(authorizer rbac.Authorizer, c *client.Client, command SocketCommand) bool {
+	if authorizer == nil {
+		return true
+	}
+
+	root := command.GetPermission()
+	for _, role := range rbac.SubjectRoles(authorizer, c) {
+		for _, r := range rbac.InheritedRules(role) {
+			for _, a := range r.Actions() {
+				if a == root || strings.HasPrefix(a, root+"/") {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// isLockableAction returns true if the given action
+// is restricted while a room's playback is locked.
+func isLockableAction(action string) bool {
+	return strings.HasPrefix(action, "stream/") && action != STREAM_INFO_ACTION && !strings.HasPrefix(action, STREAM_INFO_ACTION+"/")
+}
+
+// isSpectatorRestrictedAction returns true if the
+// given action is restricted for spectators.
+func isSpectatorRestrictedAction(action string) bool {
+	return isLockableQueueAction(action) || strings.HasPrefix(action, DJ_NAME+"/join")
+}
+
+// isLockableQueueAction returns true if the given action
+// is restricted while a room's queue is locked.
+func isLockableQueueAction(action string) bool {
+	for _, prefix := range []string{"unqueue/", "moveup/", "movedown/"} {
+		if strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(action, "queue/") && !strings.HasPrefix(action, "queue/list")
+}
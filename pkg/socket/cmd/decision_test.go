@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// recordingCmd counts its executions and
+// records the arguments it was last run with
+type recordingCmd struct {
+	Command
+	executions int
+	args       []string
+}
+
+func (h *recordingCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	h.executions++
+	h.args = args
+	return "executed", nil
+}
+
+func TestExecuteCommandAuthorization(t *testing.T) {
+	rooms := newTestRooms(t)
+	admin := rooms.join("a", "admin", rbac.ADMIN_ROLE)
+	viewer := rooms.join("a", "viewer", rbac.VIEWER_ROLE)
+
+	recorder := &recordingCmd{
+		Command: Command{
+			name:       "recorder",
+			permission: "kick",
+		},
+	}
+	rooms.cmdHandler.AddCommand(recorder)
+
+	tests := []struct {
+		name               string
+		user               *client.Client
+		cmdRoot            string
+		args               []string
+		expectUnauthorized bool
+		expectExecutions   int
+	}{
+		{
+			name:             "authorized users run the command",
+			user:             admin,
+			cmdRoot:          "recorder",
+			args:             []string{"viewer"},
+			expectExecutions: 1,
+		},
+		{
+			name:               "unauthorized users are blocked before the command runs",
+			user:               viewer,
+			cmdRoot:            "recorder",
+			args:               []string{"viewer"},
+			expectUnauthorized: true,
+			expectExecutions:   1,
+		},
+		{
+			name:               "actions with no rule are denied",
+			user:               admin,
+			cmdRoot:            "recorder",
+			expectUnauthorized: true,
+			expectExecutions:   1,
+		},
+		{
+			name:             "commands are authorized by their declared permission",
+			user:             viewer,
+			cmdRoot:          NOWPLAYING_NAME,
+			expectExecutions: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := rooms.execute(tc.user, tc.cmdRoot, tc.args...)
+			if unauthorized := errors.Is(err, ErrNotAuthorized); unauthorized != tc.expectUnauthorized {
+				t.Errorf("expected unauthorized: %v, got error %v", tc.expectUnauthorized, err)
+			}
+			if recorder.executions != tc.expectExecutions {
+				t.Errorf("expected %v executions of the command, got %v", tc.expectExecutions, recorder.executions)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	rooms := newTestRooms(t)
+	admin := rooms.join("a", "admin", rbac.ADMIN_ROLE)
+	user := rooms.join("a", "user", rbac.USER_ROLE)
+	viewer := rooms.join("a", "viewer", rbac.VIEWER_ROLE)
+	unbound := rooms.join("a", "unbound", rbac.USER_ROLE)
+	for _, b := range rooms.authorizer.Bindings() {
+		b.RemoveSubject(unbound)
+	}
+
+	holder := rooms.join("locked", "holder", rbac.ADMIN_ROLE)
+	lockedAdmin := rooms.join("locked", "admin", rbac.ADMIN_ROLE)
+	lockedUser := rooms.join("locked", "user", rbac.USER_ROLE)
+	lockedNs, _ := rooms.nsHandler.NamespaceByName("locked")
+	lockedPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(lockedNs)
+	lockedPlayback.Lock(holder.UUID(), "holder")
+	lockedPlayback.LockQueue(holder.UUID(), "holder")
+
+	spectator := rooms.join("a", "spectator", rbac.USER_ROLE)
+	spectator.SetSpectator(true)
+
+	tests := []struct {
+		name          string
+		user          *client.Client
+		action        string
+		expectAllowed bool
+		expectReason  string
+	}{
+		{
+			name:          "admin role",
+			user:          admin,
+			action:        "stream/seek",
+			expectAllowed: true,
+			expectReason:  `your roles (admin) allow "play/pause/skip/reset/load the stream"`,
+		},
+		{
+			name:         "user role",
+			user:         user,
+			action:       "stream/seek",
+			expectReason: `none of your roles (user) allow "play/pause/skip/reset/load the stream"`,
+		},
+		{
+			name:          "user role inherits from the viewer role",
+			user:          user,
+			action:        STREAM_INFO_ACTION,
+			expectAllowed: true,
+			expectReason:  `your roles (user) allow "access stream info"`,
+		},
+		{
+			name:          "viewer role",
+			user:          viewer,
+			action:        STREAM_INFO_ACTION,
+			expectAllowed: true,
+			expectReason:  `your roles (viewer) allow "access stream info"`,
+		},
+		{
+			name:         "viewer role may not add to the queue",
+			user:         viewer,
+			action:       "queue/add/url",
+			expectReason: `none of your roles (viewer) allow "add streams to the queue"`,
+		},
+		{
+			name:         "client bound to no role",
+			user:         unbound,
+			action:       STREAM_INFO_ACTION,
+			expectReason: `you are not bound to any role allowing "access stream info"`,
+		},
+		{
+			name:         "action with no rule",
+			user:         admin,
+			action:       "nonexistent",
+			expectReason: `no rule defines the action "nonexistent"`,
+		},
+		{
+			name:         "locked playback",
+			user:         lockedAdmin,
+			action:       "stream/play",
+			expectReason: `playback is locked by "holder"`,
+		},
+		{
+			name:          "locked playback allows stream info",
+			user:          lockedUser,
+			action:        STREAM_INFO_ACTION,
+			expectAllowed: true,
+			expectReason:  `your roles (user) allow "access stream info"`,
+		},
+		{
+			name:         "locked queue",
+			user:         lockedUser,
+			action:       "queue/add/url",
+			expectReason: `the queue is locked by "holder"`,
+		},
+		{
+			name:          "lock holder",
+			user:          holder,
+			action:        "stream/play",
+			expectAllowed: true,
+			expectReason:  `your roles (admin) allow "play/pause/skip/reset/load the stream"`,
+		},
+		{
+			name:         "spectator",
+			user:         spectator,
+			action:       "queue/add/url",
+			expectReason: "spectators may not change the queue",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			decision := Authorize(rooms.authorizer, tc.user, tc.action, rooms.playbackHandler)
+			if decision.Allowed != tc.expectAllowed {
+				t.Errorf("expected allowed: %v, got %v (%s)", tc.expectAllowed, decision.Allowed, decision.Reason)
+			}
+			if decision.Reason != tc.expectReason {
+				t.Errorf("expected reason %q, got %q", tc.expectReason, decision.Reason)
+			}
+			if err := decision.Err(); errors.Is(err, ErrNotAuthorized) == tc.expectAllowed {
+				t.Errorf("expected an authorization error: %v, got %v", !tc.expectAllowed, err)
+			} else if err != nil && !strings.Contains(err.Error(), tc.expectReason) {
+				t.Errorf("expected the error to include the reason %q, got %v", tc.expectReason, err)
+			}
+		})
+	}
+}
+
+func TestActionByName(t *testing.T) {
+	cmdHandler := NewHandler()
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{
+			name:     SEEK_NAME,
+			expected: "seek/*",
+		},
+		{
+			name:     "seek/+10",
+			expected: "seek/+10",
+		},
+		{
+			name:     "QUEUE/add/url",
+			expected: "queue/add/url",
+		},
+		{
+			name:     NOWPLAYING_NAME,
+			expected: STREAM_INFO_ACTION + "/*",
+		},
+		{
+			name:     "stream/play",
+			expected: "stream/play",
+		},
+		{
+			name:     "nonexistent/action",
+			expected: "nonexistent/action",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if action := ActionByName(cmdHandler, tc.name); action != tc.expected {
+				t.Errorf("expected action %q, got %q", tc.expected, action)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type DJCmd struct {
+	Command
+}
+
+const (
+	DJ_NAME        = "dj"
+	DJ_DESCRIPTION = "join or leave the room's dj rotation, where each dj in turn contributes the next stream from their queue"
+	DJ_USAGE       = "Usage: /" + DJ_NAME + " [join|leave|list]"
+)
+
+var (
+	dj_aliases = []string{}
+)
+
+func (h *DJCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to manage the dj rotation with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to manage its dj rotation.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "join":
+		if !sPlayback.JoinDJRotation(user.UUID()) {
+			return "", fmt.Errorf("error: you are already in the dj rotation")
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has joined the dj rotation", username))
+		if len(sPlayback.DJRotation()) == 1 {
+			return "you have started the room's dj rotation; only streams queued by djs will be played until every dj leaves", nil
+		}
+		return "you have joined the dj rotation", nil
+	case "leave":
+		if !sPlayback.LeaveDJRotation(user.UUID()) {
+			return "", fmt.Errorf("error: you are not in the dj rotation")
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has left the dj rotation", username))
+		if len(sPlayback.DJRotation()) == 0 {
+			user.BroadcastSystemMessageFrom("the dj rotation has ended; streams will be played from every user's queue")
+		}
+		return "you have left the dj rotation", nil
+	case "list":
+		djs := sPlayback.DJRotation()
+		if len(djs) == 0 {
+			return "there is no dj rotation in this room. " + h.usage, nil
+		}
+
+		names := make([]string, 0, len(djs))
+		for idx, id := range djs {
+			name := id
+			if c, err := clientHandler.GetClient(id); err == nil {
+				name = c.GetUsernameOrId()
+			}
+			if idx == 0 {
+				name += " (next)"
+			}
+			names = append(names, name)
+		}
+		return fmt.Sprintf("dj rotation: %s", strings.Join(names, ", ")), nil
+	}
+
+	return "", fmt.Errorf("%v", h.usage)
+}
+
+func NewCmdDJ() SocketCommand {
+	return &DJCmd{
+		Command{
+			name:        DJ_NAME,
+			description: DJ_DESCRIPTION,
+			usage:       DJ_USAGE,
+
+			aliases: dj_aliases,
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type FilterCmd struct {
+	Command
+}
+
+const (
+	FILTER_NAME        = "filter"
+	FILTER_DESCRIPTION = "controls the room's chat word filter (mode|add|remove|list)"
+	FILTER_USAGE       = "Usage: /" + FILTER_NAME + " &lt;mode &lt;off|mask|reject&gt;|add &lt;word...&gt;|remove &lt;word...&gt;|list&gt;"
+)
+
+var (
+	filter_aliases = []string{"wordfilter"}
+)
+
+func (h *FilterCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to update the word filter with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to update its word filter.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		return fmt.Sprintf("this room's word filter mode is %q, filtering %v words.", sPlayback.WordFilterMode(), len(sPlayback.FilteredWords())), nil
+	}
+
+	switch args[0] {
+	case "list":
+		words := sPlayback.FilteredWords()
+		if len(words) == 0 {
+			return "this room does not filter any words.", nil
+		}
+		return fmt.Sprintf("this room filters the following words: %s", strings.Join(words, ", ")), nil
+	case "add":
+		if len(args) < 2 {
+			return h.usage, nil
+		}
+
+		added, err := sPlayback.AddFilteredWords(args[1:]...)
+		if err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		return fmt.Sprintf("added %v words to this room's word filter.", added), nil
+	case "remove":
+		if len(args) < 2 {
+			return h.usage, nil
+		}
+
+		removed := sPlayback.RemoveFilteredWords(args[1:]...)
+		return fmt.Sprintf("removed %v words from this room's word filter.", removed), nil
+	case "mode":
+		if len(args) < 2 {
+			return fmt.Sprintf("this room's word filter mode is %q.", sPlayback.WordFilterMode()), nil
+		}
+
+		if err := sPlayback.SetWordFilterMode(playback.WordFilterMode(args[1])); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+	default:
+		return h.usage, nil
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.Settings(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("roomsettings", res)
+	return fmt.Sprintf("this room's word filter mode is now %q.", args[1]), nil
+}
+
+func NewCmdFilter() SocketCommand {
+	return &FilterCmd{
+		Command{
+			name:        FILTER_NAME,
+			description: FILTER_DESCRIPTION,
+			usage:       FILTER_USAGE,
+
+			aliases: filter_aliases,
+		},
+	}
+}
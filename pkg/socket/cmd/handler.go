@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
@@ -26,12 +27,16 @@ type SocketCommandHandler interface {
 	// the command from the handler's internal map, and calls the
 	// SocketCommand's execute method
 	ExecuteCommand(string, []string, *client.Client, client.SocketClientHandler, playback.PlaybackHandler, stream.StreamHandler) (string, error)
+	// ClearCooldowns receives a client id and removes any
+	// command cooldowns currently in effect for that client
+	ClearCooldowns(string)
 }
 
 // Handler implements SocketCommandHandler
 type Handler struct {
-	commands map[string]SocketCommand
-	aliases  map[string]SocketCommand
+	commands  map[string]SocketCommand
+	aliases   map[string]SocketCommand
+	cooldowns *cooldowns
 }
 
 func (h *Handler) Authorizer() rbac.Authorizer {
@@ -77,8 +82,31 @@ func (h *Handler) ExecuteCommand(cmdRoot string, args []string, client *client.C
 		return "", fmt.Errorf("error: that command does not exist")
 	}
 
-	return command.Execute(h, args, client, clientHandler, playbackHandler, streamHandler)
+	if _, err := AuthorizeCommand(nil, client, command, args, playbackHandler); err != nil {
+		return "", err
+	}
+
+	return executeWithCooldown(h, h.cooldowns, command, args, client, clientHandler, playbackHandler, streamHandler)
+}
+
+func (h *Handler) ClearCooldowns(clientId string) {
+	h.cooldowns.clear(clientId)
+}
+
+// executeWithCooldown runs the given command unless the client is still
+// waiting out the command's cooldown, and starts a new cooldown once the
+// command succeeds.
+func executeWithCooldown(cmdHandler SocketCommandHandler, cd *cooldowns, command SocketCommand, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if err := cd.check(command, user.UUID()); err != nil {
+		return "", err
+	}
 
+	commandsExecuted.WithLabel(command.Name()).Inc()
+	output, err := command.Execute(cmdHandler, args, user, clientHandler, playbackHandler, streamHandler)
+	if err == nil {
+		cd.record(command, user.UUID())
+	}
+	return output, err
 }
 
 // NewHandler creates a new SocketCommand handler
@@ -86,8 +114,9 @@ func (h *Handler) ExecuteCommand(cmdRoot string, args []string, client *client.C
 // invoked through an assigned command id string
 func NewHandler() SocketCommandHandler {
 	h := &Handler{
-		commands: make(map[string]SocketCommand),
-		aliases:  make(map[string]SocketCommand),
+		commands:  make(map[string]SocketCommand),
+		aliases:   make(map[string]SocketCommand),
+		cooldowns: newCooldowns(),
 	}
 
 	addSocketCommands(h)
@@ -100,6 +129,7 @@ type HandlerWithRBAC struct {
 	SocketCommandHandler
 
 	AccessController rbac.Authorizer
+	cooldowns        *cooldowns
 }
 
 func (c *HandlerWithRBAC) Authorizer() rbac.Authorizer {
@@ -112,28 +142,32 @@ func (c *HandlerWithRBAC) ExecuteCommand(cmdRoot string, args []string, client *
 		return "", fmt.Errorf("error: that command does not exist")
 	}
 
-	action := util.CommandAction(command.Name(), args)
-
-	rule, exists := rbac.RuleByAction(c.AccessController.Bindings(), action)
-	if !exists {
-		log.Printf("ERR SOCKET CMD AUTHZ unable to find rule for action %q for client %q with id (%s)", action, client.GetUsernameOrId(), client.UUID())
-		return "", fmt.Errorf("error: unable to authorize the requested command\n%s", command.GetUsage())
+	decision, err := AuthorizeCommand(c.AccessController, client, command, args, playbackHandler)
+	if err != nil {
+		action := util.CommandAction(command.GetPermission(), loggableArgs(command, args))
+		if !decision.hasRule {
+			log.Printf("ERR SOCKET CMD AUTHZ unable to find rule for action %q for client %q with id (%s)", action, client.GetUsernameOrId(), client.UUID())
+		} else {
+			log.Printf("ERR SOCKET CMD AUTHZ client %q with id (%s) has attempted to perform unauthorized action: %q (%s)", client.GetUsernameOrId(), client.UUID(), action, decision.Reason)
+		}
+		return "", err
 	}
 
-	if c.AccessController.Verify(client.Connection(), rule) {
-		return command.Execute(c, args, client, clientHandler, playbackHandler, streamHandler)
+	output, err := executeWithCooldown(c, c.cooldowns, command, args, client, clientHandler, playbackHandler, streamHandler)
+	if isPrivilegedAction(c.AccessController, decision.Action) {
+		recordAudit(command, args, client, clientHandler, playbackHandler, err)
 	}
-
-	log.Printf("ERR SOCKET CMD AUTHZ client %q with id (%s) has attempted to perform unauthorized action: %q", client.GetUsernameOrId(), client.UUID(), action)
-	return "", fmt.Errorf("error: you are not authorized to perform that command")
+	return output, err
 }
 
 // NewControlledHandler returns a command handler capable
 // of restricting command access based on a client's role
 func NewHandlerWithRBAC(authorizer rbac.Authorizer) SocketCommandHandler {
+	h := NewHandler()
 	return &HandlerWithRBAC{
-		SocketCommandHandler: NewHandler(),
+		SocketCommandHandler: h,
 		AccessController:     authorizer,
+		cooldowns:            h.(*Handler).cooldowns,
 	}
 }
 
@@ -141,18 +175,55 @@ func NewHandlerWithRBAC(authorizer rbac.Authorizer) SocketCommandHandler {
 // to a SocketCommand handler
 func addSocketCommands(handler SocketCommandHandler) {
 	handler.AddCommand(NewCmdRole())
+	handler.AddCommand(NewCmdAuditLog())
+	handler.AddCommand(NewCmdAway())
+	handler.AddCommand(NewCmdBack())
+	handler.AddCommand(NewCmdBan())
+	handler.AddCommand(NewCmdCapacity())
 	handler.AddCommand(NewCmdClear())
+	handler.AddCommand(NewCmdColor())
 	handler.AddCommand(NewCmdDebug())
+	handler.AddCommand(NewCmdDJ())
+	handler.AddCommand(NewCmdFilter())
 	handler.AddCommand(NewCmdHelp())
+	handler.AddCommand(NewCmdInvite())
+	handler.AddCommand(NewCmdKick())
+	handler.AddCommand(NewCmdLock())
+	handler.AddCommand(NewCmdMoveDown())
+	handler.AddCommand(NewCmdMoveUp())
+	handler.AddCommand(NewCmdMsg())
+	handler.AddCommand(NewCmdMute())
+	handler.AddCommand(NewCmdNowPlaying())
+	handler.AddCommand(NewCmdPin())
+	handler.AddCommand(NewCmdPoll())
+	handler.AddCommand(NewCmdPresentation())
+	handler.AddCommand(NewCmdRefresh())
+	handler.AddCommand(NewCmdRepeat())
+	handler.AddCommand(NewCmdSlowMode())
+	handler.AddCommand(NewCmdSpectators())
 	handler.AddCommand(NewCmdStream())
 	handler.AddCommand(NewCmdSubtitles())
+	handler.AddCommand(NewCmdSeek())
 	handler.AddCommand(NewCmdQueue())
+	handler.AddCommand(NewCmdRoom())
+	handler.AddCommand(NewCmdSchedule())
+	handler.AddCommand(NewCmdTopic())
+	handler.AddCommand(NewCmdUnlock())
+	handler.AddCommand(NewCmdUnmute())
+	handler.AddCommand(NewCmdUnpin())
+	handler.AddCommand(NewCmdUnqueue())
+	handler.AddCommand(NewCmdUnschedule())
 	handler.AddCommand(NewCmdUser())
 	handler.AddCommand(NewCmdVolume())
+	handler.AddCommand(NewCmdVote())
+	handler.AddCommand(NewCmdVoteSkip())
 	handler.AddCommand(NewCmdWhoami())
 }
 
 func resolveCommandAlias(cmdRoot string, commands, aliases map[string]SocketCommand) (SocketCommand, bool) {
+	// command names and aliases are matched case-insensitively
+	cmdRoot = strings.ToLower(cmdRoot)
+
 	command, exists := commands[cmdRoot]
 	if !exists {
 		command, exists = aliases[cmdRoot]
@@ -161,15 +232,30 @@ func resolveCommandAlias(cmdRoot string, commands, aliases map[string]SocketComm
 	return command, exists
 }
 
+const (
+	// CHAT_EXPORT_ACTION is authorized for users who
+	// may export their room's chat history
+	CHAT_EXPORT_ACTION = "export/chat"
+	// CHAT_DELETE_ACTION is authorized for users who may
+	// delete chat messages sent by other users
+	CHAT_DELETE_ACTION = "chat/delete"
+	// STREAM_INFO_ACTION is authorized for users who may
+	// view information about the room's current stream
+	STREAM_INFO_ACTION = "stream/info"
+)
+
 func AddDefaultRoles(authz rbac.Authorizer) {
 	// default rules
 	clearChat := rbac.NewRule("clear the chat", []string{"clear"})
+	clearQueue := rbac.NewRule("clear the room's queue", []string{"clear/queue"})
 	debugReload := rbac.NewRule("reload all clients", []string{
 		"debug/reload",
 		"debug/refresh",
 	})
 	help := rbac.NewRule("access command help", []string{"help"})
-	streamInfo := rbac.NewRule("access stream info", []string{"stream/info"})
+	streamInfo := rbac.NewRule("access stream info", []string{
+		STREAM_INFO_ACTION,
+	})
 	streamControl := rbac.NewRule("play/pause/skip/reset/load the stream", []string{
 		"stream/play",
 		"stream/skip",
@@ -178,14 +264,37 @@ func AddDefaultRoles(authz rbac.Authorizer) {
 		"stream/pause",
 		"stream/stop",
 		"stream/seek",
+		"stream/duration",
+		"stream/duration/*",
+		"stream/chapters/set",
+		"stream/chapters/jump",
+		"stream/lock",
+		"stream/unlock",
+		"stream/interrupt",
+		"stream/start",
+		"stream/snapshot/load",
+	})
+	repeat := rbac.NewRule("set the room's repeat mode", []string{
+		"repeat",
+		"repeat/*",
+	})
+	seek := rbac.NewRule("seek the stream", []string{
+		"seek/*",
 	})
 	subtitles := rbac.NewRule("control stream subtitles", []string{
 		"subtitles/on",
 		"subtitles/off",
+		"subtitles/list",
+	})
+	subtitlesTrack := rbac.NewRule("select the subtitle track everyone in the room should enable", []string{
+		"subtitles/track/*",
 	})
 	queueAdd := rbac.NewRule("add streams to the queue", []string{
 		"queue/add/*",
 	})
+	queueImport := rbac.NewRule("import lists of streams to the queue", []string{
+		"queue/import/*",
+	})
 	queueList := rbac.NewRule("list items in the queue", []string{
 		"queue/list/*",
 	})
@@ -218,6 +327,12 @@ func AddDefaultRoles(authz rbac.Authorizer) {
 		"role/set/*",
 		"role/add/*",
 		"role/remove/*",
+		"role/bind/*",
+		"role/unbind/*",
+	})
+	roleCreate := rbac.NewRule("create roles from existing permissions, and set the roles they inherit from", []string{
+		"role/create/*",
+		"role/inherit/*",
 	})
 	userUpdateName := rbac.NewRule("update a client's username", []string{
 		"user/name/*",
@@ -225,42 +340,232 @@ func AddDefaultRoles(authz rbac.Authorizer) {
 	userList := rbac.NewRule("list users in a room", []string{
 		"user/list",
 	})
+	color := rbac.NewRule("update your display color", []string{
+		"color",
+		"color/*",
+	})
+	presence := rbac.NewRule("mark yourself as away or back", []string{
+		"away",
+		"back",
+	})
 	volume := rbac.NewRule("update your volume", []string{
 		"volume/*",
 	})
+	djList := rbac.NewRule("list the room's dj rotation", []string{
+		"dj",
+		"dj/list",
+	})
+	djRotation := rbac.NewRule("join or leave the room's dj rotation", []string{
+		"dj/join",
+		"dj/leave",
+	})
+	participate := rbac.NewRule("chat and change the queue", []string{
+		PARTICIPATE_ACTION,
+	})
+	spectatorsList := rbac.NewRule("list the room's spectators", []string{
+		"spectators",
+		"spectators/list",
+	})
+	spectatorsManage := rbac.NewRule("add or remove the room's spectators", []string{
+		"spectators/add/*",
+		"spectators/remove/*",
+	})
 	whoami := rbac.NewRule("list your current username", []string{
 		"whoami",
 	})
+	directMessage := rbac.NewRule("send private messages to users in the room", []string{
+		"msg/*",
+	})
+	voteSkip := rbac.NewRule("vote to skip the current stream", []string{
+		"voteskip",
+	})
+	unqueue := rbac.NewRule("remove an item from the queue", []string{
+		"unqueue/*",
+	})
+	moveMine := rbac.NewRule("move items up or down in your queue", []string{
+		"moveup/*",
+		"movedown/*",
+	})
 
 	queueMigrate := rbac.NewRule("migrate a user's queue to yours", []string{
 		"queue/migrate/*",
 	})
+	roomListing := rbac.NewRule("list or unlist the room", []string{
+		"room/list",
+		"room/unlist",
+	})
+	roomQueueLimit := rbac.NewRule("set the room's queue limit", []string{
+		"room/queuelimit",
+		"room/queuelimit/*",
+	})
+	roomDuplicates := rbac.NewRule("set the room's duplicate queue item policy", []string{
+		"room/duplicates",
+		"room/duplicates/*",
+	})
+	roomVolume := rbac.NewRule("set the volume suggested to everyone in the room", []string{
+		ROOM_VOLUME_ACTION,
+	})
+	roomQueueMode := rbac.NewRule("set the order in which the room's queue is played", []string{
+		"room/queuemode",
+		"room/queuemode/*",
+	})
+	roomAnnounce := rbac.NewRule("toggle the room's new stream announcements", []string{
+		"room/announce",
+		"room/announce/*",
+	})
+	roomMarkdown := rbac.NewRule("toggle Markdown formatting of the room's chat messages", []string{
+		"room/markdown",
+		"room/markdown/*",
+	})
+	roomCountdown := rbac.NewRule("set the room's pre-stream countdown", []string{
+		"room/countdown",
+		"room/countdown/*",
+	})
+	moderateUsers := rbac.NewRule("kick or ban users from the room", []string{
+		"kick/*",
+		"ban/*",
+	})
+	moderateChat := rbac.NewRule("delete other users' chat messages", []string{
+		CHAT_DELETE_ACTION,
+	})
+	pinMessages := rbac.NewRule("pin or unpin chat messages", []string{
+		"pin/*",
+		"unpin",
+		"unpin/*",
+	})
+	muteUsers := rbac.NewRule("mute or unmute users in the room", []string{
+		"mute/*",
+		"unmute/*",
+	})
+	roomPassword := rbac.NewRule("set or remove the room's password", []string{
+		"lock/*",
+		"unlock",
+	})
+	roomCapacity := rbac.NewRule("set the maximum number of users in the room", []string{
+		"capacity",
+		"capacity/*",
+	})
+	roomInvite := rbac.NewRule("create or revoke invites to the room", []string{
+		"invite",
+		"invite/*",
+	})
+	topicView := rbac.NewRule("view the room's topic", []string{
+		"topic",
+	})
+	topicSet := rbac.NewRule("set or clear the room's topic and description", []string{
+		"topic/*",
+	})
+	wordFilter := rbac.NewRule("view or change the room's chat word filter", []string{
+		"filter",
+		"filter/*",
+	})
+	slowMode := rbac.NewRule("set the room's chat slow mode, and chat regardless of it", []string{
+		"slowmode",
+		"slowmode/*",
+	})
+	pollView := rbac.NewRule("view the room's poll", []string{
+		"poll",
+	})
+	pollVote := rbac.NewRule("vote in the room's poll", []string{
+		"vote/*",
+	})
+	pollManage := rbac.NewRule("create or close the room's poll", []string{
+		"poll/create/*",
+		"poll/close",
+	})
+	scheduleView := rbac.NewRule("view the room's scheduled stream", []string{
+		"schedule",
+	})
+	scheduleSet := rbac.NewRule("schedule or cancel a stream for the room", []string{
+		"schedule/*",
+		"unschedule",
+	})
+	streamRefresh := rbac.NewRule("fetch stream metadata again", []string{
+		"refresh",
+		"refresh/*",
+	})
+	auditLog := rbac.NewRule("view the room's audit log", []string{
+		"auditlog",
+		"auditlog/*",
+	})
+	chatExport := rbac.NewRule("export the room's chat history", []string{
+		CHAT_EXPORT_ACTION,
+	})
+	presentation := rbac.NewRule("toggle presentation mode", []string{
+		"presentation/on",
+		"presentation/off",
+	})
 
 	// default roles
 	viewerRole := rbac.NewRole(rbac.VIEWER_ROLE, []rbac.Rule{
+		color,
+		djList,
 		help,
+		pollView,
 		streamInfo,
 		subtitles,
+		presence,
 		queueList,
+		scheduleView,
+		spectatorsList,
+		topicView,
 		userList,
 		volume,
 		whoami,
 	})
-	userRole := rbac.NewRole(rbac.USER_ROLE, append([]rbac.Rule{
+	userRole := rbac.NewRoleWithParent(rbac.USER_ROLE, viewerRole, []rbac.Rule{
 		clearChat,
+		directMessage,
+		djRotation,
+		moveMine,
+		participate,
+		pollVote,
 		queueAdd,
 		queueClearMine,
+		queueImport,
 		queueOrderMine,
+		streamRefresh,
+		unqueue,
 		userUpdateName,
-	}, viewerRole.Rules()...))
-	adminRole := rbac.NewRole(rbac.ADMIN_ROLE, append([]rbac.Rule{
+		voteSkip,
+	})
+	adminRole := rbac.NewRoleWithParent(rbac.ADMIN_ROLE, userRole, []rbac.Rule{
+		auditLog,
+		chatExport,
+		clearQueue,
 		debugReload,
+		moderateChat,
+		moderateUsers,
+		muteUsers,
+		pinMessages,
+		pollManage,
+		presentation,
 		queueClearRoom,
 		queueMigrate,
 		queueOrderRoom,
+		repeat,
+		roleCreate,
 		roleEdit,
+		roomAnnounce,
+		roomCapacity,
+		roomCountdown,
+		roomDuplicates,
+		roomInvite,
+		roomListing,
+		roomMarkdown,
+		roomPassword,
+		roomQueueLimit,
+		roomQueueMode,
+		roomVolume,
+		scheduleSet,
+		seek,
+		slowMode,
+		spectatorsManage,
 		streamControl,
-	}, userRole.Rules()...))
+		subtitlesTrack,
+		topicSet,
+		wordFilter,
+	})
 
 	roles := []rbac.Role{
 		viewerRole,
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// fakeConnection implements connection.Connection without a websocket,
+// recording the messages it broadcasts to its room and the messages
+// sent to it
+type fakeConnection struct {
+	id       string
+	ns       connection.Namespace
+	req      *http.Request
+	metadata connection.ConnectionMetadata
+
+	// guards broadcasts and sent, which may be
+	// recorded by commands' background work
+	mutex      sync.Mutex
+	broadcasts []fakeBroadcast
+	sent       []fakeBroadcast
+}
+
+// fakeBroadcast is a response broadcast to a room by a client, or sent to it
+type fakeBroadcast struct {
+	Event string          `json:"event"`
+	Data  client.Response `json:"data"`
+}
+
+func (c *fakeConnection) Broadcast(room, evt string, data []byte) {
+	message := fakeBroadcast{}
+	if err := json.Unmarshal(data, &message); err == nil {
+		c.mutex.Lock()
+		c.broadcasts = append(c.broadcasts, message)
+		c.mutex.Unlock()
+	}
+}
+func (c *fakeConnection) BroadcastFrom(string, string, []byte) {}
+func (c *fakeConnection) Close() error                         { return nil }
+func (c *fakeConnection) Metadata() connection.ConnectionMetadata {
+	return c.metadata
+}
+func (c *fakeConnection) MissedPongs() int { return 0 }
+func (c *fakeConnection) Ping() error      { return nil }
+func (c *fakeConnection) Connections() []connection.Connection {
+	return c.ns.Connections()
+}
+func (c *fakeConnection) Emit(string, connection.MessageDataCodec) {}
+func (c *fakeConnection) UUID() string                             { return c.id }
+func (c *fakeConnection) Join(string)                              {}
+func (c *fakeConnection) Leave(string)                             {}
+func (c *fakeConnection) Namespace() (connection.Namespace, bool) {
+	return c.ns, c.ns != nil
+}
+func (c *fakeConnection) On(string, connection.SocketEventCallback) {}
+func (c *fakeConnection) ReadMessage() (int, []byte, error) {
+	return 0, nil, fmt.Errorf("fake connections cannot be read from")
+}
+func (c *fakeConnection) ResponseWriter() http.ResponseWriter { return nil }
+func (c *fakeConnection) Request() *http.Request              { return c.req }
+func (c *fakeConnection) Send(data []byte) {
+	message := fakeBroadcast{}
+	if err := json.Unmarshal(data, &message); err == nil {
+		c.mutex.Lock()
+		c.sent = append(c.sent, message)
+		c.mutex.Unlock()
+	}
+}
+func (c *fakeConnection) WriteMessage(int, []byte) error { return nil }
+
+// waitForSent waits up to a second for a response with the given event
+// name matching the given function to be sent to the connection.
+// Returns a boolean (false) if no such response is sent in time.
+func (c *fakeConnection) waitForSent(evt string, match func(client.Response) bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		c.mutex.Lock()
+		for _, m := range c.sent {
+			if m.Event == evt && match(m.Data) {
+				c.mutex.Unlock()
+				return true
+			}
+		}
+		c.mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+// fakeStreamHandler is a stream handler that serves the metadata stubbed
+// for each stream url instead of fetching it, and never considers it
+// expired. Fetching metadata for a url with none stubbed fails. Unless
+// fetches are held, they complete before FetchMetadata returns.
+type fakeStreamHandler struct {
+	stream.StreamHandler
+
+	mutex    sync.Mutex
+	metadata map[string][]byte
+	fetches  int
+	// if set, fetches complete in the background once this is closed
+	held chan struct{}
+}
+
+func (h *fakeStreamHandler) FetchMetadata(s stream.Stream, callback stream.StreamMetadataCallback) {
+	h.mutex.Lock()
+	data, exists := h.metadata[s.GetStreamURL()]
+	h.fetches++
+	held := h.held
+	h.mutex.Unlock()
+
+	fetch := func() {
+		if !exists {
+			callback(s, nil, fmt.Errorf("no metadata stubbed for %q", s.GetStreamURL()))
+			return
+		}
+		callback(s, data, nil)
+	}
+
+	if held == nil {
+		fetch()
+		return
+	}
+	go func() {
+		<-held
+		fetch()
+	}()
+}
+
+func (h *fakeStreamHandler) MetadataExpired(stream.Stream) bool { return false }
+
+// stub sets the metadata served for the given
+// url to a stream info with the given duration
+func (h *fakeStreamHandler) stub(url string, duration float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.metadata[url] = []byte(fmt.Sprintf(`{"duration": %v}`, duration))
+}
+
+// hold causes metadata fetches to complete only once release is called
+func (h *fakeStreamHandler) hold() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.held = make(chan struct{})
+}
+
+// release completes any held metadata fetches, and stops holding new ones
+func (h *fakeStreamHandler) release() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	close(h.held)
+	h.held = nil
+}
+
+func newFakeStreamHandler() *fakeStreamHandler {
+	return &fakeStreamHandler{
+		StreamHandler: stream.NewHandler(),
+		metadata:      make(map[string][]byte),
+	}
+}
+
+// testRooms holds the handlers shared by the clients of
+// one or more rooms, with role-based access control enabled
+type testRooms struct {
+	t *testing.T
+
+	authorizer      rbac.Authorizer
+	cmdHandler      SocketCommandHandler
+	nsHandler       connection.NamespaceHandler
+	clientHandler   client.SocketClientHandler
+	playbackHandler playback.PlaybackHandler
+	streamHandler   *fakeStreamHandler
+}
+
+func newTestRooms(t *testing.T) *testRooms {
+	authorizer := rbac.NewAuthorizer()
+	AddDefaultRoles(authorizer)
+
+	nsHandler := connection.NewNamespaceHandler()
+	return &testRooms{
+		t:               t,
+		authorizer:      authorizer,
+		cmdHandler:      NewHandlerWithRBAC(authorizer),
+		nsHandler:       nsHandler,
+		clientHandler:   client.NewHandler(),
+		playbackHandler: playback.NewHandler(nsHandler),
+		streamHandler:   newFakeStreamHandler(),
+	}
+}
+
+// join adds a client with the given username and role to the room with
+// the given name, creating the room and its playback if necessary
+func (r *testRooms) join(room, username, role string) *client.Client {
+	c, _ := r.joinWithConnection(room, username, role)
+	return c
+}
+
+// joinWithConnection joins a client to a room like join, and also
+// returns the client's connection
+func (r *testRooms) joinWithConnection(room, username, role string) (*client.Client, *fakeConnection) {
+	ns, exists := r.nsHandler.NamespaceByName(room)
+	if !exists {
+		ns = r.nsHandler.NewNamespace(room)
+	}
+	if _, exists := r.playbackHandler.PlaybackByNamespace(ns); !exists {
+		r.playbackHandler.NewPlayback(ns, r.authorizer, r.clientHandler)
+	}
+
+	conn := &fakeConnection{
+		id:       room + "-" + username,
+		ns:       ns,
+		req:      httptest.NewRequest(http.MethodGet, "/"+room, nil),
+		metadata: connection.NewConnectionMetadata(),
+	}
+	ns.Add(conn)
+
+	c := r.clientHandler.CreateClient(conn)
+	if err := c.UpdateUsername(username); err != nil {
+		r.t.Fatalf("unexpected error setting username %q: %v", username, err)
+	}
+
+	rbacRole, exists := r.authorizer.Role(role)
+	if !exists {
+		r.t.Fatalf("unknown role %q", role)
+	}
+	r.authorizer.Bind(rbacRole, c)
+	return c, conn
+}
+
+// execute runs the given command line as the given client
+func (r *testRooms) execute(c *client.Client, cmdRoot string, args ...string) (string, error) {
+	return r.cmdHandler.ExecuteCommand(cmdRoot, args, c, r.clientHandler, r.playbackHandler, r.streamHandler)
+}
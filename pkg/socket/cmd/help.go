@@ -25,6 +25,11 @@ var (
 func (h *HelpCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
 	output := "Commands help:<br />"
 	for _, command := range cmdHandler.Commands() {
+		// only list commands the user is allowed to run
+		if !CanInvoke(cmdHandler.Authorizer(), user, command) {
+			continue
+		}
+
 		output += fmt.Sprintf("<br /><span class='text-hl-name'>%s</span>: %s", command.Name(), command.GetDescription())
 	}
 
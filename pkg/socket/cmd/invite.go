@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/server/path"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type InviteCmd struct {
+	Command
+}
+
+const (
+	INVITE_NAME        = "invite"
+	INVITE_DESCRIPTION = "creates a token admitting users to a password-protected room, or revokes all tokens"
+	INVITE_USAGE       = "Usage: /" + INVITE_NAME + " [&lt;uses|0&gt; [&lt;duration|never&gt;]] | revoke"
+)
+
+var (
+	invite_aliases = []string{}
+)
+
+func (h *InviteCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to create a room invite with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to invite users to it.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) > 0 && args[0] == "revoke" {
+		sPlayback.RevokeInvites()
+		return "all invites to the room have been revoked.", nil
+	}
+
+	// invites are single-use and expire after a day by default
+	uses := 1
+	ttl := playback.DefaultInviteTTL
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("error: %q is not a valid number of uses\n%s", args[0], h.usage)
+		}
+		uses = n
+	}
+	if len(args) > 1 {
+		if args[1] == "never" {
+			ttl = 0
+		} else {
+			d, err := time.ParseDuration(args[1])
+			if err != nil || d <= 0 {
+				return "", fmt.Errorf("error: %q is not a valid duration (e.g. 30m, 2h)\n%s", args[1], h.usage)
+			}
+			ttl = d
+		}
+	}
+
+	token, err := sPlayback.CreateInvite(uses, ttl)
+	if err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	limits := "unlimited uses"
+	if uses == 1 {
+		limits = "single use"
+	} else if uses > 1 {
+		limits = fmt.Sprintf("%v uses", uses)
+	}
+	if ttl > 0 {
+		limits += fmt.Sprintf(", expires in %v", ttl)
+	} else {
+		limits += ", never expires"
+	}
+
+	output := fmt.Sprintf("created an invite to the room (%s): %s", limits, token)
+	output += fmt.Sprintf("<br />join link: %s%s?%s=%s", path.RoomRootPrefix, userRoom.Name(), query.ROOM_INVITE_KEY, token)
+	if !sPlayback.HasPassword() {
+		output += "<br />note: the room does not currently require a password, so invites are not needed to join it."
+	}
+	return output, nil
+}
+
+func NewCmdInvite() SocketCommand {
+	return &InviteCmd{
+		Command{
+			name:        INVITE_NAME,
+			description: INVITE_DESCRIPTION,
+			usage:       INVITE_USAGE,
+
+			aliases: invite_aliases,
+
+			// invites stand in for the room password
+			secretArgs: true,
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type KickCmd struct {
+	Command
+}
+
+const (
+	KICK_NAME        = "kick"
+	KICK_DESCRIPTION = "disconnects a user from the room"
+	KICK_USAGE       = "Usage: /" + KICK_NAME + " &lt;username&gt;"
+)
+
+var (
+	kick_aliases = []string{}
+)
+
+func (h *KickCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	target, err := findRoomClient(user, clientHandler, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	removeClient(user, target, "kicked")
+	return fmt.Sprintf("%q has been kicked from the room.", args[0]), nil
+}
+
+// findRoomClient returns the client in the user's room with the given
+// username. Returns an error if no such client exists, if more than one
+// client shares the username, or if the client is the user themselves.
+func findRoomClient(user *client.Client, clientHandler client.SocketClientHandler, username string) (*client.Client, error) {
+	namespace, exists := user.Namespace()
+	if !exists {
+		return nil, fmt.Errorf("error: you must be in a room to perform this action.")
+	}
+
+	var match *client.Client
+	for _, conn := range namespace.Connections() {
+		c, err := clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			continue
+		}
+
+		if name, hasName := c.GetUsername(); !hasName || name != username {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("error: more than one user in your room is named %q", username)
+		}
+
+		match = c
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("error: unable to find user %q in your room", username)
+	}
+	if match.UUID() == user.UUID() {
+		return nil, fmt.Errorf("error: you cannot perform this action on yourself.")
+	}
+
+	return match, nil
+}
+
+// removeClient notifies the room that the target client has been
+// removed by the user, and disconnects the target client.
+func removeClient(user, target *client.Client, reason string) {
+	username := user.GetUsernameOrId()
+	targetName := target.GetUsernameOrId()
+
+	target.BroadcastSystemMessageTo(fmt.Sprintf("you have been %s from the room by %q", reason, username))
+	user.BroadcastAll("info_clientremoved", &client.Response{
+		Id:   target.UUID(),
+		From: targetName,
+		Extra: map[string]interface{}{
+			"reason": reason,
+			"by":     username,
+		},
+	})
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has been %s from the room by %q", targetName, reason, username))
+
+	if err := target.Disconnect(); err != nil {
+		log.Printf("ERR SOCKET CLIENT unable to disconnect client %q (%s): %v", target.UUID(), targetName, err)
+	}
+}
+
+func NewCmdKick() SocketCommand {
+	return &KickCmd{
+		Command{
+			name:        KICK_NAME,
+			description: KICK_DESCRIPTION,
+			usage:       KICK_USAGE,
+
+			aliases: kick_aliases,
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type LockCmd struct {
+	Command
+}
+
+const (
+	LOCK_NAME        = "lock"
+	LOCK_DESCRIPTION = "requires a password in order to join the room"
+	LOCK_USAGE       = "Usage: /" + LOCK_NAME + " &lt;password&gt;"
+)
+
+var (
+	lock_aliases = []string{}
+)
+
+func (h *LockCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to set a room password with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to set its password.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if err := sPlayback.SetPassword(strings.Join(args, " ")); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has password-protected the room", username))
+	return "the room now requires a password to join. Use /unlock to remove it.", nil
+}
+
+func NewCmdLock() SocketCommand {
+	return &LockCmd{
+		Command{
+			name:        LOCK_NAME,
+			description: LOCK_DESCRIPTION,
+			usage:       LOCK_USAGE,
+
+			aliases: lock_aliases,
+
+			// the room password must never be logged
+			secretArgs: true,
+		},
+	}
+}
@@ -0,0 +1,9 @@
+package cmd
+
+import "github.com/juanvallejo/streaming-server/pkg/metrics"
+
+var commandsExecuted = metrics.NewCounterVec("streaming_commands_executed_total", "Number of commands executed, by command name.", "command")
+
+func init() {
+	metrics.MustRegister(commandsExecuted)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type MoveDownCmd struct {
+	Command
+}
+
+const (
+	MOVEDOWN_NAME        = "movedown"
+	MOVEDOWN_DESCRIPTION = "moves an item down by one position in your queue"
+	MOVEDOWN_USAGE       = "Usage: /" + MOVEDOWN_NAME + " &lt;id&gt;"
+)
+
+var (
+	movedown_aliases = []string{"md"}
+)
+
+func (h *MoveDownCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	return moveUserQueueItem(user, playbackHandler, args[0], 1)
+}
+
+func NewCmdMoveDown() SocketCommand {
+	return &MoveDownCmd{
+		Command{
+			name:        MOVEDOWN_NAME,
+			description: MOVEDOWN_DESCRIPTION,
+			usage:       MOVEDOWN_USAGE,
+
+			aliases: movedown_aliases,
+		},
+	}
+}
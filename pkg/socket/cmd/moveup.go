@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type MoveUpCmd struct {
+	Command
+}
+
+const (
+	MOVEUP_NAME        = "moveup"
+	MOVEUP_DESCRIPTION = "moves an item up by one position in your queue"
+	MOVEUP_USAGE       = "Usage: /" + MOVEUP_NAME + " &lt;id&gt;"
+)
+
+var (
+	moveup_aliases = []string{"mu"}
+)
+
+func (h *MoveUpCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	return moveUserQueueItem(user, playbackHandler, args[0], -1)
+}
+
+func NewCmdMoveUp() SocketCommand {
+	return &MoveUpCmd{
+		Command{
+			name:        MOVEUP_NAME,
+			description: MOVEUP_DESCRIPTION,
+			usage:       MOVEUP_USAGE,
+
+			aliases: moveup_aliases,
+		},
+	}
+}
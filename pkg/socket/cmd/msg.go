@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type MsgCmd struct {
+	Command
+}
+
+const (
+	MSG_NAME        = "msg"
+	MSG_DESCRIPTION = "sends a private message to a single user in the room"
+	MSG_USAGE       = "Usage: /" + MSG_NAME + " &lt;username&gt; &lt;message&gt;"
+)
+
+var (
+	msg_aliases = []string{"dm"}
+)
+
+func (h *MsgCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to send a private message with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to send private messages.")
+	}
+
+	if sPlayback, exists := playbackHandler.PlaybackByNamespace(userRoom); exists {
+		if _, muted := sPlayback.MutedFor(user.Address()); muted {
+			return "", fmt.Errorf("error: you have been muted in this room")
+		}
+	}
+	if !user.AllowChatMessage() {
+		return "", fmt.Errorf("error: you are sending messages too quickly - please wait a moment and try again")
+	}
+
+	target, err := findRoomClient(user, clientHandler, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	res := &client.Response{
+		Id:      user.UUID(),
+		From:    username,
+		Message: strings.Join(args[1:], " "),
+		Extra: map[string]interface{}{
+			"direct": true,
+			"to":     target.GetUsernameOrId(),
+		},
+	}
+
+	// deliver the message to the recipient, and echo it back to the sender
+	target.BroadcastTo("chatmessage", res)
+	user.BroadcastTo("chatmessage", res)
+	return "", nil
+}
+
+func NewCmdMsg() SocketCommand {
+	return &MsgCmd{
+		Command{
+			name:        MSG_NAME,
+			description: MSG_DESCRIPTION,
+			usage:       MSG_USAGE,
+
+			aliases: msg_aliases,
+
+			// direct messages are private to their sender and recipient
+			secretArgs: true,
+		},
+	}
+}
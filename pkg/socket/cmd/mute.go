@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type MuteCmd struct {
+	Command
+}
+
+const (
+	MUTE_NAME        = "mute"
+	MUTE_DESCRIPTION = "suppresses a user's chat messages, optionally for a limited time"
+	MUTE_USAGE       = "Usage: /" + MUTE_NAME + " &lt;username&gt; [seconds|0h0m0s]"
+)
+
+var (
+	mute_aliases = []string{}
+)
+
+func (h *MuteCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to mute a user with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to mute users in it.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	var duration time.Duration
+	if len(args) > 1 {
+		seconds, err := strconv.Atoi(args[1])
+		if err != nil {
+			// if an int was not received, try to parse human-readable time format (0h0m0s)
+			seconds, err = util.HumanTimeToSeconds(args[1])
+			if err != nil {
+				return "", fmt.Errorf("error: cannot interpret %q as a valid duration. Must be of the form 12345 or 0h0m0s", args[1])
+			}
+		}
+		if seconds <= 0 {
+			return "", fmt.Errorf("error: a mute duration must be greater than zero")
+		}
+		duration = time.Duration(seconds) * time.Second
+	}
+
+	target, err := findRoomClient(user, clientHandler, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	sPlayback.Mute(target.Address(), args[0], duration)
+
+	msg := fmt.Sprintf("you have been muted by %q", username)
+	output := fmt.Sprintf("%q has been muted.", args[0])
+	if duration > 0 {
+		msg = fmt.Sprintf("%s for %v", msg, duration)
+		output = fmt.Sprintf("%q has been muted for %v.", args[0], duration)
+	}
+
+	target.BroadcastSystemMessageTo(msg)
+	return output, nil
+}
+
+func NewCmdMute() SocketCommand {
+	return &MuteCmd{
+		Command{
+			name:        MUTE_NAME,
+			description: MUTE_DESCRIPTION,
+			usage:       MUTE_USAGE,
+
+			aliases: mute_aliases,
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type NowPlayingCmd struct {
+	Command
+}
+
+const (
+	NOWPLAYING_NAME        = "nowplaying"
+	NOWPLAYING_DESCRIPTION = "displays details about the currently-playing stream"
+	NOWPLAYING_USAGE       = "Usage: /" + NOWPLAYING_NAME
+)
+
+var (
+	nowplaying_aliases = []string{"np"}
+)
+
+func (h *NowPlayingCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to view the current stream with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to view its current stream.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	s, exists := sPlayback.GetStream()
+	if !exists {
+		return "nothing is playing right now.", nil
+	}
+
+	status, ok := sPlayback.GetStatus().(*playback.PlaybackStatus)
+	if !ok {
+		return "", fmt.Errorf("error: unable to read the room's playback status")
+	}
+
+	title := s.GetName()
+	if len(title) == 0 {
+		title = s.GetStreamURL()
+	}
+
+	elapsed := util.SecondsToClockTime(sPlayback.GetTime())
+	total := "live"
+	if endTime, hasEndTime := sPlayback.EndTime(); hasEndTime {
+		total = util.SecondsToClockTime(int(endTime))
+	}
+
+	output := fmt.Sprintf("now playing: %s", title)
+	output += fmt.Sprintf("<br />url: %s", s.GetStreamURL())
+	output += fmt.Sprintf("<br />time: %s / %s", elapsed, total)
+	output += fmt.Sprintf("<br />started by: %s", status.StartedBy)
+	return output, nil
+}
+
+func NewCmdNowPlaying() SocketCommand {
+	return &NowPlayingCmd{
+		Command{
+			name:        NOWPLAYING_NAME,
+			description: NOWPLAYING_DESCRIPTION,
+			usage:       NOWPLAYING_USAGE,
+
+			aliases: nowplaying_aliases,
+
+			// shows the same information as /stream info
+			permission: STREAM_INFO_ACTION,
+		},
+	}
+}
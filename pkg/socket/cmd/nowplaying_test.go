@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestNowPlaying(t *testing.T) {
+	rooms := newTestRooms(t)
+	rooms.streamHandler.stub("http://example.com/a.mp4", 7200)
+	admin := rooms.join("playing", "admin", rbac.ADMIN_ROLE)
+	viewer := rooms.join("playing", "viewer", rbac.VIEWER_ROLE)
+	idle := rooms.join("idle", "viewer", rbac.VIEWER_ROLE)
+
+	if _, err := rooms.execute(admin, QUEUE_NAME, "add", "http://example.com/a.mp4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ns, _ := rooms.nsHandler.NamespaceByName("playing")
+	sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+
+	// keep the playback time still
+	sPlayback.Pause()
+	sPlayback.SetTime(90)
+
+	tests := []struct {
+		name         string
+		user         *client.Client
+		cmdRoot      string
+		expectOutput []string
+	}{
+		{
+			name:    "stream playing",
+			user:    viewer,
+			cmdRoot: NOWPLAYING_NAME,
+			expectOutput: []string{
+				"now playing: http://example.com/a.mp4",
+				"url: http://example.com/a.mp4",
+				"time: 1:30 / 2:00:00",
+				"started by: admin",
+			},
+		},
+		{
+			name:    "stream playing by alias",
+			user:    viewer,
+			cmdRoot: "np",
+			expectOutput: []string{
+				"now playing: http://example.com/a.mp4",
+			},
+		},
+		{
+			name:         "nothing playing",
+			user:         idle,
+			cmdRoot:      NOWPLAYING_NAME,
+			expectOutput: []string{"nothing is playing right now."},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := rooms.execute(tc.user, tc.cmdRoot)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range tc.expectOutput {
+				if !strings.Contains(output, expected) {
+					t.Errorf("expected output to contain %q, got %q", expected, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type PinCmd struct {
+	Command
+}
+
+const (
+	PIN_NAME        = "pin"
+	PIN_DESCRIPTION = "pins a chat message, keeping it visible to everyone in the room"
+	PIN_USAGE       = "Usage: /" + PIN_NAME + " &lt;messageId&gt;"
+)
+
+var (
+	pin_aliases = []string{}
+)
+
+func (h *PinCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to pin a message with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to pin its messages.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	replaced, err := sPlayback.PinChatMessage(args[0], username)
+	if err != nil {
+		return "", err
+	}
+
+	if err := broadcastPinnedMessages(user, sPlayback); err != nil {
+		return "", err
+	}
+
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has pinned a message", username))
+	if replaced != nil {
+		return fmt.Sprintf("the message has been pinned, replacing the message from %q", replaced.From), nil
+	}
+	return "the message has been pinned.", nil
+}
+
+// broadcastPinnedMessages sends a "pins" event, describing
+// the room's pinned messages, to every client in the room
+func broadcastPinnedMessages(user *client.Client, sPlayback *playback.Playback) error {
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: user.GetUsernameOrId(),
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.PinnedMessages(), &res.Extra)
+	if err != nil {
+		return err
+	}
+
+	user.BroadcastAll("pins", res)
+	return nil
+}
+
+func NewCmdPin() SocketCommand {
+	return &PinCmd{
+		Command{
+			name:        PIN_NAME,
+			description: PIN_DESCRIPTION,
+			usage:       PIN_USAGE,
+
+			aliases: pin_aliases,
+		},
+	}
+}
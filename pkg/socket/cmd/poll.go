@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type PollCmd struct {
+	Command
+}
+
+const (
+	POLL_NAME        = "poll"
+	POLL_DESCRIPTION = "displays, creates, or closes the room's poll"
+	POLL_USAGE       = "Usage: /" + POLL_NAME + " [create \"&lt;question&gt;\" \"&lt;option&gt;\" \"&lt;option&gt;\"...|close]"
+)
+
+var (
+	poll_aliases = []string{}
+)
+
+func (h *PollCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to access a poll with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to access its poll.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		poll, exists := sPlayback.Poll()
+		if !exists {
+			return "your room has no active poll.", nil
+		}
+
+		return formatPollState(poll.State()), nil
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) < 2 {
+			return "", fmt.Errorf("%v", h.usage)
+		}
+
+		poll, err := playback.NewPoll(args[1], args[2:], username)
+		if err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		if err := sPlayback.StartPoll(poll); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+
+		if err := broadcastPollEvent(user, "pollstate", poll.State()); err != nil {
+			return "", err
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has started a poll: %s (use /vote &lt;number&gt; to vote)", username, poll.Question()))
+		return "your poll has been created.", nil
+	case "close":
+		state, err := sPlayback.ClosePoll()
+		if err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+
+		if err := broadcastPollEvent(user, "pollresult", state); err != nil {
+			return "", err
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has closed the poll.<br />%s", username, formatPollState(state)))
+		return "the poll has been closed.", nil
+	}
+
+	return "", fmt.Errorf("%v", h.usage)
+}
+
+// broadcastPollEvent sends the given poll state to every client in the user's room
+func broadcastPollEvent(user *client.Client, event string, state *playback.PollState) error {
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: user.GetUsernameOrId(),
+	}
+
+	if err := sockutil.SerializeIntoResponse(state, &res.Extra); err != nil {
+		return err
+	}
+
+	user.BroadcastAll(event, res)
+	return nil
+}
+
+// formatPollState returns a human-readable summary of a poll's question and tally
+func formatPollState(state *playback.PollState) string {
+	output := fmt.Sprintf("poll: %s", state.Question)
+	for i, option := range state.Options {
+		output += fmt.Sprintf("<br />%v. %s (%v)", i+1, option.Option, option.Votes)
+	}
+	return output
+}
+
+func NewCmdPoll() SocketCommand {
+	return &PollCmd{
+		Command{
+			name:        POLL_NAME,
+			description: POLL_DESCRIPTION,
+			usage:       POLL_USAGE,
+
+			aliases: poll_aliases,
+		},
+	}
+}
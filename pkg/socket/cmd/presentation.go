@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type PresentationCmd struct {
+	Command
+}
+
+const (
+	PRESENTATION_NAME        = "presentation"
+	PRESENTATION_DESCRIPTION = "locks playback and the queue to you, and slows down the chat (on|off)"
+	PRESENTATION_USAGE       = "Usage: /" + PRESENTATION_NAME + " &lt;on|off&gt;"
+)
+
+var (
+	presentation_aliases = []string{"present"}
+)
+
+func (h *PresentationCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return h.usage, nil
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to toggle presentation mode with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to toggle presentation mode.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	var output string
+	switch args[0] {
+	case "on":
+		if sPlayback.PresentationMode() {
+			return "", fmt.Errorf("error: your room is already in presentation mode")
+		}
+
+		err := sPlayback.SetPresentationMode(true, user.UUID(), username)
+		if err != nil {
+			return "", err
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has started presenting - stream and queue controls are locked, and chat slow mode is on", username))
+		output = "presentation mode is on. Use /presentation off to end it."
+	case "off":
+		if !sPlayback.PresentationMode() {
+			return "", fmt.Errorf("error: your room is not in presentation mode")
+		}
+
+		sPlayback.SetPresentationMode(false, user.UUID(), username)
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has ended presentation mode", username))
+		output = "presentation mode is off."
+	default:
+		return h.usage, nil
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.Settings(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("presentationmode", res)
+	return output, nil
+}
+
+func NewCmdPresentation() SocketCommand {
+	return &PresentationCmd{
+		Command{
+			name:        PRESENTATION_NAME,
+			description: PRESENTATION_DESCRIPTION,
+			usage:       PRESENTATION_USAGE,
+
+			aliases: presentation_aliases,
+		},
+	}
+}
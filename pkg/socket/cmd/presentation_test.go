@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestPresentationMode(t *testing.T) {
+	rooms := newTestRooms(t)
+	host, conn := rooms.joinWithConnection("room", "host", rbac.ADMIN_ROLE)
+	admin := rooms.join("room", "admin", rbac.ADMIN_ROLE)
+	user := rooms.join("room", "user", rbac.USER_ROLE)
+
+	ns, _ := rooms.nsHandler.NamespaceByName("room")
+	sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+
+	tests := []struct {
+		name               string
+		user               *client.Client
+		mode               string
+		expectUnauthorized bool
+		expectSettings     playback.RoomSettings
+		expectRestricted   []*client.Client
+		expectUnrestricted []*client.Client
+	}{
+		{
+			name:               "only hosts may start presenting",
+			user:               user,
+			mode:               "on",
+			expectUnauthorized: true,
+			expectUnrestricted: []*client.Client{host, admin},
+		},
+		{
+			name: "playback lock, queue lock and slow mode engage together",
+			user: host,
+			mode: "on",
+			expectSettings: playback.RoomSettings{
+				Presentation:   true,
+				PlaybackLocked: true,
+				QueueLocked:    true,
+				LockedBy:       "host",
+				SlowMode:       int(playback.PresentationSlowModeInterval.Seconds()),
+			},
+			expectRestricted:   []*client.Client{admin, user},
+			expectUnrestricted: []*client.Client{host},
+		},
+		{
+			name:               "every lock is released once presentation mode ends",
+			user:               host,
+			mode:               "off",
+			expectUnrestricted: []*client.Client{host, admin},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conn.mutex.Lock()
+			broadcasts := len(conn.broadcasts)
+			conn.mutex.Unlock()
+
+			_, err := rooms.execute(tc.user, PRESENTATION_NAME, tc.mode)
+			if unauthorized := errors.Is(err, ErrNotAuthorized); unauthorized != tc.expectUnauthorized {
+				t.Fatalf("expected unauthorized: %v, got error %v", tc.expectUnauthorized, err)
+			}
+			if !tc.expectUnauthorized && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			settings := sPlayback.Settings()
+			if settings.Presentation != tc.expectSettings.Presentation ||
+				settings.PlaybackLocked != tc.expectSettings.PlaybackLocked ||
+				settings.QueueLocked != tc.expectSettings.QueueLocked ||
+				settings.LockedBy != tc.expectSettings.LockedBy ||
+				settings.SlowMode != tc.expectSettings.SlowMode {
+				t.Errorf("expected room settings %+v, got %+v", tc.expectSettings, settings)
+			}
+
+			conn.mutex.Lock()
+			sent := conn.broadcasts[broadcasts:]
+			conn.mutex.Unlock()
+			found := false
+			for _, b := range sent {
+				if b.Event != "presentationmode" {
+					continue
+				}
+				found = true
+				if presenting, _ := b.Data.Extra["presentation"].(bool); presenting != tc.expectSettings.Presentation {
+					t.Errorf("expected a %q event with presentation mode %v, got %v", "presentationmode", tc.expectSettings.Presentation, b.Data.Extra)
+				}
+			}
+			if found == tc.expectUnauthorized {
+				t.Errorf("expected a %q event to be broadcast: %v, got %v", "presentationmode", !tc.expectUnauthorized, sent)
+			}
+
+			for _, c := range tc.expectRestricted {
+				for _, action := range []string{"stream/pause", "queue/add/*"} {
+					if decision := Authorize(rooms.authorizer, c, action, rooms.playbackHandler); decision.Allowed {
+						t.Errorf("expected %q to be restricted from %q while presenting, got %q", c.GetUsernameOrId(), action, decision.Reason)
+					}
+				}
+				if err := sPlayback.RecordChatMessage(c.UUID()); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if err := sPlayback.RecordChatMessage(c.UUID()); err == nil {
+					t.Errorf("expected %q to be held to slow mode while presenting", c.GetUsernameOrId())
+				}
+			}
+			for _, c := range tc.expectUnrestricted {
+				for _, action := range []string{"stream/pause", "queue/add/*"} {
+					if decision := Authorize(rooms.authorizer, c, action, rooms.playbackHandler); !decision.Allowed {
+						t.Errorf("expected %q to be allowed %q, got %q", c.GetUsernameOrId(), action, decision.Reason)
+					}
+				}
+				for i := 0; i < 2; i++ {
+					if err := sPlayback.RecordChatMessage(c.UUID()); err != nil {
+						t.Errorf("expected %q not to be held to slow mode, got %v", c.GetUsernameOrId(), err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
 	playbackutil "github.com/juanvallejo/streaming-server/pkg/playback/util"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/util"
 	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
 )
@@ -22,7 +24,7 @@ type QueueCmd struct {
 const (
 	QUEUE_NAME        = "queue"
 	QUEUE_DESCRIPTION = "control the room queue"
-	QUEUE_USAGE       = "Usage: /" + QUEUE_NAME + " (migrate &lt;newQueueKey&gt;|add &lt;url&gt;|clear &lt;room|mine [url]&gt;|list &lt;mine|room&gt;|order &lt;next &lt;url&gt;|mine &lt;url newposition|0,1,2...&gt;|room &lt; url newposition|0,1,2...&gt;&gt;)"
+	QUEUE_USAGE       = "Usage: /" + QUEUE_NAME + " (migrate &lt;newQueueKey&gt;|add &lt;url [start end]&gt;|import &lt;url...&gt;|clear &lt;room|mine [url]&gt;|list &lt;mine|room&gt;|order &lt;next &lt;url&gt;|mine &lt;url newposition|0,1,2...&gt;|room &lt; url newposition|0,1,2...&gt;&gt;)"
 )
 
 var mux sync.Mutex
@@ -57,6 +59,18 @@ func (h *QueueCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 			return "", err
 		}
 
+		trim, hasTrim, err := getStreamTrimFromArgs(args)
+		if err != nil {
+			return "", err
+		}
+
+		if stream.IsPlaylistUrl(url) {
+			if hasTrim {
+				return "", fmt.Errorf("error: playlists cannot be trimmed")
+			}
+			return queuePlaylist(url, user, sPlayback, streamHandler)
+		}
+
 		userQueue, exists, err := playbackutil.GetUserQueue(user, sPlayback.GetQueue())
 		if err != nil {
 			return "", err
@@ -70,8 +84,8 @@ func (h *QueueCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 		}
 
 		// do not create and push stream if user queue is at its storage limit
-		if userQueue.Size() >= queue.MaxAggregatableQueueItems {
-			return "", queue.ErrMaxQueueSizeExceeded
+		if userQueue.Size() >= sPlayback.MaxQueueItems() {
+			return "", fmt.Errorf("error: you cannot store more than %v items in your queue.", sPlayback.MaxQueueItems())
 		}
 
 		sendStreamSync := false
@@ -79,60 +93,70 @@ func (h *QueueCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 			sendStreamSync = true
 		}
 
-		s, err := sPlayback.GetOrCreateStreamFromUrl(url, user, streamHandler, func(user *client.Client, pback *playback.Playback, shouldSync bool) func([]byte, bool, error) {
-			return func(data []byte, created bool, err error) {
-				// if a new stream was created, sync fetched metadata with client
-				if !created {
-					return
-				}
+		// the callback only reports that the stream's metadata is known,
+		// as it may run before the stream is returned, or concurrently
+		metadataFetched := make(chan bool, 1)
+		s, err := sPlayback.GetOrCreateStreamFromUrl(url, user, streamHandler, func(data []byte, created bool, err error) {
+			metadataFetched <- created
+		})
+		if err != nil {
+			user.BroadcastErrorTo(err)
+			return "", err
+		}
 
-				streamIdentifier := url
-				s, ok := streamHandler.GetStream(url)
-				if ok && len(s.GetName()) > 0 {
-					streamIdentifier = s.GetName()
-				}
-				user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has added %q to the queue", username, streamIdentifier))
-				user.BroadcastSystemMessageTo(fmt.Sprintf("successfully queued %q", streamIdentifier))
+		var trimmed *stream.TrimmedStream
+		if hasTrim {
+			trimmed, err = stream.NewTrimmedStream(s, trim[0], trim[1])
+			if err != nil {
+				return "", err
+			}
+			s = trimmed
+		}
 
-				err = sendQueueSyncEvent(user, pback)
-				if err != nil {
-					log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to send queue-sync event to client")
-					return
-				}
-				err = sendUserQueueSyncEvent(user, pback)
-				if err != nil {
-					log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to send user-queue-sync event to client")
-					return
+		// the trimmed segment can only be checked against the stream's
+		// duration once its metadata is known. If it already is, the
+		// segment is checked before the stream is queued. Otherwise,
+		// an invalid segment is removed from the queue once it is known.
+		select {
+		case created := <-metadataFetched:
+			if trimmed != nil {
+				if err := trimmed.Validate(); err != nil {
+					return "", err
 				}
+			}
 
-				if !shouldSync {
-					return
-				}
+			err = sPlayback.PushToQueue(userQueue, s)
+			if err != nil {
+				return "", err
+			}
 
-				log.Printf("INFO SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK calculated queued stream info - sending streamsync\n")
+			if created {
+				announceQueuedStream(url, username, user, sPlayback, streamHandler, sendStreamSync)
+			}
+		default:
+			err = sPlayback.PushToQueue(userQueue, s)
+			if err != nil {
+				return "", err
+			}
 
-				res := &client.Response{
-					Id:   user.UUID(),
-					From: username,
+			go func(user *client.Client, pback *playback.Playback, trimmed *stream.TrimmedStream, shouldSync bool) {
+				created := <-metadataFetched
+				if trimmed != nil {
+					if err := trimmed.Validate(); err != nil {
+						if err := pback.ClearQueueItem(userQueue, trimmed); err != nil {
+							log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to remove invalid trimmed stream from queue: %v", err)
+						}
+						user.BroadcastErrorTo(err)
+						sendQueueSyncEvent(user, pback)
+						sendUserQueueSyncEvent(user, pback)
+						return
+					}
 				}
 
-				err = sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
-				if err != nil {
-					log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to serialize playback into streamsync response: %v\n", err)
-					return
+				if created {
+					announceQueuedStream(url, username, user, pback, streamHandler, shouldSync)
 				}
-
-				user.BroadcastAll("streamsync", res)
-			}
-		}(user, sPlayback, sendStreamSync))
-		if err != nil {
-			user.BroadcastErrorTo(err)
-			return "", err
-		}
-
-		err = sPlayback.PushToQueue(userQueue, s)
-		if err != nil {
-			return "", err
+			}(user, sPlayback, trimmed, sendStreamSync)
 		}
 
 		err = sendQueueSyncEvent(user, sPlayback)
@@ -151,44 +175,23 @@ func (h *QueueCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 			streamQueueMsg = fmt.Sprintf("successfully queued %q", s.GetName())
 		}
 
-		// TODO: turn this code-block into a helper (currently used here, socket/handler.go, and cmd/stream.go)
 		// if room playback state is PLAYBACK_STATE_ENDED, auto-play the next queued item (if found)
-		if sPlayback.State() == playback.PLAYBACK_STATE_ENDED || sPlayback.State() == playback.PLAYBACK_STATE_NOT_STARTED {
-			roomQueue := sPlayback.GetQueue()
-			nextQueueItem, err := roomQueue.Next()
-			if err == nil {
-				nextStream, ok := nextQueueItem.(stream.Stream)
-				if !ok {
-					return fmt.Sprintf("%s - The stream will not auto-play because it does appear to be a stream.Stream (programmer error)", streamQueueMsg), nil
-				}
-
-				sPlayback.SetStream(nextStream)
-				sPlayback.Reset()
-
-				res := &client.Response{
-					Id:   user.UUID(),
-					From: username,
-				}
-
-				err = sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
-				if err != nil {
-					return fmt.Sprintf("%s - The stream will not auto-play due to a serialization error: %v", streamQueueMsg, err), nil
-				}
-
-				user.BroadcastAll("streamload", res)
-
-				// play the newly loaded stream
-				err := sPlayback.Play()
-				if err != nil {
-					return fmt.Sprintf("%s - The stream will not auto-play due to an error: %v", streamQueueMsg, err), nil
-				}
-
-				user.BroadcastAll("streamsync", res)
-				return fmt.Sprintf("%s (auto-playing...)", streamQueueMsg), nil
-			}
+		played, err := playNextIfIdle(user, sPlayback)
+		if err != nil {
+			return fmt.Sprintf("%s - The stream will not auto-play %v", streamQueueMsg, err), nil
+		}
+		if played {
+			return fmt.Sprintf("%s (auto-playing...)", streamQueueMsg), nil
 		}
 
 		return streamQueueMsg, nil
+	case "import":
+		// add a list of streams to the end of the queue
+		if len(args) < 2 {
+			return "", fmt.Errorf("error: at least one stream url must be provided")
+		}
+
+		return importQueue(args[1:], user, sPlayback, streamHandler)
 	case "list":
 		if len(args) < 2 {
 			return "", fmt.Errorf("%v", h.usage)
@@ -567,6 +570,70 @@ func calculateQueueOrder(sourceIdx, destIdx, length int) ([]int, error) {
 	return append(newOrder, sourceIdx), nil
 }
 
+// moveUserQueueItem moves the item with the given id in the user's own
+// stack by the given offset. Moving the first item up or the last item
+// down is a no-op.
+func moveUserQueueItem(user *client.Client, playbackHandler playback.PlaybackHandler, streamId string, offset int) (string, error) {
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to re-order a queue with no room assigned", user.UUID(), user.GetUsernameOrId())
+		return "", fmt.Errorf("error: you must be in a room to re-order your queue.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), user.GetUsernameOrId(), userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	// allow only a single client to perform an "order" operation on the queue
+	mux.Lock()
+	defer mux.Unlock()
+
+	userQueue, exists, err := playbackutil.GetUserQueue(user, sPlayback.GetQueue())
+	if err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("error: unable to re-order an empty queue")
+	}
+
+	sourceIdx, found, err := queueItemIndex(streamId, userQueue.List())
+	if err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+	if !found {
+		return "", fmt.Errorf("error: source item id (%v) was not found in your queue", streamId)
+	}
+
+	destIdx := sourceIdx + offset
+	if destIdx < 0 || destIdx >= userQueue.Size() {
+		return fmt.Sprintf("%v is already at position %v in your queue", streamId, sourceIdx), nil
+	}
+
+	newOrder, err := calculateQueueOrder(sourceIdx, destIdx, userQueue.Size())
+	if err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	err = userQueue.Reorder(newOrder)
+	if err != nil {
+		return "", fmt.Errorf("error: unable to re-order your queue: %v", err)
+	}
+
+	err = sendUserQueueSyncEvent(user, sPlayback)
+	if err != nil {
+		return "", err
+	}
+
+	err = sendQueueSyncEvent(user, sPlayback)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("re-ordering your queue: moving %v to position %v...", streamId, destIdx), nil
+}
+
 func sendQueueSyncEvent(user *client.Client, sPlayback *playback.Playback) error {
 	username, hasUsername := user.GetUsername()
 	if !hasUsername {
@@ -631,3 +698,279 @@ func queueItemIndex(id string, items []queue.QueueItem) (int, bool, error) {
 
 	return -1, false, nil
 }
+
+// getStreamTrimFromArgs receives a list of cmd args and returns the start and end
+// of a trimmed stream segment, or a boolean (false) if no segment was given.
+// Times may be given in seconds, or in human-readable form (0h0m0s).
+func getStreamTrimFromArgs(args []string) ([]int, bool, error) {
+	if len(args) < 3 {
+		return nil, false, nil
+	}
+	if len(args) < 4 {
+		return nil, false, fmt.Errorf("error: both a start and an end time must be provided to trim a stream")
+	}
+
+	trim := []int{}
+	for _, rawTime := range args[2:4] {
+		t, err := strconv.Atoi(rawTime)
+		if err != nil {
+			t, err = util.HumanTimeToSeconds(rawTime)
+			if err != nil {
+				return nil, false, fmt.Errorf("error: cannot interpret %q as a valid time. Must be of the form 12345 or 0h0m0s", rawTime)
+			}
+		}
+
+		trim = append(trim, t)
+	}
+
+	return trim, true, nil
+}
+
+// announceQueuedStream notifies the given user's room that the stream with
+// the given url has been queued, once its metadata has been fetched, and
+// syncs the room's queue. The room's playback status is also sent if the
+// room was idle when the stream was queued.
+func announceQueuedStream(url, username string, user *client.Client, pback *playback.Playback, streamHandler stream.StreamHandler, shouldSync bool) {
+	streamIdentifier := url
+	s, ok := streamHandler.GetStream(url)
+	if ok && len(s.GetName()) > 0 {
+		streamIdentifier = s.GetName()
+	}
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has added %q to the queue", username, streamIdentifier))
+	user.BroadcastSystemMessageTo(fmt.Sprintf("successfully queued %q", streamIdentifier))
+
+	err := sendQueueSyncEvent(user, pback)
+	if err != nil {
+		log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to send queue-sync event to client")
+		return
+	}
+	err = sendUserQueueSyncEvent(user, pback)
+	if err != nil {
+		log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to send user-queue-sync event to client")
+		return
+	}
+
+	if !shouldSync {
+		return
+	}
+
+	log.Printf("INFO SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK calculated queued stream info - sending streamsync\n")
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err = sockutil.SerializeIntoResponse(pback.GetStatus(), &res.Extra)
+	if err != nil {
+		log.Printf("ERR SOCKET CLIENT PLAYBACK-FETCHMETADATA-CALLBACK unable to serialize playback into streamsync response: %v\n", err)
+		return
+	}
+
+	user.BroadcastAll("streamsync", res)
+}
+
+// queuePlaylist expands the playlist at the given url and pushes each of
+// its streams, in order, onto the given user's queue. Expansion is capped
+// at stream.MaxPlaylistExpansion items, or the space left in the user's queue.
+func queuePlaylist(url string, user *client.Client, sPlayback *playback.Playback, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userQueue, exists, err := playbackutil.GetUserQueue(user, sPlayback.GetQueue())
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		userQueue = queue.NewAggregatableQueue(user.UUID())
+		err := sPlayback.GetQueue().Push(userQueue)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	limit := sPlayback.MaxQueueItems() - userQueue.Size()
+	if limit <= 0 {
+		return "", fmt.Errorf("error: you cannot store more than %v items in your queue.", sPlayback.MaxQueueItems())
+	}
+	if limit > stream.MaxPlaylistExpansion {
+		limit = stream.MaxPlaylistExpansion
+	}
+
+	urls, total, err := streamHandler.ExpandPlaylist(url, limit)
+	if err != nil {
+		return "", err
+	}
+
+	added, failed := pushStreamsToQueue(urls, user, userQueue, sPlayback, streamHandler)
+	for _, err := range failed {
+		log.Printf("INF SOCKET CLIENT skipping playlist item for client %q: %v", username, err)
+	}
+
+	err = sendQueueSyncEvent(user, sPlayback)
+	if err != nil {
+		return "", err
+	}
+	err = sendUserQueueSyncEvent(user, sPlayback)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has added %d items from a playlist to the queue", username, added))
+
+	output := fmt.Sprintf("added %d of %d playlist items to your queue", added, total)
+	if added < total {
+		output += fmt.Sprintf(" (playlists are limited to %d items, and your queue to %d)", stream.MaxPlaylistExpansion, sPlayback.MaxQueueItems())
+	}
+
+	played, err := playNextIfIdle(user, sPlayback)
+	if err != nil {
+		return fmt.Sprintf("%s - The stream will not auto-play %v", output, err), nil
+	}
+	if played {
+		return fmt.Sprintf("%s (auto-playing...)", output), nil
+	}
+
+	return output, nil
+}
+
+// importQueue pushes the streams at each of the given urls, in order, onto
+// the given user's queue. Urls that cannot be resolved to a stream, or that
+// do not fit in the user's queue, are skipped and reported in the output.
+func importQueue(urls []string, user *client.Client, sPlayback *playback.Playback, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userQueue, exists, err := playbackutil.GetUserQueue(user, sPlayback.GetQueue())
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		userQueue = queue.NewAggregatableQueue(user.UUID())
+		err := sPlayback.GetQueue().Push(userQueue)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	limit := sPlayback.MaxQueueItems() - userQueue.Size()
+	if limit <= 0 {
+		return "", fmt.Errorf("error: you cannot store more than %v items in your queue.", sPlayback.MaxQueueItems())
+	}
+
+	failed := []error{}
+	if len(urls) > limit {
+		for _, streamUrl := range urls[limit:] {
+			failed = append(failed, fmt.Errorf("%q: your queue cannot store more than %v items", streamUrl, sPlayback.MaxQueueItems()))
+		}
+		urls = urls[:limit]
+	}
+
+	added, pushFailed := pushStreamsToQueue(urls, user, userQueue, sPlayback, streamHandler)
+	failed = append(pushFailed, failed...)
+
+	err = sendQueueSyncEvent(user, sPlayback)
+	if err != nil {
+		return "", err
+	}
+	err = sendUserQueueSyncEvent(user, sPlayback)
+	if err != nil {
+		return "", err
+	}
+
+	if added > 0 {
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has imported %d items to the queue", username, added))
+	}
+
+	output := fmt.Sprintf("imported %d of %d items to your queue", added, added+len(failed))
+	if len(failed) > 0 {
+		reasons := make([]string, 0, len(failed))
+		for _, err := range failed {
+			reasons = append(reasons, err.Error())
+		}
+		output += fmt.Sprintf("; %d failed: %s", len(failed), strings.Join(reasons, "; "))
+	}
+
+	played, err := playNextIfIdle(user, sPlayback)
+	if err != nil {
+		return fmt.Sprintf("%s - The stream will not auto-play %v", output, err), nil
+	}
+	if played {
+		return fmt.Sprintf("%s (auto-playing...)", output), nil
+	}
+
+	return output, nil
+}
+
+// pushStreamsToQueue resolves each of the given urls to a stream and pushes
+// it onto the given user queue. Returns the number of streams queued, and an
+// error describing each url that could not be queued.
+func pushStreamsToQueue(urls []string, user *client.Client, userQueue queue.AggregatableQueue, sPlayback *playback.Playback, streamHandler stream.StreamHandler) (int, []error) {
+	added := 0
+	failed := []error{}
+	for _, streamUrl := range urls {
+		s, err := sPlayback.GetOrCreateStreamFromUrl(streamUrl, user, streamHandler, func(data []byte, created bool, err error) {
+			if !created {
+				return
+			}
+
+			// sync fetched metadata with clients
+			sendQueueSyncEvent(user, sPlayback)
+			sendUserQueueSyncEvent(user, sPlayback)
+		})
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%q: %v", streamUrl, err))
+			continue
+		}
+
+		if err := sPlayback.PushToQueue(userQueue, s); err != nil {
+			failed = append(failed, fmt.Errorf("%q: %v", streamUrl, err))
+			continue
+		}
+		added++
+	}
+
+	return added, failed
+}
+
+// playNextIfIdle loads and plays the next item in the queue if the
+// room's playback has not yet started, or has ended.
+// Returns a boolean (true) if a stream was loaded and played.
+func playNextIfIdle(user *client.Client, sPlayback *playback.Playback) (bool, error) {
+	if sPlayback.State() != playback.PLAYBACK_STATE_ENDED && sPlayback.State() != playback.PLAYBACK_STATE_NOT_STARTED {
+		return false, nil
+	}
+
+	nextQueueItem, err := sPlayback.NextQueueItem()
+	if err != nil {
+		return false, nil
+	}
+
+	nextStream, ok := nextQueueItem.(stream.Stream)
+	if !ok {
+		return false, fmt.Errorf("because it does appear to be a stream.Stream (programmer error)")
+	}
+
+	sPlayback.SetStream(nextStream)
+	sPlayback.Reset()
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: user.GetUsernameOrId(),
+	}
+
+	err = sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		return false, fmt.Errorf("due to a serialization error: %v", err)
+	}
+
+	user.BroadcastAll("streamload", res)
+
+	// play the newly loaded stream, once the room's countdown (if any) ends
+	err = sPlayback.StartPlayback()
+	if err != nil {
+		return false, fmt.Errorf("due to an error: %v", err)
+	}
+
+	user.BroadcastAll("streamsync", res)
+	announceStream(user, sPlayback)
+	return true, nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestQueueTrimmedStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		start        string
+		end          string
+		fetchedFirst bool
+		holdFetch    bool
+		expectErr    bool
+		expectQueue  int
+		expectStart  int
+	}{
+		{
+			name:        "stream plays from the start of the segment",
+			url:         "http://example.com/new.mp4",
+			start:       "30",
+			end:         "60",
+			expectStart: 30,
+		},
+		{
+			name:         "segment of a stream that was already fetched",
+			url:          "http://example.com/fetched.mp4",
+			start:        "1m",
+			end:          "2m",
+			fetchedFirst: true,
+			expectStart:  60,
+		},
+		{
+			name:      "segment ending after a new stream",
+			url:       "http://example.com/new.mp4",
+			start:     "30",
+			end:       "700",
+			expectErr: true,
+		},
+		{
+			name:         "segment ending after a stream that was already fetched",
+			url:          "http://example.com/fetched.mp4",
+			start:        "30",
+			end:          "700",
+			fetchedFirst: true,
+			expectErr:    true,
+		},
+		{
+			name:      "segment ending before it starts",
+			url:       "http://example.com/new.mp4",
+			start:     "60",
+			end:       "30",
+			expectErr: true,
+		},
+		{
+			name:        "segment ending after a stream whose metadata is fetched once it is queued",
+			url:         "http://example.com/new.mp4",
+			start:       "30",
+			end:         "700",
+			holdFetch:   true,
+			expectQueue: 0,
+		},
+		{
+			name:        "valid segment of a stream whose metadata is fetched once it is queued",
+			url:         "http://example.com/new.mp4",
+			start:       "30",
+			end:         "60",
+			holdFetch:   true,
+			expectQueue: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rooms := newTestRooms(t)
+			rooms.streamHandler.stub("http://example.com/new.mp4", 600)
+			rooms.streamHandler.stub("http://example.com/fetched.mp4", 600)
+			rooms.streamHandler.stub("http://example.com/playing.mp4", 600)
+			user, conn := rooms.joinWithConnection("room", "user", rbac.USER_ROLE)
+
+			ns, _ := rooms.nsHandler.NamespaceByName("room")
+			sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+
+			if tc.fetchedFirst {
+				s, err := rooms.streamHandler.NewStream(tc.url)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if err := s.SetInfo([]byte(`{"duration": 600}`)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			// streams queued while another one is playing stay in the queue
+			if tc.holdFetch {
+				if _, err := rooms.execute(user, QUEUE_NAME, "add", "http://example.com/playing.mp4"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				rooms.streamHandler.hold()
+			}
+
+			_, err := rooms.execute(user, QUEUE_NAME, "add", tc.url, tc.start, tc.end)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if tc.expectErr {
+				if size := len(sPlayback.GetQueue().PeekItems()); size != 0 {
+					t.Errorf("expected an invalid segment not to be queued, got %v queued streams", size)
+				}
+				return
+			}
+
+			if tc.holdFetch {
+				if size := len(sPlayback.GetQueue().PeekItems()); size != 1 {
+					t.Fatalf("expected the segment to be queued before its metadata is fetched, got %v queued streams", size)
+				}
+
+				// the segment is checked once the fetch completes, after
+				// which the client is told the stream was queued, or why not
+				rooms.streamHandler.release()
+				evt, match := "chatmessage", func(res client.Response) bool {
+					return strings.Contains(res.Message, "successfully queued "+strconv.Quote(tc.url))
+				}
+				if tc.expectQueue == 0 {
+					evt, match = "info_clienterror", func(client.Response) bool { return true }
+				}
+				if !conn.waitForSent(evt, match) {
+					t.Fatalf("expected the client to be sent a %q event once the segment was checked", evt)
+				}
+				if size := len(sPlayback.GetQueue().PeekItems()); size != tc.expectQueue {
+					t.Errorf("expected %v queued streams once the metadata is fetched, got %v", tc.expectQueue, size)
+				}
+				return
+			}
+
+			s, exists := sPlayback.GetStream()
+			if !exists {
+				t.Fatalf("expected the queued segment to be played")
+			}
+			trimmed, ok := s.(*stream.TrimmedStream)
+			if !ok {
+				t.Fatalf("expected a trimmed stream to be played, got %T", s)
+			}
+			if trimmed.GetStreamURL() != tc.url {
+				t.Errorf("expected stream %q to be played, got %q", tc.url, trimmed.GetStreamURL())
+			}
+			if time := sPlayback.GetTime(); time != tc.expectStart {
+				t.Errorf("expected playback to start at %v, got %v", tc.expectStart, time)
+			}
+			if endTime, _ := sPlayback.EndTime(); int(endTime) != trimmed.GetEnd() {
+				t.Errorf("expected playback to end at %v, got %v", trimmed.GetEnd(), endTime)
+			}
+			if sPlayback.State() != playback.PLAYBACK_STATE_STARTED {
+				t.Errorf("expected playback to be started, got state %v", sPlayback.State())
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package rbac
 
-import "strings"
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ROOM_ROLE_SEPARATOR separates the name of the room a role
+// was created for from the role's own name
+const ROOM_ROLE_SEPARATOR = ":"
 
 // Authorizer authorizes a Subject to perform an action based
 // on Rules defined by Roles bound to that Subject
@@ -17,6 +25,8 @@ type Authorizer interface {
 	// Role returns a composed Role by a given name.
 	// Returns a boolean (false) if the role does not exist.
 	Role(string) (Role, bool)
+	// Roles returns the Roles known to the Authorizer, sorted by name.
+	Roles() []Role
 	// Verify verifies that a given subject has access to the
 	// resources defined by the given Rule.
 	// Returns a boolean (true) if the Rule given is contained
@@ -28,11 +38,17 @@ type Authorizer interface {
 // convenience methods for managing and restricting
 // command access based on a given role.
 type AuthorizerSpec struct {
+	// guards roles and bindings, which may be
+	// created while clients are being authorized
+	mutex                 sync.RWMutex
 	rolesByName           map[string]Role
 	roleBindingByRoleName map[string]RoleBinding
 }
 
 func (a *AuthorizerSpec) AddRole(r Role) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	if _, exists := a.rolesByName[r.Name()]; !exists {
 		a.rolesByName[r.Name()] = r
 		return true
@@ -42,6 +58,9 @@ func (a *AuthorizerSpec) AddRole(r Role) bool {
 }
 
 func (a *AuthorizerSpec) Bind(r Role, subjects ...Subject) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	binding, exists := a.roleBindingByRoleName[r.Name()]
 	if !exists {
 		binding = NewRoleBinding(r, subjects)
@@ -55,6 +74,9 @@ func (a *AuthorizerSpec) Bind(r Role, subjects ...Subject) bool {
 }
 
 func (a *AuthorizerSpec) Bindings() []RoleBinding {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
 	bindings := []RoleBinding{}
 
 	for _, b := range a.roleBindingByRoleName {
@@ -65,6 +87,9 @@ func (a *AuthorizerSpec) Bindings() []RoleBinding {
 }
 
 func (a *AuthorizerSpec) Role(name string) (Role, bool) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
 	if role, exists := a.rolesByName[name]; exists {
 		return role, true
 	}
@@ -72,29 +97,26 @@ func (a *AuthorizerSpec) Role(name string) (Role, bool) {
 	return nil, false
 }
 
-func (a *AuthorizerSpec) Verify(s Subject, r Rule) bool {
-	subjectRoles := []Role{}
-
-	// calculate which roles the subject is bound to
-	for _, binding := range a.roleBindingByRoleName {
-		found := false
-		for _, subject := range binding.Subjects() {
-			if subject.UUID() == s.UUID() {
-				found = true
-				break
-			}
-		}
-
-		// given subject is bound to the role in the current binding
-		if found {
-			subjectRoles = append(subjectRoles, binding.Role())
-		}
+func (a *AuthorizerSpec) Roles() []Role {
+	a.mutex.RLock()
+	roles := []Role{}
+	for _, role := range a.rolesByName {
+		roles = append(roles, role)
 	}
+	a.mutex.RUnlock()
 
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name() < roles[j].Name()
+	})
+	return roles
+}
+
+func (a *AuthorizerSpec) Verify(s Subject, r Rule) bool {
 	// iterate through the roles the given subject has been bound to
-	// and calculate if at least one role contains the given rule.
-	for _, role := range subjectRoles {
-		for _, rule := range role.Rules() {
+	// and calculate if at least one role, or one of the roles it
+	// inherits from, contains the given rule.
+	for _, role := range SubjectRoles(a, s) {
+		for _, rule := range InheritedRules(role) {
 			if r.Name() == rule.Name() {
 				return true
 			}
@@ -111,11 +133,63 @@ func NewAuthorizer() Authorizer {
 	}
 }
 
+// RoomRoleName returns the name under which a role created for
+// the room with the given name is known to an Authorizer, so
+// that rooms may not see or bind each other's roles.
+func RoomRoleName(room, role string) string {
+	return room + ROOM_ROLE_SEPARATOR + role
+}
+
+// RoleDisplayName returns the given role name without
+// the name of the room the role was created for, if any
+func RoleDisplayName(name string) string {
+	if idx := strings.LastIndex(name, ROOM_ROLE_SEPARATOR); idx >= 0 {
+		return name[idx+len(ROOM_ROLE_SEPARATOR):]
+	}
+	return name
+}
+
+// RoleInRoom returns the role with the given name created for the
+// room with the given name, or the server-wide role with that name
+// if the room has none. Returns a boolean (false) if neither exists.
+func RoleInRoom(authorizer Authorizer, room, name string) (Role, bool) {
+	if role, exists := authorizer.Role(RoomRoleName(room, name)); exists {
+		return role, true
+	}
+	return authorizer.Role(name)
+}
+
+// SubjectRoles returns the roles the given subject
+// is bound to by the given Authorizer's bindings
+func SubjectRoles(authorizer Authorizer, s Subject) []Role {
+	roles := []Role{}
+	for _, binding := range authorizer.Bindings() {
+		for _, subject := range binding.Subjects() {
+			if subject.UUID() == s.UUID() {
+				roles = append(roles, binding.Role())
+				break
+			}
+		}
+	}
+	return roles
+}
+
 // RuleByAction receives an action and returns the rule
 // corresponding to that action, or false if no rule is found.
 func RuleByAction(bindings []RoleBinding, action string) (Rule, bool) {
+	roles := []Role{}
 	for _, binding := range bindings {
-		for _, rule := range binding.Role().Rules() {
+		roles = append(roles, binding.Role())
+	}
+	return RuleByActionInRoles(roles, action)
+}
+
+// RuleByActionInRoles receives a set of roles and an action and returns
+// the first rule composed by those roles that defines the action, or
+// false if no rule is found.
+func RuleByActionInRoles(roles []Role, action string) (Rule, bool) {
+	for _, role := range roles {
+		for _, rule := range InheritedRules(role) {
 			for _, a := range rule.Actions() {
 				if verifyAction(a, action) {
 					return rule, true
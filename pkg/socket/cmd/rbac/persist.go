@@ -0,0 +1,119 @@
+package rbac
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// BindingStore remembers the names of the roles bound to stable user
+// identities in each room, so that the same roles may be bound again
+// once a user rejoins with that identity, including after a restart.
+// Every change is written through to the store's file.
+type BindingStore struct {
+	mutex sync.Mutex
+	path  string
+
+	// role names keyed by room, then by identity
+	rooms map[string]map[string][]string
+}
+
+// Load replaces the store's bindings with those saved to its file.
+// A missing file is treated as an empty set of bindings.
+func (s *BindingStore) Load() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	rooms := make(map[string]map[string][]string)
+	if err := json.Unmarshal(data, &rooms); err != nil {
+		return err
+	}
+
+	s.rooms = rooms
+	log.Printf("INF RBAC loaded saved role bindings for %v rooms\n", len(s.rooms))
+	return nil
+}
+
+// Remember stores the given role names for the given identity in the
+// given room, replacing any previously stored. An empty set of roles
+// forgets the identity.
+func (s *BindingStore) Remember(room, identity string, roles []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	identities, exists := s.rooms[room]
+	if !exists {
+		if len(roles) == 0 {
+			return nil
+		}
+
+		identities = make(map[string][]string)
+		s.rooms[room] = identities
+	}
+
+	if len(roles) == 0 {
+		delete(identities, identity)
+		if len(identities) == 0 {
+			delete(s.rooms, room)
+		}
+	} else {
+		identities[identity] = roles
+	}
+
+	return s.save()
+}
+
+// Roles returns the role names stored for the given identity in the
+// given room, or a boolean (false) if none have been stored.
+func (s *BindingStore) Roles(room, identity string) ([]string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	roles, exists := s.rooms[room][identity]
+	return roles, exists
+}
+
+// save writes the store's bindings to its file, replacing it only once
+// the new contents have been written in full. Callers must hold the mutex.
+func (s *BindingStore) save() error {
+	data, err := json.Marshal(s.rooms)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// NewBindingStore returns an empty BindingStore saving to the file
+// at the given path. Call Load to read any previously saved bindings.
+func NewBindingStore(path string) *BindingStore {
+	return &BindingStore{
+		path:  path,
+		rooms: make(map[string]map[string][]string),
+	}
+}
@@ -1,5 +1,7 @@
 package rbac
 
+import "fmt"
+
 // Role is an object that
 type Role interface {
 	// AddRule composes a new Rule in the Role.
@@ -7,13 +9,22 @@ type Role interface {
 	AddRule(Rule) bool
 	// Name returns the name assigned to the Role
 	Name() string
-	// Rules returns the set of rules composed by the Role
+	// Parent returns the Role whose rules are inherited by the Role,
+	// or a boolean (false) if the Role does not inherit from another.
+	Parent() (Role, bool)
+	// Rules returns the set of rules composed by the Role,
+	// not including any inherited rules.
 	Rules() []Rule
+	// SetParent sets the Role whose rules are inherited by the Role.
+	// A nil Role removes the Role's parent.
+	// Returns an error if the Role would end up inheriting from itself.
+	SetParent(Role) error
 }
 
 type RoleSpec struct {
-	name  string
-	rules []Rule
+	name   string
+	parent Role
+	rules  []Rule
 }
 
 func (s *RoleSpec) AddRule(r Rule) bool {
@@ -31,10 +42,25 @@ func (s *RoleSpec) Name() string {
 	return s.name
 }
 
+func (s *RoleSpec) Parent() (Role, bool) {
+	return s.parent, s.parent != nil
+}
+
 func (s *RoleSpec) Rules() []Rule {
 	return s.rules
 }
 
+func (s *RoleSpec) SetParent(parent Role) error {
+	for r, exists := parent, parent != nil; exists; r, exists = r.Parent() {
+		if r.Name() == s.name {
+			return fmt.Errorf("role %q cannot inherit from %q: circular inheritance", s.name, parent.Name())
+		}
+	}
+
+	s.parent = parent
+	return nil
+}
+
 func NewRole(name string, rules []Rule) Role {
 	return &RoleSpec{
 		name:  name,
@@ -42,6 +68,26 @@ func NewRole(name string, rules []Rule) Role {
 	}
 }
 
+// NewRoleWithParent returns a Role composing the given rules
+// that also inherits all rules from the given parent Role.
+func NewRoleWithParent(name string, parent Role, rules []Rule) Role {
+	return &RoleSpec{
+		name:   name,
+		parent: parent,
+		rules:  rules,
+	}
+}
+
+// InheritedRules returns the rules composed by the given Role,
+// followed by those composed by each of its ancestors.
+func InheritedRules(r Role) []Rule {
+	rules := []Rule{}
+	for role, exists := r, r != nil; exists; role, exists = role.Parent() {
+		rules = append(rules, role.Rules()...)
+	}
+	return rules
+}
+
 type ClearRole struct {
 	Role
 }
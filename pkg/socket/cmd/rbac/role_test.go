@@ -0,0 +1,155 @@
+package rbac
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeSubject is a subject identified by its name
+type fakeSubject string
+
+func (s fakeSubject) UUID() string {
+	return string(s)
+}
+
+func TestRoleInheritance(t *testing.T) {
+	kick := NewRule("kick users", []string{"kick"})
+	skip := NewRule("skip the stream", []string{"stream/skip"})
+	chat := NewRule("send chat messages", []string{"chat"})
+
+	user := NewRole("user", []Rule{chat})
+	moderator := NewRoleWithParent("moderator", user, []Rule{skip})
+	admin := NewRoleWithParent("admin", moderator, []Rule{kick})
+	viewer := NewRole("viewer", []Rule{})
+
+	authorizer := NewAuthorizer()
+	subjects := map[string]Subject{}
+	for _, role := range []Role{user, moderator, admin, viewer} {
+		authorizer.AddRole(role)
+		subjects[role.Name()] = fakeSubject(role.Name())
+		authorizer.Bind(role, subjects[role.Name()])
+	}
+
+	tests := []struct {
+		name        string
+		subject     string
+		rule        Rule
+		expectAllow bool
+	}{
+		{
+			name:        "rule composed by the bound role",
+			subject:     "admin",
+			rule:        kick,
+			expectAllow: true,
+		},
+		{
+			name:        "rule inherited from the parent role",
+			subject:     "admin",
+			rule:        skip,
+			expectAllow: true,
+		},
+		{
+			name:        "rule inherited from an ancestor role",
+			subject:     "admin",
+			rule:        chat,
+			expectAllow: true,
+		},
+		{
+			name:        "rules are not inherited by parent roles",
+			subject:     "moderator",
+			rule:        kick,
+			expectAllow: false,
+		},
+		{
+			name:        "role without a parent",
+			subject:     "viewer",
+			rule:        chat,
+			expectAllow: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if allowed := authorizer.Verify(subjects[tc.subject], tc.rule); allowed != tc.expectAllow {
+				t.Errorf("expected %q to be allowed %q: %v, got %v", tc.subject, tc.rule.Name(), tc.expectAllow, allowed)
+			}
+		})
+	}
+
+	t.Run("inherited actions are found by rule lookups", func(t *testing.T) {
+		rule, exists := RuleByActionInRoles([]Role{admin}, "stream/skip")
+		if !exists || rule.Name() != skip.Name() {
+			t.Errorf("expected action %q to be defined by rule %q, got %v", "stream/skip", skip.Name(), rule)
+		}
+	})
+}
+
+func TestRoleSetParent(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func() (Role, Role)
+		expectErr bool
+	}{
+		{
+			name: "parent without ancestors",
+			setup: func() (Role, Role) {
+				return NewRole("admin", nil), NewRole("moderator", nil)
+			},
+		},
+		{
+			name: "removing the parent",
+			setup: func() (Role, Role) {
+				return NewRoleWithParent("admin", NewRole("moderator", nil), nil), nil
+			},
+		},
+		{
+			name: "role inheriting from itself",
+			setup: func() (Role, Role) {
+				admin := NewRole("admin", nil)
+				return admin, admin
+			},
+			expectErr: true,
+		},
+		{
+			name: "role inheriting from its child",
+			setup: func() (Role, Role) {
+				admin := NewRole("admin", nil)
+				return admin, NewRoleWithParent("moderator", admin, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "role inheriting from its descendant",
+			setup: func() (Role, Role) {
+				admin := NewRole("admin", nil)
+				moderator := NewRoleWithParent("moderator", admin, nil)
+				return admin, NewRoleWithParent("user", moderator, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			role, parent := tc.setup()
+			previous, _ := role.Parent()
+
+			err := role.SetParent(parent)
+			if tc.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "circular inheritance") {
+					t.Fatalf("expected a circular inheritance error, got %v", err)
+				}
+				if current, _ := role.Parent(); current != previous {
+					t.Errorf("expected the role's parent to be left as %v, got %v", previous, current)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if current, _ := role.Parent(); current != parent {
+				t.Errorf("expected the role's parent to be %v, got %v", parent, current)
+			}
+		})
+	}
+}
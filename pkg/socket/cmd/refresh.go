@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type RefreshCmd struct {
+	Command
+}
+
+const (
+	REFRESH_NAME        = "refresh"
+	REFRESH_DESCRIPTION = "fetches metadata, such as duration, again for the current stream or a queued item"
+	REFRESH_USAGE       = "Usage: /" + REFRESH_NAME + " [&lt;id&gt;]"
+)
+
+var (
+	refresh_aliases = []string{}
+)
+
+func (h *RefreshCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to refresh stream metadata with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to refresh stream metadata.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	// refresh the current stream unless a queued item is given
+	if len(args) == 0 {
+		s, exists := sPlayback.GetStream()
+		if !exists {
+			return "", fmt.Errorf("error: no stream is currently loaded")
+		}
+
+		sPlayback.RefreshStreamMetadata(s, streamHandler, func(data []byte, created bool, err error) {
+			if err != nil {
+				user.BroadcastErrorTo(fmt.Errorf("error: unable to refresh stream metadata: %v", err))
+				return
+			}
+
+			res := &client.Response{
+				Id:   user.UUID(),
+				From: username,
+			}
+
+			if err := sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra); err != nil {
+				log.Printf("ERR SOCKET CLIENT unable to serialize playback status after refreshing stream metadata: %v", err)
+				return
+			}
+
+			user.BroadcastAll("streamsync", res)
+		})
+
+		return fmt.Sprintf("refreshing metadata for the current stream %q...", s.GetStreamURL()), nil
+	}
+
+	itemId := args[0]
+	_, item, exists := sPlayback.QueueItemById(itemId)
+	if !exists {
+		return "", fmt.Errorf("error: no item with id %q was found in the queue", itemId)
+	}
+
+	s, ok := item.(stream.Stream)
+	if !ok {
+		return "", fmt.Errorf("error: queue item with id %q is not a stream", itemId)
+	}
+
+	sPlayback.RefreshStreamMetadata(s, streamHandler, func(data []byte, created bool, err error) {
+		if err != nil {
+			user.BroadcastErrorTo(fmt.Errorf("error: unable to refresh stream metadata: %v", err))
+			return
+		}
+
+		if err := sendQueueSyncEvent(user, sPlayback); err != nil {
+			log.Printf("ERR SOCKET CLIENT unable to send queuesync event after refreshing stream metadata: %v", err)
+		}
+	})
+
+	return fmt.Sprintf("refreshing metadata for queued item %q...", s.GetStreamURL()), nil
+}
+
+func NewCmdRefresh() SocketCommand {
+	return &RefreshCmd{
+		Command{
+			name:        REFRESH_NAME,
+			description: REFRESH_DESCRIPTION,
+			usage:       REFRESH_USAGE,
+
+			aliases: refresh_aliases,
+
+			// each refresh re-requests metadata from the stream's provider
+			cooldown: 30 * time.Second,
+		},
+	}
+}
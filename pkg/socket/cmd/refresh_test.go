@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestRefresh(t *testing.T) {
+	rooms := newTestRooms(t)
+	admin, conn := rooms.joinWithConnection("room", "admin", rbac.ADMIN_ROLE)
+
+	// no metadata is stubbed for the stream yet, so its first fetch fails
+	url := "http://example.com/a.mp4"
+	if _, err := rooms.execute(admin, QUEUE_NAME, "add", url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ns, _ := rooms.nsHandler.NamespaceByName("room")
+	sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+	s, exists := sPlayback.GetStream()
+	if !exists || s.GetStreamURL() != url {
+		t.Fatalf("expected %q to be playing, got %v", url, s)
+	}
+	if status := s.Metadata().GetFetchStatus(); status != stream.STREAM_FETCH_STATUS_FAILED {
+		t.Fatalf("expected the first metadata fetch to fail, got status %q", status)
+	}
+
+	rooms.streamHandler.stub(url, 300)
+
+	tests := []struct {
+		name           string
+		expectErr      string
+		expectDuration float64
+	}{
+		{
+			name:           "refresh fetches the metadata again",
+			expectDuration: 300,
+		},
+		{
+			name:           "refresh is rate-limited",
+			expectErr:      "you must wait",
+			expectDuration: 300,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			broadcasts := len(conn.broadcasts)
+
+			_, err := rooms.execute(admin, REFRESH_NAME)
+			if len(tc.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectErr, err)
+				}
+				if len(conn.broadcasts) != broadcasts {
+					t.Errorf("expected nothing to be broadcast, got %v", conn.broadcasts[broadcasts:])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			status := sPlayback.GetStatus().(*playback.PlaybackStatus)
+			if duration := status.Stream.(stream.Stream).GetDuration(); duration != tc.expectDuration {
+				t.Errorf("expected status duration %v, got %v", tc.expectDuration, duration)
+			}
+
+			if len(conn.broadcasts) != broadcasts+1 || conn.broadcasts[broadcasts].Event != "streamsync" {
+				t.Fatalf("expected a single streamsync to be broadcast, got %v", conn.broadcasts[broadcasts:])
+			}
+			synced, _ := conn.broadcasts[broadcasts].Data.Extra["stream"].(map[string]interface{})
+			if duration, _ := synced["duration"].(float64); duration != tc.expectDuration {
+				t.Errorf("expected streamsync duration %v, got %v", tc.expectDuration, synced["duration"])
+			}
+		})
+	}
+}
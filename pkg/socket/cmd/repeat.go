@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type RepeatCmd struct {
+	Command
+}
+
+const (
+	REPEAT_NAME        = "repeat"
+	REPEAT_DESCRIPTION = "sets what happens once the current stream ends (off|one|all)"
+	REPEAT_USAGE       = "Usage: /" + REPEAT_NAME + " &lt;off|one|all&gt;"
+)
+
+var (
+	repeat_aliases = []string{"loop"}
+)
+
+func (h *RepeatCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to set the repeat mode with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to set its repeat mode.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		return fmt.Sprintf("the room's repeat mode is %q. %s", sPlayback.RepeatMode(), h.usage), nil
+	}
+
+	if err := sPlayback.SetRepeatMode(playback.RepeatMode(args[0])); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("streamsync", res)
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has set the room's repeat mode to %q", username, args[0]))
+	return fmt.Sprintf("the room's repeat mode is now %q", args[0]), nil
+}
+
+func NewCmdRepeat() SocketCommand {
+	return &RepeatCmd{
+		Command{
+			name:        REPEAT_NAME,
+			description: REPEAT_DESCRIPTION,
+			usage:       REPEAT_USAGE,
+
+			aliases: repeat_aliases,
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestRepeat(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		queued       []string
+		expectErr    bool
+		expectMode   playback.RepeatMode
+		expectLoaded bool
+		expectStream string
+		expectQueue  []string
+	}{
+		{
+			name:         "repeat off advances the queue",
+			mode:         "off",
+			queued:       []string{"http://example.com/b.mp4"},
+			expectMode:   playback.REPEAT_OFF,
+			expectLoaded: true,
+			expectStream: "http://example.com/b.mp4",
+			expectQueue:  []string{},
+		},
+		{
+			name:         "repeat one replays the current stream",
+			mode:         "one",
+			queued:       []string{"http://example.com/b.mp4"},
+			expectMode:   playback.REPEAT_ONE,
+			expectStream: "http://example.com/a.mp4",
+			expectQueue:  []string{"http://example.com/b.mp4"},
+		},
+		{
+			name:         "repeat all pushes the current stream to the back of the queue",
+			mode:         "all",
+			queued:       []string{"http://example.com/b.mp4"},
+			expectMode:   playback.REPEAT_ALL,
+			expectLoaded: true,
+			expectStream: "http://example.com/b.mp4",
+			expectQueue:  []string{"http://example.com/a.mp4"},
+		},
+		{
+			name:         "repeat all replays the current stream with nothing else queued",
+			mode:         "all",
+			expectMode:   playback.REPEAT_ALL,
+			expectStream: "http://example.com/a.mp4",
+			expectQueue:  []string{},
+		},
+		{
+			name:         "unknown mode",
+			mode:         "twice",
+			queued:       []string{"http://example.com/b.mp4"},
+			expectErr:    true,
+			expectMode:   playback.REPEAT_OFF,
+			expectLoaded: true,
+			expectStream: "http://example.com/b.mp4",
+			expectQueue:  []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rooms := newTestRooms(t)
+			rooms.streamHandler.stub("http://example.com/a.mp4", 600)
+			rooms.streamHandler.stub("http://example.com/b.mp4", 600)
+			admin := rooms.join("room", "admin", rbac.ADMIN_ROLE)
+
+			ns, _ := rooms.nsHandler.NamespaceByName("room")
+			sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+
+			for _, url := range append([]string{"http://example.com/a.mp4"}, tc.queued...) {
+				if _, err := rooms.execute(admin, QUEUE_NAME, "add", url); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			_, err := rooms.execute(admin, REPEAT_NAME, tc.mode)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if mode := sPlayback.RepeatMode(); mode != tc.expectMode {
+				t.Fatalf("expected repeat mode %q, got %q", tc.expectMode, mode)
+			}
+
+			// simulate the current stream playing all the way through
+			sPlayback.SetTime(600)
+			_, loaded, err := sPlayback.EndStream()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if loaded != tc.expectLoaded {
+				t.Errorf("expected a new stream to be loaded: %v, got %v", tc.expectLoaded, loaded)
+			}
+
+			s, exists := sPlayback.GetStream()
+			if !exists || s.GetStreamURL() != tc.expectStream {
+				t.Fatalf("expected stream %q to be playing, got %v", tc.expectStream, s)
+			}
+			if time := sPlayback.GetTime(); time > 1 {
+				t.Errorf("expected the stream to play from its beginning, got %v", time)
+			}
+			if sPlayback.State() != playback.PLAYBACK_STATE_STARTED {
+				t.Errorf("expected playback to be started, got state %v", sPlayback.State())
+			}
+
+			queued := []string{}
+			for _, item := range sPlayback.GetQueue().PeekItems() {
+				if s, ok := item.(stream.Stream); ok {
+					queued = append(queued, s.GetStreamURL())
+				}
+			}
+			if len(queued) != len(tc.expectQueue) {
+				t.Fatalf("expected queued streams %v, got %v", tc.expectQueue, queued)
+			}
+			for i := range queued {
+				if queued[i] != tc.expectQueue[i] {
+					t.Errorf("expected queued streams %v, got %v", tc.expectQueue, queued)
+					break
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
@@ -16,8 +17,8 @@ type RoleCmd struct {
 
 const (
 	ROLE_NAME        = "role"
-	ROLE_DESCRIPTION = "add, replace, or remove roles for a subject (requires rbac to be enabled)"
-	ROLE_USAGE       = "Usage: /" + ROLE_NAME + " &lt;add | set | remove&gt; &lt;role&gt; &lt;subject&gt;"
+	ROLE_DESCRIPTION = "create roles, or add, replace, or remove roles for a subject (requires rbac to be enabled)"
+	ROLE_USAGE       = "Usage: /" + ROLE_NAME + " &lt;add | set | remove&gt; &lt;role&gt; &lt;subject&gt; | &lt;bind | unbind&gt; &lt;subject&gt; &lt;role&gt; | create &lt;role&gt; &lt;action...&gt; | inherit &lt;role&gt; &lt;parent|none&gt;"
 )
 
 func (h *RoleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
@@ -32,17 +33,29 @@ func (h *RoleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *
 		return h.usage, nil
 	}
 
-	roleName := args[1]
-	subjectName := args[2]
+	authorizer := cmdHandler.Authorizer()
+	if authorizer == nil {
+		return "", fmt.Errorf("authorizer not enabled")
+	}
 
 	namespace, exists := user.Namespace()
 	if !exists {
 		return "", fmt.Errorf("unable to obtain namespace information")
 	}
 
-	authorizer := cmdHandler.Authorizer()
-	if authorizer == nil {
-		return "", fmt.Errorf("authorizer not enabled")
+	if args[0] == "create" {
+		return createRole(authorizer, namespace.Name(), args[1], args[2:])
+	}
+	if args[0] == "inherit" {
+		return inheritRole(authorizer, namespace.Name(), args[1], args[2])
+	}
+
+	roleName := args[1]
+	subjectName := args[2]
+
+	// bind and unbind receive the subject first
+	if args[0] == "bind" || args[0] == "unbind" {
+		roleName, subjectName = subjectName, roleName
 	}
 
 	subjects := []*client.Client{}
@@ -72,7 +85,7 @@ func (h *RoleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *
 		return "", fmt.Errorf("error: unable to find subject %q in your namespace", subjectName)
 	}
 
-	role, exists := authorizer.Role(roleName)
+	role, exists := rbac.RoleInRoom(authorizer, namespace.Name(), roleName)
 	if !exists {
 		return "", fmt.Errorf("error: role %q not found", roleName)
 	}
@@ -91,7 +104,7 @@ func (h *RoleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *
 
 			// if no errors adding role, remove all other roles from subject
 			for _, b := range authorizer.Bindings() {
-				if b.Role().Name() == roleName {
+				if b.Role().Name() == role.Name() {
 					continue
 				}
 
@@ -115,7 +128,7 @@ func (h *RoleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *
 
 		msg += fmt.Sprintf("subject %q was successfully bound to role %q", subjectName, roleName)
 		return msg, nil
-	case "add":
+	case "add", "bind":
 		errs := []string{}
 		bound := []string{}
 
@@ -142,18 +155,18 @@ func (h *RoleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *
 		}
 
 		return fmt.Sprintf("subject %q was successfully bound (additive) to role %q", subjectName, roleName), nil
-	case "remove":
+	case "remove", "unbind":
 		messages := []string{}
 
 		for _, subject := range subjects {
 			for _, b := range authorizer.Bindings() {
-				if b.Role().Name() != roleName {
+				if b.Role().Name() != role.Name() {
 					continue
 				}
 
 				removed := b.RemoveSubject(subject)
 				if removed {
-					subject.BroadcastSystemMessageTo(fmt.Sprintf("You have been removed from the %q role", role.Name()))
+					subject.BroadcastSystemMessageTo(fmt.Sprintf("You have been removed from the %q role", roleName))
 					subject.BroadcastAll("info_userlistupdated", &client.Response{
 						Id: subject.UUID(),
 					})
@@ -194,6 +207,78 @@ func NewCmdRole() SocketCommand {
 	}
 }
 
+// createRole composes a new role for the given room from the rules defining
+// each of the given actions, so that the role grants exactly the command-level
+// permissions checked for those actions. Every action must be defined by an
+// existing role. The role may only be bound, or inherited from, in the room.
+func createRole(authorizer rbac.Authorizer, room, roleName string, actions []string) (string, error) {
+	if strings.ContainsAny(roleName, ",+|="+rbac.ROOM_ROLE_SEPARATOR) {
+		return "", fmt.Errorf("error: role names may not contain any of the characters \",+|=%s\"", rbac.ROOM_ROLE_SEPARATOR)
+	}
+	if _, exists := rbac.RoleInRoom(authorizer, room, roleName); exists {
+		return "", fmt.Errorf("error: role %q already exists", roleName)
+	}
+
+	rules := []rbac.Rule{}
+	ruleNames := []string{}
+	for _, action := range actions {
+		rule, exists := rbac.RuleByActionInRoles(authorizer.Roles(), action)
+		if !exists {
+			return "", fmt.Errorf("error: no existing role defines the action %q", action)
+		}
+
+		duplicate := false
+		for _, r := range rules {
+			if r.Name() == rule.Name() {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		rules = append(rules, rule)
+		ruleNames = append(ruleNames, rule.Name())
+	}
+
+	if !authorizer.AddRole(rbac.NewRole(rbac.RoomRoleName(room, roleName), rules)) {
+		return "", fmt.Errorf("error: role %q already exists", roleName)
+	}
+	log.Printf("INF SOCKET CMD ROLE created role %q in room %q with rules %v\n", roleName, room, ruleNames)
+	return fmt.Sprintf("created role %q allowing: %s", roleName, strings.Join(ruleNames, ", ")), nil
+}
+
+// inheritRole sets the role the given role inherits rules from.
+// A parent name of "none" stops the role from inheriting rules.
+// Only roles created for the given room may be changed.
+func inheritRole(authorizer rbac.Authorizer, room, roleName, parentName string) (string, error) {
+	role, exists := authorizer.Role(rbac.RoomRoleName(room, roleName))
+	if !exists {
+		if _, exists := authorizer.Role(roleName); exists {
+			return "", fmt.Errorf("error: role %q is a server-wide role and cannot be changed", roleName)
+		}
+		return "", fmt.Errorf("error: role %q not found", roleName)
+	}
+
+	if parentName == "none" {
+		role.SetParent(nil)
+		return fmt.Sprintf("role %q no longer inherits from another role", roleName), nil
+	}
+
+	parent, exists := rbac.RoleInRoom(authorizer, room, parentName)
+	if !exists {
+		return "", fmt.Errorf("error: role %q not found", parentName)
+	}
+
+	if err := role.SetParent(parent); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	log.Printf("INF SOCKET CMD ROLE role %q now inherits from role %q\n", roleName, parentName)
+	return fmt.Sprintf("role %q now inherits all permissions of role %q", roleName, parentName), nil
+}
+
 func addRole(authorizer rbac.Authorizer, role rbac.Role, subject *client.Client) error {
 	for _, b := range authorizer.Bindings() {
 		if b.Role().Name() != role.Name() {
@@ -208,7 +293,7 @@ func addRole(authorizer rbac.Authorizer, role rbac.Role, subject *client.Client)
 
 		// found binding for role, but subject not bound; add
 		b.AddSubject(subject)
-		subject.BroadcastSystemMessageTo(fmt.Sprintf("You have been assigned to the %q role", role.Name()))
+		subject.BroadcastSystemMessageTo(fmt.Sprintf("You have been assigned to the %q role", rbac.RoleDisplayName(role.Name())))
 		subject.BroadcastAll("info_userlistupdated", &client.Response{
 			Id: subject.UUID(),
 		})
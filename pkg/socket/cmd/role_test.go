@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestRoomScopedRoles(t *testing.T) {
+	rooms := newTestRooms(t)
+	ownerA := rooms.join("a", "ownerA", rbac.ADMIN_ROLE)
+	viewerA := rooms.join("a", "viewerA", rbac.VIEWER_ROLE)
+	ownerB := rooms.join("b", "ownerB", rbac.ADMIN_ROLE)
+	rooms.join("b", "viewerB", rbac.VIEWER_ROLE)
+
+	tests := []struct {
+		name      string
+		user      string
+		args      []string
+		expectErr bool
+	}{
+		{
+			name: "create a role in the owner's room",
+			user: "ownerA",
+			args: []string{"create", "mod", "kick/viewerB"},
+		},
+		{
+			name:      "roles cannot be created twice in a room",
+			user:      "ownerA",
+			args:      []string{"create", "mod", "kick/viewerB"},
+			expectErr: true,
+		},
+		{
+			name:      "roles of another room cannot be bound",
+			user:      "ownerB",
+			args:      []string{"bind", "viewerB", "mod"},
+			expectErr: true,
+		},
+		{
+			name:      "server-wide roles cannot be changed",
+			user:      "ownerA",
+			args:      []string{"inherit", rbac.VIEWER_ROLE, rbac.ADMIN_ROLE},
+			expectErr: true,
+		},
+		{
+			name: "room roles may inherit server-wide roles",
+			user: "ownerA",
+			args: []string{"inherit", "mod", rbac.USER_ROLE},
+		},
+		{
+			name: "room roles can be bound in their room",
+			user: "ownerA",
+			args: []string{"bind", "viewerA", "mod"},
+		},
+	}
+
+	owners := map[string]*client.Client{
+		"ownerA": ownerA,
+		"ownerB": ownerB,
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := rooms.execute(owners[tc.user], ROLE_NAME, tc.args...)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+
+	if _, exists := rooms.authorizer.Role("mod"); exists {
+		t.Errorf("expected room role not to be known server-wide")
+	}
+	if _, exists := rbac.RoleInRoom(rooms.authorizer, "b", "mod"); exists {
+		t.Errorf("expected room role not to be known to other rooms")
+	}
+
+	kick, exists := rbac.RuleByActionInRoles(rooms.authorizer.Roles(), "kick/viewerB")
+	if !exists {
+		t.Fatalf("expected a rule for kicking users")
+	}
+	if !rooms.authorizer.Verify(viewerA, kick) {
+		t.Errorf("expected the bound room role to allow kicking users")
+	}
+}
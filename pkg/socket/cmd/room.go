@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type RoomCmd struct {
+	Command
+}
+
+const (
+	ROOM_NAME        = "room"
+	ROOM_DESCRIPTION = "controls room-wide settings (list|unlist|queuelimit|duplicates|queuemode|announce|markdown|countdown)"
+	ROOM_USAGE       = "Usage: /" + ROOM_NAME + " &lt;list|unlist|queuelimit &lt;count&gt;|duplicates &lt;allow|consecutive|reject&gt;|queuemode &lt;roundrobin|fifo&gt;|announce &lt;on|off&gt;|markdown &lt;on|off&gt;|countdown &lt;seconds|off&gt;&gt;"
+)
+
+var (
+	room_aliases = []string{}
+)
+
+func (h *RoomCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return h.usage, nil
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to update room settings with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to update its settings.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	var output string
+	switch args[0] {
+	case "list":
+		sPlayback.SetListed(true)
+		output = "your room is now publicly listed."
+	case "unlist":
+		sPlayback.SetListed(false)
+		output = "your room is no longer publicly listed."
+	case "queuelimit":
+		if len(args) < 2 {
+			return fmt.Sprintf("each user may store up to %v items in this room's queue.", sPlayback.MaxQueueItems()), nil
+		}
+
+		limit, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", fmt.Errorf("error: unable to convert queue limit: %v", err)
+		}
+		if err := sPlayback.SetMaxQueueItems(limit); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		output = fmt.Sprintf("each user may now store up to %v items in this room's queue.", limit)
+	case "duplicates":
+		if len(args) < 2 {
+			return fmt.Sprintf("this room's duplicate queue item policy is %q.", sPlayback.DuplicatePolicy()), nil
+		}
+
+		if err := sPlayback.SetDuplicatePolicy(playback.DuplicatePolicy(args[1])); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		output = fmt.Sprintf("this room's duplicate queue item policy is now %q.", args[1])
+	case "announce":
+		if len(args) < 2 {
+			if sPlayback.AnnounceStreams() {
+				return "this room announces new streams in chat.", nil
+			}
+			return "this room does not announce new streams in chat.", nil
+		}
+
+		switch args[1] {
+		case "on":
+			sPlayback.SetAnnounceStreams(true)
+			output = "this room will now announce new streams in chat."
+		case "off":
+			sPlayback.SetAnnounceStreams(false)
+			output = "this room will no longer announce new streams in chat."
+		default:
+			return h.usage, nil
+		}
+	case "markdown":
+		if len(args) < 2 {
+			if sPlayback.Markdown() {
+				return "this room formats Markdown in chat messages.", nil
+			}
+			return "this room does not format Markdown in chat messages.", nil
+		}
+
+		switch args[1] {
+		case "on":
+			sPlayback.SetMarkdown(true)
+			output = "this room will now format Markdown in chat messages."
+		case "off":
+			sPlayback.SetMarkdown(false)
+			output = "this room will no longer format Markdown in chat messages."
+		default:
+			return h.usage, nil
+		}
+	case "queuemode":
+		if len(args) < 2 {
+			return fmt.Sprintf("this room's queue mode is %q.", sPlayback.QueueMode()), nil
+		}
+
+		if err := sPlayback.SetQueueMode(queue.QueueMode(args[1])); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		output = fmt.Sprintf("this room's queue mode is now %q.", args[1])
+	case "countdown":
+		if len(args) < 2 {
+			return fmt.Sprintf("this room counts down %v seconds before starting a new stream.", sPlayback.Countdown()), nil
+		}
+
+		seconds := 0
+		if args[1] != "off" {
+			var err error
+			seconds, err = strconv.Atoi(args[1])
+			if err != nil {
+				return "", fmt.Errorf("error: unable to convert countdown: %v", err)
+			}
+		}
+		if err := sPlayback.SetCountdown(seconds); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		output = fmt.Sprintf("this room will now count down %v seconds before starting a new stream.", seconds)
+	default:
+		return h.usage, nil
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.Settings(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("roomsettings", res)
+	return output, nil
+}
+
+func NewCmdRoom() SocketCommand {
+	return &RoomCmd{
+		Command{
+			name:        ROOM_NAME,
+			description: ROOM_DESCRIPTION,
+			usage:       ROOM_USAGE,
+
+			aliases: room_aliases,
+		},
+	}
+}
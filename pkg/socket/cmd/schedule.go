@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/util"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type ScheduleCmd struct {
+	Command
+}
+
+const (
+	SCHEDULE_NAME        = "schedule"
+	SCHEDULE_DESCRIPTION = "schedules a stream to start at a given time (e.g. 10m, 1h30m, or 20:30 server time)"
+	SCHEDULE_USAGE       = "Usage: /" + SCHEDULE_NAME + " [&lt;when&gt; &lt;url&gt;]"
+)
+
+var (
+	schedule_aliases = []string{}
+)
+
+func (h *ScheduleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to schedule a stream with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to schedule a stream.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		status, exists := sPlayback.ScheduledStream()
+		if !exists {
+			return "your room has no scheduled stream.", nil
+		}
+
+		return fmt.Sprintf("%q is scheduled to start in %v (scheduled by %q).", status.Url, time.Duration(status.Remaining)*time.Second, status.ScheduledBy), nil
+	}
+
+	if len(args) != 2 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	at, err := parseScheduleTime(args[0], time.Now())
+	if err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	s, err := sPlayback.GetOrCreateStreamFromUrl(args[1], user, streamHandler, func(data []byte, created bool, err error) {})
+	if err != nil {
+		return "", err
+	}
+
+	if err := sPlayback.ScheduleStream(s, at, username); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	status, _ := sPlayback.ScheduledStream()
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	if err := sockutil.SerializeIntoResponse(status, &res.Extra); err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("streamschedule", res)
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has scheduled %q to start at %s", username, args[1], at.Format("15:04:05 MST")))
+	return fmt.Sprintf("%q will start in %v.", args[1], time.Duration(status.Remaining)*time.Second), nil
+}
+
+// parseScheduleTime receives a relative time (10m, 1h30m) or a
+// clock time (20:30, 20:30:15) and returns the absolute time it
+// refers to. Clock times refer to their next occurrence in the
+// server's local time.
+func parseScheduleTime(when string, now time.Time) (time.Time, error) {
+	if !strings.Contains(when, ":") {
+		secs, err := util.HumanTimeToSeconds(when)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to parse %q as a time: %v", when, err)
+		}
+		return now.Add(time.Duration(secs) * time.Second), nil
+	}
+
+	segs := strings.Split(when, ":")
+	if len(segs) == 2 {
+		segs = append(segs, "0")
+	}
+
+	clock := []int{}
+	for idx, seg := range segs {
+		val, err := strconv.Atoi(seg)
+		if err != nil || val < 0 || (idx == 0 && val > 23) || val > 59 {
+			return time.Time{}, fmt.Errorf("unable to parse %q as a time of day", when)
+		}
+		clock = append(clock, val)
+	}
+	if len(clock) != 3 {
+		return time.Time{}, fmt.Errorf("unable to parse %q as a time of day", when)
+	}
+
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock[0], clock[1], clock[2], 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+func NewCmdSchedule() SocketCommand {
+	return &ScheduleCmd{
+		Command{
+			name:        SCHEDULE_NAME,
+			description: SCHEDULE_DESCRIPTION,
+			usage:       SCHEDULE_USAGE,
+
+			aliases: schedule_aliases,
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type SeekCmd struct {
+	Command
+}
+
+const (
+	SEEK_NAME        = "seek"
+	SEEK_DESCRIPTION = "seeks the stream to the given time (90, 1:30, 1:02:03, 1m30s)"
+	SEEK_USAGE       = "Usage: /" + SEEK_NAME + " &lt;[+|-]time&gt;"
+)
+
+var (
+	seek_aliases = []string{}
+)
+
+func (h *SeekCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	// delegate to the stream command so that the playback
+	// lock and stream control rules are enforced as usual
+	return cmdHandler.ExecuteCommand(STREAM_NAME, []string{"seek", args[0]}, user, clientHandler, playbackHandler, streamHandler)
+}
+
+func NewCmdSeek() SocketCommand {
+	return &SeekCmd{
+		Command{
+			name:        SEEK_NAME,
+			description: SEEK_DESCRIPTION,
+			usage:       SEEK_USAGE,
+
+			aliases: seek_aliases,
+		},
+	}
+}
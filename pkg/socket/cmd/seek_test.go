@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestSeek(t *testing.T) {
+	rooms := newTestRooms(t)
+	rooms.streamHandler.stub("http://example.com/a.mp4", 7200)
+	admin, conn := rooms.joinWithConnection("room", "admin", rbac.ADMIN_ROLE)
+	user := rooms.join("room", "user", rbac.USER_ROLE)
+
+	if _, err := rooms.execute(admin, QUEUE_NAME, "add", "http://example.com/a.mp4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ns, _ := rooms.nsHandler.NamespaceByName("room")
+	sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+
+	// keep the playback time still between seeks
+	sPlayback.Pause()
+	sPlayback.SetTime(600)
+
+	tests := []struct {
+		name               string
+		time               string
+		unauthorized       bool
+		expectErr          bool
+		expectTime         int
+		expectUnauthorized bool
+	}{
+		{
+			name:       "seconds",
+			time:       "90",
+			expectTime: 90,
+		},
+		{
+			name:       "minutes and seconds",
+			time:       "1:30",
+			expectTime: 90,
+		},
+		{
+			name:       "hours, minutes and seconds",
+			time:       "1:02:03",
+			expectTime: 3723,
+		},
+		{
+			name:       "human-readable time",
+			time:       "1h1m",
+			expectTime: 3660,
+		},
+		{
+			name:       "advance by a clock time",
+			time:       "+1:00",
+			expectTime: 3720,
+		},
+		{
+			name:       "rewind by seconds",
+			time:       "-120",
+			expectTime: 3600,
+		},
+		{
+			name:       "end of the stream",
+			time:       "2:00:00",
+			expectTime: 7200,
+		},
+		{
+			name:       "beyond the end of the stream",
+			time:       "2:00:01",
+			expectErr:  true,
+			expectTime: 7200,
+		},
+		{
+			name:       "advancing beyond the end of the stream",
+			time:       "+1",
+			expectErr:  true,
+			expectTime: 7200,
+		},
+		{
+			name:       "unrecognized format",
+			time:       "1:xx",
+			expectErr:  true,
+			expectTime: 7200,
+		},
+		{
+			name:               "clients without permission cannot seek",
+			time:               "0",
+			unauthorized:       true,
+			expectErr:          true,
+			expectUnauthorized: true,
+			expectTime:         7200,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conn.mutex.Lock()
+			broadcasts := len(conn.broadcasts)
+			conn.mutex.Unlock()
+
+			c := admin
+			if tc.unauthorized {
+				c = user
+			}
+
+			_, err := rooms.execute(c, SEEK_NAME, tc.time)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if unauthorized := errors.Is(err, ErrNotAuthorized); unauthorized != tc.expectUnauthorized {
+				t.Errorf("expected unauthorized: %v, got error %v", tc.expectUnauthorized, err)
+			}
+			if time := sPlayback.GetTime(); time != tc.expectTime {
+				t.Errorf("expected playback time %v, got %v", tc.expectTime, time)
+			}
+
+			conn.mutex.Lock()
+			sent := conn.broadcasts[broadcasts:]
+			conn.mutex.Unlock()
+			if tc.expectErr {
+				if len(sent) != 0 {
+					t.Errorf("expected nothing to be broadcast, got %v", sent)
+				}
+				return
+			}
+
+			if len(sent) != 1 || sent[0].Event != "streamsync" {
+				t.Fatalf("expected a single streamsync to be broadcast, got %v", sent)
+			}
+			status, _ := sent[0].Data.Extra["playback"].(map[string]interface{})
+			if time, _ := status["time"].(float64); int(time) != tc.expectTime {
+				t.Errorf("expected clients to be synced to %v, got %v", tc.expectTime, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type SlowModeCmd struct {
+	Command
+}
+
+const (
+	SLOWMODE_NAME        = "slowmode"
+	SLOWMODE_DESCRIPTION = "sets the minimum number of seconds between each user's chat messages (0 disables)"
+	SLOWMODE_USAGE       = "Usage: /" + SLOWMODE_NAME + " &lt;seconds&gt;"
+
+	// SLOWMODE_EXEMPT_ACTION is authorized for users who
+	// are not subject to their room's slow mode
+	SLOWMODE_EXEMPT_ACTION = SLOWMODE_NAME + "/exempt"
+)
+
+var (
+	slowmode_aliases = []string{"slow"}
+)
+
+func (h *SlowModeCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to set slow mode with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to set its slow mode.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		if sPlayback.SlowMode() == 0 {
+			return "slow mode is off. " + h.usage, nil
+		}
+		return fmt.Sprintf("slow mode is on - users may send one message every %v.", sPlayback.SlowMode()), nil
+	}
+
+	seconds, err := strconv.Atoi(args[0])
+	if err != nil || seconds < 0 {
+		return "", fmt.Errorf("error: slow mode interval must be a positive number of seconds. %s", h.usage)
+	}
+
+	interval := time.Duration(seconds) * time.Second
+	sPlayback.SetSlowMode(interval)
+
+	var output string
+	if interval == 0 {
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has turned off slow mode", username))
+		output = "slow mode is off."
+	} else {
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has turned on slow mode - users may send one message every %v", username, interval))
+		output = fmt.Sprintf("slow mode is on - users may send one message every %v.", interval)
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err = sockutil.SerializeIntoResponse(sPlayback.Settings(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("roomsettings", res)
+	return output, nil
+}
+
+func NewCmdSlowMode() SocketCommand {
+	return &SlowModeCmd{
+		Command{
+			name:        SLOWMODE_NAME,
+			description: SLOWMODE_DESCRIPTION,
+			usage:       SLOWMODE_USAGE,
+
+			aliases: slowmode_aliases,
+		},
+	}
+}
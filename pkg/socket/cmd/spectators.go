@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type SpectatorsCmd struct {
+	Command
+}
+
+const (
+	SPECTATORS_NAME        = "spectators"
+	SPECTATORS_DESCRIPTION = "lists, adds, or removes spectators, who may watch but may not chat or change the queue"
+	SPECTATORS_USAGE       = "Usage: /" + SPECTATORS_NAME + " [list|add &lt;username&gt;|remove &lt;username&gt;]"
+
+	// PARTICIPATE_ACTION is authorized for users who may chat and
+	// change the queue. Users whose roles do not authorize it are
+	// treated as spectators.
+	PARTICIPATE_ACTION = "participate"
+)
+
+var (
+	spectators_aliases = []string{"spectator"}
+)
+
+func (h *SpectatorsCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	namespace, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to manage spectators with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to manage its spectators.")
+	}
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		names := []string{}
+		for _, conn := range namespace.Connections() {
+			c, err := clientHandler.GetClient(conn.UUID())
+			if err != nil {
+				continue
+			}
+			if IsSpectator(cmdHandler.Authorizer(), c, playbackHandler) {
+				names = append(names, c.GetUsernameOrId())
+			}
+		}
+
+		if len(names) == 0 {
+			return "there are no spectators in this room", nil
+		}
+		return fmt.Sprintf("spectators: %s", strings.Join(names, ", ")), nil
+	case "add", "remove":
+		if len(args) < 2 {
+			return "", fmt.Errorf("%v", h.usage)
+		}
+
+		target, err := findRoomClient(user, clientHandler, args[1])
+		if err != nil {
+			return "", err
+		}
+
+		spectator := args[0] == "add"
+		if target.IsSpectator() == spectator {
+			if spectator {
+				return "", fmt.Errorf("error: %q is already a spectator", args[1])
+			}
+			return "", fmt.Errorf("error: %q is not a spectator", args[1])
+		}
+
+		target.SetSpectator(spectator)
+		target.BroadcastAll("info_userlistupdated", &client.Response{
+			Id: target.UUID(),
+		})
+
+		if spectator {
+			target.BroadcastSystemMessageTo(fmt.Sprintf("%q has made you a spectator - you may keep watching, but may not chat or change the queue", username))
+			user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has made %q a spectator", username, args[1]))
+			return fmt.Sprintf("%q is now a spectator", args[1]), nil
+		}
+
+		target.BroadcastSystemMessageTo(fmt.Sprintf("%q has allowed you to chat and change the queue again", username))
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q is no longer a spectator", args[1]))
+		return fmt.Sprintf("%q is no longer a spectator", args[1]), nil
+	}
+
+	return "", fmt.Errorf("%v", h.usage)
+}
+
+// IsSpectator returns true if the given client has been made a spectator,
+// or if access control is enabled and none of the client's roles authorize
+// PARTICIPATE_ACTION.
+func IsSpectator(authorizer rbac.Authorizer, c *client.Client, playbackHandler playback.PlaybackHandler) bool {
+	if c.IsSpectator() {
+		return true
+	}
+	if authorizer == nil {
+		return false
+	}
+
+	return !Authorize(authorizer, c, PARTICIPATE_ACTION, playbackHandler).Allowed
+}
+
+func NewCmdSpectators() SocketCommand {
+	return &SpectatorsCmd{
+		Command{
+			name:        SPECTATORS_NAME,
+			description: SPECTATORS_DESCRIPTION,
+			usage:       SPECTATORS_USAGE,
+
+			aliases: spectators_aliases,
+		},
+	}
+}
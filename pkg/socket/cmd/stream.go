@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	"encoding/json"
 
@@ -20,8 +21,8 @@ type StreamCmd struct {
 
 const (
 	STREAM_NAME        = "stream"
-	STREAM_DESCRIPTION = "controls stream playback (info|pause|play|stop|set|seek|skip)'"
-	STREAM_USAGE       = "Usage: /" + STREAM_NAME + " (info|pause|play|stop|skip|seek &lt;seconds&gt;|set &lt;url&gt;)"
+	STREAM_DESCRIPTION = "controls stream playback (info|pause|play|start|stop|set|seek|skip|lock|unlock|duration)'"
+	STREAM_USAGE       = "Usage: /" + STREAM_NAME + " (info|pause|play|start|stop|skip|lock|unlock|seek &lt;seconds&gt;|duration &lt;seconds|off&gt;|set &lt;url&gt;)"
 )
 
 var (
@@ -98,8 +99,7 @@ func (h *StreamCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 		fallthrough
 	case "skip":
 		// skip the currently-playing stream and replace it with the next item in the queue
-		queue := sPlayback.GetQueue()
-		queueItem, err := queue.Next()
+		queueItem, err := sPlayback.NextQueueItem()
 		if err != nil {
 			return "", fmt.Errorf("error: %v", err)
 		}
@@ -113,7 +113,7 @@ func (h *StreamCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 		sPlayback.Reset()
 
 		if playStreamOnSkip {
-			sPlayback.Play()
+			sPlayback.StartPlayback()
 		}
 
 		res := &client.Response{
@@ -133,6 +133,7 @@ func (h *StreamCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 
 		user.BroadcastAll("streamload", res)
 		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has attempted to load the next item in the queue: %q", username, streamIdentifier))
+		announceStream(user, sPlayback)
 		return fmt.Sprintf("attempting to load the next item in the queue: %q", streamIdentifier), nil
 	case "load":
 		fallthrough
@@ -165,6 +166,34 @@ func (h *StreamCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has attempted to load a %s stream: %q", username, s.GetKind(), url))
 
 		return fmt.Sprintf("attempting to load %q", args[1]), nil
+	case "start":
+		// skip the room's countdown and start the loaded stream immediately
+		skipped, err := sPlayback.SkipCountdown()
+		if err != nil {
+			return "", fmt.Errorf("error: unable to start the stream: %v", err)
+		}
+		if !skipped {
+			return "", fmt.Errorf("error: the stream is not counting down to start")
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has skipped the countdown", username))
+		return "starting the stream...", nil
+	case "lock":
+		if _, lockedByName, locked := sPlayback.LockedBy(); locked {
+			return "", fmt.Errorf("error: stream playback is already locked by %q", lockedByName)
+		}
+
+		sPlayback.Lock(user.UUID(), username)
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has locked stream playback controls", username))
+		return "stream playback controls are now locked. Use /stream unlock to release them.", nil
+	case "unlock":
+		if _, _, locked := sPlayback.LockedBy(); !locked {
+			return "", fmt.Errorf("error: stream playback is not currently locked")
+		}
+
+		sPlayback.Unlock()
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has unlocked stream playback controls", username))
+		return "stream playback controls are now unlocked.", nil
 	}
 
 	// require stream data to have been loaded before proceeding with cases below
@@ -204,6 +233,44 @@ func (h *StreamCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 
 		user.BroadcastAll("streamsync", res)
 		return "stopping stream...", nil
+	case "duration":
+		if len(args) < 2 || len(args[1]) == 0 {
+			return "", fmt.Errorf("a duration (in seconds) or \"off\" must be provided. See usage info.")
+		}
+
+		message := "removing the current stream's duration override"
+		if args[1] == "off" {
+			sPlayback.SetDurationOverride(0)
+		} else {
+			duration, err := strconv.Atoi(args[1])
+			if err != nil {
+				// if an int was not received, try to parse human-readable time format (0h0m0s)
+				duration, err = util.HumanTimeToSeconds(args[1])
+				if err != nil {
+					return "", fmt.Errorf("error: cannot interpret %q as a valid duration. Must be of the form 12345 or 0h0m0s", args[1])
+				}
+			}
+			if duration <= 0 {
+				return "", fmt.Errorf("error: a duration override must be greater than zero")
+			}
+
+			sPlayback.SetDurationOverride(float64(duration))
+			message = fmt.Sprintf("the current stream will end after %vs", duration)
+		}
+
+		res := &client.Response{
+			Id:   user.UUID(),
+			From: username,
+		}
+
+		err := sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+		if err != nil {
+			return "", err
+		}
+
+		user.BroadcastAll("streamsync", res)
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q: %s", username, message))
+		return message, nil
 	case "seek":
 		if len(args) < 2 || len(args[1]) == 0 {
 			return "", fmt.Errorf("a time (in seconds) must be provided. See usage info.")
@@ -219,27 +286,37 @@ func (h *StreamCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 
 		newTime, err := strconv.Atoi(rawTime)
 		if err != nil {
-			// if an int was not received, try to parse human-readable time format (0h0m0s)
-			newTime, err = util.HumanTimeToSeconds(rawTime)
+			// if an int was not received, try to parse clock (1:02:03)
+			// or human-readable (0h0m0s) time formats
+			if strings.Contains(rawTime, ":") {
+				newTime, err = util.ClockTimeToSeconds(rawTime)
+			} else {
+				newTime, err = util.HumanTimeToSeconds(rawTime)
+			}
 			if err != nil {
-				return "", fmt.Errorf("error: cannot interpret %q as a valid time. Must be of the form 12345 or 0h0m0s", args[1])
+				return "", fmt.Errorf("error: cannot interpret %q as a valid time. Must be of the form 12345, 1:02:03, or 0h0m0s", args[1])
 			}
 		}
 
 		message := "setting the stream playback to"
+		targetTime := newTime
 
 		if len(modifier) > 0 {
 			if modifier == "+" {
 				message = "advancing the stream playback by"
-				sPlayback.SetTime(sPlayback.GetTime() + newTime)
+				targetTime = sPlayback.GetTime() + newTime
 			} else {
 				message = "rewinding the stream playback by"
-				sPlayback.SetTime(sPlayback.GetTime() - newTime)
+				targetTime = sPlayback.GetTime() - newTime
 			}
-		} else {
-			sPlayback.SetTime(newTime)
 		}
 
+		if endTime, hasEndTime := sPlayback.EndTime(); hasEndTime && float64(targetTime) > endTime {
+			return "", fmt.Errorf("error: cannot seek to %vs - the stream is only %vs long", targetTime, endTime)
+		}
+
+		sPlayback.SetTime(targetTime)
+
 		res := &client.Response{
 			Id:   user.UUID(),
 			From: username,
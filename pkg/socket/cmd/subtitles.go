@@ -8,6 +8,7 @@ import (
 
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
 	"github.com/juanvallejo/streaming-server/pkg/socket/util"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
 )
@@ -18,8 +19,8 @@ type SubtitlesCmd struct {
 
 const (
 	SUBTITLES_NAME        = "subtitles"
-	SUBTITLES_DESCRIPTION = "controls stream subtitles"
-	SUBTITLES_USAGE       = "Usage: /" + SUBTITLES_NAME + " &lt;(on|off)&gt;"
+	SUBTITLES_DESCRIPTION = "controls stream subtitles, or lists and selects the current stream's subtitle track for the room"
+	SUBTITLES_USAGE       = "Usage: /" + SUBTITLES_NAME + " &lt;on|off|list|track &lt;id|off&gt;&gt;"
 
 	SUBTITLES_FILE_ROOT = "/src/static/subtitles/"
 )
@@ -44,6 +45,13 @@ func (h *SubtitlesCmd) Execute(cmdHandler SocketCommandHandler, args []string, u
 		return "", fmt.Errorf("error: you must be in a stream to control stream playback")
 	}
 
+	switch args[0] {
+	case "list":
+		return listSubtitleTracks(userRoom, playbackHandler)
+	case "track":
+		return h.selectSubtitleTrack(args[1:], user, username, userRoom, playbackHandler)
+	}
+
 	currentDir := util.GetCurrentDirectory()
 	subFile := roomToSubsFile(userRoom.Name())
 
@@ -81,6 +89,80 @@ func (h *SubtitlesCmd) Execute(cmdHandler SocketCommandHandler, args []string, u
 	return h.usage, nil
 }
 
+// listSubtitleTracks describes the subtitle tracks
+// available for the current stream in the given room
+func listSubtitleTracks(userRoom connection.Namespace, playbackHandler playback.PlaybackHandler) (string, error) {
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	s, exists := sPlayback.GetStream()
+	if !exists {
+		return "", fmt.Errorf("error: no stream is currently loaded")
+	}
+
+	tracks := s.GetSubtitles()
+	if len(tracks) == 0 {
+		return "the current stream has no subtitle tracks.", nil
+	}
+
+	selected, _ := sPlayback.SubtitleTrack()
+	descriptions := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		desc := fmt.Sprintf("%s (%s)", track.Id, track.Label)
+		if track.Id == selected.Id {
+			desc += " [selected]"
+		}
+		descriptions = append(descriptions, desc)
+	}
+	return fmt.Sprintf("subtitle tracks for the current stream: %s", strings.Join(descriptions, ", ")), nil
+}
+
+// selectSubtitleTrack selects the subtitle track every client in the
+// given room should enable, or deselects it, broadcasting the change
+// in a streamsync
+func (h *SubtitlesCmd) selectSubtitleTrack(args []string, user *client.Client, username string, userRoom connection.Namespace, playbackHandler playback.PlaybackHandler) (string, error) {
+	if len(args) == 0 {
+		return h.usage, nil
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("SOCKET CLIENT ERR unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	var announcement, output string
+	if args[0] == "off" {
+		sPlayback.ClearSubtitleTrack()
+		announcement = fmt.Sprintf("%q has turned off subtitles for the room", username)
+		output = "subtitles are now off for the room."
+	} else {
+		if err := sPlayback.SetSubtitleTrack(args[0]); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+
+		track, _ := sPlayback.SubtitleTrack()
+		announcement = fmt.Sprintf("%q has selected the %q subtitle track for the room", username, track.Label)
+		output = fmt.Sprintf("the room's subtitle track is now %q.", track.Label)
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("streamsync", res)
+	user.BroadcastSystemMessageFrom(announcement)
+	return output, nil
+}
+
 func roomToSubsFile(roomName string) string {
 	segs := strings.Split(roomName, ".")
 	return segs[0] + ".vtt"
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type TopicCmd struct {
+	Command
+}
+
+const (
+	TOPIC_NAME        = "topic"
+	TOPIC_DESCRIPTION = "displays or sets the room's topic and description"
+	TOPIC_USAGE       = "Usage: /" + TOPIC_NAME + " [&lt;topic&gt;|description &lt;text&gt;|clear]"
+)
+
+var (
+	topic_aliases = []string{}
+)
+
+func (h *TopicCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to access the room topic with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to access its topic.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		if !sPlayback.HasTopic() {
+			return "your room has no topic set.", nil
+		}
+
+		topic := sPlayback.Topic()
+		output := fmt.Sprintf("topic: %s", topic.Topic)
+		if len(topic.Description) > 0 {
+			output += fmt.Sprintf("<br />description: %s", topic.Description)
+		}
+		return output, nil
+	}
+
+	var output string
+	switch args[0] {
+	case "clear":
+		sPlayback.ClearTopic()
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has cleared the room's topic", username))
+		output = "the room's topic has been cleared."
+	case "description":
+		if len(args) < 2 {
+			return "", fmt.Errorf("%v", h.usage)
+		}
+
+		if err := sPlayback.SetDescription(strings.Join(args[1:], " "), username); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has updated the room's description", username))
+		output = "the room's description has been updated."
+	default:
+		topic := strings.Join(args, " ")
+		if err := sPlayback.SetTopic(topic, username); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has set the room's topic to %q", username, topic))
+		output = "the room's topic has been updated."
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.Topic(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("topic", res)
+	return output, nil
+}
+
+func NewCmdTopic() SocketCommand {
+	return &TopicCmd{
+		Command{
+			name:        TOPIC_NAME,
+			description: TOPIC_DESCRIPTION,
+			usage:       TOPIC_USAGE,
+
+			aliases: topic_aliases,
+		},
+	}
+}
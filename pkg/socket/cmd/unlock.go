@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type UnlockCmd struct {
+	Command
+}
+
+const (
+	UNLOCK_NAME        = "unlock"
+	UNLOCK_DESCRIPTION = "allows anyone to join the room without a password"
+	UNLOCK_USAGE       = "Usage: /" + UNLOCK_NAME
+)
+
+var (
+	unlock_aliases = []string{}
+)
+
+func (h *UnlockCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to remove a room password with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to remove its password.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if !sPlayback.HasPassword() {
+		return "", fmt.Errorf("error: the room does not require a password")
+	}
+
+	sPlayback.ClearPassword()
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has removed the room's password", username))
+	return "the room no longer requires a password to join.", nil
+}
+
+func NewCmdUnlock() SocketCommand {
+	return &UnlockCmd{
+		Command{
+			name:        UNLOCK_NAME,
+			description: UNLOCK_DESCRIPTION,
+			usage:       UNLOCK_USAGE,
+
+			aliases: unlock_aliases,
+		},
+	}
+}
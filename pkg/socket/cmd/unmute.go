@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type UnmuteCmd struct {
+	Command
+}
+
+const (
+	UNMUTE_NAME        = "unmute"
+	UNMUTE_DESCRIPTION = "allows a muted user to send chat messages again"
+	UNMUTE_USAGE       = "Usage: /" + UNMUTE_NAME + " &lt;username&gt;"
+)
+
+var (
+	unmute_aliases = []string{}
+)
+
+func (h *UnmuteCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to unmute a user with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to unmute users in it.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if !sPlayback.Unmute(args[0]) {
+		return "", fmt.Errorf("error: %q is not muted in this room", args[0])
+	}
+
+	if target, err := findRoomClient(user, clientHandler, args[0]); err == nil {
+		target.BroadcastSystemMessageTo(fmt.Sprintf("you have been unmuted by %q", username))
+	}
+
+	return fmt.Sprintf("%q has been unmuted.", args[0]), nil
+}
+
+func NewCmdUnmute() SocketCommand {
+	return &UnmuteCmd{
+		Command{
+			name:        UNMUTE_NAME,
+			description: UNMUTE_DESCRIPTION,
+			usage:       UNMUTE_USAGE,
+
+			aliases: unmute_aliases,
+		},
+	}
+}
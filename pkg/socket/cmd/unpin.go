@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type UnpinCmd struct {
+	Command
+}
+
+const (
+	UNPIN_NAME        = "unpin"
+	UNPIN_DESCRIPTION = "unpins a pinned chat message, or every pinned message if no message id is given"
+	UNPIN_USAGE       = "Usage: /" + UNPIN_NAME + " [messageId]"
+)
+
+var (
+	unpin_aliases = []string{}
+)
+
+func (h *UnpinCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to unpin a message with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to unpin its messages.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	messageId := ""
+	if len(args) > 0 {
+		messageId = args[0]
+	}
+
+	if !sPlayback.UnpinChatMessage(messageId) {
+		if len(messageId) == 0 {
+			return "", fmt.Errorf("error: there are no pinned messages in this room")
+		}
+		return "", fmt.Errorf("error: that message is not pinned")
+	}
+
+	if err := broadcastPinnedMessages(user, sPlayback); err != nil {
+		return "", err
+	}
+
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has unpinned a message", username))
+	return "the message has been unpinned.", nil
+}
+
+func NewCmdUnpin() SocketCommand {
+	return &UnpinCmd{
+		Command{
+			name:        UNPIN_NAME,
+			description: UNPIN_DESCRIPTION,
+			usage:       UNPIN_USAGE,
+
+			aliases: unpin_aliases,
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type UnqueueCmd struct {
+	Command
+}
+
+const (
+	UNQUEUE_NAME        = "unqueue"
+	UNQUEUE_DESCRIPTION = "removes a single pending item from the queue"
+	UNQUEUE_USAGE       = "Usage: /" + UNQUEUE_NAME + " &lt;id&gt;"
+)
+
+var (
+	unqueue_aliases = []string{"uq"}
+)
+
+func (h *UnqueueCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to remove a queue item with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to remove items from its queue.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	itemId := args[0]
+	userQueue, _, exists := sPlayback.QueueItemById(itemId)
+	if !exists {
+		return "", fmt.Errorf("error: no item with id %q was found in the queue", itemId)
+	}
+
+	// items pushed by other users may only be removed
+	// by those allowed to clear the room's queue
+	if userQueue.UUID() != user.UUID() {
+		decision := Authorize(cmdHandler.Authorizer(), user, "queue/clear/room", playbackHandler)
+		if !decision.Allowed {
+			return "", fmt.Errorf("error: you may only remove items you have queued - %s", decision.Reason)
+		}
+	}
+
+	if err := sPlayback.RemoveQueueItemById(itemId); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	if err := sendQueueSyncEvent(user, sPlayback); err != nil {
+		return "", err
+	}
+	if err := sendUserQueueSyncEvent(user, sPlayback); err != nil {
+		return "", err
+	}
+
+	// keep the item owner's stack in sync if removed by someone else
+	if userQueue.UUID() != user.UUID() {
+		if owner, err := clientHandler.GetClient(userQueue.UUID()); err == nil {
+			if err := sendUserQueueSyncEvent(owner, sPlayback); err != nil {
+				log.Printf("ERR SOCKET CLIENT unable to send stacksync event to client with id %q: %v", owner.UUID(), err)
+			}
+		}
+	}
+
+	return fmt.Sprintf("removing item with id %q from the queue...", itemId), nil
+}
+
+func NewCmdUnqueue() SocketCommand {
+	return &UnqueueCmd{
+		Command{
+			name:        UNQUEUE_NAME,
+			description: UNQUEUE_DESCRIPTION,
+			usage:       UNQUEUE_USAGE,
+
+			aliases: unqueue_aliases,
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type UnscheduleCmd struct {
+	Command
+}
+
+const (
+	UNSCHEDULE_NAME        = "unschedule"
+	UNSCHEDULE_DESCRIPTION = "cancels the room's scheduled stream"
+	UNSCHEDULE_USAGE       = "Usage: /" + UNSCHEDULE_NAME
+)
+
+var (
+	unschedule_aliases = []string{}
+)
+
+func (h *UnscheduleCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to cancel a scheduled stream with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to cancel a scheduled stream.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if !sPlayback.UnscheduleStream() {
+		return "", fmt.Errorf("error: your room has no scheduled stream")
+	}
+
+	user.BroadcastAll("streamschedule", &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	})
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has cancelled the scheduled stream", username))
+	return "the scheduled stream has been cancelled.", nil
+}
+
+func NewCmdUnschedule() SocketCommand {
+	return &UnscheduleCmd{
+		Command{
+			name:        UNSCHEDULE_NAME,
+			description: UNSCHEDULE_DESCRIPTION,
+			usage:       UNSCHEDULE_USAGE,
+
+			aliases: unschedule_aliases,
+		},
+	}
+}
@@ -56,8 +56,104 @@ func HumanTimeToSeconds(t string) (int, error) {
 	return tsecs, nil
 }
 
+// ClockTimeToSeconds receives a clock-formatted
+// time (1:30, 1:02:03) and returns it in seconds.
+func ClockTimeToSeconds(t string) (int, error) {
+	segs := strings.Split(t, ":")
+	if len(segs) < 2 || len(segs) > 3 {
+		return 0, fmt.Errorf("unable to parse string... invalid format")
+	}
+
+	tsecs := 0
+	for idx, seg := range segs {
+		val, err := strconv.Atoi(seg)
+		if err != nil || val < 0 {
+			return 0, fmt.Errorf("unable to parse time segment %q", seg)
+		}
+		// minutes and seconds may not exceed 59 unless leading
+		if idx > 0 && val > 59 {
+			return 0, fmt.Errorf("time segment %q out of range", seg)
+		}
+
+		tsecs = tsecs*60 + val
+	}
+
+	return tsecs, nil
+}
+
+// SecondsToClockTime receives a time in seconds and
+// returns it clock-formatted (1:30, 1:02:03).
+func SecondsToClockTime(t int) string {
+	if t < 0 {
+		t = 0
+	}
+
+	hrs := t / 3600
+	mins := (t % 3600) / 60
+	secs := t % 60
+	if hrs > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hrs, mins, secs)
+	}
+	return fmt.Sprintf("%d:%02d", mins, secs)
+}
+
 // CommandAction returns an "action" string from a given
 // command root and command args.
 func CommandAction(root string, args []string) string {
 	return root + "/" + strings.Join(args, "/")
 }
+
+// SplitCommandArgs splits a command string into its root and arguments,
+// shell-style. Runs of whitespace separate arguments; a single- or
+// double-quoted phrase at the start of an argument is kept together
+// (quotes inside a word, as in "don't", are literal); and a backslash
+// escapes the character that follows it, including spaces and quotes.
+func SplitCommandArgs(command string) ([]string, error) {
+	args := []string{}
+
+	var current []rune
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			current = append(current, r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			current = append(current, r)
+		case (r == '"' || r == '\'') && len(current) == 0 && !inArg:
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, string(current))
+				current = nil
+				inArg = false
+			}
+		default:
+			current = append(current, r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("error: unterminated %c quote in command", quote)
+	}
+	if escaped {
+		current = append(current, '\\')
+	}
+	if inArg {
+		args = append(args, string(current))
+	}
+
+	return args, nil
+}
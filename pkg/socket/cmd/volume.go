@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"log"
 	"strconv"
 
 	"fmt"
 
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	sockutil "github.com/juanvallejo/streaming-server/pkg/socket/util"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
 )
 
@@ -16,8 +18,12 @@ type VolumeCmd struct {
 
 const (
 	VOLUME_NAME        = "volume"
-	VOLUME_DESCRIPTION = "increase, decrease, or set a volume value"
-	VOLUME_USAGE       = "Usage: /" + VOLUME_NAME
+	VOLUME_DESCRIPTION = "increase, decrease, or set a volume value, or the volume suggested to everyone in the room"
+	VOLUME_USAGE       = "Usage: /" + VOLUME_NAME + " &lt;[+|-]level|room [level|off]&gt;"
+
+	// ROOM_VOLUME_ACTION is authorized for users who may
+	// set the volume suggested to everyone in their room
+	ROOM_VOLUME_ACTION = "roomvolume"
 )
 
 var (
@@ -29,6 +35,10 @@ func (h *VolumeCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 		return h.usage, nil
 	}
 
+	if args[0] == "room" {
+		return h.setRoomVolume(cmdHandler, args[1:], user, playbackHandler)
+	}
+
 	rawVol := args[0]
 	modifier := string(rawVol[0])
 	if modifier == "+" || modifier == "-" {
@@ -60,6 +70,67 @@ func (h *VolumeCmd) Execute(cmdHandler SocketCommandHandler, args []string, user
 	return fmt.Sprintf("Setting volume to %v...", newVol), nil
 }
 
+// setRoomVolume sets, clears, or shows the volume suggested
+// to everyone in the given user's room. Changes are broadcast
+// in a streamsync, so that every client may apply them.
+func (h *VolumeCmd) setRoomVolume(cmdHandler SocketCommandHandler, args []string, user *client.Client, playbackHandler playback.PlaybackHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to set the room volume with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to set its volume.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if len(args) == 0 {
+		if level, hasVolume := sPlayback.Volume(); hasVolume {
+			return fmt.Sprintf("the room's suggested volume is %v.", level), nil
+		}
+		return "the room does not suggest a volume.", nil
+	}
+
+	if decision := Authorize(cmdHandler.Authorizer(), user, ROOM_VOLUME_ACTION, playbackHandler); !decision.Allowed {
+		return "", decision.Err()
+	}
+
+	var announcement, output string
+	if args[0] == "off" {
+		sPlayback.ClearVolume()
+		announcement = fmt.Sprintf("%q has cleared the room's suggested volume", username)
+		output = "the room no longer suggests a volume."
+	} else {
+		level, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", fmt.Errorf("error: volume must be an integer between %v and %v", playback.MinRoomVolume, playback.MaxRoomVolume)
+		}
+		if err := sPlayback.SetVolume(level); err != nil {
+			return "", fmt.Errorf("error: %v", err)
+		}
+		announcement = fmt.Sprintf("%q has set the room's suggested volume to %v", username, level)
+		output = fmt.Sprintf("the room's suggested volume is now %v.", level)
+	}
+
+	res := &client.Response{
+		Id:   user.UUID(),
+		From: username,
+	}
+
+	err := sockutil.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		return "", err
+	}
+
+	user.BroadcastAll("streamsync", res)
+	user.BroadcastSystemMessageFrom(announcement)
+	return output, nil
+}
+
 func NewCmdVolume() SocketCommand {
 	return &VolumeCmd{
 		Command{
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestRoomVolume(t *testing.T) {
+	rooms := newTestRooms(t)
+	admin, conn := rooms.joinWithConnection("a", "admin", rbac.ADMIN_ROLE)
+	viewer := rooms.join("a", "viewer", rbac.VIEWER_ROLE)
+
+	ns, _ := admin.Namespace()
+	sPlayback, _ := rooms.playbackHandler.PlaybackByNamespace(ns)
+
+	tests := []struct {
+		name               string
+		args               []string
+		viewer             bool
+		expectErr          bool
+		expectUnauthorized bool
+		expectVolume       *int
+	}{
+		{
+			name:         "set the room's volume",
+			args:         []string{"room", "40"},
+			expectVolume: newVolume(40),
+		},
+		{
+			name:         "volume is limited to 100",
+			args:         []string{"room", "101"},
+			expectErr:    true,
+			expectVolume: newVolume(40),
+		},
+		{
+			name:         "volume may not be negative",
+			args:         []string{"room", "-1"},
+			expectErr:    true,
+			expectVolume: newVolume(40),
+		},
+		{
+			name:               "viewers may not set the room's volume",
+			args:               []string{"room", "10"},
+			viewer:             true,
+			expectErr:          true,
+			expectUnauthorized: true,
+			expectVolume:       newVolume(40),
+		},
+		{
+			name: "clear the room's volume",
+			args: []string{"room", "off"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := admin
+			if tc.viewer {
+				user = viewer
+			}
+			broadcasts := len(conn.broadcasts)
+
+			_, err := rooms.execute(user, VOLUME_NAME, tc.args...)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if errors.Is(err, ErrNotAuthorized) != tc.expectUnauthorized {
+				t.Fatalf("expected unauthorized: %v, got %v", tc.expectUnauthorized, err)
+			}
+
+			status := sPlayback.GetStatus().(*playback.PlaybackStatus)
+			if (status.Volume == nil) != (tc.expectVolume == nil) || (status.Volume != nil && *status.Volume != *tc.expectVolume) {
+				t.Errorf("expected status volume %v, got %v", tc.expectVolume, status.Volume)
+			}
+
+			if tc.expectErr {
+				if len(conn.broadcasts) != broadcasts {
+					t.Errorf("expected nothing to be broadcast, got %v", conn.broadcasts[broadcasts:])
+				}
+				return
+			}
+			if len(conn.broadcasts) != broadcasts+1 || conn.broadcasts[broadcasts].Event != "streamsync" {
+				t.Fatalf("expected a single streamsync to be broadcast, got %v", conn.broadcasts[broadcasts:])
+			}
+			volume, hasVolume := conn.broadcasts[broadcasts].Data.Extra["volume"].(float64)
+			if hasVolume != (tc.expectVolume != nil) || (hasVolume && int(volume) != *tc.expectVolume) {
+				t.Errorf("expected streamsync volume %v, got %v", tc.expectVolume, conn.broadcasts[broadcasts].Data.Extra["volume"])
+			}
+		})
+	}
+}
+
+func newVolume(level int) *int {
+	return &level
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type VoteCmd struct {
+	Command
+}
+
+const (
+	VOTE_NAME        = "vote"
+	VOTE_DESCRIPTION = "votes for an option in the room's poll"
+	VOTE_USAGE       = "Usage: /" + VOTE_NAME + " &lt;option number&gt;"
+)
+
+var (
+	vote_aliases = []string{}
+)
+
+func (h *VoteCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to vote in a poll with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to vote in a poll.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	poll, exists := sPlayback.Poll()
+	if !exists {
+		return "", fmt.Errorf("error: your room has no active poll")
+	}
+
+	option, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", fmt.Errorf("%v", h.usage)
+	}
+
+	if err := poll.Vote(user.UUID(), option); err != nil {
+		return "", fmt.Errorf("error: %v", err)
+	}
+
+	if err := broadcastPollEvent(user, "pollstate", poll.State()); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("you have voted for %q.", poll.Options()[option-1]), nil
+}
+
+func NewCmdVote() SocketCommand {
+	return &VoteCmd{
+		Command{
+			name:        VOTE_NAME,
+			description: VOTE_DESCRIPTION,
+			usage:       VOTE_USAGE,
+
+			aliases: vote_aliases,
+		},
+	}
+}
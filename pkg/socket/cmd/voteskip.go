@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+type VoteSkipCmd struct {
+	Command
+}
+
+const (
+	VOTESKIP_NAME        = "voteskip"
+	VOTESKIP_DESCRIPTION = "votes to skip the currently-playing stream"
+	VOTESKIP_USAGE       = "Usage: /" + VOTESKIP_NAME
+)
+
+var (
+	voteskip_aliases = []string{"vs"}
+)
+
+func (h *VoteSkipCmd) Execute(cmdHandler SocketCommandHandler, args []string, user *client.Client, clientHandler client.SocketClientHandler, playbackHandler playback.PlaybackHandler, streamHandler stream.StreamHandler) (string, error) {
+	username := user.GetUsernameOrId()
+
+	userRoom, hasRoom := user.Namespace()
+	if !hasRoom {
+		log.Printf("ERR SOCKET CLIENT client with id %q (%s) attempted to vote to skip a stream with no room assigned", user.UUID(), username)
+		return "", fmt.Errorf("error: you must be in a room to vote to skip a stream.")
+	}
+
+	sPlayback, sPlaybackExists := playbackHandler.PlaybackByNamespace(userRoom)
+	if !sPlaybackExists {
+		log.Printf("ERR SOCKET CLIENT unable to associate client %q (%s) in room %q with any stream playback objects", user.UUID(), username, userRoom)
+		return "", fmt.Errorf("error: no stream playback is currently loaded for your room")
+	}
+
+	if _, exists := sPlayback.GetStream(); !exists {
+		return "", fmt.Errorf("error: no stream is currently loaded for your room")
+	}
+
+	if !sPlayback.AddSkipVote(user.UUID()) {
+		return "", fmt.Errorf("error: you have already voted to skip this stream")
+	}
+
+	votes := sPlayback.SkipVotes()
+	required := playback.RequiredSkipVotes(len(user.Connections()))
+
+	user.BroadcastAll("voteskip", &client.Response{
+		Id:   user.UUID(),
+		From: username,
+		Extra: map[string]interface{}{
+			"current":  votes,
+			"required": required,
+		},
+	})
+
+	if votes < required {
+		user.BroadcastSystemMessageFrom(fmt.Sprintf("%q has voted to skip the current stream (%d/%d)", username, votes, required))
+		return fmt.Sprintf("you have voted to skip the current stream (%d/%d)", votes, required), nil
+	}
+
+	err := AdvancePlayback(user, sPlayback)
+	if err != nil {
+		return "", fmt.Errorf("error: unable to skip the current stream: %v", err)
+	}
+
+	user.BroadcastSystemMessageFrom(fmt.Sprintf("the room has voted to skip the current stream (%d/%d)", votes, required))
+	return fmt.Sprintf("the room has voted to skip the current stream (%d/%d)", votes, required), nil
+}
+
+func NewCmdVoteSkip() SocketCommand {
+	return &VoteSkipCmd{
+		Command{
+			name:        VOTESKIP_NAME,
+			description: VOTESKIP_DESCRIPTION,
+			usage:       VOTESKIP_USAGE,
+
+			aliases: voteskip_aliases,
+		},
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"sync"
+	"sync/atomic"
 	"net/http"
 	"time"
 
@@ -62,19 +64,41 @@ type Message struct {
 
 type ConnectionMetadata interface {
 	CreationTimestamp() time.Time
+	// LastActivity returns the time the connection
+	// last received an event from its client
+	LastActivity() time.Time
+	// UpdateLastActivity marks the connection as active
+	UpdateLastActivity()
 }
 
 type ConnectionMetadataSpec struct {
 	creationTimestamp time.Time
+
+	activityMutex sync.Mutex
+	lastActivity  time.Time
 }
 
 func (m *ConnectionMetadataSpec) CreationTimestamp() time.Time {
 	return m.creationTimestamp
 }
 
+func (m *ConnectionMetadataSpec) LastActivity() time.Time {
+	m.activityMutex.Lock()
+	defer m.activityMutex.Unlock()
+	return m.lastActivity
+}
+
+func (m *ConnectionMetadataSpec) UpdateLastActivity() {
+	m.activityMutex.Lock()
+	defer m.activityMutex.Unlock()
+	m.lastActivity = time.Now()
+}
+
 func NewConnectionMetadata() ConnectionMetadata {
+	now := time.Now()
 	return &ConnectionMetadataSpec{
-		creationTimestamp: time.Now(),
+		creationTimestamp: now,
+		lastActivity:      now,
 	}
 }
 
@@ -87,8 +111,16 @@ type Connection interface {
 	// BroadcastFrom behaves like Broadcast, except the connection id provided
 	// is skipped from any effects or mutations taken by the handler's method.
 	BroadcastFrom(string, string, []byte)
+	// Close closes the underlying socket connection
+	Close() error
 	// Metadata returns ConnectionMetadata for the current connection
 	Metadata() ConnectionMetadata
+	// MissedPongs returns the number of consecutive heartbeat
+	// pings the client has not answered with a pong
+	MissedPongs() int
+	// Ping sends a heartbeat ping to the client. The ping
+	// counts as missed until the client answers with a pong.
+	Ping() error
 	// Connections returns socket connections that are in the same namespace as the connection
 	Connections() []Connection
 	// Emit iterates through all stored SocketEventCallback functions and calls
@@ -119,6 +151,20 @@ type Connection interface {
 	WriteMessage(int, []byte) error
 }
 
+// RemoteHost returns the host (without the port)
+// the given connection was made from
+func RemoteHost(conn Connection) string {
+	host, _, err := net.SplitHostPort(conn.Request().RemoteAddr)
+	if err != nil {
+		return conn.Request().RemoteAddr
+	}
+
+	return host
+}
+
+// pingWriteTimeout is the amount of time allowed to send a heartbeat ping
+const pingWriteTimeout = 10 * time.Second
+
 // Socket composes a websocket.Conn and implements Connection
 type SocketConn struct {
 	*websocket.Conn
@@ -131,6 +177,10 @@ type SocketConn struct {
 	nsHandler  NamespaceHandler
 	ns         string
 
+	// missedPongs is the number of heartbeat pings sent
+	// since the client last answered with a pong
+	missedPongs int32
+
 	mutex sync.Mutex
 }
 
@@ -210,6 +260,15 @@ func (c *SocketConn) WriteMessage(messageType int, data []byte) error {
 	return c.Conn.WriteMessage(messageType, data)
 }
 
+func (c *SocketConn) MissedPongs() int {
+	return int(atomic.LoadInt32(&c.missedPongs))
+}
+
+func (c *SocketConn) Ping() error {
+	atomic.AddInt32(&c.missedPongs, 1)
+	return c.Conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingWriteTimeout))
+}
+
 func (c *SocketConn) ResponseWriter() http.ResponseWriter {
 	return c.respWriter
 }
@@ -229,7 +288,7 @@ func NewConnection(nsHandler NamespaceHandler, ws *websocket.Conn, w http.Respon
 }
 
 func NewConnectionWithUUID(uuid string, nsHandler NamespaceHandler, ws *websocket.Conn, w http.ResponseWriter, r *http.Request) Connection {
-	return &SocketConn{
+	c := &SocketConn{
 		Conn: ws,
 
 		metadata:   NewConnectionMetadata(),
@@ -239,4 +298,11 @@ func NewConnectionWithUUID(uuid string, nsHandler NamespaceHandler, ws *websocke
 		callbacks:  make(map[string][]SocketEventCallback),
 		nsHandler:  nsHandler,
 	}
+
+	// pongs are handled while reading the connection's messages
+	ws.SetPongHandler(func(string) error {
+		atomic.StoreInt32(&c.missedPongs, 0)
+		return nil
+	})
+	return c
 }
@@ -102,6 +102,12 @@ func NewHandlerWithRBAC(authorizer rbac.Authorizer, nsHandler NamespaceHandler)
 	}
 }
 
+// passiveEvents are sent automatically by clients,
+// and do not count towards a connection's activity
+var passiveEvents = map[string]bool{
+	"pong": true,
+}
+
 func HandleConnection(handler ConnectionHandler, conn Connection) {
 	for {
 		var connClosed bool
@@ -131,6 +137,9 @@ func HandleConnection(handler ConnectionHandler, conn Connection) {
 				continue
 			}
 
+			if !passiveEvents[message.Event] {
+				conn.Metadata().UpdateLastActivity()
+			}
 			conn.Emit(message.Event, message.Data)
 			continue
 		}
@@ -0,0 +1,111 @@
+package connection
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/pubsub"
+)
+
+// RelayChannelPrefix prefixes the name of the channel
+// each namespace's broadcasts are published to
+var RelayChannelPrefix = "streaming-server:namespace:"
+
+// relayMessage is a serializable schema describing
+// a broadcast published to other server instances
+type relayMessage struct {
+	Origin      string `json:"origin"`
+	MessageType int    `json:"messageType"`
+	Event       string `json:"event"`
+	ExcludeId   string `json:"excludeId,omitempty"`
+	Data        []byte `json:"data"`
+}
+
+// RelayNamespaceHandler implements NamespaceHandler. Broadcasts are sent
+// to local connections, and published through a pubsub.Broker so that
+// other server instances subscribed to the same broker can relay them
+// to their own connections in the same namespace.
+type RelayNamespaceHandler struct {
+	NamespaceHandler
+
+	broker pubsub.Broker
+	id     string
+}
+
+func (h *RelayNamespaceHandler) Broadcast(messageType int, ns, eventName string, data []byte) {
+	h.NamespaceHandler.Broadcast(messageType, ns, eventName, data)
+	h.publish(ns, &relayMessage{
+		MessageType: messageType,
+		Event:       eventName,
+		Data:        data,
+	})
+}
+
+func (h *RelayNamespaceHandler) BroadcastFrom(messageType int, connId, ns, eventName string, data []byte) {
+	h.NamespaceHandler.BroadcastFrom(messageType, connId, ns, eventName, data)
+	h.publish(ns, &relayMessage{
+		MessageType: messageType,
+		Event:       eventName,
+		ExcludeId:   connId,
+		Data:        data,
+	})
+}
+
+func (h *RelayNamespaceHandler) publish(ns string, m *relayMessage) {
+	m.Origin = h.id
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("ERR SOCKET CONN NAMESPACE unable to serialize %q broadcast for namespace %q: %v", m.Event, ns, err)
+		return
+	}
+
+	if err := h.broker.Publish(RelayChannelPrefix+ns, b); err != nil {
+		log.Printf("ERR SOCKET CONN NAMESPACE unable to relay %q broadcast for namespace %q: %v", m.Event, ns, err)
+	}
+}
+
+// relay sends a broadcast published by another server
+// instance to local connections in its namespace
+func (h *RelayNamespaceHandler) relay(channel string, data []byte) {
+	m := &relayMessage{}
+	if err := json.Unmarshal(data, m); err != nil {
+		log.Printf("ERR SOCKET CONN NAMESPACE unable to de-serialize relayed broadcast on channel %q: %v", channel, err)
+		return
+	}
+
+	// broadcasts published by this instance were already sent
+	if m.Origin == h.id {
+		return
+	}
+
+	ns := strings.TrimPrefix(channel, RelayChannelPrefix)
+	if len(m.ExcludeId) > 0 {
+		h.NamespaceHandler.BroadcastFrom(m.MessageType, m.ExcludeId, ns, m.Event, m.Data)
+		return
+	}
+	h.NamespaceHandler.Broadcast(m.MessageType, ns, m.Event, m.Data)
+}
+
+// NewRelayNamespaceHandler wraps the given NamespaceHandler, relaying
+// broadcasts to and from other server instances through the given broker.
+// Returns an error if the broker's channels cannot be subscribed to.
+func NewRelayNamespaceHandler(nsHandler NamespaceHandler, broker pubsub.Broker) (NamespaceHandler, error) {
+	id, err := util.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate relay instance id: %v", err)
+	}
+
+	h := &RelayNamespaceHandler{
+		NamespaceHandler: nsHandler,
+		broker:           broker,
+		id:               id,
+	}
+
+	if err := broker.PSubscribe(RelayChannelPrefix+"*", h.relay); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
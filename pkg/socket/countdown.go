@@ -0,0 +1,39 @@
+package socket
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/util"
+)
+
+// handleCountdownTick notifies a room of the time remaining until its
+// current stream starts, and syncs the room once the stream has started.
+func (h *Handler) handleCountdownTick(namespace connection.Namespace, remaining int) {
+	if remaining > 0 {
+		h.BroadcastToNamespace(namespace, "streamstarting", &client.Response{
+			From: client.USER_SYSTEM,
+			Extra: map[string]interface{}{
+				"remaining": remaining,
+			},
+		})
+		return
+	}
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(namespace)
+	if !exists {
+		h.logger.Errorf("COUNTDOWN SOCKET CLIENT attempted to send streamsync event to client, but stream playback does not exist.")
+		return
+	}
+
+	res := &client.Response{
+		From: client.USER_SYSTEM,
+	}
+
+	err := util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		h.logger.Errorf("COUNTDOWN SOCKET CLIENT unable to serialize playback status: %v", err)
+		return
+	}
+
+	h.BroadcastToNamespace(namespace, "streamsync", res)
+}
@@ -3,21 +3,30 @@ package socket
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/gorilla/websocket"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
+	"github.com/juanvallejo/streaming-server/pkg/logging"
 	"github.com/juanvallejo/streaming-server/pkg/playback"
 	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
 	playbackutil "github.com/juanvallejo/streaming-server/pkg/playback/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/auth"
 	"github.com/juanvallejo/streaming-server/pkg/socket/client"
 	"github.com/juanvallejo/streaming-server/pkg/socket/cmd"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	cmdutil "github.com/juanvallejo/streaming-server/pkg/socket/cmd/util"
 	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	connutil "github.com/juanvallejo/streaming-server/pkg/socket/connection/util"
 	socketserver "github.com/juanvallejo/streaming-server/pkg/socket/server"
 	"github.com/juanvallejo/streaming-server/pkg/socket/util"
 	"github.com/juanvallejo/streaming-server/pkg/stream"
+	"github.com/juanvallejo/streaming-server/pkg/webhook"
 )
 
 type Handler struct {
@@ -26,24 +35,120 @@ type Handler struct {
 	PlaybackHandler playback.PlaybackHandler
 	StreamHandler   stream.StreamHandler
 
-	server *socketserver.Server
+	nsHandler   connection.NamespaceHandler
+	server      *socketserver.Server
+	imageProber *ImageProber
+	previewer   *LinkPreviewer
+	sessions    *sessionStore
+	bindings    *rbac.BindingStore
+	logger      logging.Logger
+	shutdown    *shutdownState
+	stateFile   string
+	// authenticator verifies the tokens connections present, if set
+	authenticator auth.Validator
+	// operatorAuthenticator verifies the tokens operators present, if set
+	operatorAuthenticator auth.Validator
 }
 
+// MaxChatMessageLength is the maximum number of characters
+// in a chat message. Longer messages are not broadcast.
+var MaxChatMessageLength = DEFAULT_MAX_CHAT_MESSAGE_LENGTH
+
+// StripChatVideoUrls determines whether video urls embedded
+// from chat messages are removed from the message text.
+var StripChatVideoUrls = false
+
 const (
 	ROOM_DEFAULT_STREAMSYNC_RATE         = 10 // seconds to wait before emitting streamsync to clients
 	ROOM_DEFAULT_STREAMSYNC_LOGGING_RATE = 50
+
+	DEFAULT_MAX_CHAT_MESSAGE_LENGTH = 2000
 )
 
 func (h *Handler) HandleClientConnection(conn connection.Connection) {
-	log.Printf("INF SOCKET CONN client (%s) has connected with id %q\n", conn.Request().RemoteAddr, conn.UUID())
+	h.logger.Infof("SOCKET CONN client (%s) has connected with id %q\n", conn.Request().RemoteAddr, conn.UUID())
+
+	if h.IsShuttingDown() {
+		h.logger.Infof("SOCKET CONN refusing client (%s) with id %q: server is shutting down\n", conn.Request().RemoteAddr, conn.UUID())
+		h.refuseConnection(conn, "info_clienterror", &client.Response{
+			ErrMessage: "error: " + ShutdownNotice,
+			IsSystem:   true,
+		})
+		return
+	}
+
+	identity, err := h.authenticate(conn)
+	if err != nil {
+		h.logger.Infof("SOCKET CONN refusing unauthenticated client (%s) with id %q: %v\n", conn.Request().RemoteAddr, conn.UUID(), err)
+		h.refuseConnection(conn, "unauthorized", &client.Response{
+			ErrMessage: "error: " + err.Error(),
+			IsSystem:   true,
+		})
+		return
+	}
+
+	if h.isBanned(conn) {
+		h.logger.Infof("SOCKET CONN refusing banned client (%s) with id %q\n", conn.Request().RemoteAddr, conn.UUID())
+		h.refuseConnection(conn, "info_clienterror", &client.Response{
+			ErrMessage: "error: you have been banned from this room",
+			IsSystem:   true,
+		})
+		return
+	}
+
+	// capacity is checked first, so that invites are
+	// not used up by clients who cannot join anyway
+	if ns, full := h.isRoomFull(conn); full {
+		h.logger.Infof("SOCKET CONN refusing client (%s) with id %q: room %q is full\n", conn.Request().RemoteAddr, conn.UUID(), ns.Name())
+		h.refuseConnection(conn, "roomfull", &client.Response{
+			ErrMessage: "error: this room is full",
+			IsSystem:   true,
+			Extra: map[string]interface{}{
+				"room": ns.Name(),
+			},
+		})
+		return
+	}
+
+	if ns, protected := h.requiresPassword(conn); protected {
+		h.logger.Infof("SOCKET CONN refusing client (%s) with id %q: missing or incorrect room password\n", conn.Request().RemoteAddr, conn.UUID())
+		h.refuseConnection(conn, "authchallenge", &client.Response{
+			ErrMessage: "error: this room requires a password",
+			IsSystem:   true,
+			Extra: map[string]interface{}{
+				"room":        ns.Name(),
+				"param":       query.ROOM_PASSWORD_KEY,
+				"inviteParam": query.ROOM_INVITE_KEY,
+			},
+		})
+		return
+	}
 
 	h.RegisterClient(conn)
-	log.Printf("INF SOCKET currently %v clients registered\n", h.clientHandler.GetClientSize())
+	if identity != nil {
+		h.applyIdentity(conn, identity)
+	}
+	h.startSession(conn)
+	h.logger.Infof("SOCKET currently %v clients registered\n", h.clientHandler.GetClientSize())
+
+	if c, err := h.clientHandler.GetClient(conn.UUID()); err == nil {
+		h.dispatchUserEvent(c, webhook.EVENT_USER_JOINED)
+	}
+
+	if ns, exists := conn.Namespace(); exists {
+		h.recordViewerCount(ns)
+	}
 
 	conn.On("disconnection", func(data connection.MessageDataCodec) {
-		log.Printf("INF DCONN SOCKET client with id %q has disconnected\n", conn.UUID())
+		h.logger.Infof("DCONN SOCKET client with id %q has disconnected\n", conn.UUID())
+
+		room, hasRoom := conn.Namespace()
 
 		if c, err := h.clientHandler.GetClient(conn.UUID()); err == nil {
+			// hold the client's state in case it resumes its session
+			h.suspendSession(c)
+			h.dispatchUserEvent(c, webhook.EVENT_USER_LEFT)
+
 			userName, exists := c.GetUsername()
 			if !exists {
 				userName = c.UUID()
@@ -55,16 +160,18 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 
 			ns, exists := c.Namespace()
 			if exists {
+				authorizer := h.CommandHandler.Authorizer()
+
 				sPlayback, sPlaybackExists := h.PlaybackHandler.PlaybackByNamespace(ns)
 				if sPlaybackExists {
 					// update room's last updated time to give buffer
 					// between last client leaving and room reaping.
 					sPlayback.SetLastUpdated(time.Now())
+					sPlayback.HandleDisconnection(c.Connection(), authorizer, h.clientHandler)
 				}
 
-				// remove user from authorizer role-bindings
-				authorizer := h.CommandHandler.Authorizer()
-				sPlayback.HandleDisconnection(c.Connection(), authorizer, h.clientHandler)
+				// remove user from authorizer role-bindings, even
+				// if the room's playback no longer exists
 				if authorizer != nil {
 					for _, b := range authorizer.Bindings() {
 						b.RemoveSubject(c.Connection())
@@ -73,8 +180,14 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 			}
 		}
 
+		h.CommandHandler.ClearCooldowns(conn.UUID())
 		if err := h.DeregisterClient(conn); err != nil {
-			log.Printf("ERR SOCKET %v", err)
+			h.logger.Errorf("SOCKET %v", err)
+		}
+
+		if hasRoom {
+			h.recordViewerCount(room)
+			h.updateRoomBuffering(room)
 		}
 	})
 
@@ -82,74 +195,115 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 	conn.On("request_updateusername", func(data connection.MessageDataCodec) {
 		messageData, ok := data.(connection.MessageData)
 		if !ok {
-			log.Printf("ERR SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_chatmessage")
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_chatmessage")
 			return
 		}
 
 		rawUsername, ok := messageData.Key("user")
 		if !ok {
-			log.Printf("ERR SOCKET CLIENT client %q sent malformed request to update username. Ignoring request.", conn.UUID())
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request to update username. Ignoring request.", conn.UUID())
 			return
 		}
 
 		username, ok := rawUsername.(string)
 		if !ok {
-			log.Printf("ERR SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "username")
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "username")
 			return
 		}
 
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT %v. Broadcasting as info_clienterror event", err)
+			h.logger.Errorf("SOCKET CLIENT %v. Broadcasting as info_clienterror event", err)
 			c.BroadcastErrorTo(err)
 			return
 		}
 
 		err = util.UpdateClientUsername(c, username, h.clientHandler)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT %v. Broadcasting as \"info_clienterror\" event", err)
+			h.logger.Errorf("SOCKET CLIENT %v. Broadcasting as \"info_clienterror\" event", err)
 			c.BroadcastErrorTo(err)
 			return
 		}
 	})
 
+	// this event is received when a client replies to a "ping" event
+	conn.On("pong", func(data connection.MessageDataCodec) {
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "pong")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT could not retrieve client. Ignoring pong: %v", err)
+			return
+		}
+
+		rawTimestamp, ok := messageData.Key("timestamp")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a pong with no timestamp. Ignoring.", conn.UUID())
+			return
+		}
+
+		timestamp, ok := rawTimestamp.(float64)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-numeric value for the field %q", conn.UUID(), "timestamp")
+			return
+		}
+
+		if _, err := c.RecordPong(int64(timestamp)); err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to record pong for client %q: %v", conn.UUID(), err)
+		}
+	})
+
 	// this event is received when a client is requesting to broadcast a chat message
 	conn.On("request_chatmessage", func(data connection.MessageDataCodec) {
 		messageData, ok := data.(connection.MessageData)
 		if !ok {
-			log.Printf("ERR SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_chatmessage")
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_chatmessage")
 			return
 		}
 
 		username, ok := messageData.Key("user")
 		if ok {
-			log.Printf("INF SOCKET CLIENT client with id %q requested a chat message broadcast with name %q", conn.UUID(), username)
+			h.logger.Infof("SOCKET CLIENT client with id %q requested a chat message broadcast with name %q", conn.UUID(), username)
 		}
 
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT could not retrieve client. Ignoring request_chatmessage request: %v", err)
+			h.logger.Errorf("SOCKET CLIENT could not retrieve client. Ignoring request_chatmessage request: %v", err)
 			return
 		}
 
 		command, isCommand, err := h.ParseCommandMessage(c, messageData)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to parse client chat message as command: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to parse client chat message as command: %v", err)
 			c.BroadcastSystemMessageTo(err.Error())
 			return
 		}
 
 		if isCommand {
-			cmdSegments := strings.Split(command, " ")
+			cmdSegments, err := cmdutil.SplitCommandArgs(command)
+			if err != nil {
+				h.logger.Errorf("SOCKET CLIENT unable to parse command for client id (%q): %v", conn.UUID(), err)
+				c.BroadcastSystemMessageTo(err.Error())
+				return
+			}
+			if len(cmdSegments) == 0 {
+				return
+			}
+
 			cmdArgs := []string{}
 			if len(cmdSegments) > 1 {
 				cmdArgs = cmdSegments[1:]
 			}
 
-			log.Printf("INF SOCKET CLIENT interpreting chat message as user command %q for client id (%q) with name %q", command, conn.UUID(), username)
+			loggableCommand := cmd.LoggableCommand(h.CommandHandler, cmdSegments[0], cmdArgs)
+			h.logger.Infof("SOCKET CLIENT interpreting chat message as user command %q for client id (%q) with name %q", loggableCommand, conn.UUID(), username)
 			result, err := h.CommandHandler.ExecuteCommand(cmdSegments[0], cmdArgs, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler)
 			if err != nil {
-				log.Printf("ERR SOCKET CLIENT unable to execute command with id %q: %v", command, err)
+				h.logger.Errorf("SOCKET CLIENT unable to execute command with id %q: %v", loggableCommand, err)
 				c.BroadcastSystemMessageTo(err.Error())
 				return
 			}
@@ -160,15 +314,54 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 			return
 		}
 
+		if cmd.IsSpectator(h.CommandHandler.Authorizer(), c, h.PlaybackHandler) {
+			h.logger.Infof("SOCKET CLIENT dropping chat message from client with id %q: client is a spectator", conn.UUID())
+			c.BroadcastSystemMessageTo("error: spectators may not send chat messages")
+			return
+		}
+
+		if text, ok := messageData.Key("message"); ok {
+			if textStr, ok := text.(string); ok && utf8.RuneCountInString(textStr) > MaxChatMessageLength {
+				h.logger.Infof("SOCKET CLIENT dropping chat message from client with id %q: message exceeds %v characters", conn.UUID(), MaxChatMessageLength)
+				c.BroadcastSystemMessageTo(fmt.Sprintf("error: messages may be at most %v characters long", MaxChatMessageLength))
+				return
+			}
+		}
+
+		if !c.AllowChatMessage() {
+			h.logger.Infof("SOCKET CLIENT dropping chat message from client with id %q: rate limit exceeded", conn.UUID())
+			c.BroadcastSystemMessageTo("error: you are sending messages too quickly - please wait a moment and try again")
+			return
+		}
+
+		if sPlayback, err := h.getPlaybackFromClient(c); err == nil {
+			if remaining, muted := sPlayback.MutedFor(c.Address()); muted {
+				msg := "you have been muted in this room"
+				if remaining > 0 {
+					msg = fmt.Sprintf("%s - you may send messages again in %v", msg, remaining.Round(time.Second))
+				}
+				c.BroadcastSystemMessageTo(msg)
+				return
+			}
+			if !h.isSlowModeExempt(c) {
+				if err := sPlayback.RecordChatMessage(c.UUID()); err != nil {
+					c.BroadcastSystemMessageTo(err.Error())
+					return
+				}
+			}
+		}
+
 		images, err := h.ParseMessageMedia(messageData)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to parse client chat message media: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to parse client chat message media: %v", err)
 			return
 		}
 
+		// only the text of the message is taken from the client; every
+		// other field of the response is set by the server
 		res := &client.Response{
 			Id:    c.UUID(),
-			From:  "system",
+			From:  c.GetUsernameOrId(),
 			Extra: make(map[string]interface{}),
 		}
 
@@ -177,30 +370,313 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 			res.Extra["images"] = images
 		}
 
-		b, err := data.Serialize()
+		videos, err := h.ParseMessageVideos(messageData, StripChatVideoUrls)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to serialize client chat message data: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to parse client chat message videos: %v", err)
+			return
+		}
+
+		// if video links could be extracted from message, add to response
+		if len(videos) > 0 {
+			res.Extra["videos"] = videos
+		}
+
+		// the message's text may have had its video urls stripped
+		text, _ := messageData.Key("message")
+		if res.Message, ok = text.(string); !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "message")
 			return
 		}
 
-		err = json.Unmarshal(b, res)
+		if sPlayback, err := h.getPlaybackFromClient(c); err == nil {
+			filtered, err := sPlayback.FilterChatMessage(res.Message)
+			if err != nil {
+				h.logger.Infof("SOCKET CLIENT dropping chat message from client with id %q: message contains a filtered word", conn.UUID())
+				c.BroadcastSystemMessageTo(err.Error())
+				return
+			}
+			res.Message = filtered
+		}
+
+		// preview the pages linked to by any remaining urls
+		if h.previewer != nil {
+			if previews := h.previewer.Preview(previewableUrls(res.Message)); len(previews) > 0 {
+				if res.Extra == nil {
+					res.Extra = make(map[string]interface{})
+				}
+				res.Extra["preview"] = previews
+			}
+		}
+
+		// neutralize any markup in the message, now that commands
+		// and media urls have been parsed from its original text
+		sanitizeChatMessage(res)
+
+		// stamp the message with the server's time, overriding
+		// any timestamp value provided by the client
+		if res.Extra == nil {
+			res.Extra = make(map[string]interface{})
+		}
+		res.Extra["timestamp"] = time.Now().UnixNano() / int64(time.Millisecond)
+		res.Extra["color"] = c.Color()
+
+		// give the message a stable id that reactions can refer to
+		messageId, err := connutil.GenerateUUID()
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to de-serialize client chat message into client response: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to generate chat message id: %v", err)
 			return
 		}
+		res.Extra[playback.ChatMessageIdKey] = messageId
+
+		// format the message once, so that every client renders it identically
+		delete(res.Extra, playback.ChatMarkdownKey)
+		if sPlayback, err := h.getPlaybackFromClient(c); err == nil && sPlayback.Markdown() {
+			if segments := ParseChatMarkdown(res.Message); segments != nil {
+				res.Extra[playback.ChatMarkdownKey] = segments
+			}
+		}
+
+		mentioned := h.ParseMessageMentions(c, res.Message)
+		if len(mentioned) > 0 {
+			names := make([]string, 0, len(mentioned))
+			for _, m := range mentioned {
+				names = append(names, m.GetUsernameOrId())
+			}
+			res.Extra["mentions"] = names
+		}
+
+		if sPlayback, err := h.getPlaybackFromClient(c); err == nil {
+			sPlayback.RecordChatHistory(res)
+		}
 
 		c.BroadcastAll("chatmessage", res)
-		fmt.Printf("INF SOCKET CLIENT chatmessage received %v\n", data)
+		chatMessages.Inc()
+		for _, m := range mentioned {
+			m.BroadcastTo("mention", res)
+		}
+		h.logger.Debugf("SOCKET CLIENT chatmessage received %v\n", data)
+	})
+
+	// this event is received when a client edits one of their chat messages
+	conn.On("request_editmessage", func(data connection.MessageDataCodec) {
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_editmessage")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT could not retrieve client. Ignoring request_editmessage request: %v", err)
+			return
+		}
+
+		messageId, ok := h.messageIdFromData(c, messageData)
+		if !ok {
+			return
+		}
+
+		rawText, hasText := messageData.Key("message")
+		text, ok := rawText.(string)
+		if !hasText || !ok || len(strings.TrimSpace(text)) == 0 {
+			c.BroadcastErrorTo(fmt.Errorf("error: an edited message cannot be empty - delete the message instead"))
+			return
+		}
+		if utf8.RuneCountInString(text) > MaxChatMessageLength {
+			c.BroadcastErrorTo(fmt.Errorf("error: messages may be at most %v characters long", MaxChatMessageLength))
+			return
+		}
+
+		if cmd.IsSpectator(h.CommandHandler.Authorizer(), c, h.PlaybackHandler) {
+			c.BroadcastErrorTo(fmt.Errorf("error: spectators may not send chat messages"))
+			return
+		}
+		if !c.AllowChatMessage() {
+			c.BroadcastErrorTo(fmt.Errorf("error: you are sending messages too quickly - please wait a moment and try again"))
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve playback for client %q. Ignoring request_editmessage request: %v", conn.UUID(), err)
+			return
+		}
+		if _, muted := sPlayback.MutedFor(c.Address()); muted {
+			c.BroadcastErrorTo(fmt.Errorf("error: you have been muted in this room"))
+			return
+		}
+
+		msg, exists := sPlayback.ChatMessage(messageId)
+		if !exists {
+			c.BroadcastErrorTo(fmt.Errorf("error: unable to find that message in the chat history"))
+			return
+		}
+		if msg.Id != c.UUID() {
+			h.logger.Infof("SOCKET CLIENT client with id %q attempted to edit message %q sent by client with id %q", conn.UUID(), messageId, msg.Id)
+			c.BroadcastErrorTo(fmt.Errorf("error: you may only edit your own messages"))
+			return
+		}
+
+		text, err = sPlayback.FilterChatMessage(text)
+		if err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		text = SanitizeChatText(text, ChatSanitizeMode)
+		extra := map[string]interface{}{}
+		if sPlayback.Markdown() {
+			if segments := ParseChatMarkdown(text); segments != nil {
+				extra[playback.ChatMarkdownKey] = segments
+			}
+		}
+
+		edited, err := sPlayback.EditChatMessage(messageId, text, extra)
+		if err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		c.BroadcastAll("chatmessageupdate", edited)
+		if sPlayback.IsPinned(messageId) {
+			h.broadcastPinnedMessages(c, sPlayback)
+		}
+	})
+
+	// this event is received when a client deletes a chat message. Clients may delete
+	// their own messages, or anyone's if they are authorized to moderate the chat.
+	conn.On("request_deletemessage", func(data connection.MessageDataCodec) {
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_deletemessage")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT could not retrieve client. Ignoring request_deletemessage request: %v", err)
+			return
+		}
+
+		messageId, ok := h.messageIdFromData(c, messageData)
+		if !ok {
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve playback for client %q. Ignoring request_deletemessage request: %v", conn.UUID(), err)
+			return
+		}
+
+		msg, exists := sPlayback.ChatMessage(messageId)
+		if !exists {
+			c.BroadcastErrorTo(fmt.Errorf("error: unable to find that message in the chat history"))
+			return
+		}
+		if msg.Id != c.UUID() {
+			if err := h.authorizeAction(c, cmd.CHAT_DELETE_ACTION); err != nil {
+				c.BroadcastErrorTo(err)
+				return
+			}
+		}
+
+		wasPinned := sPlayback.IsPinned(messageId)
+		deleted, exists := sPlayback.DeleteChatMessage(messageId)
+		if !exists {
+			c.BroadcastErrorTo(fmt.Errorf("error: unable to find that message in the chat history"))
+			return
+		}
+
+		h.logger.Infof("SOCKET CLIENT client with id %q deleted message %q sent by client with id %q", conn.UUID(), messageId, deleted.Id)
+		c.BroadcastAll("chatmessageupdate", &client.Response{
+			Id:   deleted.Id,
+			From: deleted.From,
+			Extra: map[string]interface{}{
+				playback.ChatMessageIdKey: messageId,
+				playback.ChatDeletedKey:   true,
+				"by":                      c.GetUsernameOrId(),
+			},
+		})
+		if wasPinned {
+			h.broadcastPinnedMessages(c, sPlayback)
+		}
+	})
+
+	// this event is received when a client adds or removes a reaction to a chat message
+	conn.On("request_reaction", func(data connection.MessageDataCodec) {
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_reaction")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT could not retrieve client. Ignoring request_reaction request: %v", err)
+			return
+		}
+
+		rawMessageId, hasMessageId := messageData.Key(playback.ChatMessageIdKey)
+		rawEmoji, hasEmoji := messageData.Key("emoji")
+		if !hasMessageId || !hasEmoji {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a reaction with no message id or emoji. Ignoring.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a message id and an emoji are required"))
+			return
+		}
+
+		messageId, ok := rawMessageId.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), playback.ChatMessageIdKey)
+			return
+		}
+		emoji, ok := rawEmoji.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "emoji")
+			return
+		}
+
+		if err := playback.ValidateReaction(emoji); err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+		if cmd.IsSpectator(h.CommandHandler.Authorizer(), c, h.PlaybackHandler) {
+			c.BroadcastErrorTo(fmt.Errorf("error: spectators may not react to chat messages"))
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve playback for client %q. Ignoring request_reaction request: %v", conn.UUID(), err)
+			return
+		}
+
+		counts, added, err := sPlayback.ToggleChatReaction(messageId, emoji, c.UUID())
+		if err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		c.BroadcastAll("reaction", &client.Response{
+			Id:   c.UUID(),
+			From: c.GetUsernameOrId(),
+			Extra: map[string]interface{}{
+				playback.ChatMessageIdKey: messageId,
+				playback.ChatReactionsKey: counts,
+				"emoji":                   emoji,
+				"added":                   added,
+			},
+		})
 	})
 
 	// this event is received when a client is requesting authorization endpoint information
 	conn.On("request_authorization", func(data connection.MessageDataCodec) {
-		log.Printf("INF SOCKET CLIENT AUTHZ client with id %q requested authorization information", conn.UUID())
+		h.logger.Infof("SOCKET CLIENT AUTHZ client with id %q requested authorization information", conn.UUID())
 
 		// send an httprequest event to the client with authz endpoint information
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
 			return
 		}
 
@@ -209,17 +685,17 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 
 	// this event is received when a client is requesting the current queue state
 	conn.On("request_queuesync", func(data connection.MessageDataCodec) {
-		log.Printf("INF SOCKET CLIENT client with id %q requested a queue-sync", conn.UUID())
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a queue-sync", conn.UUID())
 
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
 			return
 		}
 
 		sPlayback, err := h.getPlaybackFromClient(c)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT %v", err)
+			h.logger.Errorf("SOCKET CLIENT %v", err)
 			c.BroadcastErrorTo(err)
 			return
 		}
@@ -244,17 +720,17 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 
 	// this event is received when a client is requesting the current queue state for a specific Queue stack
 	conn.On("request_stacksync", func(data connection.MessageDataCodec) {
-		log.Printf("INF SOCKET CLIENT client with id %q requested a queue-stack-sync", conn.UUID())
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a queue-stack-sync", conn.UUID())
 
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
 			return
 		}
 
 		sPlayback, err := h.getPlaybackFromClient(c)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT %v", err)
+			h.logger.Errorf("SOCKET CLIENT %v", err)
 			c.BroadcastErrorTo(err)
 			return
 		}
@@ -287,17 +763,17 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 
 	// this event is received when a client is requesting current stream state information
 	conn.On("request_streamsync", func(data connection.MessageDataCodec) {
-		log.Printf("INF SOCKET CLIENT client with id %q requested a streamsync", conn.UUID())
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a streamsync", conn.UUID())
 
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
 			return
 		}
 
 		sPlayback, err := h.getPlaybackFromClient(c)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT %v", err)
+			h.logger.Errorf("SOCKET CLIENT %v", err)
 			c.BroadcastErrorTo(err)
 			return
 		}
@@ -308,7 +784,7 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 
 		err = util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to serialize playback status: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to serialize playback status: %v", err)
 			return
 		}
 
@@ -317,93 +793,944 @@ func (h *Handler) HandleClientConnection(conn connection.Connection) {
 
 	// this event is received when a client is requesting current stream user information
 	conn.On("request_userlist", func(data connection.MessageDataCodec) {
-		log.Printf("INF SOCKET CLIENT client with id %q requested a userlist", conn.UUID())
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a userlist", conn.UUID())
 
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
 			return
 		}
 
 		ns, exists := c.Namespace()
 		if !exists {
-			log.Printf("ERR SOCKET CLIENT client with id %q requested a user list for room, but client is not currently in a room. Broadcasting error...", conn.UUID())
+			h.logger.Errorf("SOCKET CLIENT client with id %q requested a user list for room, but client is not currently in a room. Broadcasting error...", conn.UUID())
 			c.BroadcastErrorTo(fmt.Errorf("error: unable to get user list - you are not currently in a room"))
 			return
 		}
 
 		userList := &client.SerializableClientList{}
+		rolesBySubject := h.rolesBySubject()
 		for _, conn := range c.Connections() {
 			user, err := h.clientHandler.GetClient(conn.UUID())
 			if err != nil {
 				continue
 			}
 
-			roles := []string{}
-			authorizer := h.CommandHandler.Authorizer()
-			if authorizer != nil {
-				for _, b := range authorizer.Bindings() {
-					for _, u := range b.Subjects() {
-						if u.UUID() == conn.UUID() {
-							roles = append(roles, b.Role().Name())
-						}
-					}
-				}
+			roles, hasRoles := rolesBySubject[conn.UUID()]
+			if !hasRoles {
+				roles = []string{}
 			}
 
 			username, _ := user.GetUsername()
 			userList.Clients = append(userList.Clients, client.SerializableClient{
-				Username: username,
-				Id:       user.UUID(),
-				Room:     ns.Name(),
-				Roles:    roles,
+				Username:  username,
+				Id:        user.UUID(),
+				Room:      ns.Name(),
+				Roles:     roles,
+				Quality:   user.Quality(),
+				Color:     user.Color(),
+				Presence:  user.Presence(),
+				Spectator: cmd.IsSpectator(h.CommandHandler.Authorizer(), user, h.PlaybackHandler),
+				Latency:   int64(user.Latency() / time.Millisecond),
 			})
 		}
 
 		c.BroadcastTo("userlist", userList)
 	})
 
-	// this event is received when a client is requesting to update stream state information in the server
-	conn.On("streamdata", func(data connection.MessageDataCodec) {
+	// this event is received when a client is requesting the list of users bound to a given role
+	conn.On("request_rolemembers", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a role member list", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_rolemembers")
+			return
+		}
+
 		c, err := h.clientHandler.GetClient(conn.UUID())
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
 			return
 		}
 
-		ns, exists := c.Namespace()
-		if !exists {
-			log.Printf("ERR SOCKET CLIENT client with id (%q) has no room association. Ignoring streamsync request.", c.UUID())
+		rawRole, ok := messageData.Key("role")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request for role members. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a role name is required"))
 			return
 		}
 
-		sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
-		if !exists {
-			log.Printf("ERR SOCKET CLIENT client with id (%q) requested a streamsync but no Playback could be found associated with that client.", c.UUID())
-			c.BroadcastErrorTo(fmt.Errorf("Warning: could not update stream playback. No room could be detected."))
+		roleName, ok := rawRole.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "role")
 			return
 		}
 
-		s, exists := sPlayback.GetStream()
+		ns, exists := c.Namespace()
 		if !exists {
-			log.Printf("ERR SOCKET CLIENT client with id (%q) sent updated streamdata but no stream could be found associated with the current playback.", c.UUID())
+			h.logger.Errorf("SOCKET CLIENT client with id %q requested role members for room, but client is not currently in a room. Broadcasting error...", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: unable to get role members - you are not currently in a room"))
+			return
+		}
+
+		if h.CommandHandler.Authorizer() == nil {
+			c.BroadcastErrorTo(fmt.Errorf("error: unable to get role members - authorizer not enabled"))
+			return
+		}
+
+		members := &client.SerializableClientList{
+			Clients: []client.SerializableClient{},
+		}
+		rolesBySubject := h.rolesBySubject()
+		for _, conn := range c.Connections() {
+			user, err := h.clientHandler.GetClient(conn.UUID())
+			if err != nil {
+				continue
+			}
+
+			roles := rolesBySubject[conn.UUID()]
+			for _, r := range roles {
+				if r != roleName {
+					continue
+				}
+
+				username, _ := user.GetUsername()
+				members.Clients = append(members.Clients, client.SerializableClient{
+					Username:  username,
+					Id:        user.UUID(),
+					Room:      ns.Name(),
+					Roles:     roles,
+					Quality:   user.Quality(),
+					Color:     user.Color(),
+					Presence:  user.Presence(),
+					Spectator: cmd.IsSpectator(h.CommandHandler.Authorizer(), user, h.PlaybackHandler),
+					Latency:   int64(user.Latency() / time.Millisecond),
+				})
+				break
+			}
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(members, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize role member list: %v", err)
+			return
+		}
+		res.Extra["role"] = roleName
+
+		c.BroadcastTo("rolemembers", res)
+	})
+
+	// this event is received when a client is requesting to know whether it is allowed to perform an action
+	conn.On("request_canido", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested an authorization decision", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_canido")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		rawAction, ok := messageData.Key("action")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request for an authorization decision. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: an action name is required"))
+			return
+		}
+
+		actionName, ok := rawAction.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "action")
+			return
+		}
+
+		decision := cmd.Authorize(h.CommandHandler.Authorizer(), c, cmd.ActionByName(h.CommandHandler, actionName), h.PlaybackHandler)
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(decision, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize authorization decision: %v", err)
+			return
+		}
+
+		c.BroadcastTo("canido", res)
+	})
+
+	// this event is received when a client is requesting to set the chapter list for the current stream
+	conn.On("request_setchapters", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested to set stream chapters", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_setchapters")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		if err := h.authorizeAction(c, "stream/chapters/set"); err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		rawChapters, ok := messageData.Key("chapters")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request to set chapters. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a list of chapters is required"))
+			return
+		}
+
+		chapters, err := parseChapters(rawChapters)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT client %q sent an invalid chapter list: %v", conn.UUID(), err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		if err := sPlayback.SetChapters(chapters); err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to set chapters for client %q: %v", conn.UUID(), err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(&playback.ChapterList{Chapters: sPlayback.Chapters()}, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize chapter list: %v", err)
+			return
+		}
+
+		c.BroadcastAll("chapterlist", res)
+	})
+
+	// this event is received when a client is requesting to seek to a chapter in the current stream
+	conn.On("request_jumpchapter", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested to jump to a stream chapter", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_jumpchapter")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		if err := h.authorizeAction(c, "stream/chapters/jump"); err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		rawTitle, ok := messageData.Key("title")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request to jump to a chapter. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a chapter title is required"))
+			return
+		}
+
+		title, ok := rawTitle.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "title")
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		chapter, exists := sPlayback.ChapterByTitle(title)
+		if !exists {
+			c.BroadcastErrorTo(fmt.Errorf("error: no chapter named %q exists for the current stream", title))
+			return
+		}
+
+		if err := sPlayback.SetTime(chapter.Start); err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to seek to chapter %q: %v", chapter.Title, err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize playback status: %v", err)
+			return
+		}
+
+		c.BroadcastAll("streamsync", res)
+	})
+
+	// this event is received when a client is requesting to queue a segment of a stream
+	conn.On("request_queuetrimmed", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested to queue a trimmed stream", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_queuetrimmed")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		rawUrl, hasUrl := messageData.Key("url")
+		rawStart, hasStart := messageData.Key("start")
+		rawEnd, hasEnd := messageData.Key("end")
+		if !hasUrl || !hasStart || !hasEnd {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request to queue a trimmed stream. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a url, a start time, and an end time are required"))
+			return
+		}
+
+		url, ok := rawUrl.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "url")
+			return
+		}
+
+		start, ok := rawStart.(float64)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-numeric value for the field %q", conn.UUID(), "start")
+			c.BroadcastErrorTo(fmt.Errorf("error: the start time must be a number of seconds"))
+			return
+		}
+
+		end, ok := rawEnd.(float64)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-numeric value for the field %q", conn.UUID(), "end")
+			c.BroadcastErrorTo(fmt.Errorf("error: the end time must be a number of seconds"))
+			return
+		}
+
+		args := []string{"add", url, fmt.Sprintf("%d", int(start)), fmt.Sprintf("%d", int(end))}
+		result, err := h.CommandHandler.ExecuteCommand("queue", args, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to queue trimmed stream %q: %v", url, err)
+			c.BroadcastSystemMessageTo(err.Error())
+			return
+		}
+
+		if len(result) > 0 {
+			c.BroadcastSystemMessageTo(result)
+		}
+	})
+
+	// this event is received when a client is requesting the room's recent viewer counts
+	conn.On("request_viewerhistory", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested viewer history", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_viewerhistory")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		limit := 0
+		if rawLimit, exists := messageData.Key("limit"); exists {
+			if l, ok := rawLimit.(float64); ok {
+				limit = int(l)
+			}
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(&playback.ViewerHistory{Samples: sPlayback.ViewerHistory(limit)}, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize viewer history: %v", err)
+			return
+		}
+
+		c.BroadcastTo("viewerhistory", res)
+	})
+
+	// this event is received when a client is requesting the room's recent chat messages
+	conn.On("request_chathistory", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested chat history", conn.UUID())
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(&playback.ChatHistory{Messages: sPlayback.ChatHistory()}, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize chat history: %v", err)
+			return
+		}
+
+		c.BroadcastTo("chathistory", res)
+	})
+
+	// this event is received when a client is requesting the room's recently played streams
+	conn.On("request_history", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested play history", conn.UUID())
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(sPlayback.PlayHistory(), &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize play history: %v", err)
+			return
+		}
+
+		c.BroadcastTo("history", res)
+	})
+
+	// this event is received when a client is requesting to interrupt the current stream with another
+	conn.On("request_interrupt", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested to interrupt the current stream", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_interrupt")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		if err := h.authorizeAction(c, "stream/interrupt"); err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		rawUrl, ok := messageData.Key("url")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request to interrupt the current stream. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a stream url is required"))
+			return
+		}
+
+		url, ok := rawUrl.(string)
+		if !ok || len(url) == 0 {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "url")
+			c.BroadcastErrorTo(fmt.Errorf("error: a stream url is required"))
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		s, err := sPlayback.GetOrCreateStreamFromUrl(url, c, h.StreamHandler, func(data []byte, created bool, err error) {})
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve interrupting stream %q: %v", url, err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		if err := sPlayback.Interrupt(s); err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+		sPlayback.Play()
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: c.GetUsernameOrId(),
+		}
+
+		err = util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize playback status: %v", err)
+			return
+		}
+
+		c.BroadcastAll("streamload", res)
+		c.BroadcastSystemMessageFrom(fmt.Sprintf("%q has interrupted the current stream with %q", c.GetUsernameOrId(), url))
+	})
+
+	// this event is received when a client is reporting its preferred or actual playback quality
+	conn.On("request_setquality", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a playback quality update", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_setquality")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		preferred := ""
+		if rawPreferred, exists := messageData.Key("preferred"); exists {
+			if preferred, ok = rawPreferred.(string); !ok {
+				h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "preferred")
+				return
+			}
+		}
+
+		actual := ""
+		if rawActual, exists := messageData.Key("actual"); exists {
+			if actual, ok = rawActual.(string); !ok {
+				h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "actual")
+				return
+			}
+		}
+
+		if len(preferred) == 0 && len(actual) == 0 {
+			c.BroadcastErrorTo(fmt.Errorf("error: a preferred or actual quality is required"))
+			return
+		}
+
+		if err := c.SetQuality(preferred, actual); err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to set playback quality for client %q: %v", conn.UUID(), err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(c, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize client info: %v", err)
+			return
+		}
+
+		c.BroadcastTo("quality", res)
+	})
+
+	// this event is received when a client is requesting a shareable token representing the room's state
+	conn.On("request_snapshotlink", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested a room snapshot", conn.UUID())
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		token, err := sPlayback.Snapshot().Token()
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to encode room snapshot: %v", err)
+			c.BroadcastErrorTo(fmt.Errorf("error: unable to create a snapshot of the room"))
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+			Extra: map[string]interface{}{
+				"token": token,
+			},
+		}
+
+		c.BroadcastTo("snapshotlink", res)
+	})
+
+	// this event is received when a client is requesting to populate the room from a snapshot token
+	conn.On("request_loadsnapshot", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested to load a room snapshot", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_loadsnapshot")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		if err := h.authorizeAction(c, "stream/snapshot/load"); err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		rawToken, ok := messageData.Key("token")
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent malformed request to load a snapshot. Ignoring request.", conn.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("error: a snapshot token is required"))
+			return
+		}
+
+		token, ok := rawToken.(string)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", conn.UUID(), "token")
+			return
+		}
+
+		snapshot, err := playback.SnapshotFromToken(token)
+		if err != nil {
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		if err := sPlayback.LoadSnapshot(snapshot, c, h.StreamHandler); err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to load room snapshot: %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(sPlayback.GetQueue(), &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize room queue: %v", err)
+			return
+		}
+
+		c.BroadcastAll("queuesync", res)
+
+		if _, exists := sPlayback.GetStream(); exists {
+			res := &client.Response{
+				Id:   c.UUID(),
+				From: "system",
+			}
+
+			err = util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+			if err != nil {
+				h.logger.Errorf("SOCKET CLIENT unable to serialize playback status: %v", err)
+				return
+			}
+
+			c.BroadcastAll("streamload", res)
+		}
+
+		c.BroadcastSystemMessageFrom(fmt.Sprintf("%q has restored the room from a snapshot", c.GetUsernameOrId()))
+	})
+
+	// this event is received when a client taps the room's reaction hype meter
+	conn.On("request_hype", func(data connection.MessageDataCodec) {
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			return
+		}
+
+		if !sPlayback.HypeMeter().Tap(c.UUID()) {
+			h.logger.Infof("SOCKET CLIENT client with id %q exceeded the hype tap rate limit. Ignoring tap.", conn.UUID())
+		}
+	})
+
+	// this event is received when a client's player starts or stops buffering
+	conn.On("request_buffering", func(data connection.MessageDataCodec) {
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_buffering")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		rawBuffering, exists := messageData.Key("buffering")
+		if !exists {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a buffering report without the field %q", conn.UUID(), "buffering")
+			return
+		}
+
+		buffering, ok := rawBuffering.(bool)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT client %q sent a non-boolean value for the field %q", conn.UUID(), "buffering")
+			return
+		}
+
+		if !c.SetBuffering(buffering) {
+			return
+		}
+
+		if ns, exists := c.Namespace(); exists {
+			h.updateRoomBuffering(ns)
+		}
+	})
+
+	// this event is received when a client is requesting metadata for every item in the room's queue
+	conn.On("request_queuemetadata", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested queue metadata", conn.UUID())
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT %v", err)
+			c.BroadcastErrorTo(err)
+			return
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(sPlayback.QueueMetadata(h.clientHandler), &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize queue metadata: %v", err)
+			return
+		}
+
+		c.BroadcastTo("queuemetadata", res)
+	})
+
+	// this event is received when a client is requesting the streams currently playing in every listed room
+	conn.On("request_livenow", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested the list of live rooms", conn.UUID())
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		live := &playback.NowPlayingList{
+			Rooms: []playback.NowPlaying{},
+		}
+		for _, p := range h.PlaybackHandler.Playbacks() {
+			if nowPlaying, ok := p.NowPlaying(); ok {
+				live.Rooms = append(live.Rooms, nowPlaying)
+			}
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(live, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize live room list: %v", err)
+			return
+		}
+
+		c.BroadcastTo("livenow", res)
+	})
+
+	// this event is received when a client is requesting a summary of every active, public room
+	conn.On("request_roomlist", func(data connection.MessageDataCodec) {
+		h.logger.Infof("SOCKET CLIENT client with id %q requested the list of active rooms", conn.UUID())
+
+		messageData, ok := data.(connection.MessageData)
+		if !ok {
+			h.logger.Errorf("SOCKET CLIENT socket connection event handler for event %q received data of wrong type. Expecting connection.MessageData", "request_roomlist")
+			return
+		}
+
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve user info for connection id %q. No such user associated with id.", conn.UUID())
+			return
+		}
+
+		// rooms with no connected clients are omitted unless requested
+		includeEmpty := false
+		if rawEmpty, exists := messageData.Key("empty"); exists {
+			includeEmpty, _ = rawEmpty.(bool)
+		}
+
+		rooms := &playback.RoomList{
+			Rooms: []playback.RoomSummary{},
+		}
+		for _, p := range h.PlaybackHandler.Playbacks() {
+			if p.IsPrivate() {
+				continue
+			}
+
+			clients := 0
+			if ns, exists := h.nsHandler.NamespaceByName(p.UUID()); exists {
+				clients = len(ns.Connections())
+			}
+			if clients == 0 && !includeEmpty {
+				continue
+			}
+
+			rooms.Rooms = append(rooms.Rooms, p.Summary(clients))
+		}
+
+		res := &client.Response{
+			Id:   c.UUID(),
+			From: "system",
+		}
+
+		err = util.SerializeIntoResponse(rooms, &res.Extra)
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to serialize room list: %v", err)
+			return
+		}
+
+		c.BroadcastTo("roomlist", res)
+	})
+
+	// this event is received when a client is requesting to update stream state information in the server
+	conn.On("streamdata", func(data connection.MessageDataCodec) {
+		c, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to retrieve client from connection id. Ignoring request_streamsync request: %v", err)
+			return
+		}
+
+		ns, exists := c.Namespace()
+		if !exists {
+			h.logger.Errorf("SOCKET CLIENT client with id (%q) has no room association. Ignoring streamsync request.", c.UUID())
+			return
+		}
+
+		sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+		if !exists {
+			h.logger.Errorf("SOCKET CLIENT client with id (%q) requested a streamsync but no Playback could be found associated with that client.", c.UUID())
+			c.BroadcastErrorTo(fmt.Errorf("Warning: could not update stream playback. No room could be detected."))
+			return
+		}
+
+		s, exists := sPlayback.GetStream()
+		if !exists {
+			h.logger.Errorf("SOCKET CLIENT client with id (%q) sent updated streamdata but no stream could be found associated with the current playback.", c.UUID())
 			return
 		}
 
 		jsonData, err := data.Serialize()
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT unable to convert received data map into json string: %v", err)
+			h.logger.Errorf("SOCKET CLIENT unable to convert received data map into json string: %v", err)
 		}
 
-		log.Printf("INF SOCKET CLIENT received streaminfo from client with id (%q). Updating stream information...", c.UUID())
+		h.logger.Infof("SOCKET CLIENT received streaminfo from client with id (%q). Updating stream information...", c.UUID())
 		err = s.SetInfo(jsonData)
 		if err != nil {
-			log.Printf("ERR SOCKET CLIENT error updating stream data: %v", err)
+			h.logger.Errorf("SOCKET CLIENT error updating stream data: %v", err)
 			return
 		}
 	})
 }
 
+// messageImageUrl matches image urls in a chat message. Urls must end in a known
+// image extension (case-insensitive), optionally followed by a query string.
+var messageImageUrl = regexp.MustCompile(`(?i)(https?://[^ ?#]+\.(?:jpg|jpeg|png|gif|webp|svg|bmp|avif)(?:\?[^ #]*)?(?:#[^ ]*)?)(?: |$)`)
+
+// messageMention matches "@username" tokens in a chat message
+var messageMention = regexp.MustCompile(`(?:^|\s)@([^\s@]+)`)
+
 // ParseMessageMedia receives connection.MessageData and parses
 // image urls in the "message" key, removing urls from the
 // text message, and returning them as a slice of strings
@@ -418,18 +1745,133 @@ func (h *Handler) ParseMessageMedia(data connection.MessageData) ([]string, erro
 		return []string{}, fmt.Errorf("error: client message media parse error; unable to cast message to string")
 	}
 
-	re := regexp.MustCompile("(http(s)?://[^ ]+\\.(jpg|png|gif|jpeg))( )?")
-	urls := re.FindAllString(rawText, -1)
-	if urls == nil || len(urls) == 0 {
+	matches := messageImageUrl.FindAllStringSubmatch(rawText, -1)
+	if len(matches) == 0 && h.imageProber == nil {
 		return []string{}, nil
 	}
 
-	newText := re.ReplaceAllString(rawText, "")
-	data.Set("message", newText)
+	urls := make([]string, 0, len(matches))
+	for _, match := range matches {
+		urls = append(urls, match[1])
+	}
+
+	newText := messageImageUrl.ReplaceAllString(rawText, "")
 
+	// probe any remaining urls for images served without a file extension
+	if h.imageProber != nil {
+		candidates := []string{}
+		for _, match := range candidateUrl.FindAllStringSubmatch(newText, -1) {
+			candidates = append(candidates, match[1])
+		}
+
+		for _, url := range h.imageProber.Probe(candidates) {
+			urls = append(urls, url)
+			newText = strings.Replace(newText, url, "", 1)
+		}
+	}
+
+	data.Set("message", newText)
 	return urls, nil
 }
 
+// ParseMessageVideos receives connection.MessageData and parses YouTube and
+// Vimeo urls in the "message" key, returning embed information for each one.
+// If strip is true, video urls are removed from the text message.
+func (h *Handler) ParseMessageVideos(data connection.MessageData, strip bool) ([]*stream.VideoEmbed, error) {
+	message, ok := data.Key("message")
+	if !ok {
+		return []*stream.VideoEmbed{}, fmt.Errorf("error: invalid client message format; message field empty")
+	}
+
+	rawText, ok := message.(string)
+	if !ok {
+		return []*stream.VideoEmbed{}, fmt.Errorf("error: client message video parse error; unable to cast message to string")
+	}
+
+	videos := []*stream.VideoEmbed{}
+	for _, match := range candidateUrl.FindAllStringSubmatch(rawText, -1) {
+		embed, ok := stream.ParseVideoEmbed(match[1])
+		if !ok {
+			continue
+		}
+
+		videos = append(videos, embed)
+		if strip {
+			rawText = strings.Replace(rawText, match[0], "", 1)
+		}
+	}
+
+	if strip && len(videos) > 0 {
+		data.Set("message", rawText)
+	}
+
+	return videos, nil
+}
+
+// ParseMessageMentions receives a client and the text of a chat message sent
+// by that client, and returns every other client in the same room mentioned
+// by an "@username" token. Tokens that do not match a username in the room
+// are ignored.
+func (h *Handler) ParseMessageMentions(c *client.Client, message string) []*client.Client {
+	if !strings.Contains(message, "@") {
+		return []*client.Client{}
+	}
+
+	ns, exists := c.Namespace()
+	if !exists {
+		return []*client.Client{}
+	}
+
+	clientsByName := make(map[string][]*client.Client)
+	for _, conn := range ns.Connections() {
+		roomClient, err := h.clientHandler.GetClient(conn.UUID())
+		if err != nil || roomClient.UUID() == c.UUID() {
+			continue
+		}
+		if name, hasName := roomClient.GetUsername(); hasName {
+			clientsByName[name] = append(clientsByName[name], roomClient)
+		}
+	}
+
+	mentioned := []*client.Client{}
+	seen := make(map[string]bool)
+	for _, match := range messageMention.FindAllStringSubmatch(message, -1) {
+		name := match[1]
+		if _, exists := clientsByName[name]; !exists {
+			// allow mentions followed by punctuation, such as "@user,"
+			name = strings.TrimRight(name, ".,!?:;")
+		}
+		if seen[name] {
+			continue
+		}
+
+		for _, roomClient := range clientsByName[name] {
+			mentioned = append(mentioned, roomClient)
+		}
+		seen[name] = true
+	}
+
+	return mentioned
+}
+
+// SetLogger sets the logger used by the handler,
+// and by the rooms it creates from then on.
+func (h *Handler) SetLogger(logger logging.Logger) {
+	h.logger = logger
+}
+
+// SetImageProber enables probing chat message urls without an image
+// file extension for image content. Probing is disabled if nil.
+func (h *Handler) SetImageProber(prober *ImageProber) {
+	h.imageProber = prober
+}
+
+// SetLinkPreviewer enables previews of the pages linked to
+// by chat message urls. Previews are disabled if nil.
+func (h *Handler) SetLinkPreviewer(previewer *LinkPreviewer) {
+	h.previewer = previewer
+}
+
 // ParseCommandMessage receives a client pointer and a data map sent by a client
 // and determines whether the "message" field in the client data map contains a
 // valid client command. An error is returned if there are any errors while parsing
@@ -449,7 +1891,7 @@ func (h *Handler) ParseCommandMessage(client *client.Client, data connection.Mes
 		return "", false, fmt.Errorf("error: client command parse error; unable to cast message to string")
 	}
 
-	if string(command[0]) != "/" {
+	if len(command) == 0 || string(command[0]) != "/" {
 		return "", false, nil
 	}
 
@@ -464,7 +1906,7 @@ func (h *Handler) ParseCommandMessage(client *client.Client, data connection.Mes
 // stream.Stream, a "streamload" event is sent to the client with the current stream.Stream information.
 // This method is not concurrency-safe.
 func (h *Handler) RegisterClient(conn connection.Connection) {
-	log.Printf("INF SOCKET CLIENT registering client with id %q\n", conn.UUID())
+	h.logger.Infof("SOCKET CLIENT registering client with id %q\n", conn.UUID())
 
 	c := h.clientHandler.CreateClient(conn)
 	c.BroadcastFrom("info_clientjoined", &client.Response{
@@ -473,128 +1915,222 @@ func (h *Handler) RegisterClient(conn connection.Connection) {
 
 	namespace, nsExists := c.Namespace()
 	if !nsExists {
-		log.Printf("INF SOCKET SERVER client registration error: invalid or unknown namespace for connection with id (%s)", conn.UUID())
+		h.logger.Infof("SOCKET SERVER client registration error: invalid or unknown namespace for connection with id (%s)", conn.UUID())
 		return
 	}
 
 	if len(namespace.Name()) == 0 {
-		log.Printf("INF SOCKET SERVER client namespace registration error: empty namespace name provided for connection with id (%s)\n", conn.UUID())
+		h.logger.Infof("SOCKET SERVER client namespace registration error: empty namespace name provided for connection with id (%s)\n", conn.UUID())
 		return
 	}
 
-	// TODO: use a handler to broadcast to namespace
-
 	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(namespace)
 	if !exists {
-		log.Printf("INF SOCKET CLIENT Playback did not exist for room with namespace %v. Creating...", namespace)
+		h.logger.Infof("SOCKET CLIENT Playback did not exist for room with namespace %v. Creating...", namespace)
 		sPlayback = h.PlaybackHandler.NewPlayback(namespace, h.CommandHandler.Authorizer(), h.clientHandler)
+		sPlayback.SetLogger(h.logger)
+		sPlayback.HypeMeter().OnChange(func(level int) {
+			h.BroadcastToNamespace(namespace, "hypemeter", &client.Response{
+				From: "system",
+				Extra: map[string]interface{}{
+					"level": level,
+				},
+			})
+		})
+		sPlayback.OnScheduleTick(func(remaining int) {
+			h.handleScheduleTick(namespace, remaining)
+		})
+		sPlayback.OnCountdownTick(func(remaining int) {
+			h.handleCountdownTick(namespace, remaining)
+		})
 		sPlayback.OnTick(func(currentTime int) {
 			currPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(namespace)
 			if !exists {
-				log.Printf("ERR CALLBACK-PLAYBACK SOCKET CLIENT attempted to send streamsync event to client, but stream playback does not exist.")
+				h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT attempted to send streamsync event to client, but stream playback does not exist.")
 				return
 			}
 
 			if currentTime%2 == 0 {
-				currStream, streamExists := currPlayback.GetStream()
-				if streamExists {
-					// if stream exists and playback timer >= playback stream duration, stop stream
-					// or queue the next item in the playback queue (if queue not empty)
-					if currStream.GetDuration() > 0 && float64(currPlayback.GetTime()) >= currStream.GetDuration() {
-						queue := currPlayback.GetQueue()
-						queueItem, err := queue.Next()
-						if err == nil {
-							log.Printf("INF CALLBACK-PLAYBACK SOCKET CLIENT detected end of stream. Auto-queuing next stream...")
-
-							nextStream, ok := queueItem.(stream.Stream)
-							if !ok {
-								log.Printf("ERR CALLBACK-PLAYBACK SOCKET CLIENT expected next queue item to implement stream.Stream... Unable to advance the queue.")
-								return
-							}
-
-							currPlayback.SetStream(nextStream)
-							currPlayback.Reset()
-
-							res := &client.Response{
-								Id:   c.UUID(),
-								From: "system",
-							}
+				// streams reported as unplayable once their metadata has been
+				// fetched have no duration, and would otherwise never end
+				if h.skipUnplayableStreams(namespace, currPlayback) {
+					return
+				}
 
-							err = util.SerializeIntoResponse(currPlayback.GetStatus(), &res.Extra)
-							if err != nil {
-								log.Printf("ERR CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize nextStream codec: %v", err)
-								return
-							}
+				// streams with an unknown duration (such as live streams) have
+				// no end time, unless one has been set through a duration override
+				endTime, hasEndTime := currPlayback.EndTime()
+				if hasEndTime {
+					// if stream exists and playback timer >= playback stream end time, stop stream
+					// or queue the next item in the playback queue (if queue not empty)
+					if float64(currPlayback.GetTime()) >= endTime {
+						h.logger.Infof("CALLBACK-PLAYBACK SOCKET CLIENT detected end of stream. Advancing to the next stream...")
 
-							c.BroadcastAll("streamload", res)
-						} else {
-							log.Printf("INF CALLBACK-PLAYBACK SOCKET CLIENT detected end of stream and no queue items. Stopping stream...")
-							currPlayback.Stop()
+						// replay the stream, resume an interrupted stream, load the next
+						// item in the queue, or stop the stream if none of these apply
+						_, loaded, err := currPlayback.EndStream()
+						if err != nil {
+							h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT unable to advance the queue: %v", err)
+							return
 						}
 
-						// emit updated playback state to client if stream has ended
-						log.Printf("INF CALLBACK-PLAYBACK SOCKET CLIENT stream has ended after %v seconds.", currentTime)
 						res := &client.Response{
-							Id: c.UUID(),
+							From: "system",
 						}
 
 						err = util.SerializeIntoResponse(currPlayback.GetStatus(), &res.Extra)
 						if err != nil {
-							log.Printf("ERR CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
+							h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
 							return
 						}
 
-						c.BroadcastAll("streamsync", res)
+						if loaded {
+							h.BroadcastToNamespace(namespace, "streamload", res)
+							if msg, ok := currPlayback.StreamAnnouncement(); ok {
+								h.BroadcastToNamespace(namespace, "chatmessage", &client.Response{
+									From:     client.USER_SYSTEM,
+									Message:  msg,
+									IsSystem: true,
+								})
+							}
+						}
+						h.BroadcastToNamespace(namespace, "streamsync", res)
+
+						h.logger.Infof("CALLBACK-PLAYBACK SOCKET CLIENT stream has ended after %v seconds.", currentTime)
 					}
 				}
 			}
 
-			// if stream timer has not reached its duration, wait until next ROOM_DEFAULT_STREAMSYNC_RATE tick
-			// before updating client with playback information
-			if currentTime%ROOM_DEFAULT_STREAMSYNC_RATE != 0 {
+			// if stream timer has not reached its duration, wait until the next sync tick
+			// before updating client with playback information. The sync rate depends
+			// on the number of clients in the room.
+			if currentTime%StreamSyncRate(h.namespaceClientCount(namespace)) != 0 {
 				return
 			}
 
 			// log in 50 second intervals
 			if currentTime%ROOM_DEFAULT_STREAMSYNC_LOGGING_RATE == 0 {
-				log.Printf("INF CALLBACK-PLAYBACK SOCKET CLIENT streamsync event sent after %v seconds", currentTime)
+				h.logger.Infof("CALLBACK-PLAYBACK SOCKET CLIENT streamsync event sent after %v seconds", currentTime)
 			}
 
 			res := &client.Response{
-				Id: c.UUID(),
+				From: "system",
 			}
 
 			err := util.SerializeIntoResponse(currPlayback.GetStatus(), &res.Extra)
 			if err != nil {
-				log.Printf("ERR CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
+				h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
 				return
 			}
 
-			c.BroadcastAll("streamsync", res)
+			h.BroadcastToNamespace(namespace, "streamsync", res)
 		})
 
+		// restore any state saved for this room before a server restart
+		if h.PlaybackHandler.RestorePlayback(sPlayback, c, h.StreamHandler) {
+			h.logger.Infof("SOCKET CLIENT restored saved state for room with name %q", namespace.Name())
+			if _, exists := sPlayback.GetStream(); exists {
+				res := &client.Response{
+					Id: c.UUID(),
+				}
+
+				err := util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+				if err != nil {
+					h.logger.Errorf("SOCKET CLIENT unable to serialize restored playback status: %v", err)
+					return
+				}
+
+				c.BroadcastTo("streamload", res)
+			}
+		}
+
+		h.sendRoomTopic(c, sPlayback)
+		h.sendPinnedMessages(c, sPlayback)
 		return
 	}
 
 	sPlayback.SetLastUpdated(time.Now())
 
-	log.Printf("INF SOCKET CLIENT found Playback for room with name %q", namespace.Name())
+	h.logger.Infof("SOCKET CLIENT found Playback for room with name %q", namespace.Name())
 
 	pStream, exists := sPlayback.GetStream()
 	if exists {
-		log.Printf("INF SOCKET CLIENT found stream info (%s) associated with Playback for room with name %q... Sending \"streamload\" signal to client", pStream.GetStreamURL(), namespace)
+		h.logger.Infof("SOCKET CLIENT found stream info (%s) associated with Playback for room with name %q... Sending \"streamload\" signal to client", pStream.GetStreamURL(), namespace)
 		res := &client.Response{
 			Id: c.UUID(),
 		}
 
 		err := util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
 		if err != nil {
-			log.Printf("ERR CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
+			h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
 			return
 		}
 
 		c.BroadcastTo("streamload", res)
 	}
+
+	h.sendRoomTopic(c, sPlayback)
+	h.sendPinnedMessages(c, sPlayback)
+}
+
+// sendRoomTopic sends a "topic" event to a newly-registered
+// client if its room has a topic or description set.
+func (h *Handler) sendRoomTopic(c *client.Client, p *playback.Playback) {
+	if !p.HasTopic() {
+		return
+	}
+
+	res := &client.Response{
+		Id:   c.UUID(),
+		From: "system",
+	}
+
+	err := util.SerializeIntoResponse(p.Topic(), &res.Extra)
+	if err != nil {
+		h.logger.Errorf("SOCKET CLIENT unable to serialize room topic: %v", err)
+		return
+	}
+
+	c.BroadcastTo("topic", res)
+}
+
+// broadcastPinnedMessages sends a "pins" event, describing the
+// room's pinned messages, to every client in the given client's room.
+func (h *Handler) broadcastPinnedMessages(c *client.Client, p *playback.Playback) {
+	res := &client.Response{
+		Id:   c.UUID(),
+		From: c.GetUsernameOrId(),
+	}
+
+	err := util.SerializeIntoResponse(p.PinnedMessages(), &res.Extra)
+	if err != nil {
+		h.logger.Errorf("SOCKET CLIENT unable to serialize pinned messages: %v", err)
+		return
+	}
+
+	c.BroadcastAll("pins", res)
+}
+
+// sendPinnedMessages sends a "pins" event to a newly-registered
+// client if its room has any pinned chat messages.
+func (h *Handler) sendPinnedMessages(c *client.Client, p *playback.Playback) {
+	pinned := p.PinnedMessages()
+	if len(pinned.Messages) == 0 {
+		return
+	}
+
+	res := &client.Response{
+		Id:   c.UUID(),
+		From: "system",
+	}
+
+	err := util.SerializeIntoResponse(pinned, &res.Extra)
+	if err != nil {
+		h.logger.Errorf("SOCKET CLIENT unable to serialize pinned messages: %v", err)
+		return
+	}
+
+	c.BroadcastTo("pins", res)
 }
 
 func (h *Handler) DeregisterClient(conn connection.Connection) error {
@@ -605,6 +2141,252 @@ func (h *Handler) DeregisterClient(conn connection.Connection) error {
 	return nil
 }
 
+// subjectRoles walks the authorizer's role-bindings and returns
+// the names of every role the given subject is bound to.
+// Returns an empty slice if no authorizer has been set.
+func (h *Handler) subjectRoles(subject rbac.Subject) []string {
+	roles, exists := h.rolesBySubject()[subject.UUID()]
+	if !exists {
+		return []string{}
+	}
+	return roles
+}
+
+// rolesBySubject walks the authorizer's role-bindings once and returns
+// the names of the roles bound to each subject, keyed by subject id.
+// A role is listed at most once per subject, in binding order.
+// Returns an empty map if no authorizer has been set.
+func (h *Handler) rolesBySubject() map[string][]string {
+	roles := make(map[string][]string)
+	authorizer := h.CommandHandler.Authorizer()
+	if authorizer == nil {
+		return roles
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, b := range authorizer.Bindings() {
+		roleName := rbac.RoleDisplayName(b.Role().Name())
+		for _, u := range b.Subjects() {
+			if seen[u.UUID()] == nil {
+				seen[u.UUID()] = make(map[string]bool)
+			}
+			if seen[u.UUID()][roleName] {
+				continue
+			}
+
+			seen[u.UUID()][roleName] = true
+			roles[u.UUID()] = append(roles[u.UUID()], roleName)
+		}
+	}
+
+	return roles
+}
+
+// BroadcastToNamespace sends an event to every connection in the given
+// namespace. Unlike a client's Broadcast* methods, it does not require
+// a reference to a connected client.
+func (h *Handler) BroadcastToNamespace(ns connection.Namespace, evt string, data connection.MessageDataCodec) {
+	m, err := json.Marshal(&connection.Message{
+		Event: evt,
+		Data:  data,
+	})
+	if err != nil {
+		h.logger.Errorf("SOCKET SERVER unable to serialize %q event for namespace %q: %v", evt, ns.Name(), err)
+		return
+	}
+
+	h.nsHandler.Broadcast(websocket.TextMessage, ns.Name(), evt, m)
+}
+
+// isBanned returns true if the given connection was made from
+// an address that has been banned from the connection's room
+func (h *Handler) isBanned(conn connection.Connection) bool {
+	ns, exists := conn.Namespace()
+	if !exists {
+		return false
+	}
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		return false
+	}
+
+	return sPlayback.IsBanned(connection.RemoteHost(conn))
+}
+
+// requiresPassword returns the connection's room and true if the room
+// is password-protected and the connection was made with neither its
+// password nor a valid invite token, consuming one use of the token.
+// Passwords and invite tokens are supplied through the connection
+// request's query.
+func (h *Handler) requiresPassword(conn connection.Connection) (connection.Namespace, bool) {
+	ns, exists := conn.Namespace()
+	if !exists {
+		return nil, false
+	}
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		return nil, false
+	}
+
+	if sPlayback.CheckPassword(conn.Request().URL.Query().Get(query.ROOM_PASSWORD_KEY)) {
+		return ns, false
+	}
+
+	// a valid invite admits the client in place of the password
+	return ns, !sPlayback.UseInvite(conn.Request().URL.Query().Get(query.ROOM_INVITE_KEY))
+}
+
+// isRoomFull returns the connection's room and true if the room has
+// a capacity which its other connected clients have already reached.
+func (h *Handler) isRoomFull(conn connection.Connection) (connection.Namespace, bool) {
+	ns, exists := conn.Namespace()
+	if !exists {
+		return nil, false
+	}
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		return nil, false
+	}
+
+	others := 0
+	for _, c := range ns.Connections() {
+		if c.UUID() != conn.UUID() {
+			others++
+		}
+	}
+
+	return ns, sPlayback.IsFull(others)
+}
+
+// refuseConnection sends the given event to the given connection
+// before removing it from its room and closing it
+func (h *Handler) refuseConnection(conn connection.Connection, evt string, res *client.Response) {
+	m, err := json.Marshal(&connection.Message{
+		Event: evt,
+		Data:  res,
+	})
+	if err == nil {
+		conn.Send(m)
+	}
+
+	if ns, exists := conn.Namespace(); exists {
+		conn.Leave(ns.Name())
+	}
+
+	if err := conn.Close(); err != nil {
+		h.logger.Errorf("SOCKET CONN unable to close connection with id %q: %v", conn.UUID(), err)
+	}
+}
+
+// messageIdFromData returns the chat message id sent with the given
+// message data, notifying the client if no valid id was sent.
+func (h *Handler) messageIdFromData(c *client.Client, messageData connection.MessageData) (string, bool) {
+	rawMessageId, exists := messageData.Key(playback.ChatMessageIdKey)
+	if !exists {
+		h.logger.Errorf("SOCKET CLIENT client %q sent a chat message request with no message id. Ignoring.", c.UUID())
+		c.BroadcastErrorTo(fmt.Errorf("error: a message id is required"))
+		return "", false
+	}
+
+	messageId, ok := rawMessageId.(string)
+	if !ok || len(messageId) == 0 {
+		h.logger.Errorf("SOCKET CLIENT client %q sent a non-string value for the field %q", c.UUID(), playback.ChatMessageIdKey)
+		return "", false
+	}
+	return messageId, true
+}
+
+// isSlowModeExempt returns true if the client is allowed to
+// chat regardless of their room's slow mode. Clients are never
+// exempt if rbac authorization is disabled.
+func (h *Handler) isSlowModeExempt(c *client.Client) bool {
+	authorizer := h.CommandHandler.Authorizer()
+	if authorizer == nil {
+		return false
+	}
+
+	return cmd.Authorize(authorizer, c, cmd.SLOWMODE_EXEMPT_ACTION, h.PlaybackHandler).Allowed
+}
+
+// authorizeAction determines whether the given client is allowed
+// to perform the given rbac action. Returns an error describing
+// why if the client is not.
+func (h *Handler) authorizeAction(c *client.Client, action string) error {
+	decision := cmd.Authorize(h.CommandHandler.Authorizer(), c, action, h.PlaybackHandler)
+	if !decision.Allowed {
+		h.logger.Errorf("SOCKET CLIENT AUTHZ client %q with id (%s) has attempted to perform unauthorized action: %q (%s)", c.GetUsernameOrId(), c.UUID(), action, decision.Reason)
+		return decision.Err()
+	}
+
+	return nil
+}
+
+// parseChapters receives a raw list of chapter objects, as decoded
+// from a client message, and returns a list of playback chapters.
+func parseChapters(raw interface{}) ([]playback.Chapter, error) {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("error: chapters must be a list")
+	}
+
+	chapters := []playback.Chapter{}
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("error: each chapter must be an object with a title and a start time")
+		}
+
+		title, ok := fields["title"].(string)
+		if !ok {
+			return nil, fmt.Errorf("error: each chapter must have a string title")
+		}
+
+		start, ok := fields["start"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("error: chapter %q must have a numeric start time", title)
+		}
+
+		chapters = append(chapters, playback.Chapter{
+			Title: strings.TrimSpace(title),
+			Start: int(start),
+		})
+	}
+
+	return chapters, nil
+}
+
+// recordViewerCount samples the number of connections in the
+// given namespace into the viewer history of its room's playback.
+func (h *Handler) recordViewerCount(ns connection.Namespace) {
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		return
+	}
+
+	sPlayback.RecordViewerCount(len(ns.Connections()))
+}
+
+// dispatchUserEvent notifies the client's room of
+// a webhook event of the given type about the client
+func (h *Handler) dispatchUserEvent(c *client.Client, eventType string) {
+	sPlayback, err := h.getPlaybackFromClient(c)
+	if err != nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"id":       c.UUID(),
+		"username": c.GetUsernameOrId(),
+	}
+	if identity, ok := c.Identity(); ok {
+		data["identity"] = identity
+	}
+	sPlayback.DispatchEvent(eventType, data)
+}
+
 func (h *Handler) getPlaybackFromClient(c *client.Client) (*playback.Playback, error) {
 	ns, exists := c.Namespace()
 	if !exists {
@@ -620,6 +2402,11 @@ func (h *Handler) getPlaybackFromClient(c *client.Client) (*playback.Playback, e
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.IsShuttingDown() {
+		http.Error(w, ShutdownNotice, http.StatusServiceUnavailable)
+		return
+	}
+
 	h.server.ServeHTTP(w, r)
 }
 
@@ -630,10 +2417,17 @@ func NewHandler(nsHandler connection.NamespaceHandler, connHandler connection.Co
 		PlaybackHandler: playbackHandler,
 		StreamHandler:   streamHandler,
 
-		server: socketserver.NewServer(connHandler, nsHandler),
+		nsHandler: nsHandler,
+		server:    socketserver.NewServer(connHandler, nsHandler),
+		sessions:  newSessionStore(),
+		logger:    logging.Default,
+		shutdown:  newShutdownState(),
 	}
 
 	handler.addRequestHandlers()
+	handler.watchPresence()
+	handler.watchLatency()
+	handler.watchHeartbeat()
 	return handler
 }
 
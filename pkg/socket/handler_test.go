@@ -0,0 +1,484 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// fakeConnection implements connection.Connection without a
+// websocket, recording the messages sent to it. Messages it broadcasts
+// are sent to the connections in its namespace. It remains bound
+// to its namespace regardless of the namespace handler's state,
+// though it is removed from the namespace's connections on leaving.
+type fakeConnection struct {
+	id        string
+	ns        connection.Namespace
+	req       *http.Request
+	metadata  connection.ConnectionMetadata
+	callbacks map[string][]connection.SocketEventCallback
+
+	mutex sync.Mutex
+	sent  [][]byte
+}
+
+func (c *fakeConnection) Broadcast(room, evt string, data []byte) {
+	for _, conn := range c.ns.Connections() {
+		conn.WriteMessage(0, data)
+	}
+}
+func (c *fakeConnection) BroadcastFrom(room, evt string, data []byte) {
+	for _, conn := range c.ns.Connections() {
+		if conn.UUID() == c.id {
+			continue
+		}
+		conn.WriteMessage(0, data)
+	}
+}
+func (c *fakeConnection) Close() error { return nil }
+func (c *fakeConnection) Metadata() connection.ConnectionMetadata {
+	return c.metadata
+}
+func (c *fakeConnection) MissedPongs() int { return 0 }
+func (c *fakeConnection) Ping() error      { return nil }
+func (c *fakeConnection) Connections() []connection.Connection {
+	return c.ns.Connections()
+}
+func (c *fakeConnection) Emit(eventName string, data connection.MessageDataCodec) {
+	for _, callback := range c.callbacks[eventName] {
+		callback(data)
+	}
+}
+func (c *fakeConnection) UUID() string { return c.id }
+func (c *fakeConnection) Join(string)  {}
+func (c *fakeConnection) Leave(string) { c.ns.Remove(c) }
+func (c *fakeConnection) Namespace() (connection.Namespace, bool) {
+	return c.ns, c.ns != nil
+}
+func (c *fakeConnection) On(eventName string, callback connection.SocketEventCallback) {
+	c.callbacks[eventName] = append(c.callbacks[eventName], callback)
+}
+func (c *fakeConnection) ReadMessage() (int, []byte, error) {
+	return 0, nil, fmt.Errorf("fake connections cannot be read from")
+}
+func (c *fakeConnection) ResponseWriter() http.ResponseWriter { return nil }
+func (c *fakeConnection) Request() *http.Request              { return c.req }
+func (c *fakeConnection) Send(data []byte) {
+	c.WriteMessage(0, data)
+}
+func (c *fakeConnection) WriteMessage(messageType int, data []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sent = append(c.sent, data)
+	return nil
+}
+
+// fakeMessage is a message sent to a fake connection, with its
+// data left undecoded
+type fakeMessage struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// messages returns the data of every message with
+// the given event name sent to the connection, in order
+func (c *fakeConnection) messages(evt string) []json.RawMessage {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data := []json.RawMessage{}
+	for _, m := range c.sent {
+		message := fakeMessage{}
+		if err := json.Unmarshal(m, &message); err != nil || message.Event != evt {
+			continue
+		}
+		data = append(data, message.Data)
+	}
+	return data
+}
+
+// lastMessage decodes the data of the last message with the given
+// event name sent to the connection into the given value. Returns
+// a boolean (false) if no such message has been sent.
+func (c *fakeConnection) lastMessage(evt string, v interface{}) bool {
+	data := c.messages(evt)
+	if len(data) == 0 {
+		return false
+	}
+	return json.Unmarshal(data[len(data)-1], v) == nil
+}
+
+// clearMessages discards the messages sent to the connection so far
+func (c *fakeConnection) clearMessages() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sent = nil
+}
+
+// fakeStreamHandler is a stream handler that serves the metadata stubbed
+// for each stream url instead of fetching it, and never considers it
+// expired. Fetching metadata for a url with none stubbed fails.
+type fakeStreamHandler struct {
+	stream.StreamHandler
+
+	mutex    sync.Mutex
+	metadata map[string][]byte
+}
+
+func (h *fakeStreamHandler) FetchMetadata(s stream.Stream, callback stream.StreamMetadataCallback) {
+	h.mutex.Lock()
+	data, exists := h.metadata[s.GetStreamURL()]
+	h.mutex.Unlock()
+
+	if !exists {
+		callback(s, nil, fmt.Errorf("no metadata stubbed for %q", s.GetStreamURL()))
+		return
+	}
+	callback(s, data, nil)
+}
+
+func (h *fakeStreamHandler) MetadataExpired(stream.Stream) bool { return false }
+
+// stub sets the metadata served for the given url
+func (h *fakeStreamHandler) stub(url, metadata string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.metadata[url] = []byte(metadata)
+}
+
+func newFakeStreamHandler() *fakeStreamHandler {
+	return &fakeStreamHandler{
+		StreamHandler: stream.NewHandler(),
+		metadata:      make(map[string][]byte),
+	}
+}
+
+// stubMetadata sets the metadata served for the given url
+// by the given handler's fake stream handler
+func stubMetadata(h *Handler, url, metadata string) {
+	h.StreamHandler.(*fakeStreamHandler).stub(url, metadata)
+}
+
+// newFakeConnection returns a fake connection with the given
+// id, bound to the given namespace, which it is added to
+func newFakeConnection(id string, ns connection.Namespace) *fakeConnection {
+	conn := &fakeConnection{
+		id:        id,
+		ns:        ns,
+		req:       httptest.NewRequest(http.MethodGet, "/"+ns.Name(), nil),
+		metadata:  connection.NewConnectionMetadata(),
+		callbacks: make(map[string][]connection.SocketEventCallback),
+	}
+	ns.Add(conn)
+	return conn
+}
+
+// newTestHandler returns a socket handler for the room with the given
+// name, along with that room's namespace
+func newTestHandler(room string) (*Handler, connection.Namespace) {
+	nsHandler := connection.NewNamespaceHandler()
+	ns := nsHandler.NewNamespace(room)
+
+	h := NewHandler(nsHandler, connection.NewHandler(nsHandler), cmd.NewHandler(), client.NewHandler(), playback.NewHandler(nsHandler), newFakeStreamHandler())
+	return h, ns
+}
+
+// newTestHandlerWithRBAC returns a socket handler for the room with
+// the given name like newTestHandler, with role-based access control
+// enabled through the returned authorizer
+func newTestHandlerWithRBAC(room string) (*Handler, connection.Namespace, rbac.Authorizer) {
+	authorizer := rbac.NewAuthorizer()
+	cmd.AddDefaultRoles(authorizer)
+
+	nsHandler := connection.NewNamespaceHandler()
+	ns := nsHandler.NewNamespace(room)
+
+	h := NewHandler(nsHandler, connection.NewHandlerWithRBAC(authorizer, nsHandler), cmd.NewHandlerWithRBAC(authorizer), client.NewHandler(), playback.NewHandler(nsHandler), newFakeStreamHandler())
+	return h, ns, authorizer
+}
+
+// connect connects a fake client with the given id and username to the
+// given namespace, binding it to the given role if an authorizer is given
+func connect(t *testing.T, h *Handler, ns connection.Namespace, authorizer rbac.Authorizer, id, username, role string) *fakeConnection {
+	conn := newFakeConnection(id, ns)
+	h.HandleClientConnection(conn)
+
+	c, err := h.clientHandler.GetClient(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.UpdateUsername(username); err != nil {
+		t.Fatalf("unexpected error setting username %q: %v", username, err)
+	}
+
+	if authorizer != nil {
+		rbacRole, exists := authorizer.Role(role)
+		if !exists {
+			t.Fatalf("unknown role %q", role)
+		}
+		authorizer.Bind(rbacRole, conn)
+	}
+	return conn
+}
+
+// statusResponse is the data of a "streamsync" event
+type statusResponse struct {
+	Extra struct {
+		QueueLength int `json:"queueLength"`
+		Stream      *struct {
+			Name     string  `json:"name"`
+			Url      string  `json:"url"`
+			Duration float64 `json:"duration"`
+			Thumb    string  `json:"thumb"`
+		} `json:"stream"`
+		Playback playback.TimerStatus `json:"playback"`
+		Chapters []playback.Chapter   `json:"chapters"`
+	} `json:"extra"`
+}
+
+// loadStream sets a local video stream with the given name and
+// duration as the current stream of the given namespace's room
+func loadStream(t *testing.T, h *Handler, ns connection.Namespace, name string, duration float64) *playback.Playback {
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		t.Fatalf("expected a playback to be created for the room")
+	}
+
+	s := stream.NewLocalVideoStream(name)
+	if err := s.SetInfo([]byte(fmt.Sprintf(`{"duration": %v}`, duration))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sPlayback.SetStream(s)
+	return sPlayback
+}
+
+func TestParseCommandMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     interface{}
+		expectCmd   string
+		expectIsCmd bool
+		expectErr   bool
+	}{
+		{
+			name:    "empty message",
+			message: "",
+		},
+		{
+			name:    "plain chat message",
+			message: "hello",
+		},
+		{
+			name:        "bare slash",
+			message:     "/",
+			expectIsCmd: true,
+		},
+		{
+			name:        "command with arguments",
+			message:     "/play url",
+			expectCmd:   "play url",
+			expectIsCmd: true,
+		},
+		{
+			name:      "non-string message",
+			message:   42,
+			expectErr: true,
+		},
+	}
+
+	h := &Handler{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := connection.NewMessageData()
+			data.Set("message", tc.message)
+
+			command, isCmd, err := h.ParseCommandMessage(nil, data)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if isCmd != tc.expectIsCmd {
+				t.Errorf("expected command: %v, got %v", tc.expectIsCmd, isCmd)
+			}
+			if command != tc.expectCmd {
+				t.Errorf("expected command %q, got %q", tc.expectCmd, command)
+			}
+		})
+	}
+}
+
+func TestHandleDisconnection(t *testing.T) {
+	tests := []struct {
+		name          string
+		reapPlayback  bool
+		expectClients int
+	}{
+		{
+			name: "room playback exists",
+		},
+		{
+			name:         "room playback no longer exists",
+			reapPlayback: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, ns := newTestHandler("room")
+			conn := newFakeConnection("client", ns)
+			h.HandleClientConnection(conn)
+
+			sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+			if !exists {
+				t.Fatalf("expected a playback to be created for the room")
+			}
+			if tc.reapPlayback {
+				h.PlaybackHandler.ReapPlayback(sPlayback)
+			}
+
+			conn.Emit("disconnection", nil)
+			if size := h.clientHandler.GetClientSize(); size != tc.expectClients {
+				t.Errorf("expected %v registered clients, got %v", tc.expectClients, size)
+			}
+		})
+	}
+}
+
+func TestRoomCapacity(t *testing.T) {
+	h, ns := newTestHandler("room")
+
+	first := newFakeConnection("first", ns)
+	h.HandleClientConnection(first)
+	h.HandleClientConnection(newFakeConnection("second", ns))
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		t.Fatalf("expected a playback to be created for the room")
+	}
+	if err := sPlayback.SetCapacity(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sPlayback.SetPassword("secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	invite, err := sPlayback.CreateInvite(1, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		id           string
+		disconnect   *fakeConnection
+		expectJoined bool
+		expectEvent  string
+	}{
+		{
+			name:        "clients beyond the room's capacity are refused",
+			id:          "third",
+			expectEvent: "roomfull",
+		},
+		{
+			name:         "invites are not used up by clients refused for capacity",
+			id:           "fourth",
+			disconnect:   first,
+			expectJoined: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.disconnect != nil {
+				tc.disconnect.Emit("disconnection", nil)
+				tc.disconnect.Leave(ns.Name())
+			}
+
+			conn := newFakeConnection(tc.id, ns)
+			conn.req = httptest.NewRequest(http.MethodGet, "/"+ns.Name()+"?"+query.ROOM_INVITE_KEY+"="+invite, nil)
+			h.HandleClientConnection(conn)
+
+			if _, err := h.clientHandler.GetClient(tc.id); (err == nil) != tc.expectJoined {
+				t.Errorf("expected client to join: %v, got error %v", tc.expectJoined, err)
+			}
+			if len(tc.expectEvent) > 0 && (len(conn.sent) == 0 || !strings.Contains(string(conn.sent[0]), tc.expectEvent)) {
+				t.Errorf("expected client to be sent a %q event, got %q", tc.expectEvent, conn.sent)
+			}
+		})
+	}
+}
+
+func TestBroadcastToNamespace(t *testing.T) {
+	h, ns := newTestHandler("room")
+	other := h.nsHandler.NewNamespace("other")
+
+	conns := []*fakeConnection{}
+	for _, id := range []string{"first", "second", "third"} {
+		conns = append(conns, connect(t, h, ns, nil, id, id, ""))
+	}
+	outsider := connect(t, h, other, nil, "outsider", "outsider", "")
+
+	// the client that first joined the room is gone by the time of the broadcast
+	conns[0].Emit("disconnection", nil)
+	conns[0].Leave(ns.Name())
+
+	tests := []struct {
+		name          string
+		conn          *fakeConnection
+		expectMessage bool
+	}{
+		{
+			name:          "connection in the namespace",
+			conn:          conns[1],
+			expectMessage: true,
+		},
+		{
+			name:          "another connection in the namespace",
+			conn:          conns[2],
+			expectMessage: true,
+		},
+		{
+			name: "connection that left the namespace",
+			conn: conns[0],
+		},
+		{
+			name: "connection in another namespace",
+			conn: outsider,
+		},
+	}
+
+	for _, tc := range tests {
+		tc.conn.clearMessages()
+	}
+
+	h.BroadcastToNamespace(ns, "announcement", &client.Response{
+		From:    "system",
+		Message: "hello",
+	})
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			received := client.Response{}
+			if ok := tc.conn.lastMessage("announcement", &received); ok != tc.expectMessage {
+				t.Fatalf("expected the connection to receive the event: %v, got %q", tc.expectMessage, tc.conn.sent)
+			}
+			if tc.expectMessage && received.Message != "hello" {
+				t.Errorf("expected message %q, got %q", "hello", received.Message)
+			}
+			if messages := len(tc.conn.messages("announcement")); tc.expectMessage && messages != 1 {
+				t.Errorf("expected the event to be received once, got %v times", messages)
+			}
+		})
+	}
+}
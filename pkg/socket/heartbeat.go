@@ -0,0 +1,54 @@
+package socket
+
+import (
+	"time"
+)
+
+const (
+	DefaultHeartbeatInterval  = 15 * time.Second // default amount of time between heartbeat pings
+	DefaultHeartbeatMissLimit = 3                // default number of consecutive pings a client may miss
+)
+
+var (
+	// HeartbeatInterval is the amount of time between heartbeat
+	// pings sent to each client. A value of 0 disables heartbeats.
+	HeartbeatInterval = DefaultHeartbeatInterval
+	// HeartbeatMissLimit is the number of consecutive heartbeat pings
+	// a client may leave unanswered before it is disconnected.
+	HeartbeatMissLimit = DefaultHeartbeatMissLimit
+)
+
+// watchHeartbeat periodically pings every client, and disconnects
+// any that have stopped answering, so that they are deregistered
+// through the normal disconnection path.
+func (h *Handler) watchHeartbeat() {
+	if HeartbeatInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(HeartbeatInterval)
+	go func() {
+		for range ticker.C {
+			h.checkHeartbeats()
+		}
+	}()
+}
+
+// checkHeartbeats disconnects every client that has missed too
+// many consecutive heartbeat pings, and pings the rest.
+func (h *Handler) checkHeartbeats() {
+	for _, c := range h.clientHandler.Clients() {
+		conn := c.Connection()
+		if missed := conn.MissedPongs(); missed >= HeartbeatMissLimit {
+			h.logger.Infof("SOCKET CONN disconnecting client with id %q: %v heartbeats missed", conn.UUID(), missed)
+			if err := c.Disconnect(); err != nil {
+				h.logger.Errorf("SOCKET CONN unable to close connection with id %q: %v", conn.UUID(), err)
+			}
+			continue
+		}
+
+		if err := conn.Ping(); err != nil {
+			h.logger.Debugf("SOCKET CONN unable to send heartbeat to client with id %q: %v", conn.UUID(), err)
+		}
+	}
+}
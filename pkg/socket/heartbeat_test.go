@@ -0,0 +1,66 @@
+package socket
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// silentConnection is a fake connection that never answers heartbeat
+// pings. Closing it disconnects it, as closing a websocket would.
+type silentConnection struct {
+	*fakeConnection
+
+	mutex  sync.Mutex
+	missed int
+	closed bool
+}
+
+func (c *silentConnection) Ping() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.missed++
+	return nil
+}
+
+func (c *silentConnection) MissedPongs() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.missed
+}
+
+func (c *silentConnection) Close() error {
+	c.mutex.Lock()
+	c.closed = true
+	c.mutex.Unlock()
+
+	c.Emit("disconnection", connection.NewMessageData())
+	return nil
+}
+
+func TestCheckHeartbeats(t *testing.T) {
+	h, ns := newTestHandler("room")
+	connect(t, h, ns, nil, "alive", "alive", "")
+
+	silent := &silentConnection{
+		fakeConnection: newFakeConnection("silent", ns),
+	}
+	h.HandleClientConnection(silent)
+
+	for i := 1; i <= HeartbeatMissLimit+1; i++ {
+		h.checkHeartbeats()
+
+		_, err := h.clientHandler.GetClient(silent.UUID())
+		if reaped := err != nil; reaped != (i > HeartbeatMissLimit) {
+			t.Fatalf("expected the silent client to be reaped after %v heartbeats: %v, got %v", i, i > HeartbeatMissLimit, reaped)
+		}
+	}
+
+	if !silent.closed {
+		t.Errorf("expected the silent client's connection to be closed")
+	}
+	if _, err := h.clientHandler.GetClient("alive"); err != nil {
+		t.Errorf("expected the responsive client to remain connected, got %v", err)
+	}
+}
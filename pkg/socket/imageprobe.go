@@ -0,0 +1,131 @@
+package socket
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultImageProbeTimeout = 2 * time.Second // time allowed for each image url probe
+	DefaultImageProbeMaxUrls = 3               // maximum number of urls probed per chat message
+)
+
+// candidateUrl matches any http(s) url in a chat message
+var candidateUrl = regexp.MustCompile(`(https?://[^ ]+)(?: |$)`)
+
+// HTTPClient performs http requests on behalf of an ImageProber.
+// Implemented by *http.Client.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// ImageProber determines whether urls without an image file extension
+// point to images by requesting their headers and inspecting the
+// returned content-type.
+type ImageProber struct {
+	client  HTTPClient
+	maxUrls int
+}
+
+// Probe requests headers for up to maxUrls of the given urls concurrently,
+// and returns the ones served with an image/* content-type, in order.
+// Urls that cannot be reached are ignored.
+func (p *ImageProber) Probe(urls []string) []string {
+	if len(urls) > p.maxUrls {
+		urls = urls[:p.maxUrls]
+	}
+
+	isImage := make([]bool, len(urls))
+
+	var wg sync.WaitGroup
+	for idx, url := range urls {
+		wg.Add(1)
+		go func(idx int, url string) {
+			defer wg.Done()
+			isImage[idx] = p.isImage(url)
+		}(idx, url)
+	}
+	wg.Wait()
+
+	images := []string{}
+	for idx, url := range urls {
+		if isImage[idx] {
+			images = append(images, url)
+		}
+	}
+	return images
+}
+
+func (p *ImageProber) isImage(url string) bool {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return false
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false
+	}
+
+	return strings.HasPrefix(strings.ToLower(res.Header.Get("Content-Type")), "image/")
+}
+
+// NewImageProber returns an ImageProber that issues requests
+// through the given client, probing up to maxUrls per message.
+func NewImageProber(client HTTPClient, maxUrls int) *ImageProber {
+	if maxUrls < 1 {
+		maxUrls = 1
+	}
+
+	return &ImageProber{
+		client:  client,
+		maxUrls: maxUrls,
+	}
+}
+
+// NewDefaultImageProber returns an ImageProber using an http client
+// bounded by DefaultImageProbeTimeout, which refuses to connect to
+// loopback, private, or link-local addresses, so that chat messages
+// cannot be used to probe the server's own network.
+func NewDefaultImageProber() *ImageProber {
+	return NewImageProber(newPublicHTTPClient(DefaultImageProbeTimeout), DefaultImageProbeMaxUrls)
+}
+
+// newPublicHTTPClient returns an http client bounded by the given timeout
+// that refuses to connect to loopback, private, link-local, or unspecified
+// addresses, including ones reached through redirects.
+func newPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+				return fmt.Errorf("refusing to connect to address %q", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package socket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImageProberRefusesLocalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		prober       *ImageProber
+		expectImages int
+	}{
+		{
+			name:         "unrestricted client reaches loopback server",
+			prober:       NewImageProber(server.Client(), DefaultImageProbeMaxUrls),
+			expectImages: 1,
+		},
+		{
+			name:   "default client refuses loopback server",
+			prober: NewDefaultImageProber(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			images := tc.prober.Probe([]string{server.URL + "/image"})
+			if len(images) != tc.expectImages {
+				t.Errorf("expected %v images, got %v", tc.expectImages, images)
+			}
+		})
+	}
+}
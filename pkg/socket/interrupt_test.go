@@ -0,0 +1,89 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestInterruptStream(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	admin := connect(t, h, ns, authorizer, "admin", "admin", rbac.ADMIN_ROLE)
+	user := connect(t, h, ns, authorizer, "user", "user", rbac.USER_ROLE)
+	stubMetadata(h, "http://example.com/ad.mp4", `{"duration": 30}`)
+
+	sPlayback := loadStream(t, h, ns, "movie.mp4", 600)
+	sPlayback.SetTime(120)
+
+	userQueue := queue.NewAggregatableQueue(user.UUID())
+	if err := sPlayback.GetQueue().Push(userQueue); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sPlayback.PushToQueue(userQueue, stream.NewLocalVideoStream("queued.mp4")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := connection.NewMessageData()
+	data.Set("url", "http://example.com/ad.mp4")
+	user.Emit("request_interrupt", data)
+	if s, _ := sPlayback.GetStream(); s.GetStreamURL() != "movie.mp4" {
+		t.Fatalf("expected clients without permission not to interrupt the stream, got %q playing", s.GetStreamURL())
+	}
+
+	admin.Emit("request_interrupt", data)
+
+	res := statusResponse{}
+	if !user.lastMessage("streamload", &res) {
+		t.Fatalf("expected a %q event to be broadcast, got %q", "streamload", user.sent)
+	}
+	if res.Extra.Stream == nil || res.Extra.Stream.Url != "http://example.com/ad.mp4" {
+		t.Fatalf("expected the interrupting stream to be loaded, got %v", res.Extra.Stream)
+	}
+	if sPlayback.State() != playback.PLAYBACK_STATE_STARTED {
+		t.Errorf("expected the interrupting stream to be played, got state %v", sPlayback.State())
+	}
+	if time := sPlayback.GetTime(); time > 1 {
+		t.Errorf("expected the interrupting stream to play from its beginning, got %v", time)
+	}
+
+	// keep the playback time still while streams are ended below
+	sPlayback.Pause()
+
+	tests := []struct {
+		name       string
+		expectUrl  string
+		expectTime int
+	}{
+		{
+			name:       "the interrupted stream resumes where it was left",
+			expectUrl:  "movie.mp4",
+			expectTime: 120,
+		},
+		{
+			name:      "the queue advances once the interrupted stream ends",
+			expectUrl: "queued.mp4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, loaded, err := sPlayback.EndStream()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !loaded {
+				t.Fatalf("expected a stream to be loaded once the current stream ends")
+			}
+			if s.GetStreamURL() != tc.expectUrl {
+				t.Errorf("expected stream %q to be loaded, got %q", tc.expectUrl, s.GetStreamURL())
+			}
+			if time := sPlayback.GetTime(); time != tc.expectTime {
+				t.Errorf("expected playback time %v, got %v", tc.expectTime, time)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package socket
+
+import (
+	"time"
+)
+
+// PingInterval is the amount of time between
+// latency measurements for each client
+var PingInterval = 30 * time.Second
+
+// watchLatency periodically sends a "ping" event to every client.
+// Round-trip times are recorded once clients reply with a "pong".
+func (h *Handler) watchLatency() {
+	ticker := time.NewTicker(PingInterval)
+	go func() {
+		for range ticker.C {
+			for _, c := range h.clientHandler.Clients() {
+				c.SendPing()
+			}
+		}
+	}()
+}
@@ -0,0 +1,331 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+const (
+	DefaultLinkPreviewTimeout  = 3 * time.Second // time allowed for each link preview fetch
+	DefaultLinkPreviewMaxUrls  = 1               // maximum number of urls previewed per chat message
+	DefaultLinkPreviewMaxBytes = 256 * 1024      // maximum number of bytes read from each previewed page
+	DefaultLinkPreviewTTL      = 1 * time.Hour   // time a fetched link preview is reused for
+)
+
+var (
+	// metaTag matches the attributes of each meta and link tag in an html document
+	metaTag = regexp.MustCompile(`(?is)<(meta|link)\s([^>]*)>`)
+	// titleTag matches the contents of an html document's title
+	titleTag = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	// tagAttribute matches each name and quoted or unquoted value of a tag's attributes
+	tagAttribute = regexp.MustCompile(`([a-zA-Z_:.-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// LinkPreview is a serializable schema describing
+// the page a url in a chat message links to
+type LinkPreview struct {
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+}
+
+type cachedLinkPreview struct {
+	// preview is nil if the url could not be previewed
+	preview   *LinkPreview
+	fetchedAt time.Time
+}
+
+// LinkPreviewer builds previews of the pages linked to by chat messages
+// from their Open Graph metadata, falling back to their oEmbed metadata,
+// if advertised, and to their html title. Previews, and urls that could
+// not be previewed, are cached by url.
+type LinkPreviewer struct {
+	client   HTTPClient
+	maxUrls  int
+	maxBytes int64
+	ttl      time.Duration
+
+	mutex sync.Mutex
+	cache map[string]cachedLinkPreview
+}
+
+// Preview fetches previews for up to maxUrls of the given urls
+// concurrently, and returns copies of the ones that could be built,
+// in order, which may be modified without affecting the cache.
+func (p *LinkPreviewer) Preview(urls []string) []*LinkPreview {
+	if len(urls) > p.maxUrls {
+		urls = urls[:p.maxUrls]
+	}
+
+	fetched := make([]*LinkPreview, len(urls))
+
+	var wg sync.WaitGroup
+	for idx, url := range urls {
+		wg.Add(1)
+		go func(idx int, url string) {
+			defer wg.Done()
+			fetched[idx] = p.preview(url)
+		}(idx, url)
+	}
+	wg.Wait()
+
+	previews := []*LinkPreview{}
+	for _, preview := range fetched {
+		if preview != nil {
+			copied := *preview
+			previews = append(previews, &copied)
+		}
+	}
+	return previews
+}
+
+// preview returns the cached preview of the given url,
+// fetching it if none is cached or the cached one expired
+func (p *LinkPreviewer) preview(url string) *LinkPreview {
+	p.mutex.Lock()
+	entry, exists := p.cache[url]
+	p.mutex.Unlock()
+	if exists && time.Since(entry.fetchedAt) <= p.ttl {
+		return entry.preview
+	}
+
+	preview := p.fetch(url)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	for u, entry := range p.cache {
+		if now.Sub(entry.fetchedAt) > p.ttl {
+			delete(p.cache, u)
+		}
+	}
+	p.cache[url] = cachedLinkPreview{
+		preview:   preview,
+		fetchedAt: now,
+	}
+	return preview
+}
+
+// fetch builds a preview of the html page at the given
+// url, or returns nil if the url is not an html page
+func (p *LinkPreviewer) fetch(url string) *LinkPreview {
+	page, err := p.get(url, "text/html", "application/xhtml+xml")
+	if err != nil {
+		return nil
+	}
+
+	preview := &LinkPreview{
+		Url: url,
+	}
+
+	oEmbedUrl := ""
+	for _, match := range metaTag.FindAllStringSubmatch(page, -1) {
+		attrs := parseTagAttributes(match[2])
+		if strings.EqualFold(match[1], "link") {
+			if strings.EqualFold(attrs["type"], "application/json+oembed") && len(oEmbedUrl) == 0 {
+				oEmbedUrl = attrs["href"]
+			}
+			continue
+		}
+
+		key := strings.ToLower(attrs["property"])
+		if len(key) == 0 {
+			key = strings.ToLower(attrs["name"])
+		}
+
+		value := strings.TrimSpace(attrs["content"])
+		switch key {
+		case "og:title":
+			preview.Title = value
+		case "twitter:title":
+			preview.Title = firstNonEmpty(preview.Title, value)
+		case "og:description":
+			preview.Description = value
+		case "twitter:description", "description":
+			preview.Description = firstNonEmpty(preview.Description, value)
+		case "og:image", "og:image:url":
+			preview.Image = value
+		case "twitter:image":
+			preview.Image = firstNonEmpty(preview.Image, value)
+		case "og:site_name":
+			preview.SiteName = value
+		}
+	}
+
+	if len(preview.Title) == 0 && len(oEmbedUrl) > 0 {
+		p.fillFromOEmbed(preview, resolvePreviewUrl(url, oEmbedUrl))
+	}
+	if len(preview.Title) == 0 {
+		if match := titleTag.FindStringSubmatch(page); match != nil {
+			preview.Title = strings.TrimSpace(html.UnescapeString(match[1]))
+		}
+	}
+	if len(preview.Title) == 0 {
+		return nil
+	}
+
+	preview.Image = resolvePreviewUrl(url, preview.Image)
+	return preview
+}
+
+// fillFromOEmbed sets the fields of the given preview
+// from the oEmbed metadata at the given url, if any
+func (p *LinkPreviewer) fillFromOEmbed(preview *LinkPreview, url string) {
+	if len(url) == 0 {
+		return
+	}
+
+	body, err := p.get(url, "application/json", "text/json", "text/javascript")
+	if err != nil {
+		return
+	}
+
+	oEmbed := struct {
+		Title        string `json:"title"`
+		AuthorName   string `json:"author_name"`
+		ProviderName string `json:"provider_name"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+	}{}
+	if err := json.Unmarshal([]byte(body), &oEmbed); err != nil {
+		return
+	}
+
+	preview.Title = oEmbed.Title
+	preview.Description = firstNonEmpty(preview.Description, oEmbed.AuthorName)
+	preview.Image = firstNonEmpty(preview.Image, oEmbed.ThumbnailUrl)
+	preview.SiteName = firstNonEmpty(preview.SiteName, oEmbed.ProviderName)
+}
+
+// get requests the given url, returning up to maxBytes of its
+// body, or an error if it is not served with one of the given
+// content-types
+func (p *LinkPreviewer) get(url string, contentTypes ...string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", strings.Join(contentTypes, ", "))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected status %q", res.Status)
+	}
+
+	contentType := strings.ToLower(res.Header.Get("Content-Type"))
+	accepted := false
+	for _, t := range contentTypes {
+		if strings.HasPrefix(contentType, t) {
+			accepted = true
+			break
+		}
+	}
+	if !accepted {
+		return "", fmt.Errorf("unexpected content-type %q", contentType)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, p.maxBytes))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// parseTagAttributes returns the unescaped values of the
+// given html tag attributes, by lower-cased attribute name
+func parseTagAttributes(attrs string) map[string]string {
+	parsed := make(map[string]string)
+	for _, match := range tagAttribute.FindAllStringSubmatch(attrs, -1) {
+		parsed[strings.ToLower(match[1])] = html.UnescapeString(match[2] + match[3] + match[4])
+	}
+	return parsed
+}
+
+// resolvePreviewUrl resolves the given, possibly relative, url against
+// the url of the page it was found in. Returns an empty string if the
+// url is not an http(s) url.
+func resolvePreviewUrl(base, ref string) string {
+	if len(ref) == 0 {
+		return ""
+	}
+
+	baseUrl, err := neturl.Parse(base)
+	if err != nil {
+		return ""
+	}
+	refUrl, err := neturl.Parse(ref)
+	if err != nil {
+		return ""
+	}
+
+	resolved := baseUrl.ResolveReference(refUrl)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	return resolved.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
+
+// previewableUrls returns the http(s) urls in the given chat message
+// text that do not link to a video that is embedded in the message
+func previewableUrls(text string) []string {
+	urls := []string{}
+	for _, match := range candidateUrl.FindAllStringSubmatch(text, -1) {
+		if _, isVideo := stream.ParseVideoEmbed(match[1]); isVideo {
+			continue
+		}
+		urls = append(urls, match[1])
+	}
+	return urls
+}
+
+// NewLinkPreviewer returns a LinkPreviewer that issues requests through
+// the given client, previewing up to maxUrls per message, reading up to
+// maxBytes of each page, and caching previews for the given ttl.
+func NewLinkPreviewer(client HTTPClient, maxUrls int, maxBytes int64, ttl time.Duration) *LinkPreviewer {
+	if maxUrls < 1 {
+		maxUrls = 1
+	}
+
+	return &LinkPreviewer{
+		client:   client,
+		maxUrls:  maxUrls,
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		cache:    make(map[string]cachedLinkPreview),
+	}
+}
+
+// NewDefaultLinkPreviewer returns a LinkPreviewer using an http client
+// bounded by DefaultLinkPreviewTimeout, which refuses to connect to
+// loopback, private, or link-local addresses, so that chat messages
+// cannot be used to probe the server's own network.
+func NewDefaultLinkPreviewer() *LinkPreviewer {
+	return NewLinkPreviewer(newPublicHTTPClient(DefaultLinkPreviewTimeout), DefaultLinkPreviewMaxUrls, DefaultLinkPreviewMaxBytes, DefaultLinkPreviewTTL)
+}
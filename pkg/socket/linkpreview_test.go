@@ -0,0 +1,149 @@
+package socket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLinkPreviewer(t *testing.T) {
+	pages := map[string]struct {
+		contentType string
+		body        string
+	}{
+		"/opengraph": {
+			contentType: "text/html; charset=utf-8",
+			body: `<html><head>
+<title>Page title</title>
+<meta property="og:title" content="Open Graph &amp; title">
+<meta property="og:description" content='A description'>
+<meta property="og:image" content="/images/cover.png">
+<meta property="og:site_name" content="Example">
+</head><body></body></html>`,
+		},
+		"/twitter": {
+			contentType: "text/html",
+			body: `<html><head>
+<meta name="twitter:title" content="Twitter title">
+<meta name="description" content="Meta description">
+<meta name="twitter:image" content="https://cdn.example.com/card.jpg">
+</head></html>`,
+		},
+		"/oembed": {
+			contentType: "text/html",
+			body:        `<html><head><link rel="alternate" type="application/json+oembed" href="/oembed.json"></head></html>`,
+		},
+		"/oembed.json": {
+			contentType: "application/json",
+			body:        `{"title": "oEmbed title", "author_name": "Author", "provider_name": "Provider", "thumbnail_url": "https://cdn.example.com/thumb.jpg"}`,
+		},
+		"/title": {
+			contentType: "text/html",
+			body:        `<html><head><title> Only a title </title></head></html>`,
+		},
+		"/untitled": {
+			contentType: "text/html",
+			body:        `<html><body>no metadata</body></html>`,
+		},
+		"/image.png": {
+			contentType: "image/png",
+			body:        "\x89PNG",
+		},
+		"/data": {
+			contentType: "application/json",
+			body:        `{"title": "not a page"}`,
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, exists := pages[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", page.contentType)
+		w.Write([]byte(page.body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		expectPreview *LinkPreview
+	}{
+		{
+			name: "open graph metadata",
+			path: "/opengraph",
+			expectPreview: &LinkPreview{
+				Title:       "Open Graph & title",
+				Description: "A description",
+				Image:       server.URL + "/images/cover.png",
+				SiteName:    "Example",
+			},
+		},
+		{
+			name: "twitter card metadata",
+			path: "/twitter",
+			expectPreview: &LinkPreview{
+				Title:       "Twitter title",
+				Description: "Meta description",
+				Image:       "https://cdn.example.com/card.jpg",
+			},
+		},
+		{
+			name: "oembed metadata",
+			path: "/oembed",
+			expectPreview: &LinkPreview{
+				Title:       "oEmbed title",
+				Description: "Author",
+				Image:       "https://cdn.example.com/thumb.jpg",
+				SiteName:    "Provider",
+			},
+		},
+		{
+			name: "html title",
+			path: "/title",
+			expectPreview: &LinkPreview{
+				Title: "Only a title",
+			},
+		},
+		{
+			name: "html page without a title",
+			path: "/untitled",
+		},
+		{
+			name: "image url",
+			path: "/image.png",
+		},
+		{
+			name: "json url",
+			path: "/data",
+		},
+		{
+			name: "missing page",
+			path: "/missing",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			previewer := NewLinkPreviewer(server.Client(), DefaultLinkPreviewMaxUrls, DefaultLinkPreviewMaxBytes, DefaultLinkPreviewTTL)
+			previews := previewer.Preview([]string{server.URL + tc.path})
+			if tc.expectPreview == nil {
+				if len(previews) != 0 {
+					t.Fatalf("expected no preview, got %+v", *previews[0])
+				}
+				return
+			}
+
+			if len(previews) != 1 {
+				t.Fatalf("expected a single preview, got %v", len(previews))
+			}
+			expected := *tc.expectPreview
+			expected.Url = server.URL + tc.path
+			if *previews[0] != expected {
+				t.Errorf("expected preview %+v, got %+v", expected, *previews[0])
+			}
+		})
+	}
+}
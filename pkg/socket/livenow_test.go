@@ -0,0 +1,133 @@
+package socket
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// nowPlayingResponse is the data of a "livenow" event
+type nowPlayingResponse struct {
+	Extra playback.NowPlayingList `json:"extra"`
+}
+
+func TestLiveNow(t *testing.T) {
+	h, ns := newTestHandler("listed")
+
+	rooms := []struct {
+		name     string
+		viewers  int
+		unlisted bool
+		stream   string
+		elapsed  int
+	}{
+		{name: "listed", viewers: 2, stream: "http://example.com/a.mp4", elapsed: 30},
+		{name: "other", viewers: 1, stream: "http://example.com/b.mp4", elapsed: 90},
+		{name: "unlisted", viewers: 1, unlisted: true, stream: "http://example.com/c.mp4"},
+		{name: "idle", viewers: 1},
+	}
+
+	var conn *fakeConnection
+	for _, r := range rooms {
+		roomNs := ns
+		if r.name != ns.Name() {
+			roomNs = h.nsHandler.NewNamespace(r.name)
+		}
+		for i := 0; i < r.viewers; i++ {
+			c := connect(t, h, roomNs, nil, fmt.Sprintf("%s-%d", r.name, i), fmt.Sprintf("%s%d", r.name, i), "")
+			if conn == nil {
+				conn = c
+			}
+		}
+
+		sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(roomNs)
+		sPlayback.SetListed(!r.unlisted)
+		if len(r.stream) == 0 {
+			continue
+		}
+
+		s := stream.NewRemoteVideoStream(r.stream)
+		if err := s.SetInfo([]byte(fmt.Sprintf(`{"name": %q, "duration": 600, "thumb": %q}`, r.name+" stream", r.stream+".jpg"))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sPlayback.SetStream(s)
+		sPlayback.SetTime(r.elapsed)
+	}
+
+	conn.Emit("request_livenow", connection.NewMessageData())
+
+	res := nowPlayingResponse{}
+	if !conn.lastMessage("livenow", &res) {
+		t.Fatalf("expected a %q event to be sent, got %q", "livenow", conn.sent)
+	}
+
+	live := map[string]playback.NowPlaying{}
+	for _, nowPlaying := range res.Extra.Rooms {
+		live[nowPlaying.Room] = nowPlaying
+	}
+
+	tests := []struct {
+		name             string
+		room             string
+		expectLive       bool
+		expectNowPlaying playback.NowPlaying
+	}{
+		{
+			name:       "listed room with a stream",
+			room:       "listed",
+			expectLive: true,
+			expectNowPlaying: playback.NowPlaying{
+				Room:      "listed",
+				Title:     "listed stream",
+				Url:       "http://example.com/a.mp4",
+				Kind:      stream.STREAM_TYPE_REMOTE,
+				Thumbnail: "http://example.com/a.mp4.jpg",
+				Duration:  600,
+				Elapsed:   30,
+				Viewers:   2,
+			},
+		},
+		{
+			name:       "another listed room with a stream",
+			room:       "other",
+			expectLive: true,
+			expectNowPlaying: playback.NowPlaying{
+				Room:      "other",
+				Title:     "other stream",
+				Url:       "http://example.com/b.mp4",
+				Kind:      stream.STREAM_TYPE_REMOTE,
+				Thumbnail: "http://example.com/b.mp4.jpg",
+				Duration:  600,
+				Elapsed:   90,
+				Viewers:   1,
+			},
+		},
+		{
+			name: "unlisted room with a stream",
+			room: "unlisted",
+		},
+		{
+			name: "listed room with no stream",
+			room: "idle",
+		},
+	}
+
+	if len(res.Extra.Rooms) != 2 {
+		t.Errorf("expected 2 live rooms, got %+v", res.Extra.Rooms)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nowPlaying, isLive := live[tc.room]
+			if isLive != tc.expectLive {
+				t.Fatalf("expected room %q to be live: %v, got %v", tc.room, tc.expectLive, isLive)
+			}
+			if nowPlaying != tc.expectNowPlaying {
+				t.Errorf("expected now playing %+v, got %+v", tc.expectNowPlaying, nowPlaying)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package socket
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	CHAT_SEGMENT_TEXT   = "text"
+	CHAT_SEGMENT_BOLD   = "bold"
+	CHAT_SEGMENT_ITALIC = "italic"
+	CHAT_SEGMENT_CODE   = "code"
+	CHAT_SEGMENT_LINK   = "link"
+)
+
+// ChatSegment is a serializable schema representing a run
+// of a chat message's text sharing a single format
+type ChatSegment struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	// Url is the target of a link segment
+	Url string `json:"url,omitempty"`
+}
+
+// ParseChatMarkdown splits the given chat message text into segments,
+// detecting a safe subset of Markdown: **bold** or __bold__, *italic*
+// or _italic_, `code` spans, and [links](https://example.com) to http(s)
+// urls. Formatting is never nested, and delimiters within words are left
+// as typed. Returns nil if the text contains no Markdown formatting.
+func ParseChatMarkdown(text string) []*ChatSegment {
+	segments := []*ChatSegment{}
+	formatted := false
+
+	start := 0
+	for i := 0; i < len(text); {
+		segment, length := matchChatMarkdown(text, i)
+		if segment == nil {
+			i++
+			continue
+		}
+
+		if i > start {
+			segments = append(segments, &ChatSegment{Type: CHAT_SEGMENT_TEXT, Text: text[start:i]})
+		}
+
+		segments = append(segments, segment)
+		formatted = true
+		i += length
+		start = i
+	}
+
+	if !formatted {
+		return nil
+	}
+	if start < len(text) {
+		segments = append(segments, &ChatSegment{Type: CHAT_SEGMENT_TEXT, Text: text[start:]})
+	}
+	return segments
+}
+
+// matchChatMarkdown returns the formatted segment starting at the given
+// index of the given text, and its length in the text, or nil if none does
+func matchChatMarkdown(text string, i int) (*ChatSegment, int) {
+	switch text[i] {
+	case '`':
+		end := strings.IndexByte(text[i+1:], '`')
+		if end <= 0 {
+			return nil, 0
+		}
+		return &ChatSegment{Type: CHAT_SEGMENT_CODE, Text: text[i+1 : i+1+end]}, end + 2
+	case '*', '_':
+		if !isMarkdownBoundary(text, i, true) {
+			return nil, 0
+		}
+
+		delim := text[i : i+1]
+		segmentType := CHAT_SEGMENT_ITALIC
+		if strings.HasPrefix(text[i+1:], delim) {
+			delim += delim
+			segmentType = CHAT_SEGMENT_BOLD
+		}
+
+		end := strings.Index(text[i+len(delim):], delim)
+		if end <= 0 {
+			return nil, 0
+		}
+
+		inner := text[i+len(delim) : i+len(delim)+end]
+		length := len(delim)*2 + end
+		if strings.TrimSpace(inner) != inner || strings.Contains(inner, delim[:1]) || !isMarkdownBoundary(text, i+length, false) {
+			return nil, 0
+		}
+		return &ChatSegment{Type: segmentType, Text: inner}, length
+	case '[':
+		labelEnd := strings.Index(text[i:], "](")
+		if labelEnd <= 1 {
+			return nil, 0
+		}
+
+		label := text[i+1 : i+labelEnd]
+		if strings.ContainsAny(label, "[]") {
+			return nil, 0
+		}
+
+		urlStart := i + labelEnd + 2
+		urlEnd := strings.IndexByte(text[urlStart:], ')')
+		if urlEnd <= 0 {
+			return nil, 0
+		}
+
+		url := text[urlStart : urlStart+urlEnd]
+		if !isSafeMarkdownUrl(url) {
+			return nil, 0
+		}
+		return &ChatSegment{Type: CHAT_SEGMENT_LINK, Text: label, Url: url}, labelEnd + 2 + urlEnd + 1
+	}
+
+	return nil, 0
+}
+
+// isMarkdownBoundary returns true if the emphasis delimiter opening
+// (or, if not opening, closing) at the given index of the given text
+// is not part of a word, so that snake_case names and arithmetic
+// such as 2*3*4 are left as typed.
+func isMarkdownBoundary(text string, i int, opening bool) bool {
+	var r rune
+	if opening {
+		if i == 0 {
+			return true
+		}
+		r, _ = utf8.DecodeLastRuneInString(text[:i])
+	} else {
+		if i >= len(text) {
+			return true
+		}
+		r, _ = utf8.DecodeRuneInString(text[i:])
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// isSafeMarkdownUrl returns true if the given link target
+// is an http(s) url that could not form markup
+func isSafeMarkdownUrl(url string) bool {
+	lower := strings.ToLower(url)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return false
+	}
+	return !strings.ContainsAny(url, " \t\n<>\"'`")
+}
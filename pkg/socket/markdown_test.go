@@ -0,0 +1,117 @@
+package socket
+
+import (
+	"testing"
+)
+
+func TestParseChatMarkdown(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		expectSegments []ChatSegment
+	}{
+		{
+			name: "plain text",
+			text: "just a message",
+		},
+		{
+			name: "delimiters within words",
+			text: "call some_function_name with 2*3*4",
+		},
+		{
+			name: "unclosed delimiters",
+			text: "a **bold start and `code start",
+		},
+		{
+			name: "delimiters around whitespace",
+			text: "* not italic * and ** not bold **",
+		},
+		{
+			name: "link to an unsafe url",
+			text: "[click](javascript:alert(1))",
+		},
+		{
+			name: "link with an empty label",
+			text: "[](https://example.com)",
+		},
+		{
+			name: "bold",
+			text: "this is **important** and __this__ too",
+			expectSegments: []ChatSegment{
+				{Type: CHAT_SEGMENT_TEXT, Text: "this is "},
+				{Type: CHAT_SEGMENT_BOLD, Text: "important"},
+				{Type: CHAT_SEGMENT_TEXT, Text: " and "},
+				{Type: CHAT_SEGMENT_BOLD, Text: "this"},
+				{Type: CHAT_SEGMENT_TEXT, Text: " too"},
+			},
+		},
+		{
+			name: "italic",
+			text: "*soft* and _quiet_",
+			expectSegments: []ChatSegment{
+				{Type: CHAT_SEGMENT_ITALIC, Text: "soft"},
+				{Type: CHAT_SEGMENT_TEXT, Text: " and "},
+				{Type: CHAT_SEGMENT_ITALIC, Text: "quiet"},
+			},
+		},
+		{
+			name: "code",
+			text: "run `go test ./...` now",
+			expectSegments: []ChatSegment{
+				{Type: CHAT_SEGMENT_TEXT, Text: "run "},
+				{Type: CHAT_SEGMENT_CODE, Text: "go test ./..."},
+				{Type: CHAT_SEGMENT_TEXT, Text: " now"},
+			},
+		},
+		{
+			name: "formatting is not nested within code",
+			text: "`**not bold**`",
+			expectSegments: []ChatSegment{
+				{Type: CHAT_SEGMENT_CODE, Text: "**not bold**"},
+			},
+		},
+		{
+			name: "link",
+			text: "see [the docs](https://example.com/docs?page=1).",
+			expectSegments: []ChatSegment{
+				{Type: CHAT_SEGMENT_TEXT, Text: "see "},
+				{Type: CHAT_SEGMENT_LINK, Text: "the docs", Url: "https://example.com/docs?page=1"},
+				{Type: CHAT_SEGMENT_TEXT, Text: "."},
+			},
+		},
+		{
+			name: "every construct",
+			text: "**a** *b* `c` [d](http://example.com)",
+			expectSegments: []ChatSegment{
+				{Type: CHAT_SEGMENT_BOLD, Text: "a"},
+				{Type: CHAT_SEGMENT_TEXT, Text: " "},
+				{Type: CHAT_SEGMENT_ITALIC, Text: "b"},
+				{Type: CHAT_SEGMENT_TEXT, Text: " "},
+				{Type: CHAT_SEGMENT_CODE, Text: "c"},
+				{Type: CHAT_SEGMENT_TEXT, Text: " "},
+				{Type: CHAT_SEGMENT_LINK, Text: "d", Url: "http://example.com"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			segments := ParseChatMarkdown(tc.text)
+			if tc.expectSegments == nil {
+				if segments != nil {
+					t.Fatalf("expected plain text to be left unformatted, got %v segments", len(segments))
+				}
+				return
+			}
+
+			if len(segments) != len(tc.expectSegments) {
+				t.Fatalf("expected %v segments, got %v", len(tc.expectSegments), len(segments))
+			}
+			for i, expected := range tc.expectSegments {
+				if *segments[i] != expected {
+					t.Errorf("expected segment %v to be %+v, got %+v", i, expected, *segments[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestParseMessageMedia(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		expectUrls []string
+		expectText string
+	}{
+		{
+			name:       "jpg",
+			message:    "look http://example.com/a.jpg",
+			expectUrls: []string{"http://example.com/a.jpg"},
+			expectText: "look ",
+		},
+		{
+			name:       "webp",
+			message:    "look http://example.com/a.webp",
+			expectUrls: []string{"http://example.com/a.webp"},
+			expectText: "look ",
+		},
+		{
+			name:       "svg",
+			message:    "https://example.com/logo.svg is the logo",
+			expectUrls: []string{"https://example.com/logo.svg"},
+			expectText: "is the logo",
+		},
+		{
+			name:       "bmp",
+			message:    "look http://example.com/a.bmp",
+			expectUrls: []string{"http://example.com/a.bmp"},
+			expectText: "look ",
+		},
+		{
+			name:       "avif",
+			message:    "look http://example.com/a.avif",
+			expectUrls: []string{"http://example.com/a.avif"},
+			expectText: "look ",
+		},
+		{
+			name:       "mixed case extension",
+			message:    "look http://example.com/a.WebP",
+			expectUrls: []string{"http://example.com/a.WebP"},
+			expectText: "look ",
+		},
+		{
+			name:       "upper case extension",
+			message:    "look http://example.com/a.AVIF",
+			expectUrls: []string{"http://example.com/a.AVIF"},
+			expectText: "look ",
+		},
+		{
+			name:       "query string after the extension",
+			message:    "look http://example.com/a.webp?w=200&h=100",
+			expectUrls: []string{"http://example.com/a.webp?w=200&h=100"},
+			expectText: "look ",
+		},
+		{
+			name:       "query string and fragment after the extension",
+			message:    "look http://example.com/a.svg?v=2#icon",
+			expectUrls: []string{"http://example.com/a.svg?v=2#icon"},
+			expectText: "look ",
+		},
+		{
+			name:       "several images",
+			message:    "http://example.com/a.bmp http://example.com/b.avif",
+			expectUrls: []string{"http://example.com/a.bmp", "http://example.com/b.avif"},
+			expectText: "",
+		},
+		{
+			name:       "extension in the middle of the path",
+			message:    "look http://example.com/a.webp/page",
+			expectUrls: []string{},
+			expectText: "look http://example.com/a.webp/page",
+		},
+		{
+			name:       "extension only in the query string",
+			message:    "look http://example.com/page?img=a.svg",
+			expectUrls: []string{},
+			expectText: "look http://example.com/page?img=a.svg",
+		},
+		{
+			name:       "unsupported extension",
+			message:    "look http://example.com/a.tiff",
+			expectUrls: []string{},
+			expectText: "look http://example.com/a.tiff",
+		},
+	}
+
+	h := &Handler{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := connection.NewMessageData()
+			data.Set("message", tc.message)
+
+			urls, err := h.ParseMessageMedia(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(urls) != len(tc.expectUrls) {
+				t.Fatalf("expected image urls %q, got %q", tc.expectUrls, urls)
+			}
+			for i := range urls {
+				if urls[i] != tc.expectUrls[i] {
+					t.Errorf("expected image urls %q, got %q", tc.expectUrls, urls)
+					break
+				}
+			}
+
+			text, _ := data.Key("message")
+			if text != tc.expectText {
+				t.Errorf("expected message text %q, got %q", tc.expectText, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestChatMessageMentions(t *testing.T) {
+	h, ns := newTestHandler("room")
+	sender := connect(t, h, ns, nil, "alice", "alice", "")
+	clients := map[string]*fakeConnection{
+		"bob":   connect(t, h, ns, nil, "bob", "bob", ""),
+		"carol": connect(t, h, ns, nil, "carol", "carol", ""),
+		"dave":  connect(t, h, ns, nil, "dave", "dave", ""),
+	}
+
+	tests := []struct {
+		name           string
+		message        string
+		expectMentions []string
+	}{
+		{
+			name:           "single mention",
+			message:        "hey @bob",
+			expectMentions: []string{"bob"},
+		},
+		{
+			name:           "multiple mentions",
+			message:        "@bob and @carol, look",
+			expectMentions: []string{"bob", "carol"},
+		},
+		{
+			name:           "repeated mention",
+			message:        "@dave @dave!",
+			expectMentions: []string{"dave"},
+		},
+		{
+			name:    "nonexistent username",
+			message: "hey @nobody",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, conn := range clients {
+				conn.clearMessages()
+			}
+
+			data := connection.NewMessageData()
+			data.Set("message", tc.message)
+			sender.Emit("request_chatmessage", data)
+
+			res := chatMessageResponse{}
+			if !clients["bob"].lastMessage("chatmessage", &res) {
+				t.Fatalf("expected a %q event to be broadcast, got %q", "chatmessage", clients["bob"].sent)
+			}
+			if res.Message != tc.message {
+				t.Errorf("expected the message text to be left as %q, got %q", tc.message, res.Message)
+			}
+
+			mentions, _ := res.Extra["mentions"].([]interface{})
+			if len(mentions) != len(tc.expectMentions) {
+				t.Fatalf("expected mentions %v, got %v", tc.expectMentions, res.Extra["mentions"])
+			}
+			for i := range mentions {
+				if mentions[i] != tc.expectMentions[i] {
+					t.Errorf("expected mentions %v, got %v", tc.expectMentions, mentions)
+					break
+				}
+			}
+
+			expectNotified := map[string]bool{}
+			for _, name := range tc.expectMentions {
+				expectNotified[name] = true
+			}
+			for name, conn := range clients {
+				mentioned := conn.messages("mention")
+				if expectNotified[name] && len(mentioned) != 1 {
+					t.Errorf("expected %q to be sent a single %q event, got %v", name, "mention", len(mentioned))
+				}
+				if !expectNotified[name] && len(mentioned) != 0 {
+					t.Errorf("expected %q not to be sent a %q event, got %v", name, "mention", len(mentioned))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package socket
+
+import "github.com/juanvallejo/streaming-server/pkg/metrics"
+
+var chatMessages = metrics.NewCounter("streaming_chat_messages_total", "Number of chat messages broadcast to rooms.")
+
+func init() {
+	metrics.MustRegister(chatMessages)
+}
+
+// RegisterMetrics adds a gauge reporting the number of
+// connected clients to the given registry.
+func (h *Handler) RegisterMetrics(registry *metrics.Registry) error {
+	return registry.Register(
+		metrics.NewGaugeFunc("streaming_clients", "Number of connected clients.", func() float64 {
+			return float64(len(h.clientHandler.Clients()))
+		}),
+	)
+}
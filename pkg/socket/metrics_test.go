@@ -0,0 +1,90 @@
+package socket
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/metrics"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// scrapeMetrics requests the given registry's metrics endpoint and
+// returns the value of each sample, keyed by its name and labels
+func scrapeMetrics(t *testing.T, registry *metrics.Registry) map[string]float64 {
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %v scraping metrics, got %v", http.StatusOK, rec.Code)
+	}
+
+	samples := map[string]float64{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.LastIndex(line, " ")
+		if idx < 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(line[idx+1:], 64)
+		if err != nil {
+			t.Fatalf("unexpected error parsing sample %q: %v", line, err)
+		}
+		samples[line[:idx]] = value
+	}
+	return samples
+}
+
+func TestMetrics(t *testing.T) {
+	h, ns := newTestHandler("room")
+	sender := connect(t, h, ns, nil, "sender", "sender", "")
+	connect(t, h, ns, nil, "receiver", "receiver", "")
+
+	tests := []struct {
+		name         string
+		message      string
+		expectSample string
+	}{
+		{
+			name:         "chat broadcast",
+			message:      "hello",
+			expectSample: "streaming_chat_messages_total",
+		},
+		{
+			name:         "command execution",
+			message:      "/nowplaying",
+			expectSample: `streaming_commands_executed_total{command="nowplaying"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before := scrapeMetrics(t, metrics.Default)
+
+			data := connection.NewMessageData()
+			data.Set("message", tc.message)
+			sender.Emit("request_chatmessage", data)
+
+			after := scrapeMetrics(t, metrics.Default)
+			if value, exists := after[tc.expectSample]; !exists || value != before[tc.expectSample]+1 {
+				t.Errorf("expected %s to be incremented from %v, got %v", tc.expectSample, before[tc.expectSample], value)
+			}
+		})
+	}
+
+	t.Run("connected clients", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+		if err := h.RegisterMetrics(registry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if clients := scrapeMetrics(t, registry)["streaming_clients"]; clients != 2 {
+			t.Errorf("expected %v connected clients, got %v", 2, clients)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package socket
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// pinsResponse is the data of a "pins" event
+type pinsResponse struct {
+	Extra struct {
+		Messages []chatMessageResponse `json:"messages"`
+	} `json:"extra"`
+}
+
+func TestPinnedMessages(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	moderator := connect(t, h, ns, authorizer, "moderator", "moderator", rbac.ADMIN_ROLE)
+	alice := connect(t, h, ns, authorizer, "alice", "alice", rbac.USER_ROLE)
+	bob := connect(t, h, ns, authorizer, "bob", "bob", rbac.USER_ROLE)
+	observer := connect(t, h, ns, authorizer, "observer", "observer", rbac.USER_ROLE)
+
+	// messageIds holds the id of the message sent by each client
+	messageIds := map[*fakeConnection]string{}
+	for _, conn := range []*fakeConnection{alice, bob} {
+		data := connection.NewMessageData()
+		data.Set("message", "sent by "+conn.UUID())
+		conn.Emit("request_chatmessage", data)
+
+		msg := chatMessageResponse{}
+		if !observer.lastMessage("chatmessage", &msg) {
+			t.Fatalf("expected a %q event to be broadcast, got %q", "chatmessage", observer.sent)
+		}
+		messageIds[conn], _ = msg.Extra[playback.ChatMessageIdKey].(string)
+	}
+
+	tests := []struct {
+		name         string
+		conn         *fakeConnection
+		cmd          string
+		sentBy       *fakeConnection
+		expectErr    string
+		expectOutput string
+		expectPinned []string
+	}{
+		{
+			name:         "pin a message",
+			conn:         moderator,
+			cmd:          "pin",
+			sentBy:       alice,
+			expectOutput: "the message has been pinned.",
+			expectPinned: []string{"sent by alice"},
+		},
+		{
+			name:      "pin a message that is already pinned",
+			conn:      moderator,
+			cmd:       "pin",
+			sentBy:    alice,
+			expectErr: "already pinned",
+		},
+		{
+			name:      "pin without authorization",
+			conn:      bob,
+			cmd:       "pin",
+			sentBy:    bob,
+			expectErr: "not authorized",
+		},
+		{
+			name:         "pin replaces the oldest pinned message",
+			conn:         moderator,
+			cmd:          "pin",
+			sentBy:       bob,
+			expectOutput: "replacing the message from \"alice\"",
+			expectPinned: []string{"sent by bob"},
+		},
+		{
+			name:      "unpin a message that is not pinned",
+			conn:      moderator,
+			cmd:       "unpin",
+			sentBy:    alice,
+			expectErr: "that message is not pinned",
+		},
+		{
+			name:         "unpin a message",
+			conn:         moderator,
+			cmd:          "unpin",
+			sentBy:       bob,
+			expectOutput: "the message has been unpinned.",
+			expectPinned: []string{},
+		},
+		{
+			name:      "unpin with nothing pinned",
+			conn:      moderator,
+			cmd:       "unpin",
+			expectErr: "there are no pinned messages",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			observer.clearMessages()
+
+			args := []string{}
+			if tc.sentBy != nil {
+				args = append(args, messageIds[tc.sentBy])
+			}
+			c, _ := h.clientHandler.GetClient(tc.conn.UUID())
+			output, err := h.CommandHandler.ExecuteCommand(tc.cmd, args, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler)
+
+			pins := pinsResponse{}
+			broadcast := observer.lastMessage("pins", &pins)
+			if len(tc.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectErr, err)
+				}
+				if broadcast {
+					t.Errorf("expected no %q event to be broadcast, got %q", "pins", observer.sent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(output, tc.expectOutput) {
+				t.Errorf("expected output containing %q, got %q", tc.expectOutput, output)
+			}
+			if !broadcast {
+				t.Fatalf("expected a %q event to be broadcast, got %q", "pins", observer.sent)
+			}
+
+			pinned := []string{}
+			for _, msg := range pins.Extra.Messages {
+				pinned = append(pinned, msg.Message)
+				if msg.Extra[playback.ChatPinnedByKey] != "moderator" {
+					t.Errorf("expected message %q to be pinned by %q, got %v", msg.Message, "moderator", msg.Extra[playback.ChatPinnedByKey])
+				}
+			}
+			if strings.Join(pinned, ",") != strings.Join(tc.expectPinned, ",") {
+				t.Errorf("expected pinned messages %q, got %q", tc.expectPinned, pinned)
+			}
+		})
+	}
+}
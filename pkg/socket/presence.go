@@ -0,0 +1,28 @@
+package socket
+
+import (
+	"time"
+)
+
+// PresenceCheckInterval is the amount of time between
+// checks for clients whose presence has changed
+var PresenceCheckInterval = 15 * time.Second
+
+// watchPresence periodically notifies rooms of any
+// clients that have become idle, or active again.
+func (h *Handler) watchPresence() {
+	ticker := time.NewTicker(PresenceCheckInterval)
+	go func() {
+		for range ticker.C {
+			for _, c := range h.clientHandler.Clients() {
+				if _, inRoom := c.Namespace(); !inRoom {
+					continue
+				}
+
+				if presence, changed := c.UpdatePresence(); changed {
+					c.BroadcastPresence(presence)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"strings"
+	"sync"
+)
+
+// MessageHandler receives the channel a message was
+// published to, and the message's contents.
+type MessageHandler func(channel string, data []byte)
+
+// Broker publishes messages to named channels, and delivers
+// messages published to channels matching a pattern to subscribers.
+// Patterns consist of a channel prefix followed by a "*".
+type Broker interface {
+	// Publish sends the given data to every subscriber
+	// of a pattern matching the given channel.
+	Publish(channel string, data []byte) error
+	// PSubscribe calls the given handler with every message
+	// published to a channel matching the given pattern.
+	PSubscribe(pattern string, handler MessageHandler) error
+	// Close stops delivering messages to the broker's subscribers
+	Close() error
+}
+
+// MemoryBroker implements Broker, delivering messages to
+// subscribers within the current process. It is safe for
+// concurrent use.
+type MemoryBroker struct {
+	mutex       sync.Mutex
+	subscribers map[string][]MessageHandler
+}
+
+func (b *MemoryBroker) Publish(channel string, data []byte) error {
+	b.mutex.Lock()
+	handlers := []MessageHandler{}
+	for pattern, subs := range b.subscribers {
+		if MatchPattern(pattern, channel) {
+			handlers = append(handlers, subs...)
+		}
+	}
+	b.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(channel, data)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) PSubscribe(pattern string, handler MessageHandler) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscribers[pattern] = append(b.subscribers[pattern], handler)
+	return nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscribers = make(map[string][]MessageHandler)
+	return nil
+}
+
+// MatchPattern returns true if the given channel matches the given
+// pattern. A pattern ending in "*" matches every channel beginning
+// with the rest of the pattern; any other pattern matches only itself.
+func MatchPattern(pattern, channel string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(channel, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == channel
+}
+
+func NewMemoryBroker() Broker {
+	return &MemoryBroker{
+		subscribers: make(map[string][]MessageHandler),
+	}
+}
@@ -0,0 +1,271 @@
+package pubsub
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/logging"
+)
+
+const (
+	redisDialTimeout    = 5 * time.Second
+	redisReconnectDelay = 2 * time.Second
+)
+
+// RedisBroker implements Broker using the publish / subscribe
+// commands of a Redis server, allowing messages to be delivered
+// to subscribers in other processes. Subscriptions are
+// re-established if the connection to the server is lost.
+// It is safe for concurrent use.
+type RedisBroker struct {
+	addr string
+
+	// mutex guards the connection used to publish messages
+	mutex  sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+
+	subMutex sync.Mutex
+	subConns []net.Conn
+	closed   chan struct{}
+}
+
+func (b *RedisBroker) Publish(channel string, data []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.conn == nil {
+		conn, err := b.dial()
+		if err != nil {
+			return err
+		}
+		b.conn = conn
+		b.reader = bufio.NewReader(conn)
+	}
+
+	_, err := b.conn.Write(encodeRedisCommand("PUBLISH", channel, string(data)))
+	if err == nil {
+		_, err = readRedisReply(b.reader)
+	}
+	if err != nil {
+		b.conn.Close()
+		b.conn = nil
+		return fmt.Errorf("unable to publish to redis channel %q: %v", channel, err)
+	}
+	return nil
+}
+
+// PSubscribe subscribes to the given pattern on a dedicated connection.
+// An error is returned if the Redis server cannot be reached.
+func (b *RedisBroker) PSubscribe(pattern string, handler MessageHandler) error {
+	conn, err := b.subscribe(pattern)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			err := b.receive(conn, handler)
+			if b.isClosed() {
+				return
+			}
+			logging.Default.Errorf("PUBSUB lost redis subscription to %q: %v", pattern, err)
+
+			for {
+				time.Sleep(redisReconnectDelay)
+				if b.isClosed() {
+					return
+				}
+				if conn, err = b.subscribe(pattern); err == nil {
+					logging.Default.Infof("PUBSUB resubscribed to %q", pattern)
+					break
+				}
+				logging.Default.Errorf("PUBSUB unable to resubscribe to %q: %v", pattern, err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *RedisBroker) Close() error {
+	b.subMutex.Lock()
+	select {
+	case <-b.closed:
+	default:
+		close(b.closed)
+	}
+	for _, conn := range b.subConns {
+		conn.Close()
+	}
+	b.subConns = nil
+	b.subMutex.Unlock()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+	return nil
+}
+
+func (b *RedisBroker) isClosed() bool {
+	select {
+	case <-b.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *RedisBroker) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", b.addr, redisDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to redis server at %q: %v", b.addr, err)
+	}
+	return conn, nil
+}
+
+// subscribe opens a new connection subscribed to the given pattern
+func (b *RedisBroker) subscribe(pattern string) (net.Conn, error) {
+	conn, err := b.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Write(encodeRedisCommand("PSUBSCRIBE", pattern)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to subscribe to %q: %v", pattern, err)
+	}
+
+	b.subMutex.Lock()
+	defer b.subMutex.Unlock()
+	if b.isClosed() {
+		conn.Close()
+		return nil, fmt.Errorf("broker is closed")
+	}
+	b.subConns = append(b.subConns, conn)
+	return conn, nil
+}
+
+// receive delivers messages received on the given subscribed
+// connection to the given handler until the connection fails.
+func (b *RedisBroker) receive(conn net.Conn, handler MessageHandler) error {
+	defer b.forget(conn)
+
+	reader := bufio.NewReader(conn)
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return err
+		}
+
+		// messages matching a pattern are received as
+		// ["pmessage", pattern, channel, data]
+		fields, ok := reply.([]interface{})
+		if !ok || len(fields) != 4 || fields[0] != "pmessage" {
+			continue
+		}
+
+		channel, _ := fields[2].(string)
+		data, _ := fields[3].(string)
+		handler(channel, []byte(data))
+	}
+}
+
+// forget closes the given subscribed connection and stops tracking it
+func (b *RedisBroker) forget(conn net.Conn) {
+	conn.Close()
+
+	b.subMutex.Lock()
+	defer b.subMutex.Unlock()
+	for i, c := range b.subConns {
+		if c == conn {
+			b.subConns = append(b.subConns[:i], b.subConns[i+1:]...)
+			return
+		}
+	}
+}
+
+// encodeRedisCommand encodes the given command
+// arguments as an array of bulk strings.
+func encodeRedisCommand(args ...string) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	return buf
+}
+
+// readRedisReply reads a single reply from the given reader. Simple
+// and bulk strings are returned as strings, integers as int64s, and
+// arrays as slices of replies. Error replies are returned as errors.
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed redis bulk string length %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed redis array length %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		items := make([]interface{}, 0, size)
+		for i := 0; i < size; i++ {
+			item, err := readRedisReply(r)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("unknown redis reply type %q", kind)
+}
+
+// NewRedisBroker returns a Broker backed by the
+// Redis server listening on the given address.
+func NewRedisBroker(addr string) Broker {
+	return &RedisBroker{
+		addr:   addr,
+		closed: make(chan struct{}),
+	}
+}
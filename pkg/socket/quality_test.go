@@ -0,0 +1,88 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestSetQuality(t *testing.T) {
+	h, ns := newTestHandler("room")
+	viewer := connect(t, h, ns, nil, "viewer", "viewer", "")
+	other := connect(t, h, ns, nil, "other", "other", "")
+
+	tests := []struct {
+		name          string
+		preferred     string
+		actual        string
+		expectErr     bool
+		expectQuality client.Quality
+	}{
+		{
+			name:          "preferred quality",
+			preferred:     "1080p",
+			expectQuality: client.Quality{Preferred: "1080p", Actual: client.QUALITY_AUTO},
+		},
+		{
+			name:          "actual quality leaves the preferred quality unchanged",
+			actual:        "720P",
+			expectQuality: client.Quality{Preferred: "1080p", Actual: "720p"},
+		},
+		{
+			name:          "unknown quality",
+			preferred:     "4k",
+			expectErr:     true,
+			expectQuality: client.Quality{Preferred: "1080p", Actual: "720p"},
+		},
+		{
+			name:          "no quality",
+			expectErr:     true,
+			expectQuality: client.Quality{Preferred: "1080p", Actual: "720p"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			viewer.clearMessages()
+			other.clearMessages()
+
+			data := connection.NewMessageData()
+			if len(tc.preferred) > 0 {
+				data.Set("preferred", tc.preferred)
+			}
+			if len(tc.actual) > 0 {
+				data.Set("actual", tc.actual)
+			}
+			viewer.Emit("request_setquality", data)
+
+			if errored := len(viewer.messages("info_clienterror")) > 0; errored != tc.expectErr {
+				t.Errorf("expected error: %v, got %v", tc.expectErr, errored)
+			}
+			if !tc.expectErr && len(viewer.messages("quality")) != 1 {
+				t.Errorf("expected a %q event to be sent, got %q", "quality", viewer.sent)
+			}
+
+			other.Emit("request_userlist", connection.NewMessageData())
+
+			roster := client.SerializableClientList{}
+			if !other.lastMessage("userlist", &roster) {
+				t.Fatalf("expected a %q event to be sent, got %q", "userlist", other.sent)
+			}
+
+			found := false
+			for _, c := range roster.Clients {
+				if c.Id != viewer.UUID() {
+					continue
+				}
+				found = true
+				if c.Quality != tc.expectQuality {
+					t.Errorf("expected quality %+v in the roster, got %+v", tc.expectQuality, c.Quality)
+				}
+			}
+			if !found {
+				t.Fatalf("expected client %q in the roster, got %+v", viewer.UUID(), roster.Clients)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package socket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// MaxQueueImportSize is the maximum size, in bytes,
+// of a playlist imported into a room's queue
+var MaxQueueImportSize int64 = 1 << 20
+
+// serveQueueAPI handles REST requests for a room's queue on behalf of
+// the given client. Changes are made through the same commands used by
+// socket clients, so they are authorized and broadcast in the same way.
+func (h *Handler) serveQueueAPI(w http.ResponseWriter, r *http.Request, c *client.Client, segs []string) {
+	switch {
+	case r.Method == http.MethodGet && len(segs) == 3:
+		sPlayback, err := h.getPlaybackFromClient(c)
+		if err != nil {
+			writeRoomAPIError(w, http.StatusNotFound, err)
+			return
+		}
+
+		b, err := sPlayback.GetQueue().Serialize()
+		if err != nil {
+			writeRoomAPIError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+	case r.Method == http.MethodPost && len(segs) == 3:
+		url, err := queueAPIRequestUrl(r)
+		if err != nil {
+			writeRoomAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		h.executeQueueAPICommand(w, c, "queue", []string{"add", url})
+	case r.Method == http.MethodPost && len(segs) == 4 && segs[3] == "import":
+		h.serveQueueImport(w, r, c)
+	case r.Method == http.MethodDelete && len(segs) == 4 && len(segs[3]) > 0:
+		h.executeQueueAPICommand(w, c, "unqueue", []string{segs[3]})
+	default:
+		writeRoomAPIError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed for %q", r.Method, r.URL.Path))
+	}
+}
+
+// executeQueueAPICommand runs the given command on behalf of the given
+// client, writing the command's output, or the reason it failed.
+func (h *Handler) executeQueueAPICommand(w http.ResponseWriter, c *client.Client, name string, args []string) {
+	output, status, err := h.runQueueAPICommand(c, name, args)
+	if err != nil {
+		writeRoomAPIError(w, status, err)
+		return
+	}
+
+	writeRoomAPIResponse(w, &endpoint.ApiResponse{
+		Message:  output,
+		HTTPCode: status,
+	})
+}
+
+// runQueueAPICommand runs the given command on behalf of the given
+// client, authorized by the command handler like any other command.
+// Returns the command's output and the http status describing its
+// result, or an error and the status describing it.
+func (h *Handler) runQueueAPICommand(c *client.Client, name string, args []string) (string, int, error) {
+	if _, exists := h.CommandHandler.Commands()[name]; !exists {
+		return "", http.StatusNotFound, fmt.Errorf("the %q command is not available", name)
+	}
+
+	h.logger.Infof("API client with id %q executing command %q", c.UUID(), cmd.LoggableCommand(h.CommandHandler, name, args))
+	output, err := h.CommandHandler.ExecuteCommand(name, args, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler)
+	if errors.Is(err, cmd.ErrNotAuthorized) {
+		return "", http.StatusForbidden, err
+	}
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	return output, http.StatusOK, nil
+}
+
+// serveQueueImport queues every stream listed by the JSON, M3U, or PLS
+// playlist given as the request's body. The playlist's format is read
+// from the request's format query parameter or Content-Type header, or
+// detected from its contents. Malformed entries are skipped and reported
+// along with the streams that could not be queued.
+func (h *Handler) serveQueueImport(w http.ResponseWriter, r *http.Request, c *client.Client) {
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		format = stream.PlaylistFormatFromContentType(r.Header.Get("Content-Type"))
+	}
+
+	urls, skipped, err := stream.ParsePlaylistFile(io.LimitReader(r.Body, MaxQueueImportSize), format)
+	if err != nil {
+		writeRoomAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	notes := make([]string, 0, len(skipped))
+	for _, err := range skipped {
+		notes = append(notes, err.Error())
+	}
+	if len(urls) == 0 {
+		writeRoomAPIError(w, http.StatusBadRequest, fmt.Errorf("no valid stream urls were found in the given playlist %v", notes))
+		return
+	}
+
+	output, status, err := h.runQueueAPICommand(c, cmd.QUEUE_NAME, append([]string{"import"}, urls...))
+	if err != nil {
+		writeRoomAPIError(w, status, err)
+		return
+	}
+	if len(notes) > 0 {
+		output += fmt.Sprintf("; skipped %d malformed entries: %s", len(notes), strings.Join(notes, "; "))
+	}
+
+	writeRoomAPIResponse(w, &endpoint.ApiResponse{
+		Message:  output,
+		HTTPCode: status,
+	})
+}
+
+// queueAPIRequestUrl returns the url to queue given with the request,
+// either as a form value or as the "url" field of a JSON body.
+func queueAPIRequestUrl(r *http.Request) (string, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body := struct {
+			Url string `json:"url"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("malformed request body: %v", err)
+		}
+		if len(body.Url) > 0 {
+			return body.Url, nil
+		}
+	} else if url := r.FormValue("url"); len(url) > 0 {
+		return url, nil
+	}
+
+	return "", fmt.Errorf("a url to queue is required")
+}
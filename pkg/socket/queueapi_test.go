@@ -0,0 +1,161 @@
+package socket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+// queueListResponse is the body of a queue API listing
+type queueListResponse struct {
+	Items []struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"items"`
+}
+
+func TestQueueAPI(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	alice := connect(t, h, ns, authorizer, "alice", "alice", rbac.USER_ROLE)
+	bob := connect(t, h, ns, authorizer, "bob", "bob", rbac.USER_ROLE)
+
+	// streams queued while another one is playing stay in the queue
+	sPlayback := loadStream(t, h, ns, "playing.mp4", 600)
+	if err := sPlayback.Play(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stubMetadata(h, "http://example.com/a.mp4", `{"name": "A", "duration": 60}`)
+	stubMetadata(h, "http://example.com/b.mp4", `{"name": "B", "duration": 120}`)
+
+	// the queue lists the next stream queued by each client,
+	// so every stream is queued through a different client
+	tokens := map[*fakeConnection]string{}
+	for _, conn := range []*fakeConnection{alice, bob} {
+		token, exists := h.sessions.token(conn.UUID())
+		if !exists {
+			t.Fatalf("expected a session to be issued for client %q", conn.UUID())
+		}
+		tokens[conn] = token
+	}
+	observer := connect(t, h, ns, authorizer, "observer", "observer", rbac.VIEWER_ROLE)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		body            string
+		contentType     string
+		conn            *fakeConnection
+		expectStatus    int
+		expectQueue     []string
+		expectQueueSync bool
+	}{
+		{
+			name:            "enqueue with a form value",
+			method:          http.MethodPost,
+			path:            "/rooms/room/queue",
+			body:            "url=" + url.QueryEscape("http://example.com/a.mp4"),
+			contentType:     "application/x-www-form-urlencoded",
+			conn:            alice,
+			expectStatus:    http.StatusOK,
+			expectQueue:     []string{"http://example.com/a.mp4"},
+			expectQueueSync: true,
+		},
+		{
+			name:            "enqueue with a JSON body",
+			method:          http.MethodPost,
+			path:            "/rooms/room/queue",
+			body:            `{"url": "http://example.com/b.mp4"}`,
+			contentType:     "application/json",
+			conn:            bob,
+			expectStatus:    http.StatusOK,
+			expectQueue:     []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+			expectQueueSync: true,
+		},
+		{
+			name:         "enqueue without a url",
+			method:       http.MethodPost,
+			path:         "/rooms/room/queue",
+			contentType:  "application/json",
+			body:         `{}`,
+			conn:         alice,
+			expectStatus: http.StatusBadRequest,
+			expectQueue:  []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+		},
+		{
+			name:            "delete a queued item",
+			method:          http.MethodDelete,
+			path:            "/rooms/room/queue/" + url.PathEscape("http://example.com/a.mp4"),
+			conn:            alice,
+			expectStatus:    http.StatusOK,
+			expectQueue:     []string{"http://example.com/b.mp4"},
+			expectQueueSync: true,
+		},
+		{
+			name:         "delete an item that is not queued",
+			method:       http.MethodDelete,
+			path:         "/rooms/room/queue/" + url.PathEscape("http://example.com/a.mp4"),
+			conn:         alice,
+			expectStatus: http.StatusBadRequest,
+			expectQueue:  []string{"http://example.com/b.mp4"},
+		},
+		{
+			name:         "request without a session",
+			method:       http.MethodDelete,
+			path:         "/rooms/room/queue/" + url.PathEscape("http://example.com/b.mp4"),
+			expectStatus: http.StatusUnauthorized,
+			expectQueue:  []string{"http://example.com/b.mp4"},
+		},
+		{
+			name:         "request with a session for another room",
+			method:       http.MethodDelete,
+			path:         "/rooms/other/queue/" + url.PathEscape("http://example.com/b.mp4"),
+			conn:         alice,
+			expectStatus: http.StatusUnauthorized,
+			expectQueue:  []string{"http://example.com/b.mp4"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			observer.clearMessages()
+
+			req := httptest.NewRequest(tc.method, tc.path+"?session="+tokens[tc.conn], strings.NewReader(tc.body))
+			if len(tc.contentType) > 0 {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeRoomAPI(w, req)
+			if w.Code != tc.expectStatus {
+				t.Fatalf("expected status %v, got %v: %s", tc.expectStatus, w.Code, w.Body.String())
+			}
+
+			if synced := len(observer.messages("queuesync")) > 0; synced != tc.expectQueueSync {
+				t.Errorf("expected a %q event to be broadcast: %v, got %v", "queuesync", tc.expectQueueSync, synced)
+			}
+
+			w = httptest.NewRecorder()
+			h.ServeRoomAPI(w, httptest.NewRequest(http.MethodGet, "/rooms/room/queue?session="+tokens[alice], nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %v listing the queue, got %v: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			res := queueListResponse{}
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			urls := []string{}
+			for _, item := range res.Items {
+				urls = append(urls, item.Url)
+			}
+			if strings.Join(urls, ",") != strings.Join(tc.expectQueue, ",") {
+				t.Errorf("expected queue %v, got %v", tc.expectQueue, urls)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package socket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint"
+	playbackutil "github.com/juanvallejo/streaming-server/pkg/playback/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+)
+
+func TestQueueImport(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		contentType   string
+		playlist      string
+		expectStatus  int
+		expectQueue   []string
+		expectMessage []string
+	}{
+		{
+			name:         "json playlist",
+			contentType:  "application/json",
+			playlist:     `["http://example.com/a.mp4", {"url": "http://example.com/b.mp4"}]`,
+			expectStatus: http.StatusOK,
+			expectQueue:  []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+			expectMessage: []string{
+				"imported 2 of 2 items",
+			},
+		},
+		{
+			name:         "m3u playlist",
+			contentType:  "audio/x-mpegurl",
+			playlist:     "#EXTM3U\n#EXTINF:60,A\nhttp://example.com/a.mp4\n#EXTINF:120,B\nhttp://example.com/b.mp4\n",
+			expectStatus: http.StatusOK,
+			expectQueue:  []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+			expectMessage: []string{
+				"imported 2 of 2 items",
+			},
+		},
+		{
+			name:         "pls playlist named by the format parameter",
+			query:        "&format=pls",
+			playlist:     "[playlist]\nFile2=http://example.com/b.mp4\nFile1=http://example.com/a.mp4\nNumberOfEntries=2\n",
+			expectStatus: http.StatusOK,
+			expectQueue:  []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+			expectMessage: []string{
+				"imported 2 of 2 items",
+			},
+		},
+		{
+			name:         "malformed entries are skipped and reported",
+			playlist:     "http://example.com/a.mp4\nnot a url\nhttp://example.com/b.mp4\n",
+			expectStatus: http.StatusOK,
+			expectQueue:  []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+			expectMessage: []string{
+				"imported 2 of 2 items",
+				"skipped 1 malformed entries",
+				"line 2:",
+			},
+		},
+		{
+			name:         "playlist with no valid entries",
+			contentType:  "application/json",
+			playlist:     `["not a url", 42]`,
+			expectStatus: http.StatusBadRequest,
+			expectMessage: []string{
+				"no valid stream urls",
+				"item 1:",
+				"item 2:",
+			},
+		},
+		{
+			name:         "malformed playlist",
+			contentType:  "application/json",
+			playlist:     `{"items": `,
+			expectStatus: http.StatusBadRequest,
+			expectMessage: []string{
+				"malformed JSON playlist",
+			},
+		},
+		{
+			name:         "unsupported playlist format",
+			query:        "&format=xspf",
+			playlist:     "<playlist></playlist>",
+			expectStatus: http.StatusBadRequest,
+			expectMessage: []string{
+				"unsupported playlist format",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, ns, authorizer := newTestHandlerWithRBAC("room")
+			conn := connect(t, h, ns, authorizer, "alice", "alice", rbac.USER_ROLE)
+
+			// streams queued while another one is playing stay in the queue
+			sPlayback := loadStream(t, h, ns, "playing.mp4", 600)
+			if err := sPlayback.Play(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stubMetadata(h, "http://example.com/a.mp4", `{"name": "A", "duration": 60}`)
+			stubMetadata(h, "http://example.com/b.mp4", `{"name": "B", "duration": 120}`)
+
+			token, _ := h.sessions.token(conn.UUID())
+			req := httptest.NewRequest(http.MethodPost, "/rooms/room/queue/import?session="+token+tc.query, strings.NewReader(tc.playlist))
+			if len(tc.contentType) > 0 {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeRoomAPI(w, req)
+			if w.Code != tc.expectStatus {
+				t.Fatalf("expected status %v, got %v: %s", tc.expectStatus, w.Code, w.Body.String())
+			}
+
+			res := endpoint.ApiResponse{}
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range tc.expectMessage {
+				if !strings.Contains(res.Message+res.Error, expected) {
+					t.Errorf("expected the response to report %q, got %q", expected, res.Message+res.Error)
+				}
+			}
+
+			c, _ := h.clientHandler.GetClient(conn.UUID())
+			urls := []string{}
+			if userQueue, exists, err := playbackutil.GetUserQueue(c, sPlayback.GetQueue()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if exists {
+				for _, item := range userQueue.List() {
+					urls = append(urls, item.UUID())
+				}
+			}
+			if strings.Join(urls, ",") != strings.Join(tc.expectQueue, ",") {
+				t.Errorf("expected queue %v, got %v", tc.expectQueue, urls)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// queueMetadataResponse is the data of a "queuemetadata" event
+type queueMetadataResponse struct {
+	Extra playback.QueueMetadata `json:"extra"`
+}
+
+func TestQueueMetadata(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	alice := connect(t, h, ns, authorizer, "alice", "alice", rbac.USER_ROLE)
+	bob := connect(t, h, ns, authorizer, "bob", "bob", rbac.USER_ROLE)
+
+	// streams queued while another one is playing stay in the queue
+	sPlayback := loadStream(t, h, ns, "playing.mp4", 600)
+	if err := sPlayback.Play(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stubMetadata(h, "http://example.com/a.mp4", `{"name": "A", "duration": 60, "thumb": "http://example.com/a.jpg"}`)
+	stubMetadata(h, "http://example.com/b.mp4", `{"name": "B", "duration": 120, "thumb": "http://example.com/b.jpg"}`)
+	stubMetadata(h, "http://example.com/c.mp4", `{"name": "C", "duration": 180, "thumb": "http://example.com/c.jpg"}`)
+
+	queued := []struct {
+		conn *fakeConnection
+		url  string
+	}{
+		{conn: alice, url: "http://example.com/a.mp4"},
+		{conn: alice, url: "http://example.com/b.mp4"},
+		{conn: bob, url: "http://example.com/c.mp4"},
+		// no metadata is stubbed for this stream, so its fetch fails
+		{conn: bob, url: "http://example.com/d.mp4"},
+	}
+	for _, q := range queued {
+		c, _ := h.clientHandler.GetClient(q.conn.UUID())
+		if _, err := h.CommandHandler.ExecuteCommand("queue", []string{"add", q.url}, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler); err != nil {
+			t.Fatalf("unexpected error queueing %q: %v", q.url, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		conn        *fakeConnection
+		expectItems []playback.QueueItemMetadata
+	}{
+		{
+			name: "every queued stream is described, grouped by the client who queued it",
+			conn: alice,
+			expectItems: []playback.QueueItemMetadata{
+				{
+					Id:          "http://example.com/a.mp4",
+					Url:         "http://example.com/a.mp4",
+					Title:       "A",
+					Kind:        stream.STREAM_TYPE_REMOTE,
+					Duration:    60,
+					Thumbnail:   "http://example.com/a.jpg",
+					QueuedBy:    "alice",
+					FetchStatus: stream.STREAM_FETCH_STATUS_FETCHED,
+				},
+				{
+					Id:          "http://example.com/b.mp4",
+					Url:         "http://example.com/b.mp4",
+					Title:       "B",
+					Kind:        stream.STREAM_TYPE_REMOTE,
+					Duration:    120,
+					Thumbnail:   "http://example.com/b.jpg",
+					QueuedBy:    "alice",
+					FetchStatus: stream.STREAM_FETCH_STATUS_FETCHED,
+				},
+				{
+					Id:          "http://example.com/c.mp4",
+					Url:         "http://example.com/c.mp4",
+					Title:       "C",
+					Kind:        stream.STREAM_TYPE_REMOTE,
+					Duration:    180,
+					Thumbnail:   "http://example.com/c.jpg",
+					QueuedBy:    "bob",
+					FetchStatus: stream.STREAM_FETCH_STATUS_FETCHED,
+				},
+				{
+					Id:          "http://example.com/d.mp4",
+					Url:         "http://example.com/d.mp4",
+					Kind:        stream.STREAM_TYPE_REMOTE,
+					QueuedBy:    "bob",
+					FetchStatus: stream.STREAM_FETCH_STATUS_FAILED,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conn.clearMessages()
+			tc.conn.Emit("request_queuemetadata", connection.NewMessageData())
+
+			res := queueMetadataResponse{}
+			if !tc.conn.lastMessage("queuemetadata", &res) {
+				t.Fatalf("expected a %q event to be sent, got %q", "queuemetadata", tc.conn.sent)
+			}
+			if len(res.Extra.Items) != len(tc.expectItems) {
+				t.Fatalf("expected metadata for %v queued streams, got %+v", len(tc.expectItems), res.Extra.Items)
+			}
+			for i := range tc.expectItems {
+				if res.Extra.Items[i] != tc.expectItems[i] {
+					t.Errorf("expected item %v to be %+v, got %+v", i, tc.expectItems[i], res.Extra.Items[i])
+				}
+			}
+		})
+	}
+}
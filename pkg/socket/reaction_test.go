@@ -0,0 +1,150 @@
+package socket
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// reactionResponse is the data of a "reaction" event
+type reactionResponse struct {
+	Id    string `json:"id"`
+	Extra struct {
+		MessageId string         `json:"messageId"`
+		Reactions map[string]int `json:"reactions"`
+		Emoji     string         `json:"emoji"`
+		Added     bool           `json:"added"`
+	} `json:"extra"`
+}
+
+func TestChatReactions(t *testing.T) {
+	h, ns := newTestHandler("room")
+	alice := connect(t, h, ns, nil, "alice", "alice", "")
+	bob := connect(t, h, ns, nil, "bob", "bob", "")
+	observer := connect(t, h, ns, nil, "observer", "observer", "")
+
+	data := connection.NewMessageData()
+	data.Set("message", "react to this")
+	alice.Emit("request_chatmessage", data)
+
+	msg := chatMessageResponse{}
+	if !observer.lastMessage("chatmessage", &msg) {
+		t.Fatalf("expected a %q event to be broadcast, got %q", "chatmessage", observer.sent)
+	}
+	messageId, _ := msg.Extra[playback.ChatMessageIdKey].(string)
+	if len(messageId) == 0 {
+		t.Fatalf("expected the message to be given an id, got %v", msg.Extra)
+	}
+
+	tests := []struct {
+		name            string
+		conn            *fakeConnection
+		messageId       string
+		emoji           string
+		expectErr       bool
+		expectAdded     bool
+		expectReactions map[string]int
+	}{
+		{
+			name:            "first reaction",
+			conn:            alice,
+			emoji:           "👍",
+			expectAdded:     true,
+			expectReactions: map[string]int{"👍": 1},
+		},
+		{
+			name:            "same reaction from another user",
+			conn:            bob,
+			emoji:           "👍",
+			expectAdded:     true,
+			expectReactions: map[string]int{"👍": 2},
+		},
+		{
+			name:            "different reaction",
+			conn:            bob,
+			emoji:           "🎉",
+			expectAdded:     true,
+			expectReactions: map[string]int{"👍": 2, "🎉": 1},
+		},
+		{
+			name:            "repeated reaction is toggled off",
+			conn:            alice,
+			emoji:           "👍",
+			expectReactions: map[string]int{"👍": 1, "🎉": 1},
+		},
+		{
+			name:            "last reaction of its kind is toggled off",
+			conn:            bob,
+			emoji:           "🎉",
+			expectReactions: map[string]int{"👍": 1},
+		},
+		{
+			name:      "reaction that is not an emoji",
+			conn:      alice,
+			emoji:     "ok",
+			expectErr: true,
+		},
+		{
+			name:      "reaction to a message that does not exist",
+			conn:      alice,
+			messageId: "missing",
+			emoji:     "👍",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conn.clearMessages()
+			observer.clearMessages()
+
+			id := messageId
+			if len(tc.messageId) > 0 {
+				id = tc.messageId
+			}
+			data := connection.NewMessageData()
+			data.Set(playback.ChatMessageIdKey, id)
+			data.Set("emoji", tc.emoji)
+			tc.conn.Emit("request_reaction", data)
+
+			res := reactionResponse{}
+			broadcast := observer.lastMessage("reaction", &res)
+			if tc.expectErr {
+				if broadcast {
+					t.Errorf("expected no %q event to be broadcast, got %q", "reaction", observer.sent)
+				}
+				notice := client.Response{}
+				if !tc.conn.lastMessage("info_clienterror", &notice) {
+					t.Errorf("expected the client to be sent an error, got %q", tc.conn.sent)
+				}
+				return
+			}
+			if !broadcast {
+				t.Fatalf("expected a %q event to be broadcast, got %q", "reaction", observer.sent)
+			}
+
+			if res.Id != tc.conn.UUID() || res.Extra.MessageId != messageId || res.Extra.Emoji != tc.emoji {
+				t.Errorf("expected a reaction %q from %q to message %q, got %+v", tc.emoji, tc.conn.UUID(), messageId, res)
+			}
+			if res.Extra.Added != tc.expectAdded {
+				t.Errorf("expected the reaction to be added: %v, got %v", tc.expectAdded, res.Extra.Added)
+			}
+			if fmt.Sprint(res.Extra.Reactions) != fmt.Sprint(tc.expectReactions) {
+				t.Errorf("expected reactions %v, got %v", tc.expectReactions, res.Extra.Reactions)
+			}
+
+			// the counts are kept with the message in the room's chat history
+			sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(ns)
+			history := sPlayback.ChatHistory()
+			if len(history) != 1 {
+				t.Fatalf("expected a single message in the chat history, got %v", len(history))
+			}
+			if counts := history[0].Extra[playback.ChatReactionsKey]; fmt.Sprint(counts) != fmt.Sprint(tc.expectReactions) {
+				t.Errorf("expected the chat history to hold reactions %v, got %v", tc.expectReactions, counts)
+			}
+		})
+	}
+}
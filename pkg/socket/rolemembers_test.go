@@ -0,0 +1,82 @@
+package socket
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// roleMembersResponse is the data of a "rolemembers" event
+type roleMembersResponse struct {
+	Extra struct {
+		Role    string                      `json:"role"`
+		Clients []client.SerializableClient `json:"clients"`
+	} `json:"extra"`
+}
+
+func TestRoleMembers(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	alice := connect(t, h, ns, authorizer, "alice", "alice", rbac.ADMIN_ROLE)
+	connect(t, h, ns, authorizer, "bob", "bob", rbac.ADMIN_ROLE)
+	connect(t, h, ns, authorizer, "carol", "carol", rbac.USER_ROLE)
+
+	tests := []struct {
+		name          string
+		role          string
+		expectMembers []string
+	}{
+		{
+			name:          "role bound to two clients",
+			role:          rbac.ADMIN_ROLE,
+			expectMembers: []string{"alice", "bob"},
+		},
+		{
+			name:          "role bound to one client",
+			role:          rbac.USER_ROLE,
+			expectMembers: []string{"carol"},
+		},
+		{
+			name:          "unbound role",
+			role:          rbac.VIEWER_ROLE,
+			expectMembers: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			alice.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set("role", tc.role)
+			alice.Emit("request_rolemembers", data)
+
+			res := roleMembersResponse{}
+			if !alice.lastMessage("rolemembers", &res) {
+				t.Fatalf("expected a %q event to be sent, got %q", "rolemembers", alice.sent)
+			}
+			if res.Extra.Role != tc.role {
+				t.Errorf("expected role %q, got %q", tc.role, res.Extra.Role)
+			}
+			if res.Extra.Clients == nil {
+				t.Fatalf("expected an empty member list rather than none")
+			}
+
+			members := []string{}
+			for _, c := range res.Extra.Clients {
+				members = append(members, c.Username)
+			}
+			sort.Strings(members)
+			if len(members) != len(tc.expectMembers) {
+				t.Fatalf("expected members %v, got %v", tc.expectMembers, members)
+			}
+			for i := range members {
+				if members[i] != tc.expectMembers[i] {
+					t.Errorf("expected members %v, got %v", tc.expectMembers, members)
+				}
+			}
+		})
+	}
+}
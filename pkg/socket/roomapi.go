@@ -0,0 +1,91 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint"
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+// ServeRoomAPI handles REST requests for a room, acting on behalf of
+// the connected client whose session token is given with the request's
+// session query parameter:
+//
+//	GET    /rooms/{room}/queue         returns the room's queue
+//	POST   /rooms/{room}/queue         queues the url given in the request
+//	POST   /rooms/{room}/queue/import  queues every url in the playlist given in the request
+//	DELETE /rooms/{room}/queue/{id}    removes the queue item with the given id
+//	GET    /rooms/{room}/chat          exports the room's chat history
+func (h *Handler) ServeRoomAPI(w http.ResponseWriter, r *http.Request) {
+	// segments are unescaped one at a time, so that ids holding
+	// slashes, such as stream urls, may be given path-escaped
+	segs := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i, seg := range segs {
+		unescaped, err := url.PathUnescape(seg)
+		if err != nil {
+			writeRoomAPIError(w, http.StatusNotFound, fmt.Errorf("endpoint not found"))
+			return
+		}
+		segs[i] = unescaped
+	}
+	if len(segs) < 3 || len(segs) > 4 || segs[0] != "rooms" {
+		writeRoomAPIError(w, http.StatusNotFound, fmt.Errorf("endpoint not found"))
+		return
+	}
+
+	c, err := h.sessionClient(r, segs[1])
+	if err != nil {
+		writeRoomAPIError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	switch segs[2] {
+	case "queue":
+		h.serveQueueAPI(w, r, c, segs)
+	case "chat":
+		h.serveChatExport(w, r, c, segs)
+	default:
+		writeRoomAPIError(w, http.StatusNotFound, fmt.Errorf("endpoint not found"))
+	}
+}
+
+// sessionClient returns the connected client holding the session whose
+// token is given with the request, or an error if the token does not
+// belong to a client connected to the given room.
+func (h *Handler) sessionClient(r *http.Request, room string) (*client.Client, error) {
+	token := r.URL.Query().Get(query.SESSION_TOKEN_KEY)
+	if len(token) == 0 {
+		return nil, fmt.Errorf("missing required parameter: %s", query.SESSION_TOKEN_KEY)
+	}
+
+	connId, sessionRoom, exists := h.sessions.connection(token)
+	if !exists || sessionRoom != room {
+		return nil, fmt.Errorf("no client connected to room %q holds the given session", room)
+	}
+
+	return h.clientHandler.GetClient(connId)
+}
+
+func writeRoomAPIError(w http.ResponseWriter, status int, err error) {
+	writeRoomAPIResponse(w, &endpoint.ApiResponse{
+		Error:    err.Error(),
+		HTTPCode: status,
+	})
+}
+
+func writeRoomAPIResponse(w http.ResponseWriter, res *endpoint.ApiResponse) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(res.HTTPCode)
+	w.Write(b)
+}
@@ -0,0 +1,158 @@
+package socket
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// roomListResponse is the data of a "roomlist" event
+type roomListResponse struct {
+	Extra playback.RoomList `json:"extra"`
+}
+
+func TestRoomList(t *testing.T) {
+	h, ns := newTestHandler("busy")
+
+	rooms := []struct {
+		name     string
+		clients  int
+		stream   string
+		queued   string
+		password string
+		unlisted bool
+	}{
+		{name: "busy", clients: 2, stream: "http://example.com/a.mp4", queued: "http://example.com/b.mp4"},
+		{name: "quiet", clients: 1},
+		{name: "empty", stream: "http://example.com/c.mp4"},
+		{name: "protected", clients: 1, password: "secret"},
+		{name: "unlisted", clients: 1, unlisted: true},
+	}
+
+	var conn *fakeConnection
+	for _, r := range rooms {
+		roomNs := ns
+		if r.name != ns.Name() {
+			roomNs = h.nsHandler.NewNamespace(r.name)
+		}
+
+		// empty rooms are left by the client that created them
+		clients := r.clients
+		if clients == 0 {
+			clients = 1
+		}
+		roomConns := []*fakeConnection{}
+		for i := 0; i < clients; i++ {
+			roomConns = append(roomConns, connect(t, h, roomNs, nil, fmt.Sprintf("%s-%d", r.name, i), fmt.Sprintf("%s%d", r.name, i), ""))
+		}
+		if conn == nil {
+			conn = roomConns[0]
+		}
+
+		sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(roomNs)
+		sPlayback.SetListed(!r.unlisted)
+		if len(r.password) > 0 {
+			if err := sPlayback.SetPassword(r.password); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(r.stream) > 0 {
+			s := stream.NewRemoteVideoStream(r.stream)
+			if err := s.SetInfo([]byte(fmt.Sprintf(`{"name": %q, "duration": 600}`, r.name+" stream"))); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sPlayback.SetStream(s)
+		}
+		if len(r.queued) > 0 {
+			userQueue := queue.NewAggregatableQueue(roomConns[0].UUID())
+			if err := sPlayback.GetQueue().Push(userQueue); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := sPlayback.PushToQueue(userQueue, stream.NewRemoteVideoStream(r.queued)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if r.clients == 0 {
+			roomConns[0].Emit("disconnection", nil)
+			roomConns[0].Leave(r.name)
+		}
+	}
+
+	busy := playback.RoomSummary{
+		Room:        "busy",
+		Clients:     2,
+		Url:         "http://example.com/a.mp4",
+		Title:       "busy stream",
+		QueueLength: 1,
+	}
+	quiet := playback.RoomSummary{
+		Room:    "quiet",
+		Clients: 1,
+	}
+	empty := playback.RoomSummary{
+		Room:  "empty",
+		Url:   "http://example.com/c.mp4",
+		Title: "empty stream",
+	}
+
+	tests := []struct {
+		name         string
+		includeEmpty interface{}
+		expectRooms  []playback.RoomSummary
+	}{
+		{
+			name:        "empty rooms are excluded by default",
+			expectRooms: []playback.RoomSummary{busy, quiet},
+		},
+		{
+			name:         "empty rooms are excluded on request",
+			includeEmpty: false,
+			expectRooms:  []playback.RoomSummary{busy, quiet},
+		},
+		{
+			name:         "empty rooms are included on request",
+			includeEmpty: true,
+			expectRooms:  []playback.RoomSummary{busy, quiet, empty},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conn.clearMessages()
+
+			data := connection.NewMessageData()
+			if tc.includeEmpty != nil {
+				data.Set("empty", tc.includeEmpty)
+			}
+			conn.Emit("request_roomlist", data)
+
+			res := roomListResponse{}
+			if !conn.lastMessage("roomlist", &res) {
+				t.Fatalf("expected a %q event to be sent, got %q", "roomlist", conn.sent)
+			}
+
+			listed := map[string]playback.RoomSummary{}
+			for _, summary := range res.Extra.Rooms {
+				listed[summary.Room] = summary
+			}
+			if len(listed) != len(tc.expectRooms) {
+				t.Errorf("expected %v rooms to be listed, got %+v", len(tc.expectRooms), res.Extra.Rooms)
+			}
+			for _, expected := range tc.expectRooms {
+				summary, exists := listed[expected.Room]
+				if !exists {
+					t.Errorf("expected room %q to be listed, got %+v", expected.Room, res.Extra.Rooms)
+					continue
+				}
+				if summary != expected {
+					t.Errorf("expected room summary %+v, got %+v", expected, summary)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package socket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+const (
+	// CHAT_SANITIZE_OFF broadcasts chat messages exactly as sent
+	CHAT_SANITIZE_OFF = "off"
+	// CHAT_SANITIZE_ESCAPE escapes characters that could form markup,
+	// so that messages are displayed exactly as typed
+	CHAT_SANITIZE_ESCAPE = "escape"
+	// CHAT_SANITIZE_STRIP removes markup tags from messages, along
+	// with the contents of script and style elements, and escapes
+	// any characters that could still form markup
+	CHAT_SANITIZE_STRIP = "strip"
+)
+
+// ChatSanitizeMode determines how markup in chat messages
+// is neutralized before messages are broadcast
+var ChatSanitizeMode = CHAT_SANITIZE_OFF
+
+// PreserveChatMediaUrls determines whether image urls extracted from
+// sanitized chat messages are still sent along with the message. Urls
+// containing markup characters are never sent with sanitized messages.
+var PreserveChatMediaUrls = true
+
+var (
+	// markupElementContents matches script and style elements, including their contents
+	markupElementContents = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?(?:</(?:script|style)\s*>|$)`)
+	// markupTag matches opening, closing, and self-closing tags, and comments
+	markupTag = regexp.MustCompile(`(?s)<!--.*?(?:-->|$)|</?[a-zA-Z][^>]*(?:>|$)`)
+
+	markupEscaper = strings.NewReplacer(
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&#34;",
+		"'", "&#39;",
+	)
+)
+
+// ValidateChatSanitizeMode returns an error if the given mode is unknown
+func ValidateChatSanitizeMode(mode string) error {
+	switch mode {
+	case CHAT_SANITIZE_OFF, CHAT_SANITIZE_ESCAPE, CHAT_SANITIZE_STRIP:
+		return nil
+	}
+	return fmt.Errorf("unknown chat sanitize mode %q (expected %s, %s, or %s)", mode, CHAT_SANITIZE_OFF, CHAT_SANITIZE_ESCAPE, CHAT_SANITIZE_STRIP)
+}
+
+// SanitizeChatText neutralizes markup in the given text according to the
+// given mode. Ampersands are left untouched, so that urls survive intact;
+// without angle brackets or quotes, they cannot form markup.
+func SanitizeChatText(text, mode string) string {
+	switch mode {
+	case CHAT_SANITIZE_ESCAPE:
+		return markupEscaper.Replace(text)
+	case CHAT_SANITIZE_STRIP:
+		text = markupElementContents.ReplaceAllString(text, "")
+		text = markupTag.ReplaceAllString(text, "")
+		return markupEscaper.Replace(text)
+	}
+	return text
+}
+
+// sanitizeChatMessage neutralizes markup in the text and sender of the
+// given chat message and in its link previews, and removes extracted image
+// and video urls that could form markup, or every extracted url unless
+// PreserveChatMediaUrls is set.
+func sanitizeChatMessage(res *client.Response) {
+	if ChatSanitizeMode == CHAT_SANITIZE_OFF {
+		return
+	}
+
+	res.Message = SanitizeChatText(res.Message, ChatSanitizeMode)
+	res.From = SanitizeChatText(res.From, ChatSanitizeMode)
+
+	if previews, ok := res.Extra["preview"].([]*LinkPreview); ok {
+		for _, preview := range previews {
+			preview.Title = SanitizeChatText(preview.Title, ChatSanitizeMode)
+			preview.Description = SanitizeChatText(preview.Description, ChatSanitizeMode)
+			preview.SiteName = SanitizeChatText(preview.SiteName, ChatSanitizeMode)
+			if hasMarkupChars(preview.Image) {
+				preview.Image = ""
+			}
+		}
+	}
+
+	if videos, ok := res.Extra["videos"].([]*stream.VideoEmbed); ok {
+		safe := []*stream.VideoEmbed{}
+		if PreserveChatMediaUrls {
+			for _, video := range videos {
+				if !hasMarkupChars(video.Url) && !hasMarkupChars(video.Id) && !hasMarkupChars(video.Kind) {
+					safe = append(safe, video)
+				}
+			}
+		}
+
+		if len(safe) == 0 {
+			delete(res.Extra, "videos")
+		} else {
+			res.Extra["videos"] = safe
+		}
+	}
+
+	images, ok := res.Extra["images"].([]string)
+	if !ok {
+		return
+	}
+
+	safe := []string{}
+	if PreserveChatMediaUrls {
+		for _, url := range images {
+			if !hasMarkupChars(url) {
+				safe = append(safe, url)
+			}
+		}
+	}
+
+	if len(safe) == 0 {
+		delete(res.Extra, "images")
+		return
+	}
+	res.Extra["images"] = safe
+}
+
+// hasMarkupChars returns true if the given
+// text contains characters that could form markup
+func hasMarkupChars(text string) bool {
+	return strings.ContainsAny(text, "<>\"'`")
+}
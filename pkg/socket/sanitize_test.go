@@ -0,0 +1,126 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestSanitizeChatMessage(t *testing.T) {
+	defer func(mode string) { ChatSanitizeMode = mode }(ChatSanitizeMode)
+	ChatSanitizeMode = CHAT_SANITIZE_STRIP
+
+	tests := []struct {
+		name         string
+		res          *client.Response
+		expectFrom   string
+		expectText   string
+		expectVideos int
+	}{
+		{
+			name: "plain text and legitimate videos survive",
+			res: &client.Response{
+				From:    "user",
+				Message: "watch this https://www.youtube.com/watch?v=abc&t=5",
+				Extra: map[string]interface{}{
+					"videos": []*stream.VideoEmbed{{Kind: "youtube", Id: "abc", Url: "https://www.youtube.com/watch?v=abc&t=5"}},
+				},
+			},
+			expectFrom:   "user",
+			expectText:   "watch this https://www.youtube.com/watch?v=abc&t=5",
+			expectVideos: 1,
+		},
+		{
+			name: "script tags are removed",
+			res: &client.Response{
+				From:    "user",
+				Message: "hi<script>alert(1)</script>",
+				Extra:   map[string]interface{}{},
+			},
+			expectFrom: "user",
+			expectText: "hi",
+		},
+		{
+			name: "markup in the sender's name is neutralized",
+			res: &client.Response{
+				From:    `<img src=x onerror="alert(1)">user`,
+				Message: "hi",
+				Extra:   map[string]interface{}{},
+			},
+			expectFrom: "user",
+			expectText: "hi",
+		},
+		{
+			name: "videos with markup in their urls are removed",
+			res: &client.Response{
+				From:    "user",
+				Message: "hi",
+				Extra: map[string]interface{}{
+					"videos": []*stream.VideoEmbed{{Kind: "youtube", Id: `abc"onload="alert(1)`, Url: `https://www.youtube.com/watch?v=abc"onload="alert(1)`}},
+				},
+			},
+			expectFrom: "user",
+			expectText: "hi",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sanitizeChatMessage(tc.res)
+			if tc.res.From != tc.expectFrom {
+				t.Errorf("expected sender %q, got %q", tc.expectFrom, tc.res.From)
+			}
+			if tc.res.Message != tc.expectText {
+				t.Errorf("expected message %q, got %q", tc.expectText, tc.res.Message)
+			}
+			videos, _ := tc.res.Extra["videos"].([]*stream.VideoEmbed)
+			if len(videos) != tc.expectVideos {
+				t.Errorf("expected %v videos, got %v", tc.expectVideos, len(videos))
+			}
+		})
+	}
+}
+
+func TestChatMessageFieldsAreSetByServer(t *testing.T) {
+	h, ns := newTestHandler("room")
+	conn := newFakeConnection("client", ns)
+	h.HandleClientConnection(conn)
+
+	data := connection.NewMessageData()
+	data.Set("message", "hello")
+	data.Set("id", "someone-else")
+	data.Set("user", "someone-else")
+	data.Set("system", true)
+	data.Set("extra", map[string]interface{}{
+		"images": []interface{}{"javascript:alert(1)"},
+		"pinned": true,
+	})
+	conn.Emit("request_chatmessage", data)
+
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(ns)
+	if !exists {
+		t.Fatalf("expected a playback to be created for the room")
+	}
+	history := sPlayback.ChatHistory()
+	if len(history) != 1 {
+		t.Fatalf("expected 1 message in the room's chat history, got %v", len(history))
+	}
+
+	res := history[0]
+	if res.Id != conn.UUID() || res.From != conn.UUID() {
+		t.Errorf("expected message to be sent by %q, got id %q and user %q", conn.UUID(), res.Id, res.From)
+	}
+	if res.IsSystem {
+		t.Errorf("expected message not to be a system message")
+	}
+	if res.Message != "hello" {
+		t.Errorf("expected message %q, got %q", "hello", res.Message)
+	}
+	for _, key := range []string{"images", "pinned"} {
+		if _, exists := res.Extra[key]; exists {
+			t.Errorf("expected client-supplied extra field %q to be ignored, got %v", key, res.Extra)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package socket
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/util"
+)
+
+// ScheduleCountdownFrom is the number of seconds before a scheduled
+// stream starts at which a room begins receiving a per-second countdown.
+// Before then, the countdown is only sent once a minute.
+var ScheduleCountdownFrom = 10
+
+// handleScheduleTick notifies a room of the time remaining until its
+// scheduled stream starts, and loads the stream for the room once it has.
+func (h *Handler) handleScheduleTick(namespace connection.Namespace, remaining int) {
+	sPlayback, exists := h.PlaybackHandler.PlaybackByNamespace(namespace)
+	if !exists {
+		h.logger.Errorf("SCHEDULE SOCKET CLIENT attempted to send scheduled stream countdown, but stream playback does not exist.")
+		return
+	}
+
+	if remaining > 0 {
+		if remaining > ScheduleCountdownFrom && remaining%60 != 0 {
+			return
+		}
+
+		h.BroadcastToNamespace(namespace, "streamcountdown", &client.Response{
+			From: client.USER_SYSTEM,
+			Extra: map[string]interface{}{
+				"remaining": remaining,
+			},
+		})
+		return
+	}
+
+	res := &client.Response{
+		From: client.USER_SYSTEM,
+	}
+
+	err := util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		h.logger.Errorf("SCHEDULE SOCKET CLIENT unable to serialize playback status: %v", err)
+		return
+	}
+
+	h.logger.Infof("SCHEDULE SOCKET CLIENT starting scheduled stream for room %q", namespace.Name())
+	h.BroadcastToNamespace(namespace, "streamload", res)
+	h.BroadcastToNamespace(namespace, "streamsync", res)
+	if msg, ok := sPlayback.StreamAnnouncement(); ok {
+		h.BroadcastToNamespace(namespace, "chatmessage", &client.Response{
+			From:     client.USER_SYSTEM,
+			Message:  msg,
+			IsSystem: true,
+		})
+	}
+}
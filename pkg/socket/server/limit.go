@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+const (
+	DefaultConnectionBurst          = 10  // number of connections an address may open at once
+	DefaultConnectionRefillRate     = 0.5 // number of connections an address regains per second
+	DefaultMaxConnectionsPerAddress = 20  // number of connections an address may hold open at once
+
+	connectionLimitPruneInterval = 1 * time.Minute
+)
+
+var (
+	ConnectionBurst      = DefaultConnectionBurst
+	ConnectionRefillRate = DefaultConnectionRefillRate
+	// MaxConnectionsPerAddress is the number of connections a single
+	// address may hold open at once. A value of 0 disables the limit.
+	MaxConnectionsPerAddress = DefaultMaxConnectionsPerAddress
+)
+
+type addressLimit struct {
+	bucket   *client.TokenBucket
+	open     int
+	lastSeen time.Time
+}
+
+// ConnectionLimiter limits the rate at which each remote address may
+// open socket connections, and the number it may hold open at once.
+// It is safe for concurrent use.
+type ConnectionLimiter struct {
+	mutex      sync.Mutex
+	burst      int
+	refillRate float64
+	maxOpen    int
+	addresses  map[string]*addressLimit
+	lastPrune  time.Time
+}
+
+// Admit records a new connection from the given address, returning
+// an error if the address has opened connections too quickly or
+// already holds too many open. Every admitted connection must be
+// released once it closes.
+func (l *ConnectionLimiter) Admit(addr string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > connectionLimitPruneInterval {
+		l.prune(now)
+	}
+
+	limit, exists := l.addresses[addr]
+	if !exists {
+		limit = &addressLimit{
+			bucket: client.NewTokenBucket(l.burst, l.refillRate),
+		}
+		l.addresses[addr] = limit
+	}
+	limit.lastSeen = now
+
+	if l.maxOpen > 0 && limit.open >= l.maxOpen {
+		return fmt.Errorf("address %q already has %v open connections", addr, limit.open)
+	}
+	if !limit.bucket.Allow() {
+		return fmt.Errorf("address %q is opening connections too quickly", addr)
+	}
+
+	limit.open++
+	return nil
+}
+
+// Release records that a connection admitted from the given address has closed
+func (l *ConnectionLimiter) Release(addr string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit, exists := l.addresses[addr]; exists && limit.open > 0 {
+		limit.open--
+		limit.lastSeen = time.Now()
+	}
+}
+
+// OpenConnections returns the number of connections
+// currently held open by the given address.
+func (l *ConnectionLimiter) OpenConnections(addr string) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit, exists := l.addresses[addr]; exists {
+		return limit.open
+	}
+	return 0
+}
+
+// prune discards addresses with no open connections whose
+// buckets have had enough time to refill completely.
+func (l *ConnectionLimiter) prune(now time.Time) {
+	l.lastPrune = now
+
+	refill := connectionLimitPruneInterval
+	if l.refillRate > 0 {
+		refill = time.Duration(float64(l.burst) / l.refillRate * float64(time.Second))
+	}
+
+	for addr, limit := range l.addresses {
+		if limit.open == 0 && now.Sub(limit.lastSeen) > refill {
+			delete(l.addresses, addr)
+		}
+	}
+}
+
+// NewConnectionLimiter returns a limiter allowing each address to open
+// a burst of connections, regaining refillRate connections per second,
+// and to hold at most maxOpen connections open at once.
+func NewConnectionLimiter(burst int, refillRate float64, maxOpen int) *ConnectionLimiter {
+	return &ConnectionLimiter{
+		burst:      burst,
+		refillRate: refillRate,
+		maxOpen:    maxOpen,
+		addresses:  make(map[string]*addressLimit),
+		lastPrune:  time.Now(),
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestConnectionLimiter(t *testing.T) {
+	type step struct {
+		addr      string
+		release   bool
+		expectErr bool
+	}
+
+	tests := []struct {
+		name       string
+		burst      int
+		refillRate float64
+		maxOpen    int
+		steps      []step
+	}{
+		{
+			name:  "rapid connects exhaust the burst",
+			burst: 3,
+			steps: []step{
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1", expectErr: true},
+				{addr: "10.0.0.1", expectErr: true},
+				{addr: "10.0.0.2"},
+				{addr: "10.0.0.2"},
+			},
+		},
+		{
+			name:    "open connections are capped",
+			burst:   10,
+			maxOpen: 2,
+			steps: []step{
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1", expectErr: true},
+				{addr: "10.0.0.2"},
+				{addr: "10.0.0.1", release: true},
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1", expectErr: true},
+			},
+		},
+		{
+			name:  "no open connection cap",
+			burst: 3,
+			steps: []step{
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1"},
+				{addr: "10.0.0.1"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := NewConnectionLimiter(tc.burst, tc.refillRate, tc.maxOpen)
+			open := map[string]int{}
+
+			for i, s := range tc.steps {
+				if s.release {
+					limiter.Release(s.addr)
+					open[s.addr]--
+					continue
+				}
+
+				err := limiter.Admit(s.addr)
+				if s.expectErr != (err != nil) {
+					t.Fatalf("step %v: expected error admitting %q: %v, got %v", i, s.addr, s.expectErr, err)
+				}
+				if err == nil {
+					open[s.addr]++
+				}
+				if actual := limiter.OpenConnections(s.addr); actual != open[s.addr] {
+					t.Errorf("step %v: expected %q to hold %v open connections, got %v", i, s.addr, open[s.addr], actual)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"strings"
 
@@ -36,6 +37,8 @@ type Server struct {
 	// connHandler is a handler for incoming connection upgrade requests
 	connHandler connection.ConnectionHandler
 	nsHandler   connection.NamespaceHandler
+	// limiter refuses connections from addresses opening too many
+	limiter *ConnectionLimiter
 }
 
 func (s *Server) On(eventName string, callback ServerEventCallback) {
@@ -67,6 +70,13 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", origin)
 	w.Header().Set("Access-Control-Allow-Credentials", "true")
 
+	addr := remoteHost(r)
+	if err := s.limiter.Admit(addr); err != nil {
+		log.Printf("WRN SOCKET SERVER refusing socket request for ref %q: %v\n", origin, err)
+		http.Error(w, "too many connections - please wait a moment and try again", http.StatusTooManyRequests)
+		return
+	}
+
 	nsName, err := util.NamespaceFromRequest(r)
 	if err != nil {
 		nsName = DEFAULT_NAMESPACE
@@ -82,10 +92,14 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	conn, err := websocket.Upgrade(w, r, w.Header(), MAX_READ_BUF_SIZE, MAX_WRITE_BUF_SIZE)
 	if err != nil {
 		log.Printf("ERR SOCKET SERVER unable to upgrade connection for %q: %v\n", r.URL.String(), err)
+		s.limiter.Release(addr)
 		return
 	}
 
 	socketConn := s.connHandler.NewConnection("", conn, w, r)
+	socketConn.On("disconnection", func(connection.MessageDataCodec) {
+		s.limiter.Release(addr)
+	})
 	socketConn.Join(namespace.Name())
 
 	s.Emit("connection", socketConn)
@@ -97,7 +111,18 @@ func NewServer(handler connection.ConnectionHandler, nsHandler connection.Namesp
 		callbacks:   make(map[string][]ServerEventCallback),
 		connHandler: handler,
 		nsHandler:   nsHandler,
+		limiter:     NewConnectionLimiter(ConnectionBurst, ConnectionRefillRate, MaxConnectionsPerAddress),
+	}
+}
+
+// remoteHost returns the host a request was made from
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
+
+	return host
 }
 
 // retrieve a client's origin consisting of
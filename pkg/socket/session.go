@@ -0,0 +1,357 @@
+package socket
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/api/endpoint/query"
+	playbackutil "github.com/juanvallejo/streaming-server/pkg/playback/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/util"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+const (
+	DefaultResumeGracePeriod = 30 * time.Second // time a disconnected client's session may be resumed for
+)
+
+// ResumeGracePeriod is the amount of time after a client disconnects
+// during which a new connection may resume its session
+var ResumeGracePeriod = DefaultResumeGracePeriod
+
+// session is a client identity that outlives a single connection.
+// Once its connection drops, the state needed to restore the client
+// is held until the session is resumed or its grace period expires.
+type session struct {
+	token  string
+	connId string
+	room   string
+
+	username string
+	roles    []string
+	queued   []stream.Stream
+	expiry   *time.Timer
+}
+
+// sessionStore aggregates client sessions by
+// resume token and by current connection id
+type sessionStore struct {
+	mutex   sync.Mutex
+	byToken map[string]*session
+	byConn  map[string]*session
+}
+
+// issue creates a session for the given connection
+// in the given room, and returns its resume token
+func (s *sessionStore) issue(connId, room string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(b)
+	s.reissue(token, connId, room)
+	return token, nil
+}
+
+// reissue creates a session for the given connection in the given
+// room that keeps an existing resume token, such as one identifying
+// a user with remembered role bindings. Returns a boolean (false) if
+// a session with the given token is still held.
+func (s *sessionStore) reissue(token, connId, room string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.byToken[token]; exists {
+		return false
+	}
+
+	sess := &session{
+		token:  token,
+		connId: connId,
+		room:   room,
+	}
+
+	s.byToken[token] = sess
+	s.byConn[connId] = sess
+	return true
+}
+
+// token returns the resume token of the session belonging to the
+// given connection, or a boolean (false) if the connection has none.
+func (s *sessionStore) token(connId string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sess, exists := s.byConn[connId]
+	if !exists {
+		return "", false
+	}
+	return sess.token, true
+}
+
+// connection returns the id of the connection currently holding the
+// session with the given token, and the room the session belongs to.
+// Returns a boolean (false) if no connected session has the token.
+func (s *sessionStore) connection(token string) (string, string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sess, exists := s.byToken[token]
+	if !exists || sess.connId == "" {
+		return "", "", false
+	}
+	return sess.connId, sess.room, true
+}
+
+// suspend marks the session belonging to the given connection as
+// disconnected, storing the given state until ResumeGracePeriod
+// has passed. Returns a boolean (false) if the connection has no session.
+func (s *sessionStore) suspend(connId, username string, roles []string, queued []stream.Stream) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sess, exists := s.byConn[connId]
+	if !exists {
+		return false
+	}
+
+	delete(s.byConn, connId)
+	sess.connId = ""
+	sess.username = username
+	sess.roles = roles
+	sess.queued = queued
+	sess.expiry = time.AfterFunc(ResumeGracePeriod, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if sess.connId == "" {
+			delete(s.byToken, sess.token)
+		}
+	})
+	return true
+}
+
+// resume re-associates a disconnected session with the given connection.
+// Returns a boolean (false) if no disconnected session exists for the given
+// token in the given room, or if its grace period has expired.
+func (s *sessionStore) resume(token, connId, room string) (*session, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sess, exists := s.byToken[token]
+	if !exists || sess.connId != "" || sess.room != room {
+		return nil, false
+	}
+	if sess.expiry != nil && !sess.expiry.Stop() {
+		// grace period expired while waiting for the lock
+		delete(s.byToken, token)
+		return nil, false
+	}
+
+	sess.connId = connId
+	sess.expiry = nil
+	s.byConn[connId] = sess
+	return sess, true
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		byToken: make(map[string]*session),
+		byConn:  make(map[string]*session),
+	}
+}
+
+// startSession resumes the session identified by the resume token in the
+// connection's request, if any, or issues a new session for the connection.
+// The session's resume token is sent to the client as a "session" event.
+func (h *Handler) startSession(conn connection.Connection) {
+	c, err := h.clientHandler.GetClient(conn.UUID())
+	if err != nil {
+		return
+	}
+
+	ns, exists := conn.Namespace()
+	if !exists {
+		return
+	}
+
+	resumed := false
+	token := conn.Request().URL.Query().Get(query.SESSION_RESUME_KEY)
+	if len(token) > 0 {
+		if sess, ok := h.sessions.resume(token, conn.UUID(), ns.Name()); ok {
+			h.logger.Infof("SOCKET CLIENT client with id %q resumed session in room %q", conn.UUID(), ns.Name())
+			h.restoreSession(c, conn, sess)
+			resumed = true
+		}
+	}
+
+	// an authenticated user is identified by their identity. Otherwise,
+	// a token that can no longer be resumed still identifies the user
+	// if role bindings have been remembered for it in the room
+	if !resumed {
+		if subject, ok := c.Identity(); ok {
+			h.restoreRoleBindings(conn, ns.Name(), subjectKey(subject))
+		} else if len(token) > 0 && h.restoreRoleBindings(conn, ns.Name(), identityKey(token)) {
+			resumed = h.sessions.reissue(token, conn.UUID(), ns.Name())
+		}
+	}
+
+	if !resumed {
+		token, err = h.sessions.issue(conn.UUID(), ns.Name())
+		if err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to issue session for client with id %q: %v", conn.UUID(), err)
+			return
+		}
+	}
+
+	c.BroadcastTo("session", &client.Response{
+		Id:   c.UUID(),
+		From: "system",
+		Extra: map[string]interface{}{
+			"token":   token,
+			"resumed": resumed,
+		},
+	})
+}
+
+// suspendSession stores the state of a disconnecting client so that
+// it may be restored if the client resumes its session.
+func (h *Handler) suspendSession(c *client.Client) {
+	username, _ := c.GetUsername()
+	roles := h.subjectRoles(c.Connection())
+
+	queued := []stream.Stream{}
+	if sPlayback, err := h.getPlaybackFromClient(c); err == nil {
+		if userQueue, exists, err := playbackutil.GetUserQueue(c, sPlayback.GetQueue()); err == nil && exists {
+			for _, item := range userQueue.List() {
+				if s, ok := item.(stream.Stream); ok {
+					queued = append(queued, s)
+				}
+			}
+		}
+	}
+
+	h.rememberRoleBindings(c, roles)
+	h.sessions.suspend(c.UUID(), username, roles, queued)
+}
+
+// identityKey returns the key under which role bindings are remembered
+// for the given resume token, so that tokens are not saved to disk.
+func identityKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// subjectKey returns the key under which role bindings are
+// remembered for the given authenticated identity.
+func subjectKey(subject string) string {
+	return identityKey("identity:" + subject)
+}
+
+// rememberRoleBindings stores the given role names for the identity
+// or session of the given client, so that they may be bound again
+// once the user rejoins the room, even after a server restart.
+// No-op if no binding store has been set.
+func (h *Handler) rememberRoleBindings(c *client.Client, roles []string) {
+	if h.bindings == nil {
+		return
+	}
+
+	ns, exists := c.Namespace()
+	if !exists {
+		return
+	}
+
+	key := ""
+	if subject, ok := c.Identity(); ok {
+		key = subjectKey(subject)
+	} else if token, exists := h.sessions.token(c.UUID()); exists {
+		key = identityKey(token)
+	} else {
+		return
+	}
+
+	// users bound only to the default role are given it again
+	// when they rejoin, and do not need to be remembered
+	if len(roles) == 1 && roles[0] == rbac.USER_ROLE {
+		roles = nil
+	}
+
+	if err := h.bindings.Remember(ns.Name(), key, roles); err != nil {
+		h.logger.Errorf("SOCKET AUTHZ unable to save role bindings for client with id %q: %v", c.UUID(), err)
+	}
+}
+
+// restoreRoleBindings binds the roles remembered under the given key
+// in the given room to the given connection. Returns a boolean (false)
+// if no roles have been remembered under the key.
+func (h *Handler) restoreRoleBindings(conn connection.Connection, room, key string) bool {
+	authorizer := h.CommandHandler.Authorizer()
+	if h.bindings == nil || authorizer == nil {
+		return false
+	}
+
+	roles, exists := h.bindings.Roles(room, key)
+	if !exists {
+		return false
+	}
+
+	for _, name := range roles {
+		if role, exists := authorizer.Role(name); exists {
+			authorizer.Bind(role, conn)
+		}
+	}
+
+	h.logger.Infof("SOCKET AUTHZ restored remembered roles %v for client with id %q in room %q", roles, conn.UUID(), room)
+	return true
+}
+
+// RememberRoleBindings stores the roles bound to every connected
+// client with a session, such as before the server shuts down.
+// No-op if no binding store has been set.
+func (h *Handler) RememberRoleBindings() {
+	if h.bindings == nil {
+		return
+	}
+
+	for _, conn := range h.clientHandler.Clients() {
+		h.rememberRoleBindings(conn, h.subjectRoles(conn.Connection()))
+	}
+}
+
+// SetBindingStore sets the store used to remember role bindings
+// across reconnects and restarts. Without one, role bindings only
+// outlive a connection for the length of its resume grace period.
+func (h *Handler) SetBindingStore(store *rbac.BindingStore) {
+	h.bindings = store
+}
+
+// restoreSession restores a resumed session's username,
+// role bindings, and queued streams to the given client.
+func (h *Handler) restoreSession(c *client.Client, conn connection.Connection, sess *session) {
+	if len(sess.username) > 0 {
+		if err := util.UpdateClientUsername(c, sess.username, h.clientHandler); err != nil {
+			h.logger.Errorf("SOCKET CLIENT unable to restore username %q for client with id %q: %v", sess.username, c.UUID(), err)
+		}
+	}
+
+	if authorizer := h.CommandHandler.Authorizer(); authorizer != nil {
+		for _, name := range sess.roles {
+			if role, exists := authorizer.Role(name); exists {
+				authorizer.Bind(role, conn)
+			}
+		}
+	}
+
+	if len(sess.queued) > 0 {
+		if sPlayback, err := h.getPlaybackFromClient(c); err == nil {
+			sPlayback.RestoreUserQueue(c, sess.queued)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package socket
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// ShutdownNotice is the message sent to every room when the server shuts down
+var ShutdownNotice = "the server is shutting down - please reconnect in a few moments"
+
+// shutdownState tracks whether the handler is shutting down.
+// It is safe for concurrent use.
+type shutdownState struct {
+	once    sync.Once
+	closing chan struct{}
+}
+
+// begin marks the handler as shutting down, returning
+// a boolean (false) if it was already shutting down.
+func (s *shutdownState) begin() bool {
+	started := false
+	s.once.Do(func() {
+		close(s.closing)
+		started = true
+	})
+	return started
+}
+
+// isClosing returns true if the handler is shutting down
+func (s *shutdownState) isClosing() bool {
+	select {
+	case <-s.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+func newShutdownState() *shutdownState {
+	return &shutdownState{
+		closing: make(chan struct{}),
+	}
+}
+
+// SetStateFile sets the file room state is saved to when the
+// handler shuts down. Room state is not saved if no file is set.
+func (h *Handler) SetStateFile(path string) {
+	h.stateFile = path
+}
+
+// IsShuttingDown returns true once Shutdown has been called
+func (h *Handler) IsShuttingDown() bool {
+	return h.shutdown.isClosing()
+}
+
+// Shutdown stops accepting new connections, notifies every room that
+// the server is shutting down, saves role bindings and room state (if
+// enabled), stops every room's playback timer, and closes all client
+// connections. An error is returned if connections are still being
+// closed once the given context is done.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	if !h.shutdown.begin() {
+		return fmt.Errorf("the server is already shutting down")
+	}
+
+	h.logger.Infof("SOCKET shutting down with %v clients connected", h.clientHandler.GetClientSize())
+
+	for _, p := range h.PlaybackHandler.Playbacks() {
+		if ns, exists := h.nsHandler.NamespaceByName(p.UUID()); exists {
+			h.BroadcastToNamespace(ns, "chatmessage", &client.Response{
+				From:     client.USER_SYSTEM,
+				Message:  ShutdownNotice,
+				IsSystem: true,
+			})
+		}
+	}
+
+	h.RememberRoleBindings()
+	h.saveRoomState()
+
+	for _, p := range h.PlaybackHandler.Playbacks() {
+		p.StopTimer()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.closeConnections()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		h.logger.Infof("SOCKET shutdown complete")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("unable to close every connection before shutting down: %v", ctx.Err())
+	}
+}
+
+// saveRoomState saves room state to the handler's state file, if one is set
+func (h *Handler) saveRoomState() {
+	if len(h.stateFile) == 0 {
+		return
+	}
+
+	f, err := os.Create(h.stateFile)
+	if err != nil {
+		h.logger.Errorf("STATE unable to create room state file %q: %v", h.stateFile, err)
+		return
+	}
+	defer f.Close()
+
+	if err := h.PlaybackHandler.SaveState(f); err != nil {
+		h.logger.Errorf("STATE unable to save room state to %q: %v", h.stateFile, err)
+		return
+	}
+
+	h.logger.Infof("STATE room state saved to %q", h.stateFile)
+}
+
+// closeConnections closes every connected client's connection
+func (h *Handler) closeConnections() {
+	wg := sync.WaitGroup{}
+	for _, c := range h.clientHandler.Clients() {
+		wg.Add(1)
+		go func(conn connection.Connection) {
+			defer wg.Done()
+			if err := conn.Close(); err != nil {
+				h.logger.Errorf("SOCKET CONN unable to close connection with id %q: %v", conn.UUID(), err)
+			}
+		}(c.Connection())
+	}
+	wg.Wait()
+}
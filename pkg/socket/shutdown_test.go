@@ -0,0 +1,61 @@
+package socket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+)
+
+func TestShutdown(t *testing.T) {
+	h, ns := newTestHandler("room")
+	conn := connect(t, h, ns, nil, "user", "user", "")
+	sPlayback := loadStream(t, h, ns, "movie.mp4", 600)
+	if err := sPlayback.Play(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := h.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notice := client.Response{}
+	if !conn.lastMessage("chatmessage", &notice) || !notice.IsSystem || notice.Message != ShutdownNotice {
+		t.Errorf("expected connected clients to be sent the shutdown notice, got %q", conn.sent)
+	}
+
+	// stopped timers are no longer playing, and cannot be played again
+	status := sPlayback.GetStatus().(*playback.PlaybackStatus)
+	if timer, ok := status.TimerStatus.(*playback.TimerStatus); !ok || timer.IsPlaying {
+		t.Errorf("expected the room's timer to be stopped")
+	}
+	if err := sPlayback.Play(); err == nil {
+		t.Errorf("expected the room's timer not to be played again")
+	}
+
+	late := newFakeConnection("late", ns)
+	h.HandleClientConnection(late)
+	if _, err := h.clientHandler.GetClient(late.UUID()); err == nil {
+		t.Errorf("expected a new connection not to be registered as a client")
+	}
+	refused := client.Response{}
+	if !late.lastMessage("info_clienterror", &refused) || refused.ErrMessage != "error: "+ShutdownNotice {
+		t.Errorf("expected a new connection to be told the server is shutting down, got %q", late.sent)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected new requests to be refused with status %v, got %v", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	if err := h.Shutdown(ctx); err == nil {
+		t.Errorf("expected an error shutting down twice")
+	}
+}
@@ -0,0 +1,113 @@
+package socket
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestSlowMode(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	moderator := connect(t, h, ns, authorizer, "moderator", "moderator", rbac.ADMIN_ROLE)
+	user := connect(t, h, ns, authorizer, "user", "user", rbac.USER_ROLE)
+	observer := connect(t, h, ns, authorizer, "observer", "observer", rbac.VIEWER_ROLE)
+
+	// delivered returns the number of chat messages from
+	// the client with the given id seen by the observer
+	delivered := func(id string) int {
+		count := 0
+		for _, data := range observer.messages("chatmessage") {
+			res := client.Response{}
+			if err := json.Unmarshal(data, &res); err == nil && !res.IsSystem && res.Id == id {
+				count++
+			}
+		}
+		return count
+	}
+
+	setSlowMode := func(seconds string) {
+		c, _ := h.clientHandler.GetClient(moderator.UUID())
+		if _, err := h.CommandHandler.ExecuteCommand("slowmode", []string{seconds}, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name            string
+		slowMode        string
+		conn            *fakeConnection
+		wait            time.Duration
+		expectDelivered bool
+	}{
+		{
+			name:            "first message once slow mode is on",
+			slowMode:        "1",
+			conn:            user,
+			expectDelivered: true,
+		},
+		{
+			name: "message within the slow mode interval",
+			conn: user,
+		},
+		{
+			name:            "message once the slow mode interval has passed",
+			conn:            user,
+			wait:            1100 * time.Millisecond,
+			expectDelivered: true,
+		},
+		{
+			name:            "moderators are exempt",
+			conn:            moderator,
+			expectDelivered: true,
+		},
+		{
+			name:            "moderators are exempt within the slow mode interval",
+			conn:            moderator,
+			expectDelivered: true,
+		},
+		{
+			name:            "message once slow mode is off",
+			slowMode:        "0",
+			conn:            user,
+			expectDelivered: true,
+		},
+		{
+			name:            "consecutive message once slow mode is off",
+			conn:            user,
+			expectDelivered: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.slowMode) > 0 {
+				setSlowMode(tc.slowMode)
+			}
+			time.Sleep(tc.wait)
+
+			before := delivered(tc.conn.UUID())
+			tc.conn.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set("message", tc.name)
+			tc.conn.Emit("request_chatmessage", data)
+
+			if sent := delivered(tc.conn.UUID()) > before; sent != tc.expectDelivered {
+				t.Fatalf("expected the message to be broadcast: %v, got %v", tc.expectDelivered, sent)
+			}
+			if tc.expectDelivered {
+				return
+			}
+
+			notice := client.Response{}
+			if !tc.conn.lastMessage("chatmessage", &notice) || !notice.IsSystem || !strings.Contains(notice.Message, "you may send another message in") {
+				t.Errorf("expected the sender to be told how long to wait, got %q", tc.conn.sent)
+			}
+		})
+	}
+}
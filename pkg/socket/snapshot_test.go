@@ -0,0 +1,154 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback/queue"
+	"github.com/juanvallejo/streaming-server/pkg/playback/util"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+// snapshotLinkResponse is the data of a "snapshotlink" event
+type snapshotLinkResponse struct {
+	Extra struct {
+		Token string `json:"token"`
+	} `json:"extra"`
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	urls := map[string]string{
+		"http://example.com/playing.mp4": `{"duration": 600}`,
+		"http://example.com/first.mp4":   `{"duration": 300}`,
+		"http://example.com/second.mp4":  `{"duration": 300}`,
+	}
+
+	h, ns, authorizer := newTestHandlerWithRBAC("a")
+	admin := connect(t, h, ns, authorizer, "admin", "admin", rbac.ADMIN_ROLE)
+	for url, metadata := range urls {
+		stubMetadata(h, url, metadata)
+	}
+
+	sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(ns)
+	newStream := func(url string) stream.Stream {
+		s, err := h.StreamHandler.NewStream(url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := s.SetInfo([]byte(urls[url])); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return s
+	}
+
+	sPlayback.SetStream(newStream("http://example.com/playing.mp4"))
+	sPlayback.SetTime(42)
+
+	userQueue := queue.NewAggregatableQueue(admin.UUID())
+	if err := sPlayback.GetQueue().Push(userQueue); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trimmed, err := stream.NewTrimmedStream(newStream("http://example.com/first.mp4"), 10, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []stream.Stream{trimmed, newStream("http://example.com/second.mp4")} {
+		if err := sPlayback.PushToQueue(userQueue, s); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	admin.Emit("request_snapshotlink", connection.NewMessageData())
+
+	link := snapshotLinkResponse{}
+	if !admin.lastMessage("snapshotlink", &link) || len(link.Extra.Token) == 0 {
+		t.Fatalf("expected a %q event with a token to be sent, got %q", "snapshotlink", admin.sent)
+	}
+
+	tests := []struct {
+		name      string
+		role      string
+		token     string
+		expectErr bool
+	}{
+		{
+			name:      "clients without permission cannot load a snapshot",
+			role:      rbac.USER_ROLE,
+			token:     link.Extra.Token,
+			expectErr: true,
+		},
+		{
+			name:      "invalid token",
+			role:      rbac.ADMIN_ROLE,
+			token:     "not a token",
+			expectErr: true,
+		},
+		{
+			name:  "snapshot is restored into a fresh room",
+			role:  rbac.ADMIN_ROLE,
+			token: link.Extra.Token,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, ns, authorizer := newTestHandlerWithRBAC("b")
+			conn := connect(t, h, ns, authorizer, "client", "client", tc.role)
+			for url, metadata := range urls {
+				stubMetadata(h, url, metadata)
+			}
+
+			data := connection.NewMessageData()
+			data.Set("token", tc.token)
+			conn.Emit("request_loadsnapshot", data)
+
+			if errored := len(conn.messages("info_clienterror")) > 0; errored != tc.expectErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, errored)
+			}
+
+			sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(ns)
+			if tc.expectErr {
+				if _, exists := sPlayback.GetStream(); exists {
+					t.Errorf("expected no stream to be loaded")
+				}
+				if size := len(sPlayback.GetQueue().PeekItems()); size != 0 {
+					t.Errorf("expected no streams to be queued, got %v", size)
+				}
+				return
+			}
+
+			status := statusResponse{}
+			if !conn.lastMessage("streamload", &status) {
+				t.Fatalf("expected a %q event to be broadcast, got %q", "streamload", conn.sent)
+			}
+			if status.Extra.Stream == nil || status.Extra.Stream.Url != "http://example.com/playing.mp4" {
+				t.Errorf("expected stream %q to be loaded, got %v", "http://example.com/playing.mp4", status.Extra.Stream)
+			}
+			if status.Extra.Playback.Time != 42 {
+				t.Errorf("expected playback to resume at 42 seconds, got %v", status.Extra.Playback.Time)
+			}
+			if len(conn.messages("queuesync")) == 0 {
+				t.Errorf("expected a %q event to be broadcast, got %q", "queuesync", conn.sent)
+			}
+
+			c, _ := h.clientHandler.GetClient(conn.UUID())
+			userQueue, exists, err := util.GetUserQueue(c, sPlayback.GetQueue())
+			if err != nil || !exists {
+				t.Fatalf("expected the snapshot's streams to be queued for the client loading it, got error %v", err)
+			}
+			items := userQueue.List()
+			if len(items) != 2 {
+				t.Fatalf("expected 2 queued streams, got %v", len(items))
+			}
+			first, ok := items[0].(*stream.TrimmedStream)
+			if !ok || first.GetStreamURL() != "http://example.com/first.mp4" || first.GetStart() != 10 || first.GetEnd() != 20 {
+				t.Errorf("expected the segment 10-20 of %q to be queued first, got %v", "http://example.com/first.mp4", items[0])
+			}
+			second, ok := items[1].(stream.Stream)
+			if !ok || second.GetStreamURL() != "http://example.com/second.mp4" {
+				t.Errorf("expected %q to be queued second, got %v", "http://example.com/second.mp4", items[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestSpectators(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	moderator := connect(t, h, ns, authorizer, "moderator", "moderator", rbac.ADMIN_ROLE)
+	bob := connect(t, h, ns, authorizer, "bob", "bob", rbac.USER_ROLE)
+	carol := connect(t, h, ns, authorizer, "carol", "carol", rbac.VIEWER_ROLE)
+	observer := connect(t, h, ns, authorizer, "observer", "observer", rbac.USER_ROLE)
+
+	// streams queued while another one is playing stay in the queue
+	sPlayback := loadStream(t, h, ns, "playing.mp4", 600)
+	if err := sPlayback.Play(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// delivered returns the number of chat messages from
+	// the client with the given id seen by the observer
+	delivered := func(id string) int {
+		count := 0
+		for _, data := range observer.messages("chatmessage") {
+			res := client.Response{}
+			if err := json.Unmarshal(data, &res); err == nil && !res.IsSystem && res.Id == id {
+				count++
+			}
+		}
+		return count
+	}
+
+	tests := []struct {
+		name            string
+		spectators      []string
+		conn            *fakeConnection
+		expectSpectator bool
+	}{
+		{
+			name: "participant",
+			conn: bob,
+		},
+		{
+			name:            "client made a spectator",
+			spectators:      []string{"add", "bob"},
+			conn:            bob,
+			expectSpectator: true,
+		},
+		{
+			name:            "client whose roles do not allow participating",
+			conn:            carol,
+			expectSpectator: true,
+		},
+		{
+			name:       "client no longer a spectator",
+			spectators: []string{"remove", "bob"},
+			conn:       bob,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.spectators) > 0 {
+				c, _ := h.clientHandler.GetClient(moderator.UUID())
+				if _, err := h.CommandHandler.ExecuteCommand("spectators", tc.spectators, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			tc.conn.clearMessages()
+
+			before := delivered(tc.conn.UUID())
+			data := connection.NewMessageData()
+			data.Set("message", tc.name)
+			tc.conn.Emit("request_chatmessage", data)
+
+			if sent := delivered(tc.conn.UUID()) > before; sent == tc.expectSpectator {
+				t.Errorf("expected the message to be broadcast: %v, got %v", !tc.expectSpectator, sent)
+			}
+			if tc.expectSpectator {
+				notice := client.Response{}
+				if !tc.conn.lastMessage("chatmessage", &notice) || !notice.IsSystem || !strings.Contains(notice.Message, "spectators may not send chat messages") {
+					t.Errorf("expected the sender to be told spectators may not chat, got %q", tc.conn.sent)
+				}
+			}
+
+			url := fmt.Sprintf("http://example.com/%v.mp4", i)
+			stubMetadata(h, url, `{"name": "queued", "duration": 60}`)
+			c, _ := h.clientHandler.GetClient(tc.conn.UUID())
+			_, err := h.CommandHandler.ExecuteCommand("queue", []string{"add", url}, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler)
+			if queued := err == nil; queued == tc.expectSpectator {
+				t.Errorf("expected the stream to be queued: %v, got error %v", !tc.expectSpectator, err)
+			}
+			if _, _, exists := sPlayback.QueueItemById(url); exists == tc.expectSpectator {
+				t.Errorf("expected the stream to be in the queue: %v, got %v", !tc.expectSpectator, exists)
+			}
+
+			// spectators are kept in sync with the room like anyone else
+			tc.conn.clearMessages()
+			tc.conn.Emit("request_streamsync", connection.NewMessageData())
+			status := statusResponse{}
+			if !tc.conn.lastMessage("streamsync", &status) {
+				t.Fatalf("expected a %q event to be sent, got %q", "streamsync", tc.conn.sent)
+			}
+			if status.Extra.Stream == nil || status.Extra.Stream.Url != "playing.mp4" {
+				t.Errorf("expected the current stream to be synced, got %v", status.Extra.Stream)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package socket
+
+import (
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+var (
+	StreamSyncMinRate = 5  // seconds between streamsync events in rooms at or below StreamSyncSmallRoomSize clients
+	StreamSyncMaxRate = 30 // seconds between streamsync events in rooms at or above StreamSyncLargeRoomSize clients
+
+	StreamSyncSmallRoomSize = 5
+	StreamSyncLargeRoomSize = 50
+)
+
+// StreamSyncRate returns the number of seconds to wait between streamsync
+// events for a room with the given number of clients. Small rooms are kept
+// tightly in sync, while large rooms are synced less often. Rooms in between
+// are synced at ROOM_DEFAULT_STREAMSYNC_RATE. The result is always bounded
+// by StreamSyncMinRate and StreamSyncMaxRate.
+func StreamSyncRate(clients int) int {
+	rate := ROOM_DEFAULT_STREAMSYNC_RATE
+	if clients <= StreamSyncSmallRoomSize {
+		rate = StreamSyncMinRate
+	} else if clients >= StreamSyncLargeRoomSize {
+		rate = StreamSyncMaxRate
+	}
+
+	if rate > StreamSyncMaxRate {
+		rate = StreamSyncMaxRate
+	}
+	if rate < StreamSyncMinRate {
+		rate = StreamSyncMinRate
+	}
+	if rate < 1 {
+		rate = 1
+	}
+
+	return rate
+}
+
+// namespaceClientCount returns the number of
+// clients currently in the given namespace
+func (h *Handler) namespaceClientCount(ns connection.Namespace) int {
+	count := 0
+	for _, c := range h.clientHandler.Clients() {
+		if cNs, exists := c.Namespace(); exists && cNs.Name() == ns.Name() {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,67 @@
+package socket
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStreamSyncRate(t *testing.T) {
+	h, ns := newTestHandler("room")
+
+	tests := []struct {
+		name       string
+		clients    int
+		expectRate int
+	}{
+		{
+			name:       "single client",
+			clients:    1,
+			expectRate: StreamSyncMinRate,
+		},
+		{
+			name:       "small room",
+			clients:    StreamSyncSmallRoomSize,
+			expectRate: StreamSyncMinRate,
+		},
+		{
+			name:       "just above the small room threshold",
+			clients:    StreamSyncSmallRoomSize + 1,
+			expectRate: ROOM_DEFAULT_STREAMSYNC_RATE,
+		},
+		{
+			name:       "just below the large room threshold",
+			clients:    StreamSyncLargeRoomSize - 1,
+			expectRate: ROOM_DEFAULT_STREAMSYNC_RATE,
+		},
+		{
+			name:       "large room",
+			clients:    StreamSyncLargeRoomSize,
+			expectRate: StreamSyncMaxRate,
+		},
+		{
+			name:       "larger room",
+			clients:    StreamSyncLargeRoomSize + 10,
+			expectRate: StreamSyncMaxRate,
+		},
+	}
+
+	// clients join the room as each test is run, and the
+	// rate is computed from the room's live client count
+	joined := 0
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for ; joined < tc.clients; joined++ {
+				id := fmt.Sprintf("client-%d", joined)
+				connect(t, h, ns, nil, id, id, "")
+			}
+
+			count := h.namespaceClientCount(ns)
+			if count != tc.clients {
+				t.Fatalf("expected %v clients in the room, got %v", tc.clients, count)
+			}
+			if rate := StreamSyncRate(count); rate != tc.expectRate {
+				t.Errorf("expected a sync rate of %v seconds, got %v", tc.expectRate, rate)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestStatusThumbnail(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    string
+		expectThumb string
+	}{
+		{
+			name:        "thumbnail provided by the metadata",
+			metadata:    `{"name": "A", "duration": 60, "thumb": "http://example.com/a.jpg"}`,
+			expectThumb: "http://example.com/a.jpg",
+		},
+		{
+			name:     "no thumbnail provided",
+			metadata: `{"name": "A", "duration": 60}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, ns, authorizer := newTestHandlerWithRBAC("room")
+			admin := connect(t, h, ns, authorizer, "admin", "admin", rbac.ADMIN_ROLE)
+
+			url := "http://example.com/a.mp4"
+			stubMetadata(h, url, tc.metadata)
+
+			c, _ := h.clientHandler.GetClient(admin.UUID())
+			if _, err := h.CommandHandler.ExecuteCommand("queue", []string{"add", url}, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			admin.clearMessages()
+			admin.Emit("request_streamsync", connection.NewMessageData())
+
+			res := statusResponse{}
+			if !admin.lastMessage("streamsync", &res) {
+				t.Fatalf("expected a %q event to be sent, got %q", "streamsync", admin.sent)
+			}
+			if res.Extra.Stream == nil || res.Extra.Stream.Url != url {
+				t.Fatalf("expected %q to be playing, got %v", url, res.Extra.Stream)
+			}
+			if res.Extra.Stream.Thumb != tc.expectThumb {
+				t.Errorf("expected thumbnail %q, got %q", tc.expectThumb, res.Extra.Stream.Thumb)
+			}
+		})
+	}
+}
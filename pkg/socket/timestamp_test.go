@@ -0,0 +1,76 @@
+package socket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// chatMessageResponse is the data of a "chatmessage" event
+type chatMessageResponse struct {
+	Message string                 `json:"message"`
+	Extra   map[string]interface{} `json:"extra"`
+}
+
+func TestChatMessageTimestamp(t *testing.T) {
+	h, ns := newTestHandler("room")
+	sender := connect(t, h, ns, nil, "sender", "sender", "")
+	receiver := connect(t, h, ns, nil, "receiver", "receiver", "")
+
+	tests := []struct {
+		name            string
+		clientTimestamp interface{}
+	}{
+		{
+			name: "no client timestamp",
+		},
+		{
+			name:            "client timestamp in the past",
+			clientTimestamp: float64(0),
+		},
+		{
+			name:            "client timestamp in the future",
+			clientTimestamp: float64(time.Now().Add(24*time.Hour).UnixNano() / int64(time.Millisecond)),
+		},
+		{
+			name:            "non-numeric client timestamp",
+			clientTimestamp: "yesterday",
+		},
+	}
+
+	last := float64(0)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver.clearMessages()
+
+			data := connection.NewMessageData()
+			data.Set("message", tc.name)
+			if tc.clientTimestamp != nil {
+				data.Set("timestamp", tc.clientTimestamp)
+				data.Set("extra", map[string]interface{}{"timestamp": tc.clientTimestamp})
+			}
+
+			before := float64(time.Now().UnixNano() / int64(time.Millisecond))
+			sender.Emit("request_chatmessage", data)
+			after := float64(time.Now().UnixNano() / int64(time.Millisecond))
+
+			res := chatMessageResponse{}
+			if !receiver.lastMessage("chatmessage", &res) {
+				t.Fatalf("expected a %q event to be broadcast, got %q", "chatmessage", receiver.sent)
+			}
+
+			timestamp, ok := res.Extra["timestamp"].(float64)
+			if !ok {
+				t.Fatalf("expected a numeric timestamp in the broadcast message, got %v", res.Extra["timestamp"])
+			}
+			if timestamp < before || timestamp > after {
+				t.Errorf("expected the server's time (between %v and %v), got %v", before, after, timestamp)
+			}
+			if timestamp < last {
+				t.Errorf("expected timestamps not to decrease across messages, got %v after %v", timestamp, last)
+			}
+			last = timestamp
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package socket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/stream"
+)
+
+func TestQueueTrimmedStream(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	conn := connect(t, h, ns, authorizer, "user", "user", rbac.USER_ROLE)
+	stubMetadata(h, "http://example.com/clip.mp4", `{"duration": 600}`)
+	stubMetadata(h, "http://example.com/next.mp4", `{"duration": 600}`)
+
+	data := connection.NewMessageData()
+	data.Set("url", "http://example.com/clip.mp4")
+	data.Set("start", float64(10))
+	data.Set("end", float64(12))
+	conn.Emit("request_queuetrimmed", data)
+
+	sPlayback, _ := h.PlaybackHandler.PlaybackByNamespace(ns)
+	s, exists := sPlayback.GetStream()
+	if !exists {
+		t.Fatalf("expected the trimmed stream to be played, got %q", conn.sent)
+	}
+	if _, ok := s.(*stream.TrimmedStream); !ok {
+		t.Fatalf("expected a trimmed stream to be played, got %T", s)
+	}
+	if time := sPlayback.GetTime(); time < 10 || time > 11 {
+		t.Errorf("expected playback to start at 10 seconds, got %v", time)
+	}
+
+	c, _ := h.clientHandler.GetClient(conn.UUID())
+	if _, err := h.CommandHandler.ExecuteCommand("queue", []string{"add", "http://example.com/next.mp4"}, c, h.clientHandler, h.PlaybackHandler, h.StreamHandler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the trimmed stream ends two seconds after it starts,
+	// and the end of a stream is checked every other second
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(conn.messages("streamload")) > 1 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	res := statusResponse{}
+	if !conn.lastMessage("streamload", &res) || len(conn.messages("streamload")) < 2 {
+		t.Fatalf("expected the queue to advance at the end of the trimmed stream")
+	}
+	if res.Extra.Stream == nil || res.Extra.Stream.Url != "http://example.com/next.mp4" {
+		t.Errorf("expected the next stream to be loaded, got %v", res.Extra.Stream)
+	}
+	if res.Extra.Playback.Time > 1 {
+		t.Errorf("expected the next stream to start from its beginning, got %v", res.Extra.Playback.Time)
+	}
+}
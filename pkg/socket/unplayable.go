@@ -0,0 +1,55 @@
+package socket
+
+import (
+	"fmt"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+	"github.com/juanvallejo/streaming-server/pkg/socket/util"
+)
+
+// skipUnplayableStreams advances a room past any loaded streams reported
+// as unplayable by their provider, and notifies the room of each skipped
+// stream. Returns a boolean (true) if any streams were skipped.
+func (h *Handler) skipUnplayableStreams(namespace connection.Namespace, sPlayback *playback.Playback) bool {
+	skipped, loaded, err := sPlayback.SkipUnplayable()
+	if err != nil {
+		h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT unable to skip unplayable stream: %v", err)
+	}
+	if len(skipped) == 0 {
+		return false
+	}
+
+	for _, s := range skipped {
+		h.logger.Infof("CALLBACK-PLAYBACK SOCKET CLIENT skipped unplayable stream %q in room %q", s.GetStreamURL(), namespace.Name())
+		h.BroadcastToNamespace(namespace, "chatmessage", &client.Response{
+			From:     client.USER_SYSTEM,
+			Message:  fmt.Sprintf("skipping %q - the stream is private, deleted, or otherwise unavailable", s.GetStreamURL()),
+			IsSystem: true,
+		})
+	}
+
+	res := &client.Response{
+		From: client.USER_SYSTEM,
+	}
+
+	err = util.SerializeIntoResponse(sPlayback.GetStatus(), &res.Extra)
+	if err != nil {
+		h.logger.Errorf("CALLBACK-PLAYBACK SOCKET CLIENT unable to serialize playback status: %v", err)
+		return true
+	}
+
+	if loaded {
+		h.BroadcastToNamespace(namespace, "streamload", res)
+		if msg, ok := sPlayback.StreamAnnouncement(); ok {
+			h.BroadcastToNamespace(namespace, "chatmessage", &client.Response{
+				From:     client.USER_SYSTEM,
+				Message:  msg,
+				IsSystem: true,
+			})
+		}
+	}
+	h.BroadcastToNamespace(namespace, "streamsync", res)
+	return true
+}
@@ -0,0 +1,76 @@
+package socket
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/socket/client"
+	"github.com/juanvallejo/streaming-server/pkg/socket/cmd/rbac"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+func TestUserListRoles(t *testing.T) {
+	h, ns, authorizer := newTestHandlerWithRBAC("room")
+	alice := connect(t, h, ns, authorizer, "alice", "alice", rbac.ADMIN_ROLE)
+	bob := connect(t, h, ns, authorizer, "bob", "bob", rbac.USER_ROLE)
+	carol := connect(t, h, ns, authorizer, "carol", "carol", rbac.VIEWER_ROLE)
+
+	// a role created for the room shares its display name with the
+	// server-wide role it overrides, and is listed once per client
+	roomAdmin := rbac.NewRole(rbac.RoomRoleName(ns.Name(), rbac.ADMIN_ROLE), []rbac.Rule{})
+	authorizer.AddRole(roomAdmin)
+	authorizer.Bind(roomAdmin, alice)
+	moderator := rbac.NewRole(rbac.RoomRoleName(ns.Name(), "moderator"), []rbac.Rule{})
+	authorizer.AddRole(moderator)
+	authorizer.Bind(moderator, alice, carol)
+
+	// binding a client to a role it is already bound to
+	userRole, _ := authorizer.Role(rbac.USER_ROLE)
+	authorizer.Bind(userRole, alice, bob, bob)
+
+	expectRoles := map[string][]string{
+		alice.UUID(): {"admin", "moderator", "user"},
+		bob.UUID():   {"user"},
+		carol.UUID(): {"moderator", "viewer"},
+	}
+
+	alice.Emit("request_userlist", connection.NewMessageData())
+	roster := client.SerializableClientList{}
+	if !alice.lastMessage("userlist", &roster) {
+		t.Fatalf("expected a %q event to be sent, got %q", "userlist", alice.sent)
+	}
+	rosterRoles := map[string][]string{}
+	for _, c := range roster.Clients {
+		rosterRoles[c.Id] = c.Roles
+	}
+
+	tests := []struct {
+		name  string
+		roles map[string][]string
+	}{
+		{
+			name:  "roles by subject",
+			roles: h.rolesBySubject(),
+		},
+		{
+			name:  "roles in the userlist",
+			roles: rosterRoles,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.roles) != len(expectRoles) {
+				t.Errorf("expected roles for %v clients, got %v", len(expectRoles), tc.roles)
+			}
+			for id, expected := range expectRoles {
+				roles := append([]string{}, tc.roles[id]...)
+				sort.Strings(roles)
+				if strings.Join(roles, ",") != strings.Join(expected, ",") {
+					t.Errorf("expected client %q to have roles %v, got %v", id, expected, tc.roles[id])
+				}
+			}
+		})
+	}
+}
@@ -22,7 +22,7 @@ const ROOM_URL_SEGMENT = "/v/"
 
 // TODO: make this function concurrency-safe
 func UpdateClientUsername(c *client.Client, username string, clientHandler client.SocketClientHandler) error {
-	err := validation.ValidateClientUsername(username)
+	username, err := validation.NormalizeClientUsername(username)
 	if err != nil {
 		return err
 	}
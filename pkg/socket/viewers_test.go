@@ -0,0 +1,73 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/juanvallejo/streaming-server/pkg/playback"
+	"github.com/juanvallejo/streaming-server/pkg/socket/connection"
+)
+
+// viewerHistoryResponse is the data of a "viewerhistory" event
+type viewerHistoryResponse struct {
+	Extra playback.ViewerHistory `json:"extra"`
+}
+
+func TestViewerHistory(t *testing.T) {
+	h, ns := newTestHandler("room")
+	first := connect(t, h, ns, nil, "first", "first", "")
+	second := connect(t, h, ns, nil, "second", "second", "")
+	third := connect(t, h, ns, nil, "third", "third", "")
+	second.Emit("disconnection", nil)
+	third.Emit("disconnection", nil)
+	connect(t, h, ns, nil, "fourth", "fourth", "")
+
+	tests := []struct {
+		name         string
+		limit        interface{}
+		expectCounts []int
+	}{
+		{
+			name:         "every count change is recorded",
+			expectCounts: []int{1, 2, 3, 2, 1, 2},
+		},
+		{
+			name:         "most recent samples",
+			limit:        float64(3),
+			expectCounts: []int{2, 1, 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			first.clearMessages()
+
+			data := connection.NewMessageData()
+			if tc.limit != nil {
+				data.Set("limit", tc.limit)
+			}
+			first.Emit("request_viewerhistory", data)
+
+			res := viewerHistoryResponse{}
+			if !first.lastMessage("viewerhistory", &res) {
+				t.Fatalf("expected a %q event to be sent, got %q", "viewerhistory", first.sent)
+			}
+
+			counts := []int{}
+			for i, sample := range res.Extra.Samples {
+				counts = append(counts, sample.Count)
+				if i > 0 && sample.Time.Before(res.Extra.Samples[i-1].Time) {
+					t.Errorf("expected samples to be ordered oldest first, got %v", res.Extra.Samples)
+				}
+			}
+			if len(counts) != len(tc.expectCounts) {
+				t.Fatalf("expected viewer counts %v, got %v", tc.expectCounts, counts)
+			}
+			for i := range counts {
+				if counts[i] != tc.expectCounts[i] {
+					t.Errorf("expected viewer counts %v, got %v", tc.expectCounts, counts)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMetadataTTL = 1 * time.Hour // default amount of time fetched stream metadata is reused for
+)
+
+// MetadataTTL is the amount of time metadata fetched for a stream url
+// is reused for, before it is considered stale and fetched again.
+var MetadataTTL = DefaultMetadataTTL
+
+type cachedMetadata struct {
+	data      []byte
+	fetchedAt time.Time
+}
+
+// metadataCache stores the metadata most recently fetched for
+// each stream url. It is safe for concurrent use.
+type metadataCache struct {
+	mutex   sync.Mutex
+	entries map[string]cachedMetadata
+}
+
+// get returns the metadata cached for the given url, and a
+// boolean (false) if no metadata is cached or it has expired.
+func (c *metadataCache) get(url string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.entries[url]
+	if !exists || time.Since(entry.fetchedAt) > MetadataTTL {
+		return nil, false
+	}
+
+	return entry.data, true
+}
+
+// set caches the given metadata for the given url,
+// discarding any other entries that have expired.
+func (c *metadataCache) set(url string, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for u, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) > MetadataTTL {
+			delete(c.entries, u)
+		}
+	}
+
+	c.entries[url] = cachedMetadata{
+		data:      data,
+		fetchedAt: now,
+	}
+}
+
+// invalidate discards any metadata cached for the given url
+func (c *metadataCache) invalidate(url string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, url)
+}
+
+func (h *Handler) FetchMetadata(s Stream, callback StreamMetadataCallback) {
+	if data, ok := h.metadata.get(s.GetStreamURL()); ok {
+		callback(s, data, nil)
+		return
+	}
+
+	start := time.Now()
+	s.FetchMetadata(func(s Stream, data []byte, err error) {
+		metadataFetchSeconds.ObserveSince(start)
+		if err != nil {
+			metadataFetchErrors.Inc()
+		} else {
+			h.metadata.set(s.GetStreamURL(), data)
+		}
+
+		callback(s, data, err)
+	})
+}
+
+func (h *Handler) MetadataExpired(s Stream) bool {
+	_, ok := h.metadata.get(s.GetStreamURL())
+	return !ok
+}
+
+func (h *Handler) InvalidateMetadata(s Stream) {
+	h.metadata.invalidate(s.GetStreamURL())
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{
+		entries: make(map[string]cachedMetadata),
+	}
+}
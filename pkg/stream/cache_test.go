@@ -0,0 +1,125 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countingStream is a stream whose metadata fetches complete
+// immediately with the next metadata it is given, counting
+// each fetch. Fetches fail if no metadata is given.
+type countingStream struct {
+	*StreamSchema
+	fetches  int
+	metadata []byte
+}
+
+func (s *countingStream) FetchMetadata(callback StreamMetadataCallback) {
+	s.fetches++
+	if s.metadata == nil {
+		callback(s, nil, fmt.Errorf("unable to fetch metadata"))
+		return
+	}
+	callback(s, s.metadata, nil)
+}
+
+func TestFetchMetadataCache(t *testing.T) {
+	url := "http://example.com/a.mp4"
+	h := NewHandler().(*Handler)
+	s := &countingStream{
+		StreamSchema: &StreamSchema{
+			Url:  url,
+			Kind: STREAM_TYPE_REMOTE,
+			Meta: NewStreamMeta(),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		metadata       string
+		expire         bool
+		invalidate     bool
+		expectErr      bool
+		expectFetches  int
+		expectMetadata string
+	}{
+		{
+			name:          "failed fetch is not cached",
+			expectErr:     true,
+			expectFetches: 1,
+		},
+		{
+			name:           "first fetch",
+			metadata:       `{"duration": 60}`,
+			expectFetches:  2,
+			expectMetadata: `{"duration": 60}`,
+		},
+		{
+			name:           "cache hit skips the fetch",
+			metadata:       `{"duration": 120}`,
+			expectFetches:  2,
+			expectMetadata: `{"duration": 60}`,
+		},
+		{
+			name:           "expired entry is fetched again",
+			metadata:       `{"duration": 120}`,
+			expire:         true,
+			expectFetches:  3,
+			expectMetadata: `{"duration": 120}`,
+		},
+		{
+			name:           "refetched entry is cached",
+			metadata:       `{"duration": 180}`,
+			expectFetches:  3,
+			expectMetadata: `{"duration": 120}`,
+		},
+		{
+			name:           "invalidated entry is fetched again",
+			metadata:       `{"duration": 180}`,
+			invalidate:     true,
+			expectFetches:  4,
+			expectMetadata: `{"duration": 180}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s.metadata = nil
+			if len(tc.metadata) > 0 {
+				s.metadata = []byte(tc.metadata)
+			}
+			if tc.expire {
+				h.metadata.mutex.Lock()
+				entry := h.metadata.entries[url]
+				entry.fetchedAt = time.Now().Add(-MetadataTTL - time.Second)
+				h.metadata.entries[url] = entry
+				h.metadata.mutex.Unlock()
+
+				if !h.MetadataExpired(s) {
+					t.Errorf("expected the cached metadata to be expired")
+				}
+			}
+			if tc.invalidate {
+				h.InvalidateMetadata(s)
+			}
+
+			var data []byte
+			var fetchErr error
+			h.FetchMetadata(s, func(_ Stream, d []byte, err error) {
+				data = d
+				fetchErr = err
+			})
+
+			if tc.expectErr != (fetchErr != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, fetchErr)
+			}
+			if s.fetches != tc.expectFetches {
+				t.Errorf("expected %v metadata fetches, got %v", tc.expectFetches, s.fetches)
+			}
+			if string(data) != tc.expectMetadata {
+				t.Errorf("expected metadata %q, got %q", tc.expectMetadata, string(data))
+			}
+		})
+	}
+}
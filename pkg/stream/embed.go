@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	EMBED_TYPE_VIMEO = "vimeo"
+)
+
+// VideoEmbed is a serializable schema describing
+// a video url shared in chat that clients may embed.
+type VideoEmbed struct {
+	Kind  string `json:"kind"`
+	Id    string `json:"id"`
+	Url   string `json:"url"`
+	Start int    `json:"start,omitempty"`
+}
+
+// ParseVideoEmbed receives a url and returns embed information for it,
+// or a boolean (false) if the url does not point to a YouTube or Vimeo video.
+func ParseVideoEmbed(videoUrl string) (*VideoEmbed, bool) {
+	u, err := url.Parse(videoUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, false
+	}
+
+	embed := &VideoEmbed{
+		Url: videoUrl,
+	}
+
+	switch hostFromUrl(u) {
+	case "youtube.com", "youtu.be", "m.youtube.com":
+		embed.Kind = STREAM_TYPE_YOUTUBE
+
+		// the video id is the "v" query parameter of watch
+		// urls, and the last segment of the path otherwise
+		id := u.Query().Get("v")
+		if u.Path != "/watch" {
+			segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+			id = segs[len(segs)-1]
+		}
+		if !ytVideoId.MatchString(id) {
+			return nil, false
+		}
+		embed.Id = id
+
+		start := u.Query().Get("t")
+		if len(start) == 0 {
+			start = u.Query().Get("start")
+		}
+		embed.Start = parseEmbedTimestamp(start)
+	case "vimeo.com", "player.vimeo.com":
+		embed.Kind = EMBED_TYPE_VIMEO
+
+		segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+		embed.Id = segs[len(segs)-1]
+		if _, err := strconv.Atoi(embed.Id); err != nil {
+			return nil, false
+		}
+
+		// vimeo timestamps are given as a "#t=" url fragment
+		embed.Start = parseEmbedTimestamp(strings.TrimPrefix(u.Fragment, "t="))
+	default:
+		return nil, false
+	}
+
+	if len(embed.Id) == 0 {
+		return nil, false
+	}
+
+	return embed, true
+}
+
+// parseEmbedTimestamp receives a video start time in seconds
+// ("90", "90s") or in hours, minutes and seconds ("1m30s"), and
+// returns the total number of seconds. Returns 0 if the timestamp
+// cannot be parsed.
+func parseEmbedTimestamp(timestamp string) int {
+	if len(timestamp) == 0 {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(timestamp); err == nil && seconds > 0 {
+		return seconds
+	}
+
+	total := 0
+	value := ""
+	for _, r := range timestamp {
+		switch r {
+		case 'h', 'm', 's':
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return 0
+			}
+
+			switch r {
+			case 'h':
+				total += n * 3600
+			case 'm':
+				total += n * 60
+			case 's':
+				total += n
+			}
+			value = ""
+		default:
+			value += string(r)
+		}
+	}
+
+	if len(value) > 0 {
+		return 0
+	}
+	return total
+}
+
+// hostFromUrl returns the given url's host,
+// without any leading "www." subdomain
+func hostFromUrl(u *url.URL) string {
+	return strings.TrimPrefix(u.Host, "www.")
+}
@@ -0,0 +1,115 @@
+package stream
+
+import "testing"
+
+func TestParseVideoEmbed(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		expectEmbed bool
+		expectKind  string
+		expectId    string
+		expectStart int
+	}{
+		{
+			name:        "youtube watch url",
+			url:         "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			expectEmbed: true,
+			expectKind:  STREAM_TYPE_YOUTUBE,
+			expectId:    "dQw4w9WgXcQ",
+		},
+		{
+			name:        "youtube watch url with other query parameters",
+			url:         "https://www.youtube.com/watch?list=PL123&v=dQw4w9WgXcQ&t=90",
+			expectEmbed: true,
+			expectKind:  STREAM_TYPE_YOUTUBE,
+			expectId:    "dQw4w9WgXcQ",
+			expectStart: 90,
+		},
+		{
+			name:        "mobile youtube watch url",
+			url:         "https://m.youtube.com/watch?v=dQw4w9WgXcQ",
+			expectEmbed: true,
+			expectKind:  STREAM_TYPE_YOUTUBE,
+			expectId:    "dQw4w9WgXcQ",
+		},
+		{
+			name:        "youtu.be short link",
+			url:         "https://youtu.be/dQw4w9WgXcQ",
+			expectEmbed: true,
+			expectKind:  STREAM_TYPE_YOUTUBE,
+			expectId:    "dQw4w9WgXcQ",
+		},
+		{
+			name:        "youtu.be short link with a timestamp",
+			url:         "https://youtu.be/dQw4w9WgXcQ?t=1m30s",
+			expectEmbed: true,
+			expectKind:  STREAM_TYPE_YOUTUBE,
+			expectId:    "dQw4w9WgXcQ",
+			expectStart: 90,
+		},
+		{
+			name: "youtube url without a video",
+			url:  "https://www.youtube.com/feed/trending",
+		},
+		{
+			name:        "vimeo link",
+			url:         "https://vimeo.com/76979871",
+			expectEmbed: true,
+			expectKind:  EMBED_TYPE_VIMEO,
+			expectId:    "76979871",
+		},
+		{
+			name:        "vimeo link with a timestamp",
+			url:         "https://vimeo.com/76979871#t=45s",
+			expectEmbed: true,
+			expectKind:  EMBED_TYPE_VIMEO,
+			expectId:    "76979871",
+			expectStart: 45,
+		},
+		{
+			name:        "vimeo player link",
+			url:         "https://player.vimeo.com/video/76979871",
+			expectEmbed: true,
+			expectKind:  EMBED_TYPE_VIMEO,
+			expectId:    "76979871",
+		},
+		{
+			name: "vimeo link without a video",
+			url:  "https://vimeo.com/channels",
+		},
+		{
+			name: "other site",
+			url:  "https://example.com/watch?v=dQw4w9WgXcQ",
+		},
+		{
+			name: "not a web url",
+			url:  "ftp://youtube.com/watch?v=dQw4w9WgXcQ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			embed, ok := ParseVideoEmbed(tc.url)
+			if ok != tc.expectEmbed {
+				t.Fatalf("expected an embed: %v, got %v", tc.expectEmbed, embed)
+			}
+			if !tc.expectEmbed {
+				return
+			}
+
+			if embed.Kind != tc.expectKind {
+				t.Errorf("expected kind %q, got %q", tc.expectKind, embed.Kind)
+			}
+			if embed.Id != tc.expectId {
+				t.Errorf("expected id %q, got %q", tc.expectId, embed.Id)
+			}
+			if embed.Url != tc.url {
+				t.Errorf("expected url %q, got %q", tc.url, embed.Url)
+			}
+			if embed.Start != tc.expectStart {
+				t.Errorf("expected start %v, got %v", tc.expectStart, embed.Start)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	paths "github.com/juanvallejo/streaming-server/pkg/server/path"
+)
+
+const (
+	FILE_STREAM_SCHEME = "file://"
+)
+
+// FileStream implements Stream and represents a video file stored
+// in the server's media directory, requested through a file:// url.
+// Its stream url is the http path the file is served from.
+type FileStream struct {
+	*StreamSchema
+
+	// fpath is the location of the file on disk
+	fpath string
+}
+
+func (s *FileStream) FetchMetadata(callback StreamMetadataCallback) {
+	go func(s *FileStream, callback StreamMetadataCallback) {
+		data, err := FetchVideoMetadata(s.fpath)
+		if err != nil {
+			callback(s, []byte{}, err)
+			return
+		}
+
+		callback(s, data, nil)
+	}(s, callback)
+}
+
+// IsFileStreamUrl returns true if the given url refers to a file in the media directory
+func IsFileStreamUrl(streamUrl string) bool {
+	return strings.HasPrefix(streamUrl, FILE_STREAM_SCHEME)
+}
+
+// fileStreamUrl receives a file:// url and returns the
+// http path the file it refers to is served from.
+func fileStreamUrl(streamUrl string) string {
+	return paths.StreamDataUrlFromFilename(strings.TrimPrefix(streamUrl, FILE_STREAM_SCHEME))
+}
+
+// NewFileStream receives a file:// url naming a file relative to the
+// server's media directory, and returns a stream serving that file.
+// Returns an error if the file is outside of the media directory, does
+// not exist, or is not a video file.
+func NewFileStream(streamUrl string) (Stream, error) {
+	fname := strings.TrimPrefix(streamUrl, FILE_STREAM_SCHEME)
+	fpath, err := paths.StreamDataFilePath(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType, err := paths.FileMimeFromFilePath(fpath)
+	if err != nil || !strings.HasPrefix(mimeType, "video") {
+		return nil, fmt.Errorf("unable to load %q. Unsupported streaming file.", streamUrl)
+	}
+
+	info, err := os.Stat(fpath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unable to load %q: video file does not exist.", streamUrl)
+		}
+		return nil, fmt.Errorf("unable to load %q: %v", streamUrl, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("unable to load %q: not a video file.", streamUrl)
+	}
+
+	return &FileStream{
+		StreamSchema: &StreamSchema{
+			Url:  fileStreamUrl(streamUrl),
+			Name: info.Name(),
+			Kind: STREAM_TYPE_LOCAL,
+			Meta: NewStreamMeta(),
+		},
+
+		fpath: fpath,
+	}, nil
+}
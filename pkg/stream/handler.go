@@ -28,6 +28,20 @@ type StreamHandler interface {
 	NewStream(string) (Stream, error)
 	// GetSize returns the number of stream objects currently registered
 	GetSize() int
+	// ExpandPlaylist receives a playlist url and a limit, and returns
+	// up to that many stream urls from the playlist, in order, along
+	// with the total number of items in the playlist.
+	ExpandPlaylist(string, int) ([]string, int, error)
+	// FetchMetadata receives a Stream and calls its FetchMetadata method,
+	// unless metadata fetched for the stream's url within MetadataTTL is
+	// cached, in which case the callback receives the cached metadata.
+	FetchMetadata(Stream, StreamMetadataCallback)
+	// MetadataExpired returns true if no metadata has been fetched
+	// for the given Stream's url within MetadataTTL.
+	MetadataExpired(Stream) bool
+	// InvalidateMetadata discards any metadata cached
+	// for the given Stream's url.
+	InvalidateMetadata(Stream)
 }
 
 // Handler provides a convenience set of methods for
@@ -36,12 +50,18 @@ type Handler struct {
 	isGarbageCollected bool
 	garbageCollector   *StreamReaper
 	streams            map[string]Stream
+	metadata           *metadataCache
 }
 
 // GetStream retrieves a stream by its assigned url
 // or a bool (false) if a stream does not exist by the
 // given resource location
 func (h *Handler) GetStream(url string) (Stream, bool) {
+	// file streams are registered under the url they are served from
+	if IsFileStreamUrl(url) {
+		url = fileStreamUrl(url)
+	}
+
 	s, exists := h.streams[url]
 	return s, exists
 }
@@ -89,20 +109,32 @@ func (h *Handler) NewStream(streamUrl string) (Stream, error) {
 		return nil, fmt.Errorf("error: a stream with resource location %q has already been registered", streamUrl)
 	}
 
+	if IsFileStreamUrl(streamUrl) {
+		if _, exists := h.streams[fileStreamUrl(streamUrl)]; exists {
+			return nil, fmt.Errorf("error: a stream with resource location %q has already been registered", streamUrl)
+		}
+
+		s, err := NewFileStream(streamUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		h.streams[s.GetStreamURL()] = s
+		return s, nil
+	}
+
 	u, err := url.Parse(streamUrl)
 	if err != nil {
 		return nil, err
 	}
 
 	if u.Scheme == "http" || u.Scheme == "https" {
-		host := u.Host
-		segs := strings.Split(u.Host, "www.")
-		if len(segs) > 1 {
-			host = segs[1]
-		}
-
-		switch host {
+		switch hostFromUrl(u) {
 		case "youtube.com", "youtu.be", "m.youtube.com":
+			if id, err := ytVideoIdFromUrl(streamUrl); err != nil || !ytVideoId.MatchString(id) {
+				return nil, fmt.Errorf("invalid YouTube url. Expecting a video url")
+			}
+
 			s := NewYouTubeStream(streamUrl)
 			h.streams[streamUrl] = s
 			return s, nil
@@ -111,7 +143,17 @@ func (h *Handler) NewStream(streamUrl string) (Stream, error) {
 			h.streams[streamUrl] = s
 			return s, nil
 		case "twitch.tv":
-			s := NewTwitchStream(streamUrl)
+			if IsTwitchVodUrl(streamUrl) {
+				s := NewTwitchStream(streamUrl)
+				h.streams[streamUrl] = s
+				return s, nil
+			}
+
+			if _, err := twitchChannelFromUrl(streamUrl); err != nil {
+				return nil, fmt.Errorf("invalid Twitch url. Expecting a channel or /videos/ url")
+			}
+
+			s := NewTwitchLiveStream(streamUrl)
 			h.streams[streamUrl] = s
 			return s, nil
 		case "clips-media-assets.twitch.tv":
@@ -132,6 +174,12 @@ func (h *Handler) NewStream(streamUrl string) (Stream, error) {
 			}
 
 			format := paths.FileExtensionFromFilePath(u.Path)
+			if strings.ToLower(format) == ".m3u8" {
+				s := NewHLSStream(streamUrl)
+				h.streams[streamUrl] = s
+				return s, nil
+			}
+
 			if supported, ok := supportedFormats[strings.ToLower(format)]; ok && supported {
 				s := NewRemoteVideoStream(streamUrl)
 				h.streams[streamUrl] = s
@@ -142,7 +190,10 @@ func (h *Handler) NewStream(streamUrl string) (Stream, error) {
 		return nil, fmt.Errorf("stream resource location interpreted as url, but stream source is not supported for: %q", streamUrl)
 	}
 
-	fpath := paths.StreamDataFilePathFromFilename(streamUrl)
+	fpath, err := paths.StreamDataFilePath(streamUrl)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %q: %v", streamUrl, err)
+	}
 
 	// determine if a mimetype can be determined from the requested filepath,
 	// and that the mimetype (if any) is supported.
@@ -167,7 +218,8 @@ func (h *Handler) NewStream(streamUrl string) (Stream, error) {
 
 func NewHandler() StreamHandler {
 	return &Handler{
-		streams: make(map[string]Stream),
+		streams:  make(map[string]Stream),
+		metadata: newMetadataCache(),
 	}
 }
 
@@ -175,6 +227,7 @@ func NewGarbageCollectedHandler() StreamHandler {
 	h := &Handler{
 		garbageCollector: NewStreamReaper(),
 		streams:          make(map[string]Stream),
+		metadata:         newMetadataCache(),
 	}
 	h.initGarbageCollector()
 	return h
@@ -0,0 +1,144 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HLSStream implements Stream and represents an HTTP Live Streaming
+// (.m3u8) playlist. HLS streams are treated as live broadcasts unless
+// their playlist is a complete (VOD) playlist, in which case their
+// duration is the sum of the playlist's segment durations.
+type HLSStream struct {
+	*StreamSchema
+}
+
+func (s *HLSStream) FetchMetadata(callback StreamMetadataCallback) {
+	go func(s *HLSStream, callback StreamMetadataCallback) {
+		playlist, err := fetchHLSPlaylist(s.Url)
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+
+		// master playlists list variant playlists of differing
+		// quality - measure the first variant listed.
+		if variant, isMaster := HLSVariantUrl(playlist, s.Url); isMaster {
+			playlist, err = fetchHLSPlaylist(variant)
+			if err != nil {
+				callback(s, nil, err)
+				return
+			}
+		}
+
+		duration, _ := HLSPlaylistDuration(playlist)
+		data, err := json.Marshal(map[string]interface{}{
+			"duration": duration,
+		})
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+
+		callback(s, data, nil)
+	}(s, callback)
+}
+
+func fetchHLSPlaylist(playlistUrl string) ([]byte, error) {
+	res, err := http.Get(playlistUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if isUnavailableStatus(res.StatusCode) {
+		return nil, ErrStreamUnavailable
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response fetching HLS playlist %q: %s", playlistUrl, res.Status)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
+
+// HLSPlaylistDuration receives the contents of a media playlist and
+// returns the total duration of its segments, in seconds. Returns a
+// boolean (false) and a duration of zero if the playlist is a live
+// playlist, whose segments do not make up the entire stream.
+func HLSPlaylistDuration(playlist []byte) (float64, bool) {
+	duration := 0.0
+	complete := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:<duration>,[<title>]
+			value := strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)[0]
+			segment, err := strconv.ParseFloat(value, 64)
+			if err == nil && segment > 0 {
+				duration += segment
+			}
+		case line == "#EXT-X-ENDLIST", line == "#EXT-X-PLAYLIST-TYPE:VOD":
+			complete = true
+		}
+	}
+
+	if !complete {
+		return 0, false
+	}
+	return duration, true
+}
+
+// HLSVariantUrl receives the contents of a playlist and the url it was
+// fetched from. If the playlist is a master playlist, the absolute url of
+// its first variant playlist is returned. Returns a boolean (false) if the
+// playlist is a media playlist.
+func HLSVariantUrl(playlist []byte, playlistUrl string) (string, bool) {
+	isMaster := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#EXT-X-STREAM-INF") {
+			isMaster = true
+			continue
+		}
+
+		// the first uri following a variant's tag locates its playlist
+		if isMaster && len(line) > 0 && !strings.HasPrefix(line, "#") {
+			base, err := url.Parse(playlistUrl)
+			if err != nil {
+				return line, true
+			}
+
+			ref, err := url.Parse(line)
+			if err != nil {
+				return line, true
+			}
+
+			return base.ResolveReference(ref).String(), true
+		}
+	}
+
+	return "", false
+}
+
+func NewHLSStream(playlistUrl string) Stream {
+	return &HLSStream{
+		StreamSchema: &StreamSchema{
+			Url:  playlistUrl,
+			Kind: STREAM_TYPE_HLS,
+			Meta: NewStreamMeta(),
+		},
+	}
+}
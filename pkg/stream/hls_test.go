@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"testing"
+)
+
+func TestNewStreamHLS(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		expectKind string
+	}{
+		{
+			name:       "playlist url",
+			url:        "https://example.com/live/index.m3u8",
+			expectKind: STREAM_TYPE_HLS,
+		},
+		{
+			name:       "playlist url with a query string",
+			url:        "https://example.com/live/INDEX.M3U8?token=abc",
+			expectKind: STREAM_TYPE_HLS,
+		},
+		{
+			name:       "video url",
+			url:        "https://example.com/videos/movie.mp4",
+			expectKind: STREAM_TYPE_REMOTE,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := NewHandler().NewStream(tc.url)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.GetKind() != tc.expectKind {
+				t.Errorf("expected stream kind %q, got %q", tc.expectKind, s.GetKind())
+			}
+			if s.GetStreamURL() != tc.url {
+				t.Errorf("expected stream url %q, got %q", tc.url, s.GetStreamURL())
+			}
+		})
+	}
+}
+
+func TestHLSPlaylistDuration(t *testing.T) {
+	tests := []struct {
+		name           string
+		playlist       string
+		expectDuration float64
+		expectComplete bool
+	}{
+		{
+			name: "vod playlist",
+			playlist: `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:10
+#EXT-X-PLAYLIST-TYPE:VOD
+#EXTINF:10.0,
+segment0.ts
+#EXTINF:10.0,
+segment1.ts
+#EXTINF:4.5,intro
+segment2.ts
+#EXT-X-ENDLIST
+`,
+			expectDuration: 24.5,
+			expectComplete: true,
+		},
+		{
+			name: "ended playlist",
+			playlist: `#EXTM3U
+#EXTINF:6,
+segment0.ts
+#EXTINF:6,
+segment1.ts
+#EXT-X-ENDLIST
+`,
+			expectDuration: 12,
+			expectComplete: true,
+		},
+		{
+			name: "live playlist",
+			playlist: `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-MEDIA-SEQUENCE:120
+#EXTINF:6,
+segment120.ts
+#EXTINF:6,
+segment121.ts
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			duration, complete := HLSPlaylistDuration([]byte(tc.playlist))
+			if complete != tc.expectComplete {
+				t.Fatalf("expected a complete playlist: %v, got %v", tc.expectComplete, complete)
+			}
+			if duration != tc.expectDuration {
+				t.Errorf("expected duration %v, got %v", tc.expectDuration, duration)
+			}
+		})
+	}
+}
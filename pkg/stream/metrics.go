@@ -0,0 +1,12 @@
+package stream
+
+import "github.com/juanvallejo/streaming-server/pkg/metrics"
+
+var (
+	metadataFetchSeconds = metrics.NewHistogram("streaming_metadata_fetch_seconds", "Time taken to fetch stream metadata that was not cached.")
+	metadataFetchErrors  = metrics.NewCounter("streaming_metadata_fetch_errors_total", "Number of stream metadata fetches that failed.")
+)
+
+func init() {
+	metrics.MustRegister(metadataFetchSeconds, metadataFetchErrors)
+}
@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	apiconfig "github.com/juanvallejo/streaming-server/pkg/api/config"
+)
+
+const (
+	DefaultMaxPlaylistExpansion   = 20               // default max number of streams a single playlist expands into
+	DefaultPlaylistRequestTimeout = 10 * time.Second // time allowed for each playlist page request
+)
+
+var (
+	// MaxPlaylistExpansion is the max number of streams a single playlist
+	// expands into. Expansion is further limited by the space left in the
+	// queue of the user adding the playlist.
+	MaxPlaylistExpansion = DefaultMaxPlaylistExpansion
+
+	playlistClient = &http.Client{Timeout: DefaultPlaylistRequestTimeout}
+)
+
+type YouTubePlaylistItemsResponse struct {
+	NextPageToken string `json:"nextPageToken"`
+	PageInfo      struct {
+		TotalResults int `json:"totalResults"`
+	} `json:"pageInfo"`
+	Items []struct {
+		ContentDetails struct {
+			VideoId string `json:"videoId"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+// IsPlaylistUrl returns true if the given url
+// refers to a supported playlist of streams.
+func IsPlaylistUrl(streamUrl string) bool {
+	_, ok := ytPlaylistIdFromUrl(streamUrl)
+	return ok
+}
+
+// ExpandPlaylist receives a playlist url and returns the urls of up
+// to limit streams in the playlist, in playlist order, along with the
+// total number of streams in the playlist. This method blocks until
+// all playlist entries have been fetched.
+func (h *Handler) ExpandPlaylist(playlistUrl string, limit int) ([]string, int, error) {
+	playlistId, ok := ytPlaylistIdFromUrl(playlistUrl)
+	if !ok {
+		return nil, 0, fmt.Errorf("error: %q is not a supported playlist url", playlistUrl)
+	}
+
+	urls := []string{}
+	total := 0
+	pageToken := ""
+	for len(urls) < limit {
+		pageSize := limit - len(urls)
+		if pageSize > 50 {
+			pageSize = 50
+		}
+
+		reqUrl := fmt.Sprintf("https://www.googleapis.com/youtube/v3/playlistItems?playlistId=%s&key=%s&part=contentDetails&maxResults=%d", url.QueryEscape(playlistId), apiconfig.YT_API_KEY, pageSize)
+		if len(pageToken) > 0 {
+			reqUrl += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+
+		res, err := playlistClient.Get(reqUrl)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		data, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		page := YouTubePlaylistItemsResponse{}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, 0, err
+		}
+
+		total = page.PageInfo.TotalResults
+		for _, item := range page.Items {
+			if len(urls) >= limit {
+				break
+			}
+			if len(item.ContentDetails.VideoId) == 0 {
+				continue
+			}
+
+			urls = append(urls, "https://www.youtube.com/watch?v="+item.ContentDetails.VideoId)
+		}
+
+		pageToken = page.NextPageToken
+		if len(pageToken) == 0 || len(page.Items) == 0 {
+			break
+		}
+	}
+
+	if len(urls) == 0 {
+		return nil, total, fmt.Errorf("error: no videos found in playlist %q", playlistId)
+	}
+
+	return urls, total, nil
+}
+
+// ytPlaylistIdFromUrl returns the value of a youtube url's
+// "list" query parameter, or false if no playlist id exists.
+func ytPlaylistIdFromUrl(playlistUrl string) (string, bool) {
+	u, err := url.Parse(playlistUrl)
+	if err != nil {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(u.Host, "www.")
+	if host != "youtube.com" && host != "m.youtube.com" && host != "youtu.be" {
+		return "", false
+	}
+
+	id := u.Query().Get("list")
+	return id, len(id) > 0
+}
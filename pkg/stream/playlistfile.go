@@ -0,0 +1,212 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	PLAYLIST_FORMAT_JSON = "json"
+	PLAYLIST_FORMAT_M3U  = "m3u"
+	PLAYLIST_FORMAT_PLS  = "pls"
+)
+
+// PlaylistFormatFromContentType returns the playlist format described
+// by the given media type, or an empty string if it describes none.
+func PlaylistFormatFromContentType(contentType string) string {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case "application/json":
+		return PLAYLIST_FORMAT_JSON
+	case "audio/x-mpegurl", "audio/mpegurl", "application/x-mpegurl", "application/vnd.apple.mpegurl":
+		return PLAYLIST_FORMAT_M3U
+	case "audio/x-scpls", "application/pls+xml":
+		return PLAYLIST_FORMAT_PLS
+	}
+	return ""
+}
+
+// ParsePlaylistFile reads a playlist in the given format (json, m3u, or pls)
+// and returns the stream urls it lists, in order. If no format is given, it
+// is detected from the playlist's contents. Malformed entries are skipped,
+// and described by the returned list of entry errors. An error is returned
+// only if the playlist cannot be read at all.
+func ParsePlaylistFile(r io.Reader, format string) ([]string, []error, error) {
+	reader := bufio.NewReader(r)
+	if len(format) == 0 {
+		format = sniffPlaylistFormat(reader)
+	}
+
+	switch strings.ToLower(format) {
+	case PLAYLIST_FORMAT_JSON:
+		return parseJSONPlaylist(reader)
+	case PLAYLIST_FORMAT_M3U, "m3u8":
+		return parseM3UPlaylist(reader)
+	case PLAYLIST_FORMAT_PLS:
+		return parsePLSPlaylist(reader)
+	}
+
+	return nil, nil, fmt.Errorf("unsupported playlist format %q; expecting one of %q, %q, or %q", format, PLAYLIST_FORMAT_JSON, PLAYLIST_FORMAT_M3U, PLAYLIST_FORMAT_PLS)
+}
+
+// sniffPlaylistFormat guesses a playlist's format from its first bytes.
+// Playlists that are neither JSON nor PLS are read as plain M3U url lists.
+func sniffPlaylistFormat(r *bufio.Reader) string {
+	head, _ := r.Peek(512)
+	head = bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(head, []byte("[")) && !bytes.HasPrefix(bytes.ToLower(head), []byte("[playlist]")):
+		return PLAYLIST_FORMAT_JSON
+	case bytes.HasPrefix(head, []byte("{")):
+		return PLAYLIST_FORMAT_JSON
+	case bytes.HasPrefix(bytes.ToLower(head), []byte("[playlist]")):
+		return PLAYLIST_FORMAT_PLS
+	}
+	return PLAYLIST_FORMAT_M3U
+}
+
+// parseJSONPlaylist reads a JSON list whose items are either
+// urls, or objects with a "url" field. A list may also be given
+// as the "urls" or "items" field of a top-level object.
+func parseJSONPlaylist(r io.Reader) ([]string, []error, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("malformed JSON playlist: %v", err)
+	}
+
+	items := []json.RawMessage{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		wrapper := struct {
+			Urls  []json.RawMessage `json:"urls"`
+			Items []json.RawMessage `json:"items"`
+		}{}
+		if err := json.Unmarshal(raw, &wrapper); err != nil {
+			return nil, nil, fmt.Errorf("malformed JSON playlist: expecting a list of urls")
+		}
+		items = append(wrapper.Urls, wrapper.Items...)
+	}
+
+	urls := []string{}
+	skipped := []error{}
+	for i, item := range items {
+		var entry string
+		if err := json.Unmarshal(item, &entry); err != nil {
+			obj := struct {
+				Url string `json:"url"`
+			}{}
+			if err := json.Unmarshal(item, &obj); err != nil {
+				skipped = append(skipped, fmt.Errorf("item %d: expecting a url or an object with a \"url\" field", i+1))
+				continue
+			}
+			entry = obj.Url
+		}
+
+		if err := validatePlaylistEntry(entry); err != nil {
+			skipped = append(skipped, fmt.Errorf("item %d: %v", i+1, err))
+			continue
+		}
+		urls = append(urls, strings.TrimSpace(entry))
+	}
+
+	return urls, skipped, nil
+}
+
+// parseM3UPlaylist reads an M3U (or extended M3U) playlist,
+// ignoring blank lines, comments, and extended directives.
+func parseM3UPlaylist(r io.Reader) ([]string, []error, error) {
+	urls := []string{}
+	skipped := []error{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\xef\xbb\xbf"))
+		if len(entry) == 0 || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		if err := validatePlaylistEntry(entry); err != nil {
+			skipped = append(skipped, fmt.Errorf("line %d: %v", line, err))
+			continue
+		}
+		urls = append(urls, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("unable to read M3U playlist: %v", err)
+	}
+
+	return urls, skipped, nil
+}
+
+// parsePLSPlaylist reads a PLS playlist, returning the
+// urls of its FileN entries ordered by their number.
+func parsePLSPlaylist(r io.Reader) ([]string, []error, error) {
+	type plsEntry struct {
+		num int
+		url string
+	}
+
+	entries := []plsEntry{}
+	skipped := []error{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\xef\xbb\xbf"))
+		if len(text) < 4 || !strings.EqualFold(text[:4], "file") {
+			continue
+		}
+
+		segs := strings.SplitN(text[4:], "=", 2)
+		if len(segs) != 2 {
+			skipped = append(skipped, fmt.Errorf("line %d: malformed entry %q", line, text))
+			continue
+		}
+
+		num, err := strconv.Atoi(strings.TrimSpace(segs[0]))
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("line %d: malformed entry number %q", line, segs[0]))
+			continue
+		}
+
+		entry := strings.TrimSpace(segs[1])
+		if err := validatePlaylistEntry(entry); err != nil {
+			skipped = append(skipped, fmt.Errorf("line %d: %v", line, err))
+			continue
+		}
+		entries = append(entries, plsEntry{num: num, url: entry})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("unable to read PLS playlist: %v", err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].num < entries[j].num
+	})
+
+	urls := make([]string, 0, len(entries))
+	for _, e := range entries {
+		urls = append(urls, e.url)
+	}
+	return urls, skipped, nil
+}
+
+// validatePlaylistEntry returns an error if the given
+// playlist entry is not an absolute http(s) url
+func validatePlaylistEntry(entry string) error {
+	entry = strings.TrimSpace(entry)
+	if len(entry) == 0 {
+		return fmt.Errorf("empty entry")
+	}
+
+	u, err := url.Parse(entry)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("%q is not an http(s) url", entry)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePlaylistFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		playlist      string
+		expectUrls    []string
+		expectSkipped []string
+		expectErr     string
+	}{
+		{
+			name:       "json list of urls",
+			format:     PLAYLIST_FORMAT_JSON,
+			playlist:   `["http://example.com/a.mp4", "https://example.com/b.mp4"]`,
+			expectUrls: []string{"http://example.com/a.mp4", "https://example.com/b.mp4"},
+		},
+		{
+			name:       "json object listing items with a url field",
+			format:     PLAYLIST_FORMAT_JSON,
+			playlist:   `{"items": [{"url": "http://example.com/a.mp4"}, {"url": " http://example.com/b.mp4 "}]}`,
+			expectUrls: []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+		},
+		{
+			name:          "json list with malformed items",
+			format:        PLAYLIST_FORMAT_JSON,
+			playlist:      `["http://example.com/a.mp4", 42, "ftp://example.com/b.mp4", "http://example.com/c.mp4"]`,
+			expectUrls:    []string{"http://example.com/a.mp4", "http://example.com/c.mp4"},
+			expectSkipped: []string{"item 2:", "item 3:"},
+		},
+		{
+			name:      "malformed json",
+			format:    PLAYLIST_FORMAT_JSON,
+			playlist:  `["http://example.com/a.mp4"`,
+			expectErr: "malformed JSON playlist",
+		},
+		{
+			name:   "extended m3u",
+			format: PLAYLIST_FORMAT_M3U,
+			playlist: `#EXTM3U
+#EXTINF:60,A
+http://example.com/a.mp4
+
+#EXTINF:120,B
+http://example.com/b.mp4
+`,
+			expectUrls: []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+		},
+		{
+			name:   "m3u with malformed lines",
+			format: PLAYLIST_FORMAT_M3U,
+			playlist: `http://example.com/a.mp4
+not a url
+/local/b.mp4
+http://example.com/c.mp4`,
+			expectUrls:    []string{"http://example.com/a.mp4", "http://example.com/c.mp4"},
+			expectSkipped: []string{"line 2:", "line 3:"},
+		},
+		{
+			name:   "pls entries are ordered by number",
+			format: PLAYLIST_FORMAT_PLS,
+			playlist: `[playlist]
+File2=http://example.com/b.mp4
+Title2=B
+File1=http://example.com/a.mp4
+Title1=A
+NumberOfEntries=2
+Version=2`,
+			expectUrls: []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+		},
+		{
+			name:   "pls with malformed entries",
+			format: PLAYLIST_FORMAT_PLS,
+			playlist: `[playlist]
+File1=http://example.com/a.mp4
+FileX=http://example.com/b.mp4
+File3
+File4=b.mp4`,
+			expectUrls:    []string{"http://example.com/a.mp4"},
+			expectSkipped: []string{"line 3:", "line 4:", "line 5:"},
+		},
+		{
+			name:       "json format is detected",
+			playlist:   `["http://example.com/a.mp4"]`,
+			expectUrls: []string{"http://example.com/a.mp4"},
+		},
+		{
+			name:       "pls format is detected",
+			playlist:   "[playlist]\nFile1=http://example.com/a.mp4",
+			expectUrls: []string{"http://example.com/a.mp4"},
+		},
+		{
+			name:       "m3u format is detected",
+			playlist:   "\xef\xbb\xbfhttp://example.com/a.mp4\nhttp://example.com/b.mp4",
+			expectUrls: []string{"http://example.com/a.mp4", "http://example.com/b.mp4"},
+		},
+		{
+			name:      "unsupported format",
+			format:    "xspf",
+			playlist:  "<playlist></playlist>",
+			expectErr: "unsupported playlist format",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			urls, skipped, err := ParsePlaylistFile(strings.NewReader(tc.playlist), tc.format)
+			if len(tc.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Join(urls, ",") != strings.Join(tc.expectUrls, ",") {
+				t.Errorf("expected urls %v, got %v", tc.expectUrls, urls)
+			}
+			if len(skipped) != len(tc.expectSkipped) {
+				t.Fatalf("expected %v skipped entries, got %v", len(tc.expectSkipped), skipped)
+			}
+			for i, expected := range tc.expectSkipped {
+				if !strings.HasPrefix(skipped[i].Error(), expected) {
+					t.Errorf("expected skipped entry %v to be reported as %q, got %q", i+1, expected, skipped[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylistFormatFromContentType(t *testing.T) {
+	tests := []struct {
+		contentType  string
+		expectFormat string
+	}{
+		{contentType: "application/json; charset=utf-8", expectFormat: PLAYLIST_FORMAT_JSON},
+		{contentType: "audio/x-mpegurl", expectFormat: PLAYLIST_FORMAT_M3U},
+		{contentType: "Application/vnd.apple.mpegurl", expectFormat: PLAYLIST_FORMAT_M3U},
+		{contentType: "audio/x-scpls", expectFormat: PLAYLIST_FORMAT_PLS},
+		{contentType: "text/plain"},
+		{contentType: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.contentType, func(t *testing.T) {
+			if format := PlaylistFormatFromContentType(tc.contentType); format != tc.expectFormat {
+				t.Errorf("expected format %q, got %q", tc.expectFormat, format)
+			}
+		})
+	}
+}
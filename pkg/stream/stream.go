@@ -2,6 +2,7 @@ package stream
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -24,7 +25,22 @@ const (
 	STREAM_TYPE_REMOTE      = "movie"
 	STREAM_TYPE_TWITCH      = "twitch"
 	STREAM_TYPE_TWITCH_CLIP = "twitch#clip"
+	STREAM_TYPE_TWITCH_LIVE = "twitch#live"
 	STREAM_TYPE_SOUNDCLOUD  = "soundcloud"
+	STREAM_TYPE_HLS         = "hls"
+
+	STREAM_FETCH_STATUS_PENDING = "pending"
+	STREAM_FETCH_STATUS_FETCHED = "fetched"
+	STREAM_FETCH_STATUS_FAILED  = "failed"
+	// STREAM_FETCH_STATUS_UNAVAILABLE indicates that the stream's provider
+	// reported the stream as private, deleted, or otherwise unplayable
+	STREAM_FETCH_STATUS_UNAVAILABLE = "unavailable"
+)
+
+var (
+	// ErrStreamUnavailable is returned by FetchMetadata when a stream's
+	// provider reports that the stream cannot be played
+	ErrStreamUnavailable = errors.New("the stream is private, deleted, or otherwise unavailable")
 )
 
 type StreamMetadataCallback func(Stream, []byte, error)
@@ -93,6 +109,10 @@ type StreamMeta interface {
 	// GetLabelledRef returns the ref stored under the given key and a boolean true,
 	// or a boolean false if the given key does not exist.
 	GetLabelledRef(string) (StreamRef, bool)
+	// SetFetchStatus receives the status of the Stream's metadata fetch
+	SetFetchStatus(string)
+	// GetFetchStatus returns the status of the Stream's metadata fetch
+	GetFetchStatus() string
 }
 
 // StreamMetaSchema implements StreamMeta
@@ -106,6 +126,8 @@ type StreamMetaSchema struct {
 	// LabelledRefs store an object reference to the
 	// Stream object under a given string label key.
 	LabelledRefs map[string]StreamRef
+	// FetchStatus describes the state of the stream's metadata fetch
+	FetchStatus string `json:"fetchStatus"`
 }
 
 func (s *StreamMetaSchema) GetCreationSource() StreamCreationSource {
@@ -124,6 +146,14 @@ func (s *StreamMetaSchema) GetLastUpdated() time.Time {
 	return s.LastUpdated
 }
 
+func (s *StreamMetaSchema) SetFetchStatus(status string) {
+	s.FetchStatus = status
+}
+
+func (s *StreamMetaSchema) GetFetchStatus() string {
+	return s.FetchStatus
+}
+
 func (s *StreamMetaSchema) GetParentRefs() []StreamRef {
 	refs := []StreamRef{}
 	for _, r := range s.ParentRefs {
@@ -181,6 +211,7 @@ func NewStreamMeta() StreamMeta {
 		LastUpdated:    time.Now(),
 		ParentRefs:     make(map[string]StreamRef),
 		LabelledRefs:   make(map[string]StreamRef),
+		FetchStatus:    STREAM_FETCH_STATUS_PENDING,
 	}
 }
 
@@ -201,6 +232,11 @@ type Stream interface {
 	GetKind() string
 	// GetDuration returns the stream's saved duration
 	GetDuration() float64
+	// GetThumbnail returns a url pointing to a still of the stream
+	GetThumbnail() string
+	// GetSubtitles returns the subtitle tracks available for the
+	// stream, once fetched. Returns an empty list if it has none.
+	GetSubtitles() []SubtitleTrack
 	// Codec returns a serializable representation of the
 	// current stream
 	Codec() api.ApiCodec
@@ -227,6 +263,8 @@ type StreamSchema struct {
 	Duration float64 `json:"duration"`
 	// Thumbnail is a url pointing to a still of the stream
 	Thumbnail string `json:"thumb"`
+	// Subtitles are the subtitle tracks available for the stream
+	Subtitles []SubtitleTrack `json:"subtitles,omitempty"`
 	// Metadata stores Stream abject meta information
 	Meta StreamMeta `json:"metadata"`
 }
@@ -251,6 +289,10 @@ func (s *StreamSchema) GetDuration() float64 {
 	return s.Duration
 }
 
+func (s *StreamSchema) GetThumbnail() string {
+	return s.Thumbnail
+}
+
 func (s *StreamSchema) Metadata() StreamMeta {
 	return s.Meta
 }
@@ -292,10 +334,31 @@ type YouTubeVideoListResponse struct {
 type YouTubeVideoItem struct {
 	ContentDetails map[string]interface{} `json:"contentDetails"`
 	Snippet        struct {
-		Title string `json:"title"`
+		Title      string                           `json:"title"`
+		Thumbnails map[string]YouTubeVideoThumbnail `json:"thumbnails"`
 	} `json:"snippet"`
 }
 
+type YouTubeVideoThumbnail struct {
+	Url string `json:"url"`
+}
+
+// youTubeThumbnailSizes lists the thumbnail sizes
+// returned by the YouTube api, largest first.
+var youTubeThumbnailSizes = []string{"maxres", "standard", "high", "medium", "default"}
+
+// Thumbnail returns the url of the largest thumbnail available for the
+// video, or an empty string if the api did not return any thumbnails.
+func (yt *YouTubeVideoItem) Thumbnail() string {
+	for _, size := range youTubeThumbnailSizes {
+		if thumb, exists := yt.Snippet.Thumbnails[size]; exists && len(thumb.Url) > 0 {
+			return thumb.Url
+		}
+	}
+
+	return ""
+}
+
 // ParseDuration retrieves a YouTubeVideoItem "duration" field value and
 // replaces it with a seconds-parsed int64 value.
 func (yt *YouTubeVideoItem) ParseDuration() error {
@@ -354,8 +417,9 @@ func (s *YouTubeStream) FetchMetadata(callback StreamMetadataCallback) {
 			return
 		}
 
+		// private and deleted videos are omitted from api results
 		if len(dataItems.Items) == 0 {
-			callback(s, nil, fmt.Errorf("no contentData found for video id %q", videoId))
+			callback(s, nil, ErrStreamUnavailable)
 			return
 		}
 
@@ -367,8 +431,19 @@ func (s *YouTubeStream) FetchMetadata(callback StreamMetadataCallback) {
 			return
 		}
 
-		// append title
+		// append title, and thumbnail if the api provided one
 		videoData.ContentDetails["name"] = videoData.Snippet.Title
+		if thumb := videoData.Thumbnail(); len(thumb) > 0 {
+			videoData.ContentDetails["thumb"] = thumb
+		}
+
+		// list the video's caption tracks, if the api reported any; videos
+		// are still played without captions if they cannot be listed
+		if caption, _ := videoData.ContentDetails["caption"].(string); caption == "true" {
+			if tracks, err := fetchYouTubeCaptionTracks(videoId, apiKey); err == nil && len(tracks) > 0 {
+				videoData.ContentDetails["subtitles"] = tracks
+			}
+		}
 		jsonData, err := json.Marshal(videoData.ContentDetails)
 		if err != nil {
 			callback(s, nil, err)
@@ -413,7 +488,14 @@ type LocalVideoStream struct {
 
 func (s *LocalVideoStream) FetchMetadata(callback StreamMetadataCallback) {
 	go func(s *LocalVideoStream, callback StreamMetadataCallback) {
-		data, err := FetchVideoMetadata(pathutil.StreamDataFilePathFromUrl(s.Url))
+		fpath := pathutil.StreamDataFilePathFromUrl(s.Url)
+		data, err := FetchVideoMetadata(fpath)
+		if err != nil {
+			callback(s, []byte{}, err)
+			return
+		}
+
+		data, err = withSubtitleTracks(data, LocalSubtitleTracks(fpath))
 		if err != nil {
 			callback(s, []byte{}, err)
 			return
@@ -683,6 +765,115 @@ func NewTwitchClipStream(videoUrl string) Stream {
 	}
 }
 
+// TwitchLiveStream implements Stream
+// and represents a live twitch.tv channel stream.
+// Live streams have no fixed duration.
+type TwitchLiveStream struct {
+	*StreamSchema
+
+	apiKey string
+}
+
+// TwitchLiveResponseItem contains twitch api response data
+// for a channel's live stream. Stream is nil if the
+// channel is offline.
+type TwitchLiveResponseItem struct {
+	Stream *TwitchLiveResponseStream `json:"stream"`
+}
+
+type TwitchLiveResponseStream struct {
+	Preview TwitchLiveResponsePreview `json:"preview"`
+	Channel TwitchLiveResponseChannel `json:"channel"`
+}
+
+type TwitchLiveResponsePreview struct {
+	Url string `json:"medium"`
+}
+
+type TwitchLiveResponseChannel struct {
+	Status      string `json:"status"`
+	DisplayName string `json:"display_name"`
+}
+
+type TwitchLiveItem map[string]interface{}
+
+func (s *TwitchLiveStream) FetchMetadata(callback StreamMetadataCallback) {
+	channel, err := twitchChannelFromUrl(s.Url)
+	if err != nil {
+		callback(s, []byte{}, err)
+		return
+	}
+
+	go func(channel, apiKey string, callback StreamMetadataCallback) {
+		client := &http.Client{}
+
+		req, err := http.NewRequest("GET", "https://api.twitch.tv/kraken/streams/"+channel, nil)
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+
+		req.Header.Set("Client-ID", apiKey)
+
+		res, err := client.Do(req)
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+
+		defer res.Body.Close()
+
+		data, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+
+		responseItem := &TwitchLiveResponseItem{}
+		err = json.Unmarshal(data, responseItem)
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+		if responseItem.Stream == nil {
+			callback(s, nil, fmt.Errorf("twitch channel %q is not currently live", channel))
+			return
+		}
+
+		name := responseItem.Stream.Channel.Status
+		if len(name) == 0 {
+			name = responseItem.Stream.Channel.DisplayName
+		}
+
+		// craft callback metadata response with default fields.
+		// live streams have no fixed duration.
+		twitchLiveItem := TwitchLiveItem{}
+		twitchLiveItem["name"] = name
+		twitchLiveItem["duration"] = float64(0)
+		twitchLiveItem["thumb"] = responseItem.Stream.Preview.Url
+
+		jsonData, err := json.Marshal(twitchLiveItem)
+		if err != nil {
+			callback(s, nil, err)
+			return
+		}
+
+		callback(s, jsonData, nil)
+	}(channel, s.apiKey, callback)
+}
+
+func NewTwitchLiveStream(channelUrl string) Stream {
+	return &TwitchLiveStream{
+		StreamSchema: &StreamSchema{
+			Url:  channelUrl,
+			Kind: STREAM_TYPE_TWITCH_LIVE,
+			Meta: NewStreamMeta(),
+		},
+
+		apiKey: apiconfig.TWITCH_API_KEY,
+	}
+}
+
 // SoundCloudStream implements Stream
 // and represents a soundcloud video stream data and state
 type SoundCloudStream struct {
@@ -718,6 +909,11 @@ func (s *SoundCloudStream) FetchMetadata(callback StreamMetadataCallback) {
 
 		defer res.Body.Close()
 
+		if isUnavailableStatus(res.StatusCode) {
+			callback(s, nil, ErrStreamUnavailable)
+			return
+		}
+
 		data, err := ioutil.ReadAll(res.Body)
 		if err != nil {
 			callback(s, nil, err)
@@ -759,6 +955,10 @@ func NewSoundCloudStream(videoUrl string) Stream {
 	}
 }
 
+// ytVideoId matches a youtube video id, optionally
+// followed by query parameters (such as ?t=)
+var ytVideoId = regexp.MustCompile(`^[A-Za-z0-9_-]{11}([?&#]|$)`)
+
 func ytVideoIdFromUrl(videoUrl string) (string, error) {
 	segs := strings.Split(videoUrl, "/")
 	if len(segs) < 2 {
@@ -789,6 +989,56 @@ func twitchVideoIdFromUrl(videoUrl string) (string, error) {
 	return segs[1], nil
 }
 
+// IsLiveStream returns true if the given stream is a live
+// broadcast, and therefore has no fixed duration
+func IsLiveStream(s Stream) bool {
+	switch s.GetKind() {
+	case STREAM_TYPE_TWITCH_LIVE:
+		return true
+	case STREAM_TYPE_HLS:
+		// complete (VOD) playlists have a known duration
+		return s.GetDuration() == 0
+	}
+
+	return false
+}
+
+// IsUnplayable returns true if the given stream's provider
+// reported it as unavailable when its metadata was fetched
+func IsUnplayable(s Stream) bool {
+	return s.Metadata().GetFetchStatus() == STREAM_FETCH_STATUS_UNAVAILABLE
+}
+
+// isUnavailableStatus returns true if the given http status
+// code indicates that a requested stream does not exist, or
+// may not be accessed.
+func isUnavailableStatus(code int) bool {
+	return code == http.StatusForbidden || code == http.StatusNotFound || code == http.StatusGone
+}
+
+// IsTwitchVodUrl returns true if the given
+// twitch.tv url refers to a past broadcast
+func IsTwitchVodUrl(videoUrl string) bool {
+	_, err := twitchVideoIdFromUrl(videoUrl)
+	return err == nil
+}
+
+// twitchChannelFromUrl receives a twitch.tv channel url
+// (twitch.tv/<channel>) and returns the channel name
+func twitchChannelFromUrl(channelUrl string) (string, error) {
+	u, err := url.Parse(channelUrl)
+	if err != nil {
+		return "", err
+	}
+
+	channel := strings.Trim(u.Path, "/")
+	if len(channel) == 0 || strings.Contains(channel, "/") {
+		return "", fmt.Errorf("invalid twitch channel url")
+	}
+
+	return channel, nil
+}
+
 func twitchClipIdFromUrl(clipUrl string) (string, error) {
 	u, err := url.Parse(clipUrl)
 	if err != nil {
@@ -0,0 +1,176 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	pathutil "github.com/juanvallejo/streaming-server/pkg/server/path"
+)
+
+const DefaultCaptionRequestTimeout = 10 * time.Second // time allowed for each caption list request
+
+var captionClient = &http.Client{Timeout: DefaultCaptionRequestTimeout}
+
+// SubtitleTrack describes a subtitle or caption
+// track available for a stream
+type SubtitleTrack struct {
+	// Id uniquely identifies the track among the stream's tracks
+	Id string `json:"id"`
+	// Language is the track's BCP-47 language code, if known
+	Language string `json:"language,omitempty"`
+	// Label is a human-readable name for the track
+	Label string `json:"label"`
+	// Url locates the track's WebVTT file, if it is served
+	// separately from the stream. Tracks without a url are
+	// rendered by the stream's own player.
+	Url string `json:"url,omitempty"`
+}
+
+// SubtitleTrack returns the subtitle track with the
+// given id, or a boolean (false) if the stream has none
+func (s *StreamSchema) SubtitleTrack(id string) (SubtitleTrack, bool) {
+	for _, track := range s.Subtitles {
+		if track.Id == id {
+			return track, true
+		}
+	}
+	return SubtitleTrack{}, false
+}
+
+func (s *StreamSchema) GetSubtitles() []SubtitleTrack {
+	return s.Subtitles
+}
+
+type youTubeCaptionListResponse struct {
+	Items []struct {
+		Id      string `json:"id"`
+		Snippet struct {
+			Language  string `json:"language"`
+			Name      string `json:"name"`
+			TrackKind string `json:"trackKind"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
+
+// ParseYouTubeCaptionTracks receives a YouTube api caption list response
+// and returns the caption tracks it describes, sorted by language.
+func ParseYouTubeCaptionTracks(data []byte) ([]SubtitleTrack, error) {
+	captions := youTubeCaptionListResponse{}
+	if err := json.Unmarshal(data, &captions); err != nil {
+		return nil, err
+	}
+
+	tracks := []SubtitleTrack{}
+	for _, item := range captions.Items {
+		if len(item.Id) == 0 {
+			continue
+		}
+
+		label := item.Snippet.Name
+		if len(label) == 0 {
+			label = item.Snippet.Language
+		}
+		if strings.EqualFold(item.Snippet.TrackKind, "asr") {
+			label += " (auto-generated)"
+		}
+
+		tracks = append(tracks, SubtitleTrack{
+			Id:       item.Id,
+			Language: item.Snippet.Language,
+			Label:    strings.TrimSpace(label),
+		})
+	}
+
+	sort.SliceStable(tracks, func(i, j int) bool {
+		return tracks[i].Language < tracks[j].Language
+	})
+	return tracks, nil
+}
+
+// fetchYouTubeCaptionTracks requests the caption tracks of
+// the YouTube video with the given id from the YouTube api
+func fetchYouTubeCaptionTracks(videoId, apiKey string) ([]SubtitleTrack, error) {
+	res, err := captionClient.Get("https://www.googleapis.com/youtube/v3/captions?videoId=" + url.QueryEscape(videoId) + "&key=" + apiKey + "&part=snippet")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q listing caption tracks", res.Status)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseYouTubeCaptionTracks(data)
+}
+
+// LocalSubtitleTracks returns a subtitle track for each WebVTT file
+// stored alongside the local video file at the given path, sharing its
+// name. A file named "movie.vtt" describes a track in an unknown language,
+// and a file named "movie.en.vtt" describes an english track. File names
+// are matched literally, so they may contain glob metacharacters.
+func LocalSubtitleTracks(fpath string) []SubtitleTrack {
+	dir, name := filepath.Split(fpath)
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	files, err := ioutil.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return []SubtitleTrack{}
+	}
+
+	// files are listed sorted by name
+	tracks := []SubtitleTrack{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), base) || !strings.HasSuffix(file.Name(), ".vtt") {
+			continue
+		}
+
+		language := strings.TrimPrefix(strings.TrimSuffix(file.Name(), ".vtt"), base)
+		if len(language) > 0 && !strings.HasPrefix(language, ".") {
+			// belongs to another video sharing a prefix with this one
+			continue
+		}
+		language = strings.TrimPrefix(language, ".")
+
+		label := language
+		id := language
+		if len(language) == 0 {
+			label = "Default"
+			id = "default"
+		}
+
+		tracks = append(tracks, SubtitleTrack{
+			Id:       id,
+			Language: language,
+			Label:    label,
+			Url:      pathutil.StreamDataUrlFromFilename(file.Name()),
+		})
+	}
+	return tracks
+}
+
+// withSubtitleTracks adds the given subtitle tracks to the given
+// stream metadata. The metadata is returned as is if there are none.
+func withSubtitleTracks(data []byte, tracks []SubtitleTrack) ([]byte, error) {
+	if len(tracks) == 0 {
+		return data, nil
+	}
+
+	kv := map[string]interface{}{}
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, err
+	}
+
+	kv["subtitles"] = tracks
+	return json.Marshal(kv)
+}
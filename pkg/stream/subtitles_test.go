@@ -0,0 +1,109 @@
+package stream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pathutil "github.com/juanvallejo/streaming-server/pkg/server/path"
+)
+
+func TestParseYouTubeCaptionTracks(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expected  []SubtitleTrack
+		expectErr bool
+	}{
+		{
+			name:     "video without captions",
+			data:     `{"items": []}`,
+			expected: []SubtitleTrack{},
+		},
+		{
+			name: "tracks are sorted by language",
+			data: `{"items": [
+				{"id": "fr1", "snippet": {"language": "fr", "name": "Français", "trackKind": "standard"}},
+				{"id": "en1", "snippet": {"language": "en", "name": "", "trackKind": "ASR"}},
+				{"id": "", "snippet": {"language": "de", "name": "Deutsch"}}
+			]}`,
+			expected: []SubtitleTrack{
+				{Id: "en1", Language: "en", Label: "en (auto-generated)"},
+				{Id: "fr1", Language: "fr", Label: "Français"},
+			},
+		},
+		{
+			name:      "malformed response",
+			data:      `{"items": {}}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracks, err := ParseYouTubeCaptionTracks([]byte(tc.data))
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.expectErr, err)
+			}
+			if !tc.expectErr && !reflect.DeepEqual(tracks, tc.expected) {
+				t.Errorf("expected tracks %v, got %v", tc.expected, tracks)
+			}
+		})
+	}
+}
+
+func TestLocalSubtitleTracks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subtitles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{
+		"movie [1080p].mp4",
+		"movie [1080p].vtt",
+		"movie [1080p].en.vtt",
+		"movie [1080p] extras.vtt",
+		"movie 1.vtt",
+		"other.vtt",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte{}, 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		fpath    string
+		expected []SubtitleTrack
+	}{
+		{
+			name:  "file names containing glob metacharacters",
+			fpath: filepath.Join(dir, "movie [1080p].mp4"),
+			expected: []SubtitleTrack{
+				{Id: "en", Language: "en", Label: "en", Url: pathutil.StreamDataUrlFromFilename("movie [1080p].en.vtt")},
+				{Id: "default", Label: "Default", Url: pathutil.StreamDataUrlFromFilename("movie [1080p].vtt")},
+			},
+		},
+		{
+			name:     "video without subtitles",
+			fpath:    filepath.Join(dir, "movie.mp4"),
+			expected: []SubtitleTrack{},
+		},
+		{
+			name:     "missing directory",
+			fpath:    filepath.Join(dir, "missing", "movie.mp4"),
+			expected: []SubtitleTrack{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tracks := LocalSubtitleTracks(tc.fpath); !reflect.DeepEqual(tracks, tc.expected) {
+				t.Errorf("expected tracks %v, got %v", tc.expected, tracks)
+			}
+		})
+	}
+}
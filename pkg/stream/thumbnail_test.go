@@ -0,0 +1,48 @@
+package stream
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestYouTubeVideoItemThumbnail(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name: "largest thumbnail is used",
+			data: `{"snippet": {"thumbnails": {
+				"default": {"url": "http://example.com/default.jpg"},
+				"high": {"url": "http://example.com/high.jpg"},
+				"medium": {"url": "http://example.com/medium.jpg"}
+			}}}`,
+			expected: "http://example.com/high.jpg",
+		},
+		{
+			name: "thumbnails without a url are skipped",
+			data: `{"snippet": {"thumbnails": {
+				"maxres": {"url": ""},
+				"default": {"url": "http://example.com/default.jpg"}
+			}}}`,
+			expected: "http://example.com/default.jpg",
+		},
+		{
+			name: "no thumbnails",
+			data: `{"snippet": {"title": "video"}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			item := YouTubeVideoItem{}
+			if err := json.Unmarshal([]byte(tc.data), &item); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if thumb := item.Thumbnail(); thumb != tc.expected {
+				t.Errorf("expected thumbnail %q, got %q", tc.expected, thumb)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+
+	api "github.com/juanvallejo/streaming-server/pkg/api/types"
+)
+
+// TrimmedStream is a Stream whose playback is
+// restricted to a segment of the stream it wraps.
+type TrimmedStream struct {
+	Stream
+
+	start int
+	end   int
+}
+
+// TrimmedStreamCodec is a serializable representation of a
+// TrimmedStream. Implements pkg/api/types.ApiCodec.
+type TrimmedStreamCodec struct {
+	codec api.ApiCodec
+	start int
+	end   int
+}
+
+func (c *TrimmedStreamCodec) Serialize() ([]byte, error) {
+	b, err := c.codec.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	m["duration"] = c.end
+	m["trim"] = map[string]int{
+		"start": c.start,
+		"end":   c.end,
+	}
+
+	return json.Marshal(m)
+}
+
+// UUID returns a unique id composed of the wrapped
+// stream's id and the bounds of the trimmed segment.
+func (s *TrimmedStream) UUID() string {
+	return fmt.Sprintf("%s#t=%d,%d", s.Stream.UUID(), s.start, s.end)
+}
+
+// GetDuration returns the effective duration of the
+// stream - the point at which playback of the segment ends.
+func (s *TrimmedStream) GetDuration() float64 {
+	return float64(s.end)
+}
+
+// GetStart returns the offset, in seconds, at
+// which playback of the segment begins.
+func (s *TrimmedStream) GetStart() int {
+	return s.start
+}
+
+// GetEnd returns the offset, in seconds, at
+// which playback of the segment ends.
+func (s *TrimmedStream) GetEnd() int {
+	return s.end
+}
+
+func (s *TrimmedStream) Codec() api.ApiCodec {
+	return &TrimmedStreamCodec{
+		codec: s.Stream.Codec(),
+		start: s.start,
+		end:   s.end,
+	}
+}
+
+// Validate returns an error if the trimmed segment does not
+// fall within the wrapped stream's duration. Bounds are only
+// checked against the duration once it has been fetched.
+func (s *TrimmedStream) Validate() error {
+	if s.start < 0 {
+		return fmt.Errorf("error: the start of a trimmed stream must be a positive number")
+	}
+	if s.start >= s.end {
+		return fmt.Errorf("error: the start of a trimmed stream must come before its end")
+	}
+	if duration := s.Stream.GetDuration(); duration > 0 && float64(s.end) > duration {
+		return fmt.Errorf("error: the end of a trimmed stream (%ds) must not exceed the stream's duration (%vs)", s.end, duration)
+	}
+
+	return nil
+}
+
+// NewTrimmedStream receives a stream and the bounds of a segment
+// within it, and returns a stream restricted to that segment.
+// Returns an error if the segment bounds are invalid.
+func NewTrimmedStream(s Stream, start, end int) (*TrimmedStream, error) {
+	trimmed := &TrimmedStream{
+		Stream: s,
+		start:  start,
+		end:    end,
+	}
+
+	if err := trimmed.Validate(); err != nil {
+		return nil, err
+	}
+
+	return trimmed, nil
+}
@@ -3,21 +3,68 @@ package validation
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MinUsernameLength = 1
+	MaxUsernameLength = 24
 )
 
 var ClientValidationPattern string = "^[a-zA-Z_0-9]+$"
 var ClientValidation *regexp.Regexp
 
+// ReservedUsernames is the set of lowercase usernames
+// that may not be claimed by any client.
+var ReservedUsernames = map[string]bool{
+	"system": true,
+}
+
+// ReserveUsernames adds the given names to the set of reserved usernames
+func ReserveUsernames(names ...string) {
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) > 0 {
+			ReservedUsernames[name] = true
+		}
+	}
+}
+
+// IsReservedUsername returns true if the given username
+// is reserved, regardless of its case.
+func IsReservedUsername(name string) bool {
+	return ReservedUsernames[strings.ToLower(name)]
+}
+
 // ValidateClientUsername receives a username and returns an error if it does not comply
-// with ClientValidationPattern
+// with ClientValidationPattern, is not between MinUsernameLength and MaxUsernameLength
+// characters long, or is reserved.
 func ValidateClientUsername(name string) error {
+	if length := utf8.RuneCountInString(name); length < MinUsernameLength || length > MaxUsernameLength {
+		return fmt.Errorf("error: usernames must be between %v and %v characters long", MinUsernameLength, MaxUsernameLength)
+	}
 	if !ClientValidation.MatchString(name) {
-		return fmt.Errorf("error: username %q is invalid", name)
+		return fmt.Errorf("error: username %q is invalid; usernames may only contain letters, numbers, and underscores", name)
+	}
+	if IsReservedUsername(name) {
+		return fmt.Errorf("error: the username %q is reserved", name)
 	}
 
 	return nil
 }
 
+// NormalizeClientUsername trims surrounding whitespace from a
+// username and validates the result. Returns the trimmed username.
+func NormalizeClientUsername(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if err := ValidateClientUsername(name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
 func init() {
 	var err error
 	ClientValidation, err = regexp.Compile(ClientValidationPattern)
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeClientUsername(t *testing.T) {
+	ReserveUsernames(" Admin ")
+	defer delete(ReservedUsernames, "admin")
+
+	tests := []struct {
+		name           string
+		username       string
+		expectErr      string
+		expectUsername string
+	}{
+		{
+			name:           "valid username",
+			username:       "some_user42",
+			expectUsername: "some_user42",
+		},
+		{
+			name:           "surrounding whitespace is trimmed",
+			username:       "  some_user \t",
+			expectUsername: "some_user",
+		},
+		{
+			name:           "username at the maximum length",
+			username:       strings.Repeat("a", MaxUsernameLength),
+			expectUsername: strings.Repeat("a", MaxUsernameLength),
+		},
+		{
+			name:      "over-long username",
+			username:  strings.Repeat("a", MaxUsernameLength+1),
+			expectErr: "must be between",
+		},
+		{
+			name:      "empty username",
+			username:  "",
+			expectErr: "must be between",
+		},
+		{
+			name:      "whitespace-only username",
+			username:  "   ",
+			expectErr: "must be between",
+		},
+		{
+			name:      "reserved username",
+			username:  "system",
+			expectErr: "is reserved",
+		},
+		{
+			name:      "reserved username in a different case",
+			username:  "SyStem",
+			expectErr: "is reserved",
+		},
+		{
+			name:      "configured reserved username",
+			username:  "admin",
+			expectErr: "is reserved",
+		},
+		{
+			name:      "inner whitespace",
+			username:  "some user",
+			expectErr: "may only contain",
+		},
+		{
+			name:      "markup",
+			username:  "<b>user</b>",
+			expectErr: "may only contain",
+		},
+		{
+			name:      "control characters",
+			username:  "user\u0000\u001b",
+			expectErr: "may only contain",
+		},
+		{
+			name:      "non-ascii letters",
+			username:  "usér",
+			expectErr: "may only contain",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			username, err := NormalizeClientUsername(tc.username)
+			if len(tc.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if username != tc.expectUsername {
+				t.Errorf("expected username %q, got %q", tc.expectUsername, username)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/juanvallejo/streaming-server/pkg/logging"
+)
+
+const (
+	EVENT_STREAM_CHANGED     = "stream.changed"
+	EVENT_ROOM_CREATED       = "room.created"
+	EVENT_ROOM_REAPED        = "room.reaped"
+	EVENT_USER_JOINED        = "user.joined"
+	EVENT_USER_LEFT          = "user.left"
+	EVENT_COMMAND_PRIVILEGED = "command.privileged"
+
+	DefaultQueueSize   = 256              // default number of events waiting to be delivered
+	DefaultMaxAttempts = 3                // default number of times delivery of an event is attempted
+	DefaultTimeout     = 10 * time.Second // default amount of time allowed for a single delivery
+
+	initialRetryDelay = 1 * time.Second
+)
+
+// Event is a serializable schema describing
+// something notable that happened in a room
+type Event struct {
+	Type string                 `json:"type"`
+	Room string                 `json:"room"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// Dispatcher delivers room events to external systems
+type Dispatcher interface {
+	// Dispatch queues the given event for delivery without
+	// blocking. Events are dropped if the queue is full.
+	Dispatch(Event)
+}
+
+// HTTPDispatcher implements Dispatcher, POSTing every event as
+// JSON to a list of urls. Events are delivered in the order they
+// were dispatched by a single background worker, so that a slow
+// endpoint delays only other webhook deliveries.
+type HTTPDispatcher struct {
+	urls        []string
+	client      *http.Client
+	queue       chan Event
+	maxAttempts int
+}
+
+func (d *HTTPDispatcher) Dispatch(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	select {
+	case d.queue <- e:
+	default:
+		logging.Default.Warnf("WEBHOOK queue is full; dropping %q event for room %q", e.Type, e.Room)
+	}
+}
+
+// run delivers queued events until the queue is closed
+func (d *HTTPDispatcher) run() {
+	for e := range d.queue {
+		body, err := json.Marshal(e)
+		if err != nil {
+			logging.Default.Errorf("WEBHOOK unable to serialize %q event for room %q: %v", e.Type, e.Room, err)
+			continue
+		}
+
+		for _, url := range d.urls {
+			d.deliver(url, e, body)
+		}
+	}
+}
+
+// deliver POSTs the given serialized event to the given url,
+// retrying with exponential backoff if delivery fails.
+func (d *HTTPDispatcher) deliver(url string, e Event, body []byte) {
+	delay := initialRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := d.post(url, body)
+		if err == nil {
+			return
+		}
+
+		if attempt >= d.maxAttempts {
+			logging.Default.Errorf("WEBHOOK giving up delivering %q event for room %q to %q after %v attempts: %v", e.Type, e.Room, url, attempt, err)
+			return
+		}
+
+		logging.Default.Warnf("WEBHOOK unable to deliver %q event for room %q to %q (retrying in %v): %v", e.Type, e.Room, url, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (d *HTTPDispatcher) post(url string, body []byte) error {
+	res, err := d.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", res.Status)
+	}
+	return nil
+}
+
+// NewHTTPDispatcher returns a Dispatcher POSTing events to the given
+// urls. At most queueSize events wait to be delivered at once, and
+// delivery of each event to each url is attempted up to maxAttempts times.
+func NewHTTPDispatcher(urls []string, queueSize, maxAttempts int) Dispatcher {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	d := &HTTPDispatcher{
+		urls:        urls,
+		client:      &http.Client{Timeout: DefaultTimeout},
+		queue:       make(chan Event, queueSize),
+		maxAttempts: maxAttempts,
+	}
+
+	go d.run()
+	return d
+}